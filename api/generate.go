@@ -0,0 +1,4 @@
+// Package api holds the protobuf definitions of the gRPC API and the code generated from them
+package api
+
+//go:generate protoc -I proto --go_out=gen --go_opt=paths=source_relative --go-grpc_out=gen --go-grpc_opt=paths=source_relative quotes/v1/quotes.proto
@@ -1,38 +1,271 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"github.com/shoksin/quotes-service/configs"
+	"github.com/shoksin/quotes-service/internal/auth"
+	"github.com/shoksin/quotes-service/internal/cache"
+	"github.com/shoksin/quotes-service/internal/card"
+	graphqlserver "github.com/shoksin/quotes-service/internal/delivery/graphql"
+	grpcserver "github.com/shoksin/quotes-service/internal/delivery/grpc"
 	handler "github.com/shoksin/quotes-service/internal/delivery/http"
 	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/delivery/http/openapi"
+	"github.com/shoksin/quotes-service/internal/domain"
 	"github.com/shoksin/quotes-service/internal/repository"
 	"github.com/shoksin/quotes-service/internal/storage"
 	"github.com/shoksin/quotes-service/internal/usecase"
+	"github.com/shoksin/quotes-service/internal/webhook"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
+const (
+	// eventsChannel is the LISTEN/NOTIFY channel announcing new quote events, see migrations/010
+	eventsChannel = "quote_events"
+	// cacheChannel is the LISTEN/NOTIFY channel announcing writes that invalidate cached reads, see migrations/013
+	cacheChannel = "quote_cache"
+	// shutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+	shutdownTimeout = 30 * time.Second
+)
+
+const usage = `Usage: api [command]
+
+Commands:
+  serve    start the HTTP server (default)
+  export   write all quotes to a file or stdout
+  import   load quotes from a file or stdin
+`
+
 func main() {
+	command := "serve"
+	var args []string
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
 	cfg := configs.Load()
 
+	switch command {
+	case "serve":
+		os.Exit(serve(cfg))
+	case "export":
+		os.Exit(runExport(cfg, args))
+	case "import":
+		os.Exit(runImport(cfg, args))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// newQuoteUseCase builds the use case from the settings shared by every command; serve
+// adds its own through extra
+func newQuoteUseCase(cfg *configs.Config, db *sql.DB, extra ...usecase.Option) *usecase.QuoteUseCase {
+	quoteRepo := repository.NewQuoteRepository(db)
+
+	opts := []usecase.Option{
+		usecase.WithValidationRules(domain.ValidationRules{
+			MaxAuthorLength: cfg.Validation.MaxAuthorLength,
+			MaxQuoteLength:  cfg.Validation.MaxQuoteLength,
+			MaxTags:         cfg.Validation.MaxTags,
+			MaxTagLength:    cfg.Validation.MaxTagLength,
+		}),
+		usecase.WithBatchLimits(domain.BatchLimits{
+			MaxCreateItems: cfg.Batch.MaxCreateItems,
+			MaxDeleteItems: cfg.Batch.MaxDeleteItems,
+		}),
+		usecase.WithWebhooks(webhook.NewSender(cfg.Webhook.Timeout, webhook.WithPrivateNetworks(cfg.Webhook.AllowPrivateNetworks)), domain.WebhookPolicy{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			BackoffBase: cfg.Webhook.BackoffBase,
+			BackoffMax:  cfg.Webhook.BackoffMax,
+			BatchSize:   cfg.Webhook.BatchSize,
+			// Sends run concurrently, so a claim only needs to outlast one of them
+			Lease: 2*cfg.Webhook.Timeout + 5*time.Second,
+		}),
+		usecase.WithStatsPolicy(domain.StatsPolicy{
+			Days:         domain.DefaultStatsPolicy.Days,
+			Weeks:        domain.DefaultStatsPolicy.Weeks,
+			Months:       domain.DefaultStatsPolicy.Months,
+			TopAuthors:   cfg.Stats.TopAuthors,
+			TopWords:     cfg.Stats.TopWords,
+			Materialized: cfg.Stats.Materialized,
+		}),
+	}
+	return usecase.NewQuoteUseCase(quoteRepo, append(opts, extra...)...)
+}
+
+// readCache returns the store of the read cache selected by the settings, nil when it is off
+func readCache(cfg configs.CacheConfig) (usecase.Cache, error) {
+	switch cfg.Backend {
+	case "memory":
+		return cache.NewMemory(cfg.Size), nil
+	case "redis":
+		store, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "off":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown CACHE_BACKEND %q, expected memory, redis or off", cfg.Backend)
+}
+
+// apiVersions turns the API settings into the versions the handler serves
+func apiVersions(cfg configs.APIConfig) (handler.VersionConfig, error) {
+	versions := handler.VersionConfig{Deprecations: make(map[handler.APIVersion]handler.Deprecation)}
+	var err error
+	if versions.Default, err = handler.ParseAPIVersion(cfg.DefaultVersion); err != nil {
+		return versions, err
+	}
+	if !cfg.V1Deprecation.IsZero() || !cfg.V1Sunset.IsZero() {
+		versions.Deprecations[handler.APIv1] = handler.Deprecation{
+			At:     cfg.V1Deprecation,
+			Sunset: cfg.V1Sunset,
+			Link:   cfg.V1DeprecationLink,
+		}
+	}
+	return versions, nil
+}
+
+// serve runs the servers until a signal asks them to stop or one of them fails, and returns
+// the exit code
+func serve(cfg *configs.Config) int {
+	// ctx ends on SIGINT or SIGTERM, stopping the background workers and the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	db, err := storage.NewPostgresConnection(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
-	quoteRepo := repository.NewQuoteRepository(db)
+	apiKeys, err := auth.ParseKeys(cfg.Auth.APIKeys)
+	if err != nil {
+		log.Fatalf("Invalid API_KEYS: %v", err)
+	}
+
+	var cacheOpts []usecase.Option
+	store, err := readCache(cfg.Cache)
+	if err != nil {
+		log.Fatalf("Invalid cache settings: %v", err)
+	}
+	if store != nil {
+		cacheOpts = append(cacheOpts, usecase.WithCache(store, cfg.Cache.TTL))
+	}
+
+	quoteUseCase := newQuoteUseCase(cfg, db, cacheOpts...)
+	if cfg.Trash.Retention > 0 && cfg.Trash.PurgeInterval > 0 {
+		go quoteUseCase.RunTrashPurger(ctx, cfg.Trash.PurgeInterval, cfg.Trash.Retention)
+	}
+	if cfg.Audit.ChainInterval > 0 {
+		go quoteUseCase.RunAuditChainer(ctx, cfg.Audit.ChainInterval)
+	}
+	if cfg.Webhook.PollInterval > 0 {
+		go quoteUseCase.RunWebhookDispatcher(ctx, cfg.Webhook.PollInterval)
+	}
+	if cfg.Webhook.OutboxRetention > 0 && cfg.Webhook.OutboxPruneInterval > 0 {
+		go quoteUseCase.RunOutboxPruner(ctx, cfg.Webhook.OutboxPruneInterval, cfg.Webhook.OutboxRetention)
+	}
+	if cfg.Stats.Materialized {
+		go quoteUseCase.RunStatsRefresher(ctx, cfg.Stats.RefreshInterval)
+	}
+
+	// Every replica reads new events once per notification and fans them out to its own streams
+	wake, err := storage.ListenNotifications(ctx, cfg.Database, eventsChannel)
+	if err != nil {
+		log.Printf("Quote event notifications unavailable, polling every %s: %v", cfg.Stream.PollInterval, err)
+	}
+	go quoteUseCase.RunEventStream(ctx, wake, cfg.Stream.PollInterval)
+
+	// Writes on any replica drop the cached reads of all of them
+	if store != nil {
+		cacheWake, err := storage.ListenNotifications(ctx, cfg.Database, cacheChannel)
+		if err != nil {
+			log.Printf("Cache invalidation notifications unavailable, cached reads may be stale for up to %s: %v", cfg.Cache.TTL, err)
+		} else {
+			go quoteUseCase.RunCacheInvalidator(ctx, cacheWake)
+		}
+	}
 
-	quoteUseCase := usecase.NewQuoteUseCase(quoteRepo)
+	versions, err := apiVersions(cfg.API)
+	if err != nil {
+		log.Fatalf("Invalid API version settings: %v", err)
+	}
 
-	quoteHandler := handler.NewQuoteHandler(quoteUseCase)
+	quoteHandler := handler.NewQuoteHandler(quoteUseCase,
+		handler.WithMaxBodyBytes(cfg.Server.MaxBodyBytes),
+		handler.WithBaseURL(cfg.Server.PublicURL),
+		handler.WithMaxBatchBodyBytes(cfg.Batch.MaxBodyBytes),
+		handler.WithMaxImportBodyBytes(cfg.Batch.MaxImportBodyBytes),
+		handler.WithFeed(handler.FeedConfig{
+			Title:       cfg.Feed.Title,
+			Description: handler.DefaultFeedConfig.Description,
+			Entries:     cfg.Feed.Entries,
+		}),
+		handler.WithCardCache(card.NewCache(cfg.Card.CacheBytes)),
+		handler.WithEmbed(handler.EmbedConfig{
+			ProviderName:   cfg.Feed.Title,
+			AllowedOrigins: cfg.Embed.AllowedOrigins,
+			Width:          cfg.Embed.Width,
+			Height:         cfg.Embed.Height,
+			CacheAge:       handler.DefaultEmbedConfig.CacheAge,
+		}),
+		handler.WithStream(handler.StreamConfig{
+			Heartbeat: cfg.Stream.Heartbeat,
+			Retry:     cfg.Stream.Retry,
+		}),
+		handler.WithWebSocket(handler.WebSocketConfig{
+			PingInterval:   cfg.WebSocket.PingInterval,
+			QueueSize:      cfg.WebSocket.QueueSize,
+			AckWindow:      cfg.WebSocket.AckWindow,
+			AllowedOrigins: cfg.WebSocket.AllowedOrigins,
+		}),
+		handler.WithVersions(versions),
+		handler.WithStatsMaxAge(cfg.Stats.MaxAge),
+	)
 
 	router := http.NewServeMux()
 	quoteHandler.RegisterRoutes(router)
-	wrapped := middleware.LoggingMiddleware(router)
+	router.Handle("/graphql", graphqlserver.NewHandler(quoteUseCase, graphqlserver.Config{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+		MaxBodyBytes:  cfg.Server.MaxBodyBytes,
+		GraphiQL:      cfg.GraphQL.GraphiQL,
+	}))
+
+	var routes http.Handler = router
+	if cfg.OpenAPI.ValidateRequests || cfg.OpenAPI.ValidateResponses {
+		spec, err := openapi.Load()
+		if err != nil {
+			log.Fatalf("Failed to load the OpenAPI document: %v", err)
+		}
+		routes = middleware.ValidationMiddleware(spec, middleware.ValidationConfig{
+			Requests:     cfg.OpenAPI.ValidateRequests,
+			Responses:    cfg.OpenAPI.ValidateResponses,
+			MaxBodyBytes: max(cfg.Server.MaxBodyBytes, cfg.Batch.MaxBodyBytes),
+		}, router)
+	}
+	// The keys of API_KEYS are checked first, then the keys created through /admin/keys
+	authenticator := auth.Chain{apiKeys, quoteUseCase}
+	wrapped := middleware.RequestIDMiddleware(middleware.LoggingMiddleware(middleware.AuthMiddleware(authenticator, routes)))
+
+	grpcServer := grpcserver.NewServer(quoteUseCase, authenticator)
 
 	addr := ":" + cfg.Server.Port
 
+	// Streaming responses such as /quotes/stream and /quotes/export lift WriteTimeout for themselves
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      wrapped,
@@ -41,9 +274,65 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("Server listening on port %s", cfg.Server.Port)
-	if err = server.ListenAndServe(); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	// Either server failing stops the service
+	serverErr := make(chan error, 2)
+	if cfg.GRPC.Multiplex {
+		server.Handler = grpcserver.Multiplex(grpcServer, wrapped)
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		log.Printf("gRPC served on the HTTP port %s", cfg.Server.Port)
+	} else {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Printf("Failed to listen for gRPC: %v", err)
+			return 1
+		}
+		go func() {
+			log.Printf("gRPC server listening on port %s", cfg.GRPC.Port)
+			if err := grpcServer.Serve(lis); err != nil {
+				serverErr <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
 	}
 
+	go func() {
+		log.Printf("Server listening on port %s", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			serverErr <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	var failure error
+	select {
+	case failure = <-serverErr:
+		log.Printf("Server failed: %v", failure)
+	case <-ctx.Done():
+		log.Println("Shutting down")
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// gRPC streams are ended once they finish, or cut off when the shutdown timeout runs out
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+		<-grpcStopped
+	}
+	log.Println("Server stopped")
+	if failure != nil {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shoksin/quotes-service/configs"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/storage"
+	"github.com/shoksin/quotes-service/internal/transfer"
+)
+
+// runExport implements `api export [-format json] [-o file]`
+func runExport(cfg *configs.Config, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "json", "output format: csv, jsonl, json or fortune")
+	output := fs.String("o", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := transfer.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create %s: %v\n", *output, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	buffered := bufio.NewWriter(out)
+
+	db, err := storage.NewPostgresConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	enc, err := transfer.NewEncoder(format, buffered)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	count := 0
+	err = newQuoteUseCase(cfg, db).ExportQuotes(func(quote *domain.Quote) error {
+		count++
+		return enc.Encode(quote)
+	})
+	if err == nil {
+		err = enc.Close()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d quotes\n", count)
+	return 0
+}
+
+// runImport implements `api import [-format fortune] [-default-author Name] file`
+func runImport(cfg *configs.Config, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatName := fs.String("format", "", "input format: csv, jsonl, json or fortune (default: from file extension)")
+	defaultAuthor := fs.String("default-author", "", "author for fortune entries without attribution")
+	actor := fs.String("actor", "cli", "name recorded as the actor in quote revisions")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: api import [-format fmt] [-default-author name] <file|->")
+		return 2
+	}
+	path := fs.Arg(0)
+
+	name := *formatName
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(path), ".")
+		if name == "txt" || name == "" {
+			name = string(transfer.FormatFortune)
+		}
+	}
+	format, err := transfer.ParseFormat(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open %s: %v\n", path, err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	db, err := storage.NewPostgresConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	records := transfer.Records(format, bufio.NewReader(in), transfer.DecodeOptions{DefaultAuthor: *defaultAuthor})
+	ctx := domain.WithActor(context.Background(), *actor)
+	report, err := newQuoteUseCase(cfg, db).ImportQuotes(ctx, records)
+
+	if report != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+func adminCommand() *command {
+	return &command{
+		name:    "admin",
+		summary: "Manage API keys, the trash and webhooks",
+		subcommands: []*command{
+			{
+				name:    "keys",
+				summary: "Create and revoke API keys and check the key in use",
+				subcommands: []*command{
+					{name: "list", summary: "List the keys created through the API", setup: listKeys},
+					{name: "create", args: "<name>", summary: "Create a key and show it once", setup: createKey},
+					{name: "revoke", args: "<id>", summary: "Revoke a key", setup: revokeKey},
+					{name: "check", summary: "Check the key in use and show its role", setup: checkKey},
+				},
+			},
+			{
+				name:    "trash",
+				summary: "Inspect and empty the trash",
+				subcommands: []*command{
+					{name: "list", summary: "List deleted quotes", setup: listTrash},
+					{name: "restore", args: "<id>", summary: "Restore a deleted quote", setup: restoreQuote},
+					{name: "purge", args: "<id>", summary: "Delete a quote permanently", setup: purgeQuote},
+				},
+			},
+			{
+				name:    "webhooks",
+				summary: "Manage webhook subscriptions and their deliveries",
+				subcommands: []*command{
+					{name: "list", summary: "List subscriptions", setup: listWebhooks},
+					{name: "get", args: "<id>", summary: "Show a subscription", setup: getWebhook},
+					{name: "create", summary: "Subscribe a URL to quote events", setup: createWebhook},
+					{name: "update", args: "<id>", summary: "Change a subscription", setup: updateWebhook},
+					{name: "delete", args: "<id>", summary: "Delete a subscription", setup: deleteWebhook},
+					{name: "deliveries", args: "<id>", summary: "List the deliveries of a subscription", setup: listDeliveries},
+					{name: "redeliver", args: "<id> <delivery>", summary: "Retry a delivery now", setup: redeliver},
+				},
+			},
+		},
+	}
+}
+
+func listKeys(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		keys, err := c.GetAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, keysView(keys))
+	}
+}
+
+func createKey(a *app, fs *flag.FlagSet) runner {
+	role := fs.String("role", string(client.RoleUser), "role of the key: user or admin")
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("keys create takes the name of the key")
+		}
+		if r := client.Role(*role); r != client.RoleUser && r != client.RoleAdmin {
+			return usagef("unknown role %q, expected user or admin", *role)
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		key, err := c.CreateAPIKey(ctx, &client.KeyRequest{Name: args[0], Role: client.Role(*role)})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.stderr, "the key is not shown again, store it now")
+		return a.print(s.output, keyView(key))
+	}
+}
+
+func revokeKey(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("keys revoke takes the ID of the key")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil || id <= 0 {
+			return usagef("invalid key ID %q", args[0])
+		}
+		c, _, err := a.client()
+		if err != nil {
+			return err
+		}
+		if err := c.RevokeAPIKey(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "key %d revoked\n", id)
+		return nil
+	}
+}
+
+func checkKey(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		if s.apiKey == "" {
+			return usagef("no API key is set, see --api-key")
+		}
+
+		// Listing keys takes an admin key, which tells the roles apart
+		role := client.RoleAdmin
+		if _, err := c.GetAPIKeys(ctx); err != nil {
+			if !errors.Is(err, client.ErrForbidden) {
+				return err
+			}
+			role = client.RoleUser
+		}
+		return a.print(s.output, fieldsView([][2]string{
+			{"url", s.url}, {"key", maskKey(s.apiKey)}, {"role", string(role)},
+		}, map[string]string{"url": s.url, "role": string(role)}))
+	}
+}
+
+func listTrash(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quotes, err := c.GetTrash(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, trashView(quotes))
+	}
+}
+
+func restoreQuote(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		id, err := parseID(args, "trash restore")
+		if err != nil {
+			return err
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quote, err := c.RestoreQuote(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, quoteView(quote))
+	}
+}
+
+func purgeQuote(a *app, fs *flag.FlagSet) runner {
+	version := fs.Int("version", 0, "only delete the quote while it is at this version")
+	return func(ctx context.Context, args []string) error {
+		id, err := parseID(args, "trash purge")
+		if err != nil {
+			return err
+		}
+		c, _, err := a.client()
+		if err != nil {
+			return err
+		}
+		if err := c.HardDeleteQuote(ctx, id, *version); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "quote %d deleted permanently\n", id)
+		return nil
+	}
+}
+
+// parseWebhookID reads the subscription ID, the first of the n arguments of a command
+func parseWebhookID(args []string, name string, n int) (int, error) {
+	if len(args) != n {
+		return 0, usagef("%s takes %d arguments, see -h", name, n)
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, usagef("invalid subscription ID %q", args[0])
+	}
+	return id, nil
+}
+
+// webhookFlags are the settings of a subscription
+type webhookFlags struct {
+	url      string
+	events   stringList
+	secret   string
+	inactive bool
+}
+
+func (f *webhookFlags) register(fs *flag.FlagSet) {
+	// The global --url selects the service, so the subscribed URL has a flag of its own
+	fs.StringVar(&f.url, "target", "", "URL the events are posted to")
+	fs.Var(&f.events, "event", "event type to send: quote.created, quote.updated or quote.deleted; repeat for more (default all)")
+	fs.StringVar(&f.secret, "secret", "", "secret the payloads are signed with (default generated)")
+	fs.BoolVar(&f.inactive, "inactive", false, "pause deliveries")
+}
+
+func (f *webhookFlags) eventTypes() []client.EventType {
+	events := make([]client.EventType, len(f.events))
+	for i, e := range f.events {
+		events[i] = client.EventType(e)
+	}
+	return events
+}
+
+func listWebhooks(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		subs, err := c.GetWebhooks(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, webhooksView(subs))
+	}
+}
+
+func getWebhook(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		id, err := parseWebhookID(args, "webhooks get", 1)
+		if err != nil {
+			return err
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		sub, err := c.GetWebhook(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, webhookView(sub))
+	}
+}
+
+func createWebhook(a *app, fs *flag.FlagSet) runner {
+	var f webhookFlags
+	f.register(fs)
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 0 {
+			return usagef("unexpected argument %q", args[0])
+		}
+		if f.url == "" {
+			return usagef("webhooks create needs --target")
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		active := !f.inactive
+		sub, err := c.CreateWebhook(ctx, &client.WebhookSubscriptionRequest{
+			URL: f.url, Secret: f.secret, Events: f.eventTypes(), Active: &active,
+		})
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, webhookView(sub))
+	}
+}
+
+func updateWebhook(a *app, fs *flag.FlagSet) runner {
+	var f webhookFlags
+	f.register(fs)
+	active := fs.Bool("active", false, "resume deliveries")
+	return func(ctx context.Context, args []string) error {
+		id, err := parseWebhookID(args, "webhooks update", 1)
+		if err != nil {
+			return err
+		}
+		if f.inactive && *active {
+			return usagef("--active and --inactive are exclusive")
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+
+		// An update replaces the subscription, so unset flags keep the current values
+		sub, err := c.GetWebhook(ctx, id)
+		if err != nil {
+			return err
+		}
+		req := &client.WebhookSubscriptionRequest{URL: sub.URL, Secret: f.secret, Events: sub.Events, Active: &sub.Active}
+		if f.url != "" {
+			req.URL = f.url
+		}
+		if len(f.events) > 0 {
+			req.Events = f.eventTypes()
+		}
+		if f.inactive || *active {
+			req.Active = active
+		}
+
+		sub, err = c.UpdateWebhook(ctx, id, req)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, webhookView(sub))
+	}
+}
+
+func deleteWebhook(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		id, err := parseWebhookID(args, "webhooks delete", 1)
+		if err != nil {
+			return err
+		}
+		c, _, err := a.client()
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteWebhook(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "subscription %d deleted\n", id)
+		return nil
+	}
+}
+
+func listDeliveries(a *app, fs *flag.FlagSet) runner {
+	status := fs.String("status", "", "only deliveries in this state: pending, delivered or dead")
+	return func(ctx context.Context, args []string) error {
+		id, err := parseWebhookID(args, "webhooks deliveries", 1)
+		if err != nil {
+			return err
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		deliveries, err := c.GetDeliveries(ctx, id, client.DeliveryStatus(*status))
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, deliveriesView(deliveries))
+	}
+}
+
+func redeliver(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		id, err := parseWebhookID(args, "webhooks redeliver", 2)
+		if err != nil {
+			return err
+		}
+		deliveryID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || deliveryID <= 0 {
+			return usagef("invalid delivery ID %q", args[1])
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		delivery, err := c.RedeliverWebhook(ctx, id, deliveryID)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, deliveriesView([]*client.WebhookDelivery{delivery}))
+	}
+}
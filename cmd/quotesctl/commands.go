@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+func quoteCommands() []*command {
+	return []*command{
+		{name: "add", args: "[text|-]", summary: "Add a quote, reading the text from stdin without one", setup: addQuote},
+		{name: "get", args: "<id>", summary: "Show a quote", setup: getQuote},
+		{name: "list", summary: "List quotes, a page at a time", setup: listQuotes},
+		{name: "search", args: "<query>...", summary: "Search the text, author and tags of quotes", setup: searchQuotes},
+		{name: "random", summary: "Show a random quote", setup: randomQuote},
+		{name: "daily", summary: "Show the quote of the day", setup: dailyQuote},
+		{name: "delete", args: "<id>", summary: "Move a quote to the trash", setup: deleteQuote},
+		{name: "import", args: "<file|->", summary: "Import quotes from a csv, jsonl, json or fortune file", setup: importQuotes},
+		{name: "export", summary: "Export every quote", setup: exportQuotes},
+	}
+}
+
+// stringList is a flag that may be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseID reads the ID argument of a command
+func parseID(args []string, name string) (int, error) {
+	if len(args) != 1 {
+		return 0, usagef("%s takes the ID of a quote", name)
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, usagef("invalid ID %q", args[0])
+	}
+	return id, nil
+}
+
+func addQuote(a *app, fs *flag.FlagSet) runner {
+	author := fs.String("author", "", "author of the quote (required)")
+	var tags stringList
+	fs.Var(&tags, "tag", "tag of the quote, repeat for more")
+	return func(ctx context.Context, args []string) error {
+		if *author == "" {
+			return usagef("add needs --author")
+		}
+		text := strings.Join(args, " ")
+		if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+			data, err := io.ReadAll(a.stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read the quote: %w", err)
+			}
+			text = strings.TrimSpace(string(data))
+		}
+
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quote, err := c.CreateQuote(ctx, &client.CreateQuoteRequest{Author: *author, Quote: text, Tags: tags})
+		if err != nil {
+			return err
+		}
+		if s.output == outputPlain {
+			_, err := fmt.Fprintln(a.stdout, quote.ID)
+			return err
+		}
+		return a.print(s.output, quoteView(quote))
+	}
+}
+
+func getQuote(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		id, err := parseID(args, "get")
+		if err != nil {
+			return err
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quote, err := c.GetQuote(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, quoteView(quote))
+	}
+}
+
+func randomQuote(a *app, fs *flag.FlagSet) runner {
+	return showQuote(a, (*client.Client).GetRandomQuote)
+}
+
+func dailyQuote(a *app, fs *flag.FlagSet) runner {
+	return showQuote(a, (*client.Client).GetDailyQuote)
+}
+
+func showQuote(a *app, get func(*client.Client, context.Context) (*client.Quote, error)) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 0 {
+			return usagef("unexpected argument %q", args[0])
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quote, err := get(c, ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, quoteView(quote))
+	}
+}
+
+func listQuotes(a *app, fs *flag.FlagSet) runner {
+	var opts client.ListOptions
+	fs.StringVar(&opts.Author, "author", "", "only quotes by this author")
+	fs.StringVar(&opts.Tag, "tag", "", "only quotes with this tag")
+	fs.Func("created-after", "only quotes created after this date or RFC 3339 time", timeFlag(&opts.CreatedAfter))
+	fs.Func("created-before", "only quotes created before this date or RFC 3339 time", timeFlag(&opts.CreatedBefore))
+	fs.IntVar(&opts.MinLength, "min-length", 0, "only quotes of at least this many characters")
+	fs.IntVar(&opts.MaxLength, "max-length", 0, "only quotes of at most this many characters")
+	fs.StringVar(&opts.Sort, "sort", "", "sort by created_at, author or length; prefix with - to reverse (default by ID)")
+	fs.IntVar(&opts.Limit, "limit", 20, "quotes per page")
+	fs.StringVar(&opts.Cursor, "cursor", "", "continue after the page that printed this cursor")
+	all := fs.Bool("all", false, "list every page")
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 0 {
+			return usagef("unexpected argument %q", args[0])
+		}
+		if opts.Limit <= 0 {
+			return usagef("--limit must be positive")
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+
+		if *all {
+			quotes := []*client.Quote{}
+			for quote, err := range c.Quotes(ctx, opts) {
+				if err != nil {
+					return err
+				}
+				quotes = append(quotes, quote)
+			}
+			return a.print(s.output, quotesView(quotes, quotes))
+		}
+
+		page, err := c.ListQuotes(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if err := a.print(s.output, quotesView(page.Quotes, page)); err != nil {
+			return err
+		}
+		// The hint goes to stderr so that the output stays a list of quotes
+		if page.NextCursor != "" && s.output != outputJSON {
+			fmt.Fprintf(a.stderr, "more quotes: --cursor %s\n", page.NextCursor)
+		}
+		return nil
+	}
+}
+
+// timeFlag sets t from a date such as 2026-01-02, meaning its start in UTC, or an RFC 3339 time
+func timeFlag(t *time.Time) func(string) error {
+	return func(value string) error {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			if parsed, err = time.Parse(time.RFC3339, value); err != nil {
+				return errors.New("expected a date such as 2026-01-02 or an RFC 3339 time")
+			}
+		}
+		*t = parsed
+		return nil
+	}
+}
+
+func searchQuotes(a *app, fs *flag.FlagSet) runner {
+	limit := fs.Int("limit", 0, "most quotes to show (default chosen by the service)")
+	return func(ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return usagef("search needs a query")
+		}
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		quotes, err := c.SearchQuotes(ctx, strings.Join(args, " "), *limit)
+		if err != nil {
+			return err
+		}
+		return a.print(s.output, quotesView(quotes, quotes))
+	}
+}
+
+func deleteQuote(a *app, fs *flag.FlagSet) runner {
+	version := fs.Int("version", 0, "only delete the quote while it is at this version")
+	hard := fs.Bool("hard", false, "delete permanently instead of moving to the trash (admin)")
+	return func(ctx context.Context, args []string) error {
+		id, err := parseID(args, "delete")
+		if err != nil {
+			return err
+		}
+		c, _, err := a.client()
+		if err != nil {
+			return err
+		}
+		if *hard {
+			err = c.HardDeleteQuote(ctx, id, *version)
+		} else {
+			err = c.DeleteQuote(ctx, id, *version)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "quote %d deleted\n", id)
+		return nil
+	}
+}
+
+// formatFromPath infers the format of a file from its extension
+func formatFromPath(path string) (client.Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return client.FormatCSV, true
+	case ".jsonl", ".ndjson":
+		return client.FormatJSONL, true
+	case ".json":
+		return client.FormatJSON, true
+	case ".txt", ".fortune":
+		return client.FormatFortune, true
+	}
+	return "", false
+}
+
+func formatNames() string {
+	names := make([]string, len(client.Formats))
+	for i, f := range client.Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+func importQuotes(a *app, fs *flag.FlagSet) runner {
+	format := fs.String("format", "", "format of the file: "+formatNames()+" (default from the file extension)")
+	defaultAuthor := fs.String("default-author", "", "author of records without one, such as fortune cookies")
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("import takes a file, or - for stdin")
+		}
+
+		var opts client.ImportOptions
+		if *format != "" {
+			f, err := client.ParseFormat(*format)
+			if err != nil {
+				return usagef("%v", err)
+			}
+			opts.Format = f
+		} else if f, ok := formatFromPath(args[0]); ok {
+			opts.Format = f
+		} else {
+			return usagef("cannot tell the format of %q, set --format", args[0])
+		}
+		opts.DefaultAuthor = *defaultAuthor
+
+		r := a.stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		c, s, err := a.client()
+		if err != nil {
+			return err
+		}
+		report, err := c.ImportQuotesFrom(ctx, r, opts)
+		if err != nil {
+			return err
+		}
+		if err := a.print(s.output, importView(report)); err != nil {
+			return err
+		}
+		if report.Failed > 0 {
+			return fmt.Errorf("%d of %d records were rejected", report.Failed, report.Total)
+		}
+		return nil
+	}
+}
+
+func exportQuotes(a *app, fs *flag.FlagSet) runner {
+	format := fs.String("format", string(client.FormatJSON), "format of the file: "+formatNames())
+	file := fs.String("file", "", "write to this file instead of stdout")
+	return func(ctx context.Context, args []string) (err error) {
+		if len(args) != 0 {
+			return usagef("unexpected argument %q", args[0])
+		}
+		f, err := client.ParseFormat(*format)
+		if err != nil {
+			return usagef("%v", err)
+		}
+		c, _, err := a.client()
+		if err != nil {
+			return err
+		}
+
+		w := a.stdout
+		if *file != "" {
+			out, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := out.Close(); err == nil {
+					err = cerr
+				}
+				// A partial export is worse than none
+				if err != nil {
+					os.Remove(*file)
+				}
+			}()
+			w = out
+		}
+		if err := c.ExportQuotesTo(ctx, f, w); err != nil {
+			return err
+		}
+		if *file != "" {
+			fmt.Fprintf(a.stderr, "exported to %s\n", *file)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func completionCommand() *command {
+	return &command{
+		name:    "completion",
+		args:    "<bash|zsh|fish>",
+		summary: "Print a shell completion script",
+		setup:   completion,
+	}
+}
+
+func completion(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("completion takes the shell: bash, zsh or fish")
+		}
+		nodes := a.completionNodes()
+		switch args[0] {
+		case "bash":
+			writeBash(a.stdout, nodes)
+		case "zsh":
+			// zsh runs the bash script through its bash compatibility layer
+			fmt.Fprintln(a.stdout, "autoload -U +X bashcompinit && bashcompinit")
+			writeBash(a.stdout, nodes)
+		case "fish":
+			writeFish(a.stdout, nodes)
+		default:
+			return usagef("unsupported shell %q, expected bash, zsh or fish", args[0])
+		}
+		return nil
+	}
+}
+
+// completionNode is a command of the tree as the completion scripts see it
+type completionNode struct {
+	// path joins the names from the root with slashes, such as quotesctl/admin/keys
+	path    string
+	name    string
+	summary string
+	flags   []*flag.Flag
+	subs    []*completionNode
+}
+
+// completionNodes walks the command tree. The flags of a command are read by running
+// its setup on a throwaway flag set; the global flags are completed everywhere.
+func (a *app) completionNodes() []*completionNode {
+	var global globals
+	globalFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	global.register(globalFlags)
+
+	var nodes []*completionNode
+	var walk func(cmd *command, path string) *completionNode
+	walk = func(cmd *command, path string) *completionNode {
+		node := &completionNode{path: path, name: cmd.name, summary: cmd.summary}
+		nodes = append(nodes, node)
+		if cmd.setup != nil {
+			fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+			cmd.setup(a, fs)
+			fs.VisitAll(func(f *flag.Flag) {
+				if globalFlags.Lookup(f.Name) == nil {
+					node.flags = append(node.flags, f)
+				}
+			})
+		}
+		for _, sub := range cmd.subcommands {
+			node.subs = append(node.subs, walk(sub, path+"/"+sub.name))
+		}
+		return node
+	}
+	root := walk(a.root, a.root.name)
+	globalFlags.VisitAll(func(f *flag.Flag) {
+		root.flags = append(root.flags, f)
+	})
+	return nodes
+}
+
+// flagName is how the scripts complete a flag. Go accepts one dash or two; two read
+// better, except for the single-letter -o.
+func flagName(f *flag.Flag) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+func writeBash(w io.Writer, nodes []*completionNode) {
+	root := nodes[0]
+	var paths []string
+	for _, node := range nodes[1:] {
+		paths = append(paths, node.path)
+	}
+
+	fmt.Fprintf(w, `_quotesctl() {
+	local cur prev path word words i
+	cur=${COMP_WORDS[COMP_CWORD]}
+	prev=${COMP_WORDS[COMP_CWORD-1]}
+	path=%s
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		word=${COMP_WORDS[i]}
+		case "$path/$word" in
+		%s) path=$path/$word ;;
+		esac
+	done
+
+	case "$prev" in
+	--profile | -profile)
+		COMPREPLY=($(compgen -W "$(%s config list -o plain 2>/dev/null)" -- "$cur"))
+		return ;;
+	-o | --o)
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		return ;;
+	--format | -format)
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		return ;;
+	--config | -config | --file | -file)
+		COMPREPLY=($(compgen -f -- "$cur"))
+		return ;;
+	esac
+
+	case "$path" in
+`, root.path, strings.Join(paths, " | "), root.name, strings.Join(outputs, " "), strings.ReplaceAll(formatNames(), ",", ""))
+
+	for _, node := range nodes {
+		var words []string
+		for _, sub := range node.subs {
+			words = append(words, sub.name)
+		}
+		for _, f := range node.flags {
+			words = append(words, flagName(f))
+		}
+		if node != root {
+			for _, f := range root.flags {
+				words = append(words, flagName(f))
+			}
+		}
+		fmt.Fprintf(w, "\t%s) words=%q ;;\n", node.path, strings.Join(words, " "))
+	}
+
+	fmt.Fprintf(w, `	esac
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -o default -F _quotesctl %s
+`, root.name)
+}
+
+// fishQuote quotes s as a single-quoted fish string
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writeFish(w io.Writer, nodes []*completionNode) {
+	root := nodes[0]
+	var paths []string
+	for _, node := range nodes[1:] {
+		paths = append(paths, node.path)
+	}
+
+	fmt.Fprintf(w, `function __quotesctl_path
+	set -l path %s
+	for word in (commandline -opc)[2..-1]
+		switch "$path/$word"
+			case %s
+				set path $path/$word
+		end
+	end
+	echo $path
+end
+
+complete -c %s -f
+`, root.path, strings.Join(paths, " "), root.name)
+
+	for _, node := range nodes {
+		cond := fishQuote("test (__quotesctl_path) = " + node.path)
+		for _, sub := range node.subs {
+			fmt.Fprintf(w, "complete -c %s -n %s -a %s -d %s\n", root.name, cond, sub.name, fishQuote(sub.summary))
+		}
+		for _, f := range node.flags {
+			opt := "-l " + f.Name
+			if len(f.Name) == 1 {
+				opt = "-o " + f.Name
+			}
+			extra := ""
+			switch f.Name {
+			case "profile":
+				extra = " -x -a " + fishQuote("("+root.name+" config list -o plain 2>/dev/null)")
+			case "o":
+				extra = " -x -a " + fishQuote(strings.Join(outputs, " "))
+			case "format":
+				extra = " -x -a " + fishQuote(strings.ReplaceAll(formatNames(), ",", ""))
+			case "config", "file":
+				extra = " -r -F"
+			}
+			if node == root {
+				// The global flags apply to every command
+				fmt.Fprintf(w, "complete -c %s %s%s -d %s\n", root.name, opt, extra, fishQuote(f.Usage))
+			} else {
+				fmt.Fprintf(w, "complete -c %s -n %s %s%s -d %s\n", root.name, cond, opt, extra, fishQuote(f.Usage))
+			}
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+const (
+	defaultURL    = "http://localhost:8080"
+	defaultOutput = outputTable
+)
+
+// Config is the config file: named profiles, one per environment, and the one used
+// unless --profile selects another
+type Config struct {
+	CurrentProfile string              `json:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles"`
+}
+
+// Profile holds the settings of one environment
+type Profile struct {
+	URL    string `json:"url"`
+	APIKey string `json:"api_key,omitempty"`
+	Output string `json:"output,omitempty"`
+}
+
+// configPath returns the config file to use: --config, QUOTESCTL_CONFIG or
+// quotesctl/config.json in the user config directory
+func (a *app) configPath() (string, error) {
+	if a.globals.configPath != "" {
+		return a.globals.configPath, nil
+	}
+	if path := a.getenv("QUOTESCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the config file, set --config: %w", err)
+	}
+	return filepath.Join(dir, "quotesctl", "config.json"), nil
+}
+
+// loadConfig reads the config file; a missing one is an empty config
+func (a *app) loadConfig() (*Config, error) {
+	path, err := a.configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{Profiles: map[string]*Profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file readable by the user only, as it holds API keys
+func (a *app) saveConfig(cfg *Config) error {
+	path, err := a.configPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// settings are the connection and output settings a command runs with
+type settings struct {
+	profile string
+	url     string
+	apiKey  string
+	output  string
+}
+
+// resolve merges the settings. Flags win over environment variables, which win over
+// the profile, which wins over the defaults.
+func (a *app) resolve() (*settings, error) {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &settings{url: defaultURL, output: defaultOutput}
+	s.profile = first(a.globals.profile, a.getenv("QUOTESCTL_PROFILE"), cfg.CurrentProfile)
+	if s.profile != "" {
+		p, ok := cfg.Profiles[s.profile]
+		if !ok {
+			return nil, usagef("unknown profile %q, see `quotesctl config list`", s.profile)
+		}
+		s.url, s.apiKey, s.output = first(p.URL, s.url), p.APIKey, first(p.Output, s.output)
+	}
+	s.url = first(a.globals.url, a.getenv("QUOTESCTL_URL"), s.url)
+	s.apiKey = first(a.globals.apiKey, a.getenv("QUOTESCTL_API_KEY"), s.apiKey)
+	s.output = first(a.globals.output, a.getenv("QUOTESCTL_OUTPUT"), s.output)
+
+	if !slices.Contains(outputs, s.output) {
+		return nil, usagef("unknown output %q, expected one of %s", s.output, strings.Join(outputs, ", "))
+	}
+	return s, nil
+}
+
+// first returns the first non-empty value
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// client returns a client of the service the settings point at
+func (a *app) client() (*client.Client, *settings, error) {
+	s, err := a.resolve()
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := []client.Option{client.WithUserAgent("quotesctl")}
+	if s.apiKey != "" {
+		opts = append(opts, client.WithAuth(client.BearerToken(s.apiKey)))
+	}
+	c, err := a.newClient(s.url, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, s, nil
+}
+
+func configCommand() *command {
+	return &command{
+		name:    "config",
+		summary: "Manage the profiles of the config file",
+		subcommands: []*command{
+			{name: "list", summary: "List the profiles", setup: configList},
+			{name: "show", args: "[name]", summary: "Show the settings a command would use", setup: configShow},
+			{name: "set", args: "<name>", summary: "Create or change a profile", setup: configSet},
+			{name: "use", args: "<name>", summary: "Make a profile the default", setup: configUse},
+			{name: "delete", args: "<name>", summary: "Delete a profile", setup: configDelete},
+		},
+	}
+}
+
+func configList(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return err
+		}
+		s, err := a.outputOnly()
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		type profileRow struct {
+			Name    string `json:"name"`
+			Current bool   `json:"current"`
+			URL     string `json:"url"`
+		}
+		rows := make([]profileRow, len(names))
+		v := view{header: []string{"", "NAME", "URL"}}
+		for i, name := range names {
+			rows[i] = profileRow{Name: name, Current: name == cfg.CurrentProfile, URL: cfg.Profiles[name].URL}
+			mark := ""
+			if rows[i].Current {
+				mark = "*"
+			}
+			v.rows = append(v.rows, []string{mark, name, rows[i].URL})
+			v.plain = append(v.plain, name)
+		}
+		v.value = rows
+		return a.print(s.output, v)
+	}
+}
+
+// outputOnly resolves the settings for commands that do not call the service, which
+// still work with a broken profile
+func (a *app) outputOnly() (*settings, error) {
+	if s, err := a.resolve(); err == nil {
+		return s, nil
+	}
+	output := first(a.globals.output, a.getenv("QUOTESCTL_OUTPUT"), defaultOutput)
+	if !slices.Contains(outputs, output) {
+		return nil, usagef("unknown output %q, expected one of %s", output, strings.Join(outputs, ", "))
+	}
+	return &settings{output: output}, nil
+}
+
+func configShow(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return usagef("config show takes at most one profile name")
+		}
+		if len(args) == 1 {
+			a.globals.profile = args[0]
+		}
+		s, err := a.resolve()
+		if err != nil {
+			return err
+		}
+		path, _ := a.configPath()
+		key := ""
+		if s.apiKey != "" {
+			key = maskKey(s.apiKey)
+		}
+		fields := [][2]string{{"config", path}, {"profile", s.profile}, {"url", s.url}, {"api key", key}, {"output", s.output}}
+		return a.print(s.output, fieldsView(fields, map[string]string{
+			"config": path, "profile": s.profile, "url": s.url, "api_key": key, "output": s.output,
+		}))
+	}
+}
+
+// maskKey hides all but the last characters of an API key
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", 8) + key[len(key)-4:]
+}
+
+func configSet(a *app, fs *flag.FlagSet) runner {
+	// The global --url, --api-key and -o are the settings stored in the profile here
+	url := fs.Lookup("url")
+	apiKey := fs.Lookup("api-key")
+	output := fs.Lookup("o")
+	use := fs.Bool("use", false, "also make the profile the default")
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("config set takes the profile name")
+		}
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return err
+		}
+		p := cfg.Profiles[args[0]]
+		if p == nil {
+			p = &Profile{URL: defaultURL}
+			cfg.Profiles[args[0]] = p
+		}
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if set["url"] {
+			if _, err := client.New(url.Value.String()); err != nil {
+				return usagef("%v", err)
+			}
+			p.URL = url.Value.String()
+		}
+		if set["api-key"] {
+			p.APIKey = apiKey.Value.String()
+		}
+		if set["o"] {
+			if !slices.Contains(outputs, output.Value.String()) {
+				return usagef("unknown output %q, expected one of %s", output.Value, strings.Join(outputs, ", "))
+			}
+			p.Output = output.Value.String()
+		}
+		if *use || cfg.CurrentProfile == "" {
+			cfg.CurrentProfile = args[0]
+		}
+		if err := a.saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "profile %q saved\n", args[0])
+		return nil
+	}
+}
+
+func configUse(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("config use takes the profile name")
+		}
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Profiles[args[0]]; !ok {
+			return usagef("unknown profile %q, see `quotesctl config list`", args[0])
+		}
+		cfg.CurrentProfile = args[0]
+		return a.saveConfig(cfg)
+	}
+}
+
+func configDelete(a *app, fs *flag.FlagSet) runner {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usagef("config delete takes the profile name")
+		}
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Profiles[args[0]]; !ok {
+			return usagef("unknown profile %q, see `quotesctl config list`", args[0])
+		}
+		delete(cfg.Profiles, args[0])
+		if cfg.CurrentProfile == args[0] {
+			cfg.CurrentProfile = ""
+		}
+		return a.saveConfig(cfg)
+	}
+}
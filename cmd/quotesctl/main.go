@@ -0,0 +1,227 @@
+// Command quotesctl is a command-line client for the quotes service.
+//
+//	quotesctl config set prod --url https://quotes.example.com --api-key $KEY
+//	quotesctl --profile prod list --author "Jane Austen" -o json
+//	quotesctl admin webhooks create --target https://hooks.example.com/quotes
+//
+// Run `quotesctl help` for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+// command is a node of the command tree. A command either runs or groups subcommands.
+type command struct {
+	name    string
+	args    string
+	summary string
+	// setup registers the flags of the command on fs and returns what runs it
+	setup       func(a *app, fs *flag.FlagSet) runner
+	subcommands []*command
+}
+
+func (c *command) find(name string) *command {
+	for _, sub := range c.subcommands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// runner runs a command with its positional arguments
+type runner func(ctx context.Context, args []string) error
+
+// usageError is a mistake in the command line; it exits with status 2
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// globals are the flags every command accepts
+type globals struct {
+	configPath string
+	profile    string
+	url        string
+	apiKey     string
+	output     string
+	timeout    time.Duration
+}
+
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.configPath, "config", g.configPath, "config file (env QUOTESCTL_CONFIG)")
+	fs.StringVar(&g.profile, "profile", g.profile, "profile of the config file to use (env QUOTESCTL_PROFILE)")
+	fs.StringVar(&g.url, "url", g.url, "base URL of the service (env QUOTESCTL_URL)")
+	fs.StringVar(&g.apiKey, "api-key", g.apiKey, "API key (env QUOTESCTL_API_KEY)")
+	fs.StringVar(&g.output, "o", g.output, "output: table, json or plain (env QUOTESCTL_OUTPUT)")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "time limit of the command")
+}
+
+// app is one run of quotesctl
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	globals globals
+	root    *command
+	// newClient is replaced by tests
+	newClient func(baseURL string, opts ...client.Option) (*client.Client, error)
+}
+
+func newApp(stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) *app {
+	a := &app{
+		stdin:     stdin,
+		stdout:    stdout,
+		stderr:    stderr,
+		getenv:    getenv,
+		globals:   globals{timeout: time.Minute},
+		newClient: client.New,
+	}
+	a.root = &command{
+		name:        "quotesctl",
+		subcommands: append(quoteCommands(), configCommand(), adminCommand(), completionCommand()),
+	}
+	return a
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(newApp(os.Stdin, os.Stdout, os.Stderr, os.Getenv).run(ctx, os.Args[1:]))
+}
+
+// run executes the command line args and returns the exit status
+func (a *app) run(ctx context.Context, args []string) int {
+	cmd, path := a.root, []string{a.root.name}
+	for cmd.setup == nil {
+		fs := a.flagSet(cmd, path)
+		if err := fs.Parse(args); err != nil {
+			return parseStatus(err)
+		}
+		args = fs.Args()
+		if len(args) == 0 || args[0] == "help" {
+			a.usage(cmd, path, a.stdout)
+			return 0
+		}
+		sub := cmd.find(args[0])
+		if sub == nil {
+			return a.fail(usagef("unknown command %q, see `%s help`", args[0], strings.Join(path, " ")))
+		}
+		cmd, path, args = sub, append(path, sub.name), args[1:]
+	}
+
+	fs := a.flagSet(cmd, path)
+	run := cmd.setup(a, fs)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return parseStatus(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.globals.timeout)
+	defer cancel()
+	return a.fail(run(ctx, positional))
+}
+
+// flagSet returns the flag set of cmd with the global flags registered. Commands add
+// their own in setup.
+func (a *app) flagSet(cmd *command, path []string) *flag.FlagSet {
+	name := strings.Join(path, " ")
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		if cmd.setup == nil {
+			a.usage(cmd, path, a.stderr)
+			return
+		}
+		fmt.Fprintf(a.stderr, "Usage: %s\n\n%s\n\nFlags:\n", strings.TrimSpace(name+" [flags] "+cmd.args), cmd.summary)
+		fs.PrintDefaults()
+	}
+	a.globals.register(fs)
+	return fs
+}
+
+// parseStatus is the exit status after a flag parse error, which the flag set has
+// already reported along with the usage
+func parseStatus(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 2
+}
+
+// parseInterspersed parses fs from args that may mix flags and positional arguments,
+// such as `get 42 -o json`, and returns the positional ones
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		// Everything after a "--" is positional
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// fail reports err and returns the exit status for it
+func (a *app) fail(err error) int {
+	var usage *usageError
+	var apiErr *client.Error
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &usage):
+		fmt.Fprintf(a.stderr, "quotesctl: %s\n", usage.msg)
+		return 2
+	case errors.As(err, &apiErr):
+		fmt.Fprintf(a.stderr, "quotesctl: %s (HTTP %d)\n", apiErr.Message, apiErr.StatusCode)
+		for _, detail := range apiErr.Details {
+			fmt.Fprintf(a.stderr, "  %s\n", detail.Error())
+		}
+		return 1
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(a.stderr, "quotesctl: timed out after %s\n", a.globals.timeout)
+		return 1
+	}
+	fmt.Fprintf(a.stderr, "quotesctl: %v\n", err)
+	return 1
+}
+
+func (a *app) usage(cmd *command, path []string, w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", strings.Join(path, " "))
+	for _, sub := range cmd.subcommands {
+		synopsis := sub.name
+		if sub.args != "" {
+			synopsis += " " + sub.args
+		}
+		fmt.Fprintf(w, "  %-34s %s\n", synopsis, sub.summary)
+	}
+	fmt.Fprintf(w, "\nRun `%s <command> -h` for the flags of a command.\n", strings.Join(path, " "))
+}
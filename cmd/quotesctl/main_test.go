@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+// testApp runs quotesctl with the given environment and a config file in a temporary
+// directory. Retries are off so that failures show at once.
+type testApp struct {
+	*app
+	stdin          *strings.Reader
+	stdout, stderr *bytes.Buffer
+	env            map[string]string
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	ta := &testApp{
+		stdin:  strings.NewReader(""),
+		stdout: &bytes.Buffer{},
+		stderr: &bytes.Buffer{},
+		env:    map[string]string{"QUOTESCTL_CONFIG": filepath.Join(t.TempDir(), "config.json")},
+	}
+	ta.app = newApp(ta.stdin, ta.stdout, ta.stderr, func(name string) string { return ta.env[name] })
+	ta.newClient = func(baseURL string, opts ...client.Option) (*client.Client, error) {
+		return client.New(baseURL, append(opts, client.WithRetryPolicy(client.RetryPolicy{}))...)
+	}
+	return ta
+}
+
+// exec runs args in a fresh app sharing the environment, as separate invocations would
+func (ta *testApp) exec(args ...string) int {
+	ta.stdout.Reset()
+	ta.stderr.Reset()
+	a := newApp(ta.stdin, ta.stdout, ta.stderr, ta.app.getenv)
+	a.newClient = ta.newClient
+	return a.run(context.Background(), args)
+}
+
+func TestRun_Usage(t *testing.T) {
+	ta := newTestApp(t)
+
+	if code := ta.exec(); code != 0 || !strings.Contains(ta.stdout.String(), "Commands:") {
+		t.Errorf("no args: exit %d, stdout %q", code, ta.stdout)
+	}
+	if code := ta.exec("admin", "help"); code != 0 || !strings.Contains(ta.stdout.String(), "webhooks") {
+		t.Errorf("admin help: exit %d, stdout %q", code, ta.stdout)
+	}
+	if code := ta.exec("list", "-h"); code != 0 || !strings.Contains(ta.stderr.String(), "-cursor") {
+		t.Errorf("list -h: exit %d, stderr %q", code, ta.stderr)
+	}
+	if code := ta.exec("frobnicate"); code != 2 {
+		t.Errorf("unknown command: exit %d, want 2", code)
+	}
+	if code := ta.exec("get", "abc"); code != 2 || !strings.Contains(ta.stderr.String(), `invalid ID "abc"`) {
+		t.Errorf("invalid ID: exit %d, stderr %q", code, ta.stderr)
+	}
+	if code := ta.exec("list", "--limit"); code != 2 {
+		t.Errorf("missing flag value: exit %d, want 2", code)
+	}
+	if code := ta.exec("list", "--created-after", "yesterday"); code != 2 || !strings.Contains(ta.stderr.String(), "expected a date") {
+		t.Errorf("invalid date: exit %d, stderr %q", code, ta.stderr)
+	}
+}
+
+func TestRun_Profiles(t *testing.T) {
+	ta := newTestApp(t)
+
+	if code := ta.exec("config", "set", "prod", "--url", "https://prod.example.com", "--api-key", "prod-secret-key"); code != 0 {
+		t.Fatalf("config set prod: exit %d, stderr %q", code, ta.stderr)
+	}
+	if code := ta.exec("config", "set", "dev", "--url", "http://dev.example.com", "-o", "json"); code != 0 {
+		t.Fatalf("config set dev: exit %d, stderr %q", code, ta.stderr)
+	}
+
+	info, err := os.Stat(ta.env["QUOTESCTL_CONFIG"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config file mode = %v, want 0600", perm)
+	}
+
+	// The first profile saved becomes the default
+	ta.exec("config", "list", "-o", "plain")
+	if got := ta.stdout.String(); got != "dev\nprod\n" {
+		t.Errorf("config list = %q", got)
+	}
+	s, err := ta.resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.profile != "prod" || s.url != "https://prod.example.com" || s.apiKey != "prod-secret-key" || s.output != outputTable {
+		t.Errorf("default settings = %+v", s)
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		ta.env["QUOTESCTL_PROFILE"] = "dev"
+		ta.env["QUOTESCTL_API_KEY"] = "env-key"
+		defer delete(ta.env, "QUOTESCTL_PROFILE")
+		defer delete(ta.env, "QUOTESCTL_API_KEY")
+
+		s, err := ta.resolve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.url != "http://dev.example.com" || s.apiKey != "env-key" || s.output != outputJSON {
+			t.Errorf("env settings = %+v", s)
+		}
+
+		ta.globals = globals{url: "http://flag.example.com", output: outputPlain}
+		defer func() { ta.globals = globals{} }()
+		s, err = ta.resolve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.url != "http://flag.example.com" || s.apiKey != "env-key" || s.output != outputPlain {
+			t.Errorf("flag settings = %+v", s)
+		}
+	})
+
+	t.Run("show masks the key", func(t *testing.T) {
+		ta.exec("config", "show", "-o", "json")
+		var shown map[string]string
+		if err := json.Unmarshal(ta.stdout.Bytes(), &shown); err != nil {
+			t.Fatalf("config show: %v, stdout %q", err, ta.stdout)
+		}
+		if shown["api_key"] != "********-key" || shown["url"] != "https://prod.example.com" {
+			t.Errorf("config show = %v", shown)
+		}
+	})
+
+	t.Run("use and delete", func(t *testing.T) {
+		if code := ta.exec("config", "use", "dev"); code != 0 {
+			t.Fatalf("config use: exit %d, stderr %q", code, ta.stderr)
+		}
+		if s, _ := ta.resolve(); s == nil || s.profile != "dev" {
+			t.Errorf("profile after use = %+v", s)
+		}
+		if code := ta.exec("config", "use", "staging"); code != 2 {
+			t.Errorf("use of an unknown profile: exit %d, want 2", code)
+		}
+		if code := ta.exec("config", "delete", "dev"); code != 0 {
+			t.Fatalf("config delete: exit %d, stderr %q", code, ta.stderr)
+		}
+		if s, _ := ta.resolve(); s == nil || s.profile != "" || s.url != defaultURL {
+			t.Errorf("settings after deleting the default profile = %+v", s)
+		}
+	})
+
+	if code := ta.exec("get", "1", "--profile", "missing"); code != 2 || !strings.Contains(ta.stderr.String(), `unknown profile "missing"`) {
+		t.Errorf("unknown profile: exit %d, stderr %q", code, ta.stderr)
+	}
+}
+
+// stubServer answers the few requests the commands below make
+func stubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	quotes := []*client.Quote{
+		{ID: 1, Author: "Jane Austen", Quote: "It is a truth universally acknowledged.", Tags: []string{"wit"}, Version: 1},
+		{ID: 2, Author: "Oscar Wilde", Quote: "Be yourself; everyone else is already taken.", Version: 1},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/quotes", func(w http.ResponseWriter, r *http.Request) {
+		page := quotes[:1]
+		if r.URL.Query().Get("cursor") == "1" {
+			page = quotes[1:]
+		} else {
+			w.Header().Set("Link", `</quotes?cursor=1&limit=1>; rel="next"`)
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+	mux.HandleFunc("GET /v1/quotes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Quote not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(quotes[0])
+	})
+	mux.HandleFunc("POST /v1/quotes", func(w http.ResponseWriter, r *http.Request) {
+		var req client.CreateQuoteRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(&client.Quote{ID: 3, Author: req.Author, Quote: req.Quote, Tags: req.Tags, Version: 1})
+	})
+	mux.HandleFunc("GET /v1/admin/keys", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin-key" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "admin role required"})
+			return
+		}
+		json.NewEncoder(w).Encode([]*client.Key{{ID: 1, Name: "ci", Role: client.RoleUser}})
+	})
+	mux.HandleFunc("POST /v1/admin/keys", func(w http.ResponseWriter, r *http.Request) {
+		var req client.KeyRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(&client.Key{ID: 2, Name: req.Name, Role: req.Role, Key: "qk_generated"})
+	})
+	mux.HandleFunc("DELETE /v1/admin/keys/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "API key not found"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRun_Quotes(t *testing.T) {
+	srv := stubServer(t)
+	ta := newTestApp(t)
+	ta.env["QUOTESCTL_URL"] = srv.URL
+
+	t.Run("list", func(t *testing.T) {
+		if code := ta.exec("list", "--limit", "1"); code != 0 {
+			t.Fatalf("exit %d, stderr %q", code, ta.stderr)
+		}
+		lines := strings.Split(strings.TrimSpace(ta.stdout.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "Jane Austen") {
+			t.Errorf("table = %q", ta.stdout)
+		}
+		if got := ta.stderr.String(); got != "more quotes: --cursor 1\n" {
+			t.Errorf("stderr = %q", got)
+		}
+
+		ta.exec("list", "-o", "json")
+		var page client.QuotePage
+		if err := json.Unmarshal(ta.stdout.Bytes(), &page); err != nil || page.NextCursor != "1" || len(page.Quotes) != 1 {
+			t.Errorf("json page = %+v (%v)", page, err)
+		}
+
+		ta.exec("list", "--all", "-o", "plain")
+		if got := strings.Count(ta.stdout.String(), "\n"); got != 2 {
+			t.Errorf("--all printed %d quotes, want 2: %q", got, ta.stdout)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		if code := ta.exec("get", "1", "-o", "plain"); code != 0 {
+			t.Fatalf("exit %d, stderr %q", code, ta.stderr)
+		}
+		if got := ta.stdout.String(); got != "It is a truth universally acknowledged.\n— Jane Austen\n" {
+			t.Errorf("stdout = %q", got)
+		}
+
+		if code := ta.exec("get", "9"); code != 1 {
+			t.Errorf("missing quote: exit %d, want 1", code)
+		}
+		if got := ta.stderr.String(); got != "quotesctl: Quote not found (HTTP 404)\n" {
+			t.Errorf("stderr = %q", got)
+		}
+	})
+
+	t.Run("add from stdin", func(t *testing.T) {
+		ta.stdin.Reset("  Brevity is the soul of wit.\n")
+		if code := ta.exec("add", "--author", "William Shakespeare", "--tag", "wit", "--tag", "brevity", "-o", "json"); code != 0 {
+			t.Fatalf("exit %d, stderr %q", code, ta.stderr)
+		}
+		var quote client.Quote
+		if err := json.Unmarshal(ta.stdout.Bytes(), &quote); err != nil {
+			t.Fatal(err)
+		}
+		if quote.Quote != "Brevity is the soul of wit." || strings.Join(quote.Tags, ",") != "wit,brevity" {
+			t.Errorf("created %+v", quote)
+		}
+	})
+
+	t.Run("keys check", func(t *testing.T) {
+		for key, role := range map[string]string{"admin-key": "admin", "user-key": "user"} {
+			if code := ta.exec("admin", "keys", "check", "--api-key", key, "-o", "json"); code != 0 {
+				t.Fatalf("%s: exit %d, stderr %q", key, code, ta.stderr)
+			}
+			var got map[string]string
+			json.Unmarshal(ta.stdout.Bytes(), &got)
+			if got["role"] != role {
+				t.Errorf("%s: role = %q, want %q", key, got["role"], role)
+			}
+		}
+	})
+}
+
+func TestRun_Keys(t *testing.T) {
+	srv := stubServer(t)
+	ta := newTestApp(t)
+	ta.env["QUOTESCTL_URL"] = srv.URL
+	ta.env["QUOTESCTL_API_KEY"] = "admin-key"
+
+	if code := ta.exec("admin", "keys", "create", "deploy", "--role", "admin", "-o", "json"); code != 0 {
+		t.Fatalf("create: exit %d, stderr %q", code, ta.stderr)
+	}
+	var created client.Key
+	if err := json.Unmarshal(ta.stdout.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "qk_generated" || created.Name != "deploy" || created.Role != client.RoleAdmin {
+		t.Errorf("created %+v", created)
+	}
+
+	if code := ta.exec("admin", "keys", "list", "-o", "plain"); code != 0 || ta.stdout.String() != "1\n" {
+		t.Errorf("list: exit %d, stdout %q", code, ta.stdout)
+	}
+	if code := ta.exec("admin", "keys", "revoke", "1"); code != 0 || ta.stderr.String() != "key 1 revoked\n" {
+		t.Errorf("revoke: exit %d, stderr %q", code, ta.stderr)
+	}
+	if code := ta.exec("admin", "keys", "revoke", "9"); code != 1 {
+		t.Errorf("revoke missing key: exit %d, want 1", code)
+	}
+	if code := ta.exec("admin", "keys", "create", "ci", "--role", "root"); code != 2 {
+		t.Errorf("unknown role: exit %d, want 2", code)
+	}
+}
+
+func TestRun_Completion(t *testing.T) {
+	ta := newTestApp(t)
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		if code := ta.exec("completion", shell); code != 0 {
+			t.Fatalf("%s: exit %d, stderr %q", shell, code, ta.stderr)
+		}
+		for _, want := range []string{"quotesctl/admin/webhooks/redeliver", "default-author", "config list -o plain"} {
+			if !strings.Contains(ta.stdout.String(), want) {
+				t.Errorf("%s script lacks %q", shell, want)
+			}
+		}
+	}
+	if code := ta.exec("completion", "powershell"); code != 2 {
+		t.Errorf("unsupported shell: exit %d, want 2", code)
+	}
+}
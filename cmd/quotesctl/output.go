@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shoksin/quotes-service/pkg/client"
+)
+
+// Output formats selected with -o
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputPlain = "plain"
+)
+
+var outputs = []string{outputTable, outputJSON, outputPlain}
+
+// view is a result in every output format: value is encoded as JSON, header and rows
+// make the table and plain has one line per item for scripts
+type view struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+	plain  []string
+}
+
+func (a *app) print(output string, v view) error {
+	switch output {
+	case outputJSON:
+		data, err := json.MarshalIndent(v.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "%s\n", data)
+		return err
+	case outputPlain:
+		for _, line := range v.plain {
+			if _, err := fmt.Fprintln(a.stdout, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	if len(v.header) > 0 {
+		fmt.Fprintln(tw, strings.Join(v.header, "\t"))
+	}
+	for _, row := range v.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// fieldsView shows a single item as name-value lines
+func fieldsView(fields [][2]string, value interface{}) view {
+	v := view{value: value}
+	for _, f := range fields {
+		v.rows = append(v.rows, []string{f[0] + ":", f[1]})
+		v.plain = append(v.plain, f[1])
+	}
+	return v
+}
+
+// maxTextWidth bounds the quote column of a table; the other formats show the whole text
+const maxTextWidth = 60
+
+func truncate(s string, width int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+func quotesView(quotes []*client.Quote, value interface{}) view {
+	v := view{value: value, header: []string{"ID", "AUTHOR", "QUOTE", "TAGS"}}
+	for _, q := range quotes {
+		v.rows = append(v.rows, []string{
+			strconv.Itoa(q.ID), q.Author, truncate(q.Quote, maxTextWidth), strings.Join(q.Tags, ","),
+		})
+		v.plain = append(v.plain, fmt.Sprintf("%s — %s", q.Quote, q.Author))
+	}
+	return v
+}
+
+func trashView(quotes []*client.Quote) view {
+	v := view{value: quotes, header: []string{"ID", "AUTHOR", "QUOTE", "DELETED"}}
+	for _, q := range quotes {
+		deleted := ""
+		if q.DeletedAt != nil {
+			deleted = formatTime(*q.DeletedAt)
+		}
+		v.rows = append(v.rows, []string{strconv.Itoa(q.ID), q.Author, truncate(q.Quote, maxTextWidth), deleted})
+		v.plain = append(v.plain, strconv.Itoa(q.ID))
+	}
+	return v
+}
+
+func quoteView(q *client.Quote) view {
+	v := fieldsView([][2]string{
+		{"id", strconv.Itoa(q.ID)},
+		{"author", q.Author},
+		{"quote", q.Quote},
+		{"tags", strings.Join(q.Tags, ", ")},
+		{"version", strconv.Itoa(q.Version)},
+		{"created", formatTime(q.CreatedAt)},
+		{"updated", formatTime(q.UpdatedAt)},
+	}, q)
+	// The plain form of a single quote is the one a fortune-style prompt would print
+	v.plain = []string{q.Quote, "— " + q.Author}
+	return v
+}
+
+func eventsString(events []client.EventType) string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+	return strings.Join(names, ",")
+}
+
+func keysView(keys []*client.Key) view {
+	v := view{value: keys, header: []string{"ID", "NAME", "ROLE", "CREATED"}}
+	for _, k := range keys {
+		v.rows = append(v.rows, []string{strconv.Itoa(k.ID), k.Name, string(k.Role), formatTime(k.CreatedAt)})
+		v.plain = append(v.plain, strconv.Itoa(k.ID))
+	}
+	return v
+}
+
+func keyView(k *client.Key) view {
+	fields := [][2]string{
+		{"id", strconv.Itoa(k.ID)},
+		{"name", k.Name},
+		{"role", string(k.Role)},
+	}
+	// The key is only returned when it is created
+	if k.Key != "" {
+		fields = append(fields, [2]string{"key", k.Key})
+	}
+	fields = append(fields, [2]string{"created", formatTime(k.CreatedAt)})
+	return fieldsView(fields, k)
+}
+
+func webhooksView(subs []*client.WebhookSubscription) view {
+	v := view{value: subs, header: []string{"ID", "URL", "EVENTS", "ACTIVE"}}
+	for _, s := range subs {
+		v.rows = append(v.rows, []string{strconv.Itoa(s.ID), s.URL, eventsString(s.Events), strconv.FormatBool(s.Active)})
+		v.plain = append(v.plain, strconv.Itoa(s.ID))
+	}
+	return v
+}
+
+func webhookView(s *client.WebhookSubscription) view {
+	fields := [][2]string{
+		{"id", strconv.Itoa(s.ID)},
+		{"url", s.URL},
+		{"events", eventsString(s.Events)},
+		{"active", strconv.FormatBool(s.Active)},
+	}
+	// The secret is only returned when a subscription is created
+	if s.Secret != "" {
+		fields = append(fields, [2]string{"secret", s.Secret})
+	}
+	fields = append(fields, [2]string{"created", formatTime(s.CreatedAt)}, [2]string{"updated", formatTime(s.UpdatedAt)})
+	return fieldsView(fields, s)
+}
+
+func deliveriesView(deliveries []*client.WebhookDelivery) view {
+	v := view{value: deliveries, header: []string{"ID", "EVENT", "STATUS", "ATTEMPTS", "LAST ERROR", "NEXT ATTEMPT"}}
+	for _, d := range deliveries {
+		lastError := d.LastError
+		if d.LastStatusCode != 0 {
+			lastError = strings.TrimSpace(fmt.Sprintf("HTTP %d %s", d.LastStatusCode, lastError))
+		}
+		next := ""
+		if d.Status == client.DeliveryPending {
+			next = formatTime(d.NextAttemptAt)
+		}
+		v.rows = append(v.rows, []string{
+			strconv.FormatInt(d.ID, 10), string(d.EventType), string(d.Status), strconv.Itoa(d.Attempts),
+			truncate(lastError, maxTextWidth), next,
+		})
+		v.plain = append(v.plain, strconv.FormatInt(d.ID, 10))
+	}
+	return v
+}
+
+func importView(report *client.ImportReport) view {
+	v := fieldsView([][2]string{
+		{"total", strconv.Itoa(report.Total)},
+		{"imported", strconv.Itoa(report.Imported)},
+		{"duplicates", strconv.Itoa(report.Duplicates)},
+		{"failed", strconv.Itoa(report.Failed)},
+	}, report)
+	v.plain = []string{fmt.Sprintf("%d imported, %d duplicates, %d failed of %d",
+		report.Imported, report.Duplicates, report.Failed, report.Total)}
+	for _, e := range report.Errors {
+		line := fmt.Sprintf("record %d: %s", e.Record, e.Error)
+		v.rows = append(v.rows, []string{"", line})
+		v.plain = append(v.plain, line)
+	}
+	return v
+}
@@ -3,16 +3,38 @@ package configs
 import (
 	"os"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Validation ValidationConfig
+	Batch      BatchConfig
+	Feed       FeedConfig
+	Card       CardConfig
+	Embed      EmbedConfig
+	Auth       AuthConfig
+	Trash      TrashConfig
+	Audit      AuditConfig
+	Webhook    WebhookConfig
+	Stream     StreamConfig
+	WebSocket  WebSocketConfig
+	GRPC       GRPCConfig
+	GraphQL    GraphQLConfig
+	OpenAPI    OpenAPIConfig
+	API        APIConfig
+	Cache      CacheConfig
+	Stats      StatsConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port         string
+	MaxBodyBytes int64
+	// PublicURL is the address clients reach the service at, used for absolute links
+	PublicURL string
 }
 type DatabaseConfig struct {
 	Host     string
@@ -22,6 +44,144 @@ type DatabaseConfig struct {
 	Password string
 }
 
+type BatchConfig struct {
+	MaxCreateItems     int
+	MaxDeleteItems     int
+	MaxBodyBytes       int64
+	MaxImportBodyBytes int64
+}
+
+type FeedConfig struct {
+	Title   string
+	Entries int
+}
+
+type CardConfig struct {
+	CacheBytes int64
+}
+
+type EmbedConfig struct {
+	AllowedOrigins []string
+	Width          int
+	Height         int
+}
+
+type AuthConfig struct {
+	// APIKeys are name:role:key entries
+	APIKeys []string
+}
+
+type TrashConfig struct {
+	// Retention is how long deleted quotes are kept before they are purged; 0 keeps them forever
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+type AuditConfig struct {
+	// ChainInterval is how often new audit events are added to the hash chain; 0 disables it
+	ChainInterval time.Duration
+}
+
+type WebhookConfig struct {
+	// PollInterval is how often the dispatcher looks for events to deliver; 0 disables it
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	BatchSize    int
+	// AllowPrivateNetworks lets deliveries reach loopback, link-local and private addresses
+	AllowPrivateNetworks bool
+	// OutboxRetention is how long delivered events are kept for replay; 0 keeps them forever
+	OutboxRetention     time.Duration
+	OutboxPruneInterval time.Duration
+}
+
+type StreamConfig struct {
+	// Heartbeat is how often idle event streams get a keep-alive comment
+	Heartbeat time.Duration
+	// Retry is the reconnection delay suggested to stream clients
+	Retry time.Duration
+	// PollInterval is how often the change log is read when no notification arrives; 0 relies on notifications alone
+	PollInterval time.Duration
+}
+
+type WebSocketConfig struct {
+	PingInterval time.Duration
+	// QueueSize bounds the messages waiting to be written to one connection
+	QueueSize int
+	// AckWindow is the number of pushed messages a client may leave unacknowledged
+	AckWindow int
+	// AllowedOrigins are host patterns of other sites whose pages may connect
+	AllowedOrigins []string
+}
+
+type GRPCConfig struct {
+	// Port is where the gRPC server listens unless it is multiplexed
+	Port string
+	// Multiplex serves gRPC on the HTTP port instead, over HTTP/2 without TLS
+	Multiplex bool
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+	// GraphiQL serves the GraphiQL IDE at /graphql, meant for development
+	GraphiQL bool
+}
+
+type OpenAPIConfig struct {
+	// ValidateRequests rejects requests that do not match the OpenAPI document
+	ValidateRequests bool
+	// ValidateResponses logs responses that do not match it, meant for testing and staging
+	ValidateResponses bool
+}
+
+type APIConfig struct {
+	// DefaultVersion serves the paths without a version prefix
+	DefaultVersion string
+	// V1Deprecation and V1Sunset announce the retirement of /v1; zero times are not announced
+	V1Deprecation time.Time
+	V1Sunset      time.Time
+	// V1DeprecationLink points at the guide to moving off /v1
+	V1DeprecationLink string
+}
+
+type CacheConfig struct {
+	// Backend is memory, redis or off
+	Backend string
+	// TTL bounds how stale a cached read may get when an invalidation is missed
+	TTL time.Duration
+	// Size is the number of entries of the memory backend
+	Size int
+	// RedisURL locates the redis backend, such as redis://:password@localhost:6379/0; rediss:// uses TLS
+	RedisURL string
+}
+
+type StatsConfig struct {
+	// Materialized serves GET /stats from views refreshed at start and every RefreshInterval;
+	// otherwise every cache miss aggregates the whole collection
+	Materialized    bool
+	RefreshInterval time.Duration
+	TopAuthors      int
+	// TopWords is the number of common words listed for each top author
+	TopWords int
+	// MaxAge is how long clients may reuse the statistics
+	MaxAge time.Duration
+}
+
+// maxAuthorColumnLength is the size of quotes.author, VARCHAR(255) in migrations/001_init.sql
+const maxAuthorColumnLength = 255
+
+type ValidationConfig struct {
+	// MaxAuthorLength is clamped to 1..maxAuthorColumnLength, so that longer authors are
+	// rejected by validation rather than by the database
+	MaxAuthorLength int
+	MaxQuoteLength  int
+	MaxTags         int
+	MaxTagLength    int
+}
+
 var (
 	once sync.Once
 	cfg  *Config
@@ -31,7 +191,10 @@ func Load() *Config {
 	once.Do(func() {
 		cfg = &Config{
 			Server: ServerConfig{
-				Port: getEnv("SERVER_PORT", "8080"),
+				Port:         getEnv("SERVER_PORT", "8080"),
+				MaxBodyBytes: int64(getEnvInt("SERVER_MAX_BODY_BYTES", 1<<20)),
+				// FEED_BASE_URL is the name the setting had while only the feeds used it
+				PublicURL: getEnv("PUBLIC_BASE_URL", getEnv("FEED_BASE_URL", "http://localhost:8080")),
 			},
 			Database: DatabaseConfig{
 				Host:     getEnv("DB_HOST", "localhost"),
@@ -40,6 +203,94 @@ func Load() *Config {
 				User:     getEnv("DB_USER", "quotes_user"),
 				Password: getEnv("DB_PASSWORD", "quotes_password"),
 			},
+			Validation: ValidationConfig{
+				MaxAuthorLength: max(1, min(getEnvInt("QUOTE_MAX_AUTHOR_LENGTH", 255), maxAuthorColumnLength)),
+				MaxQuoteLength:  getEnvInt("QUOTE_MAX_QUOTE_LENGTH", 2000),
+				MaxTags:         getEnvInt("QUOTE_MAX_TAGS", 10),
+				MaxTagLength:    getEnvInt("QUOTE_MAX_TAG_LENGTH", 50),
+			},
+			Batch: BatchConfig{
+				MaxCreateItems:     getEnvInt("BATCH_MAX_CREATE_ITEMS", 1000),
+				MaxDeleteItems:     getEnvInt("BATCH_MAX_DELETE_ITEMS", 1000),
+				MaxBodyBytes:       int64(getEnvInt("BATCH_MAX_BODY_BYTES", 10<<20)),
+				MaxImportBodyBytes: int64(getEnvInt("IMPORT_MAX_BODY_BYTES", 50<<20)),
+			},
+			Feed: FeedConfig{
+				Title:   getEnv("FEED_TITLE", "Quotes"),
+				Entries: getEnvInt("FEED_ENTRIES", 20),
+			},
+			Card: CardConfig{
+				CacheBytes: int64(getEnvInt("CARD_CACHE_BYTES", 64<<20)),
+			},
+			Embed: EmbedConfig{
+				AllowedOrigins: getEnvList("EMBED_ALLOWED_ORIGINS", []string{"*"}),
+				Width:          getEnvInt("EMBED_WIDTH", 500),
+				Height:         getEnvInt("EMBED_HEIGHT", 220),
+			},
+			Auth: AuthConfig{
+				APIKeys: getEnvList("API_KEYS", nil),
+			},
+			Trash: TrashConfig{
+				Retention:     getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+				PurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+			},
+			Audit: AuditConfig{
+				ChainInterval: getEnvDuration("AUDIT_CHAIN_INTERVAL", 5*time.Second),
+			},
+			Webhook: WebhookConfig{
+				PollInterval:         getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+				Timeout:              getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+				MaxAttempts:          getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+				BackoffBase:          getEnvDuration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+				BackoffMax:           getEnvDuration("WEBHOOK_BACKOFF_MAX", 6*time.Hour),
+				BatchSize:            getEnvInt("WEBHOOK_BATCH_SIZE", 20),
+				AllowPrivateNetworks: getEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+				OutboxRetention:      getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+				OutboxPruneInterval:  getEnvDuration("OUTBOX_PRUNE_INTERVAL", time.Hour),
+			},
+			Stream: StreamConfig{
+				Heartbeat:    getEnvDuration("STREAM_HEARTBEAT", 15*time.Second),
+				Retry:        getEnvDuration("STREAM_RETRY", 3*time.Second),
+				PollInterval: getEnvDuration("STREAM_POLL_INTERVAL", 30*time.Second),
+			},
+			WebSocket: WebSocketConfig{
+				PingInterval:   getEnvDuration("WS_PING_INTERVAL", 30*time.Second),
+				QueueSize:      getEnvInt("WS_QUEUE_SIZE", 64),
+				AckWindow:      getEnvInt("WS_ACK_WINDOW", 32),
+				AllowedOrigins: getEnvList("WS_ALLOWED_ORIGINS", nil),
+			},
+			GRPC: GRPCConfig{
+				Port:      getEnv("GRPC_PORT", "9090"),
+				Multiplex: getEnvBool("GRPC_MULTIPLEX", false),
+			},
+			GraphQL: GraphQLConfig{
+				MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+				MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 10000),
+				GraphiQL:      getEnvBool("GRAPHQL_GRAPHIQL", false),
+			},
+			OpenAPI: OpenAPIConfig{
+				ValidateRequests:  getEnvBool("OPENAPI_VALIDATE_REQUESTS", false),
+				ValidateResponses: getEnvBool("OPENAPI_VALIDATE_RESPONSES", false),
+			},
+			API: APIConfig{
+				DefaultVersion:    getEnv("API_DEFAULT_VERSION", "v1"),
+				V1Deprecation:     getEnvTime("API_V1_DEPRECATION", time.Time{}),
+				V1Sunset:          getEnvTime("API_V1_SUNSET", time.Time{}),
+				V1DeprecationLink: getEnv("API_V1_DEPRECATION_LINK", ""),
+			},
+			Cache: CacheConfig{
+				Backend:  getEnv("CACHE_BACKEND", "memory"),
+				TTL:      getEnvDuration("CACHE_TTL", time.Minute),
+				Size:     getEnvInt("CACHE_SIZE", 1000),
+				RedisURL: getEnv("REDIS_URL", "redis://localhost:6379/0"),
+			},
+			Stats: StatsConfig{
+				Materialized:    getEnvBool("STATS_MATERIALIZED", true),
+				RefreshInterval: getEnvDuration("STATS_REFRESH_INTERVAL", 15*time.Minute),
+				TopAuthors:      getEnvInt("STATS_TOP_AUTHORS", 10),
+				TopWords:        getEnvInt("STATS_TOP_WORDS", 10),
+				MaxAge:          getEnvDuration("STATS_MAX_AGE", time.Minute),
+			},
 		}
 	})
 	return cfg
@@ -59,3 +310,43 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvBool reads a boolean such as true, 1 or false
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration reads a duration such as 90m or 720h
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvTime reads a time such as 2026-07-01 or 2026-07-01T00:00:00Z
+func getEnvTime(key string, defaultValue time.Time) time.Time {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if value, err := time.Parse(layout, os.Getenv(key)); err == nil {
+			return value
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma-separated list, ignoring empty items
+func getEnvList(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
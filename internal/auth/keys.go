@@ -0,0 +1,71 @@
+// Package auth authenticates API keys
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// StaticKeys authenticates against a fixed set of API keys. Only digests of the keys are kept.
+type StaticKeys struct {
+	keys map[[sha256.Size]byte]*domain.Principal
+}
+
+// ParseKeys builds StaticKeys from "name:role:key" entries, where role is user or admin
+func ParseKeys(entries []string) (*StaticKeys, error) {
+	k := &StaticKeys{keys: make(map[[sha256.Size]byte]*domain.Principal, len(entries))}
+
+	for i, entry := range entries {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("API key %d: expected name:role:key", i+1)
+		}
+
+		role := domain.Role(parts[1])
+		if role != domain.RoleUser && role != domain.RoleAdmin {
+			return nil, fmt.Errorf("API key %q: unknown role %q", parts[0], parts[1])
+		}
+
+		digest := sha256.Sum256([]byte(parts[2]))
+		if _, ok := k.keys[digest]; ok {
+			return nil, fmt.Errorf("API key %q: the key is already used", parts[0])
+		}
+		k.keys[digest] = &domain.Principal{Name: parts[0], Role: role}
+	}
+
+	return k, nil
+}
+
+// Authenticate returns the caller the key belongs to, or domain.ErrInvalidAPIKey.
+// Keys are looked up by digest, so the time taken does not depend on how much of a key matches.
+func (k *StaticKeys) Authenticate(key string) (*domain.Principal, error) {
+	principal, ok := k.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+// Authenticator resolves an API key to the caller it belongs to
+type Authenticator interface {
+	Authenticate(key string) (*domain.Principal, error)
+}
+
+// Chain tries each authenticator in turn, so the keys of API_KEYS and the keys created
+// through the admin API are both accepted. The first authenticator knowing a key decides;
+// an error other than domain.ErrInvalidAPIKey stops the search.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(key string) (*domain.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(key)
+		if !errors.Is(err, domain.ErrInvalidAPIKey) {
+			return principal, err
+		}
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys([]string{"alice:admin:s3cret", "bob:user:pa:ss"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, err := keys.Authenticate("s3cret")
+	if err != nil || principal.Name != "alice" || !principal.IsAdmin() {
+		t.Errorf("expected alice as admin, got %+v, %v", principal, err)
+	}
+	principal, err = keys.Authenticate("pa:ss")
+	if err != nil || principal.Name != "bob" || principal.IsAdmin() {
+		t.Errorf("expected bob as user, got %+v, %v", principal, err)
+	}
+	if _, err = keys.Authenticate("s3cre"); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestParseKeys_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+	}{
+		{name: "missing key", entries: []string{"alice:admin"}},
+		{name: "empty key", entries: []string{"alice:admin:"}},
+		{name: "empty name", entries: []string{":admin:secret"}},
+		{name: "unknown role", entries: []string{"alice:root:secret"}},
+		{name: "duplicate key", entries: []string{"alice:admin:secret", "bob:user:secret"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseKeys(tt.entries); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// authFunc adapts a function to Authenticator
+type authFunc func(key string) (*domain.Principal, error)
+
+func (f authFunc) Authenticate(key string) (*domain.Principal, error) {
+	return f(key)
+}
+
+func TestChain(t *testing.T) {
+	static, err := ParseKeys([]string{"alice:admin:s3cret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dbErr := errors.New("database error")
+	stored := authFunc(func(key string) (*domain.Principal, error) {
+		switch key {
+		case "qk_stored":
+			return &domain.Principal{Name: "ci", Role: domain.RoleUser}, nil
+		case "qk_broken":
+			return nil, dbErr
+		}
+		return nil, domain.ErrInvalidAPIKey
+	})
+	chain := Chain{static, stored}
+
+	if principal, err := chain.Authenticate("s3cret"); err != nil || principal.Name != "alice" {
+		t.Errorf("expected alice, got %+v, %v", principal, err)
+	}
+	if principal, err := chain.Authenticate("qk_stored"); err != nil || principal.Name != "ci" {
+		t.Errorf("expected ci, got %+v, %v", principal, err)
+	}
+	if _, err := chain.Authenticate("qk_broken"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the store error, got %v", err)
+	}
+	if _, err := chain.Authenticate("unknown"); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
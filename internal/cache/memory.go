@@ -0,0 +1,94 @@
+// Package cache holds the stores behind the read cache of the quotes use case: an
+// in-process LRU with per-entry expiry and a Redis client shared by the replicas.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// Memory is a least-recently-used cache bounded by its number of entries, whose entries
+// also expire after the TTL they were stored with. It is safe for concurrent use.
+type Memory struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+// NewMemory returns a cache holding at most maxEntries values; a non-positive maxEntries
+// disables caching
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value stored for key unless it has expired
+func (c *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := el.Value.(*memoryEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+// Set stores value for ttl, evicting the least recently used entries if needed
+func (c *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxEntries <= 0 || ttl <= 0 {
+		return nil
+	}
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expires: c.now().Add(ttl)})
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Invalidate drops every entry
+func (c *Memory) Invalidate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+	return nil
+}
+
+// Len returns the number of stored entries, expired ones included until they are read or evicted
+func (c *Memory) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
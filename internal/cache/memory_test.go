@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory_LRU(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(2)
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	// Reading a makes b the least recently used entry
+	if v, ok, _ := c.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("expected a to be cached, got %q %v", v, ok)
+	}
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestMemory_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMemory(10)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "short", []byte("1"), time.Second)
+	c.Set(ctx, "long", []byte("2"), time.Minute)
+	c.Set(ctx, "none", []byte("3"), 0)
+
+	now = now.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "short"); ok {
+		t.Error("expected the entry to expire after its TTL")
+	}
+	if _, ok, _ := c.Get(ctx, "long"); !ok {
+		t.Error("expected the entry to outlive a shorter TTL")
+	}
+	if _, ok, _ := c.Get(ctx, "none"); ok {
+		t.Error("expected a zero TTL not to be stored")
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected the expired entry to be dropped, got %d entries", c.Len())
+	}
+}
+
+func TestMemory_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(10)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Invalidate(ctx)
+
+	if _, ok, _ := c.Get(ctx, "a"); ok || c.Len() != 0 {
+		t.Errorf("expected an empty cache, got %d entries", c.Len())
+	}
+
+	c.Set(ctx, "a", []byte("2"), time.Minute)
+	if v, ok, _ := c.Get(ctx, "a"); !ok || string(v) != "2" {
+		t.Errorf("expected the cache to work after invalidation, got %q %v", v, ok)
+	}
+}
+
+func TestMemory_Disabled(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(0)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("expected a cache of size 0 to store nothing")
+	}
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultRedisPrefix namespaces the keys of the service in a shared Redis
+	DefaultRedisPrefix = "quotes:cache:"
+	// DefaultRedisTimeout bounds a command whose context has no deadline
+	DefaultRedisTimeout = time.Second
+	// DefaultRedisPoolSize is the number of connections kept open at most
+	DefaultRedisPoolSize = 8
+)
+
+// Redis stores the cache in Redis, shared by every replica of the service. Every key
+// carries a generation number that Invalidate increments, so the entries stored before
+// become unreachable for all replicas at once and expire on their own.
+type Redis struct {
+	client    *redis.Client
+	prefix    string
+	timeout   time.Duration
+	pool      int
+	tlsConfig *tls.Config
+}
+
+// RedisOption configures optional Redis settings
+type RedisOption func(*Redis)
+
+// WithRedisPrefix overrides DefaultRedisPrefix
+func WithRedisPrefix(prefix string) RedisOption {
+	return func(c *Redis) {
+		c.prefix = prefix
+	}
+}
+
+// WithRedisTimeout overrides DefaultRedisTimeout
+func WithRedisTimeout(timeout time.Duration) RedisOption {
+	return func(c *Redis) {
+		c.timeout = timeout
+	}
+}
+
+// WithRedisPoolSize overrides DefaultRedisPoolSize
+func WithRedisPoolSize(size int) RedisOption {
+	return func(c *Redis) {
+		c.pool = size
+	}
+}
+
+// WithRedisTLSConfig sets the TLS settings of rediss:// URLs, such as the CA that signed
+// the certificate of the server. The server name defaults to the host of the URL.
+func WithRedisTLSConfig(config *tls.Config) RedisOption {
+	return func(c *Redis) {
+		c.tlsConfig = config
+	}
+}
+
+// NewRedis returns a cache in the Redis server at rawURL, such as
+// redis://:password@localhost:6379/0, or rediss:// for TLS. Connections are opened on
+// first use.
+func NewRedis(rawURL string, opts ...RedisOption) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if (u.Scheme != "redis" && u.Scheme != "rediss") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid Redis URL %q, expected redis[s]://[[user]:password@]host[:port][/db]", rawURL)
+	}
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	c := &Redis{prefix: DefaultRedisPrefix, timeout: DefaultRedisTimeout, pool: DefaultRedisPoolSize}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if options.TLSConfig != nil && c.tlsConfig != nil {
+		config := c.tlsConfig.Clone()
+		if config.ServerName == "" {
+			config.ServerName = options.TLSConfig.ServerName
+		}
+		options.TLSConfig = config
+	}
+	options.DialTimeout, options.ReadTimeout, options.WriteTimeout = c.timeout, c.timeout, c.timeout
+	options.ContextTimeoutEnabled = true
+	options.PoolSize = max(c.pool, 1)
+	// The cache is a shortcut: a failed command is a miss, not worth a retry
+	options.MaxRetries = -1
+	options.DisableIdentity = true
+	c.client = redis.NewClient(options)
+	return c, nil
+}
+
+// Get returns the value stored for key in the current generation
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	gen, err := c.generation(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	value, err := c.client.Get(ctx, c.prefix+gen+":"+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores value for ttl in the current generation
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	gen, err := c.generation(ctx)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.prefix+gen+":"+key, value, max(ttl, time.Millisecond)).Err()
+}
+
+// Invalidate starts a new generation, leaving the entries of the previous one to expire
+func (c *Redis) Invalidate(ctx context.Context) error {
+	return c.client.Incr(ctx, c.prefix+"generation").Err()
+}
+
+// generation returns the current generation, "0" before the first invalidation
+func (c *Redis) generation(ctx context.Context) (string, error) {
+	gen, err := c.client.Get(ctx, c.prefix+"generation").Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return gen, err
+}
+
+// Close closes the connections
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,100 @@
+package card
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Key identifies a rendered card. It includes the quote's update time,
+// so editing a quote makes its old cards unreachable until they are evicted.
+type Key struct {
+	QuoteID   int
+	UpdatedAt int64
+	Format    Format
+	Theme     string
+	Size      string
+}
+
+// KeyFor returns the cache key of a card
+func KeyFor(quote *domain.Quote, format Format, opts Options) Key {
+	return Key{
+		QuoteID:   quote.ID,
+		UpdatedAt: quote.UpdatedAt.UnixNano(),
+		Format:    format,
+		Theme:     opts.Theme.Name,
+		Size:      opts.Size.Name,
+	}
+}
+
+type cacheEntry struct {
+	key  Key
+	data []byte
+}
+
+// Cache is a least-recently-used cache of rendered cards bounded by total size in bytes.
+// It is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[Key]*list.Element
+}
+
+// NewCache returns a cache holding at most maxBytes of rendered cards;
+// a non-positive maxBytes disables caching
+func NewCache(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[Key]*list.Element),
+	}
+}
+
+// Get returns the cached card for key
+func (c *Cache) Get(key Key) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).data, true
+}
+
+// Add stores a rendered card, evicting the least recently used ones if needed.
+// Cards larger than the whole cache are not stored.
+func (c *Cache) Add(key Key, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if int64(len(data)) > c.maxBytes {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.size -= int64(len(el.Value.(*cacheEntry).data))
+		c.order.Remove(el)
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, data: data})
+	c.size += int64(len(data))
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.data))
+	}
+}
+
+// Len returns the number of cached cards
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,137 @@
+// Package card renders quotes as shareable image cards in SVG and PNG.
+// Both formats share one layout computed with the embedded Go Regular font,
+// so a card looks the same whichever format is requested.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown card format")
+	ErrUnknownTheme  = errors.New("unknown card theme")
+	ErrUnknownSize   = errors.New("unknown card size")
+)
+
+// Format is the image format of a card
+type Format string
+
+const (
+	FormatSVG Format = "svg"
+	FormatPNG Format = "png"
+)
+
+// ParseFormat returns the format for a name such as "svg" or "png"
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatSVG, FormatPNG:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w %q (available: svg, png)", ErrUnknownFormat, name)
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	if f == FormatPNG {
+		return "image/png"
+	}
+	return "image/svg+xml"
+}
+
+// Theme is the color scheme of a card
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Foreground color.RGBA
+	Accent     color.RGBA
+}
+
+// Size is the pixel size of a card
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Themes lists the available themes; the first one is the default
+var Themes = []Theme{
+	{Name: "light", Background: rgb(0xfa, 0xf7, 0xf2), Foreground: rgb(0x1f, 0x23, 0x28), Accent: rgb(0xb4, 0x54, 0x1f)},
+	{Name: "dark", Background: rgb(0x15, 0x17, 0x1c), Foreground: rgb(0xf2, 0xf2, 0xf2), Accent: rgb(0xe0, 0xa4, 0x58)},
+	{Name: "ocean", Background: rgb(0x0b, 0x3d, 0x5c), Foreground: rgb(0xf4, 0xf8, 0xfb), Accent: rgb(0x7f, 0xd1, 0xe8)},
+}
+
+// Sizes lists the available sizes; the first one is the default
+var Sizes = []Size{
+	{Name: "og", Width: 1200, Height: 630},
+	{Name: "square", Width: 1080, Height: 1080},
+	{Name: "story", Width: 1080, Height: 1920},
+}
+
+// Options selects the look of a card
+type Options struct {
+	Theme Theme
+	Size  Size
+}
+
+// ParseOptions resolves theme and size names; empty names select the defaults
+func ParseOptions(theme, size string) (Options, error) {
+	opts := Options{Theme: Themes[0], Size: Sizes[0]}
+
+	if theme != "" {
+		t, ok := find(Themes, func(t Theme) string { return t.Name }, theme)
+		if !ok {
+			return opts, fmt.Errorf("%w %q (available: %s)", ErrUnknownTheme, theme, names(Themes, func(t Theme) string { return t.Name }))
+		}
+		opts.Theme = t
+	}
+
+	if size != "" {
+		s, ok := find(Sizes, func(s Size) string { return s.Name }, size)
+		if !ok {
+			return opts, fmt.Errorf("%w %q (available: %s)", ErrUnknownSize, size, names(Sizes, func(s Size) string { return s.Name }))
+		}
+		opts.Size = s
+	}
+
+	return opts, nil
+}
+
+// Render writes the card of quote in the given format
+func Render(w io.Writer, format Format, quote *domain.Quote, opts Options) error {
+	l := newLayout(quote, opts.Size)
+	switch format {
+	case FormatSVG:
+		return writeSVG(w, l, quote, opts.Theme)
+	case FormatPNG:
+		return writePNG(w, l, opts.Theme)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+func rgb(r, g, b uint8) color.RGBA {
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func find[T any](items []T, name func(T) string, want string) (T, bool) {
+	for _, item := range items {
+		if strings.EqualFold(name(item), want) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func names[T any](items []T, name func(T) string) string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = name(item)
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,258 @@
+package card
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func testQuote() *domain.Quote {
+	return &domain.Quote{
+		ID:        7,
+		Author:    "Confucius",
+		Quote:     "Life is really simple, but we insist on making it complicated & <hard>.",
+		CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func longQuote() *domain.Quote {
+	q := testQuote()
+	q.Author = "Лев Николаевич Толстой"
+	q.Quote = strings.Repeat("Все счастливые семьи похожи друг на друга, каждая несчастливая семья несчастлива по-своему. ", 40)
+	return q
+}
+
+func render(t *testing.T, format Format, quote *domain.Quote, theme, size string) []byte {
+	t.Helper()
+	opts, err := ParseOptions(theme, size)
+	if err != nil {
+		t.Fatalf("ParseOptions() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err = Render(&buf, format, quote, opts); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func goldenPath(name string) string {
+	return filepath.Join("testdata", name)
+}
+
+func TestRender_SVG_Golden(t *testing.T) {
+	got := render(t, FormatSVG, testQuote(), "dark", "og")
+
+	if err := xml.Unmarshal(got, new(struct{})); err != nil {
+		t.Fatalf("SVG is not well-formed: %v", err)
+	}
+	if !bytes.Contains(got, []byte(fontSource)) {
+		t.Error("SVG must embed the font")
+	}
+
+	// The embedded font is large and covered above, keep it out of the golden file
+	got = bytes.ReplaceAll(got, []byte(fontSource), []byte("data:font/ttf;base64,…"))
+
+	path := goldenPath("dark-og.svg.golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("SVG does not match golden file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRender_PNG_Golden(t *testing.T) {
+	tests := []struct {
+		golden string
+		quote  *domain.Quote
+		theme  string
+		size   string
+	}{
+		{golden: "light-og.png", quote: testQuote(), theme: "light", size: "og"},
+		{golden: "ocean-square.png", quote: testQuote(), theme: "ocean", size: "square"},
+		{golden: "dark-og-long.png", quote: longQuote(), theme: "dark", size: "og"},
+		{golden: "light-story.png", quote: testQuote(), theme: "light", size: "story"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			data := render(t, FormatPNG, tt.quote, tt.theme, tt.size)
+			got, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("rendered PNG cannot be decoded: %v", err)
+			}
+
+			path := goldenPath(tt.golden)
+			if *update {
+				if err = os.WriteFile(path, data, 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			defer f.Close()
+			want, err := png.Decode(f)
+			if err != nil {
+				t.Fatalf("failed to decode golden file: %v", err)
+			}
+
+			// Compare pixels rather than bytes, PNG compression may differ between Go versions
+			if diff := diffPixels(got, want); diff != "" {
+				t.Errorf("PNG does not match golden image: %s", diff)
+			}
+		})
+	}
+}
+
+func diffPixels(got, want image.Image) string {
+	if got.Bounds() != want.Bounds() {
+		return fmt.Sprintf("bounds %v, want %v", got.Bounds(), want.Bounds())
+	}
+	differ := 0
+	b := got.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r1, g1, b1, a1 := got.At(x, y).RGBA()
+			r2, g2, b2, a2 := want.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				differ++
+			}
+		}
+	}
+	if differ > 0 {
+		return fmt.Sprintf("%d pixels differ", differ)
+	}
+	return ""
+}
+
+func TestRender_Sizes(t *testing.T) {
+	for _, size := range Sizes {
+		t.Run(size.Name, func(t *testing.T) {
+			img, err := png.Decode(bytes.NewReader(render(t, FormatPNG, testQuote(), "", size.Name)))
+			if err != nil {
+				t.Fatalf("rendered PNG cannot be decoded: %v", err)
+			}
+			if img.Bounds().Dx() != size.Width || img.Bounds().Dy() != size.Height {
+				t.Errorf("expected %dx%d, got %v", size.Width, size.Height, img.Bounds())
+			}
+		})
+	}
+}
+
+func TestLayout_AutoSizesAndFits(t *testing.T) {
+	size := Sizes[0]
+	short := newLayout(testQuote(), size)
+	long := newLayout(longQuote(), size)
+
+	if long.FontSize >= short.FontSize {
+		t.Errorf("expected long quotes to use a smaller font, got %d and %d", long.FontSize, short.FontSize)
+	}
+
+	for _, l := range []*layout{short, long} {
+		face := newFace(l.FontSize)
+		for _, ln := range l.Lines {
+			if ln.X+measure(face, ln.Text) > size.Width || ln.Y > l.Author.Y {
+				t.Errorf("line %q at (%d, %d) does not fit the card", ln.Text, ln.X, ln.Y)
+			}
+		}
+		face.Close()
+	}
+
+	last := long.Lines[len(long.Lines)-1].Text
+	if !strings.HasSuffix(last, "…") {
+		t.Errorf("expected overflowing text to end with an ellipsis, got %q", last)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	face := newFace(20)
+	defer face.Close()
+
+	lines := wrap(face, "first line\nsecond "+strings.Repeat("x", 200), 300)
+	if lines[0] != "first line" {
+		t.Errorf("expected explicit line break to be kept, got %q", lines)
+	}
+	for _, l := range lines {
+		if measure(face, l) > 300 {
+			t.Errorf("line %q is wider than 300px", l)
+		}
+	}
+	if got := strings.Join(lines[1:], ""); got != "second"+strings.Repeat("x", 200) {
+		t.Errorf("expected long word to be split without losing text, got %q", lines[1:])
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions("", "")
+	if err != nil || opts.Theme.Name != "light" || opts.Size.Name != "og" {
+		t.Errorf("expected defaults, got %+v, %v", opts, err)
+	}
+
+	opts, err = ParseOptions("Dark", "story")
+	if err != nil || opts.Theme.Name != "dark" || opts.Size.Height != 1920 {
+		t.Errorf("unexpected options %+v, %v", opts, err)
+	}
+
+	if _, err = ParseOptions("neon", ""); !errors.Is(err, ErrUnknownTheme) {
+		t.Errorf("expected ErrUnknownTheme, got %v", err)
+	}
+	if _, err = ParseOptions("", "a4"); !errors.Is(err, ErrUnknownSize) {
+		t.Errorf("expected ErrUnknownSize, got %v", err)
+	}
+	if _, err = ParseFormat("gif"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(10)
+	opts, _ := ParseOptions("", "")
+	quote := testQuote()
+	key := KeyFor(quote, FormatPNG, opts)
+
+	cache.Add(key, []byte("12345"))
+	if data, ok := cache.Get(key); !ok || string(data) != "12345" {
+		t.Fatalf("expected cached card, got %q, %v", data, ok)
+	}
+
+	quote.UpdatedAt = quote.UpdatedAt.Add(time.Second)
+	if _, ok := cache.Get(KeyFor(quote, FormatPNG, opts)); ok {
+		t.Error("expected an updated quote to miss the cache")
+	}
+
+	other := KeyFor(quote, FormatSVG, opts)
+	cache.Add(other, []byte("123456"))
+	if _, ok := cache.Get(key); ok {
+		t.Error("expected the least recently used card to be evicted")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("expected 1 cached card, got %d", cache.Len())
+	}
+
+	cache.Add(key, []byte("way too large for the cache"))
+	if _, ok := cache.Get(key); ok {
+		t.Error("expected oversized cards not to be cached")
+	}
+}
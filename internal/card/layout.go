@@ -0,0 +1,189 @@
+package card
+
+import (
+	"image"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+const (
+	// lineSpacing is the line height as a multiple of the font size
+	lineSpacing = 1.3
+	// fontStep is the decrement used while searching for the largest fitting font size
+	fontStep = 2
+)
+
+var regular = mustParseFont(goregular.TTF)
+
+func mustParseFont(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic("card: failed to parse embedded font: " + err.Error())
+	}
+	return f
+}
+
+func newFace(size int) font.Face {
+	face, err := opentype.NewFace(regular, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		panic("card: failed to create font face: " + err.Error())
+	}
+	return face
+}
+
+// line is a run of text positioned at its baseline origin
+type line struct {
+	Text string
+	X, Y int
+}
+
+// layout is the resolution-independent description of a card shared by all renderers
+type layout struct {
+	Width, Height int
+	FontSize      int
+	Lines         []line
+	AuthorSize    int
+	Author        line
+	AccentBar     image.Rectangle
+}
+
+// newLayout wraps the quote into the largest font size that still fits the card,
+// falling back to the smallest size with the overflowing text truncated.
+func newLayout(quote *domain.Quote, size Size) *layout {
+	short := min(size.Width, size.Height)
+	padding := short * 8 / 100
+	maxFont, minFont := short/8, short/30
+	authorSize := short / 22
+
+	l := &layout{
+		Width:      size.Width,
+		Height:     size.Height,
+		AuthorSize: authorSize,
+		AccentBar:  image.Rect(0, 0, max(8, size.Width/100), size.Height),
+	}
+
+	textWidth := size.Width - 2*padding
+	textHeight := size.Height - 2*padding - authorSize*22/10
+	text := "“" + strings.TrimSpace(quote.Quote) + "”"
+
+	var lines []string
+	fontSize := maxFont
+	for ; fontSize >= minFont; fontSize -= fontStep {
+		face := newFace(fontSize)
+		lines = wrap(face, text, textWidth)
+		face.Close()
+		if len(lines)*lineHeight(fontSize) <= textHeight {
+			break
+		}
+	}
+	if fontSize < minFont {
+		fontSize = minFont
+		face := newFace(fontSize)
+		lines = truncateLines(face, wrap(face, text, textWidth), max(1, textHeight/lineHeight(fontSize)), textWidth)
+		face.Close()
+	}
+	l.FontSize = fontSize
+
+	face := newFace(fontSize)
+	ascent := face.Metrics().Ascent.Round()
+	face.Close()
+
+	// Center the quote vertically in the space above the author
+	top := padding + (textHeight-len(lines)*lineHeight(fontSize))/2
+	for i, text := range lines {
+		l.Lines = append(l.Lines, line{Text: text, X: padding, Y: top + i*lineHeight(fontSize) + ascent})
+	}
+
+	authorFace := newFace(authorSize)
+	author := truncate(authorFace, "— "+strings.TrimSpace(quote.Author), textWidth)
+	authorFace.Close()
+	l.Author = line{Text: author, X: padding, Y: size.Height - padding}
+
+	return l
+}
+
+func lineHeight(fontSize int) int {
+	return int(math.Round(float64(fontSize) * lineSpacing))
+}
+
+func measure(face font.Face, s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+// wrap breaks text into lines no wider than width, keeping explicit line breaks
+// and splitting words that do not fit on a line of their own
+func wrap(face font.Face, text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if measure(face, candidate) <= width {
+				current = candidate
+				continue
+			}
+			if current != "" {
+				lines = append(lines, current)
+			}
+			for measure(face, word) > width {
+				head := fitRunes(face, word, width)
+				lines = append(lines, head)
+				word = word[len(head):]
+			}
+			current = word
+		}
+		if current != "" {
+			lines = append(lines, current)
+		}
+	}
+	return lines
+}
+
+// fitRunes returns the longest prefix of s, at least one rune long, no wider than width
+func fitRunes(face font.Face, s string, width int) string {
+	end := 0
+	for i, r := range s {
+		next := i + utf8.RuneLen(r)
+		if end > 0 && measure(face, s[:next]) > width {
+			break
+		}
+		end = next
+	}
+	return s[:end]
+}
+
+// truncate shortens s with an ellipsis until it fits width
+func truncate(face font.Face, s string, width int) string {
+	if measure(face, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && measure(face, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + "…"
+}
+
+func truncateLines(face font.Face, lines []string, maxLines, width int) []string {
+	if len(lines) <= maxLines {
+		return lines
+	}
+	lines = lines[:maxLines]
+	last := lines[maxLines-1]
+	if measure(face, last+"…") > width {
+		last = truncate(face, last, width)
+	} else {
+		last += "…"
+	}
+	lines[maxLines-1] = last
+	return lines
+}
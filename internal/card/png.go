@@ -0,0 +1,33 @@
+package card
+
+import (
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+func writePNG(w io.Writer, l *layout, theme Theme) error {
+	img := image.NewRGBA(image.Rect(0, 0, l.Width, l.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Point{}, draw.Src)
+	draw.Draw(img, l.AccentBar, image.NewUniform(theme.Accent), image.Point{}, draw.Src)
+
+	drawLines(img, l.FontSize, image.NewUniform(theme.Foreground), l.Lines...)
+	drawLines(img, l.AuthorSize, image.NewUniform(theme.Accent), l.Author)
+
+	return png.Encode(w, img)
+}
+
+func drawLines(dst draw.Image, size int, src image.Image, lines ...line) {
+	face := newFace(size)
+	defer face.Close()
+
+	d := &font.Drawer{Dst: dst, Src: src, Face: face}
+	for _, ln := range lines {
+		d.Dot = fixed.P(ln.X, ln.Y)
+		d.DrawString(ln.Text)
+	}
+}
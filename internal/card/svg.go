@@ -0,0 +1,54 @@
+package card
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// fontFamily is the name the embedded font is registered under in SVG documents
+const fontFamily = "Quote Card"
+
+// fontSource is the embedded font as a data: URI so SVG cards render without fetching anything
+var fontSource = "data:font/ttf;base64," + base64.StdEncoding.EncodeToString(goregular.TTF)
+
+func writeSVG(w io.Writer, l *layout, quote *domain.Quote, theme Theme) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		l.Width, l.Height, l.Width, l.Height, escape(quote.Quote+" — "+quote.Author))
+	fmt.Fprintf(bw, `<style>@font-face{font-family:"%s";src:url(%s) format("truetype");}text{font-family:"%s",sans-serif;}</style>`+"\n",
+		fontFamily, fontSource, fontFamily)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", l.Width, l.Height, hex(theme.Background))
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+		l.AccentBar.Min.X, l.AccentBar.Min.Y, l.AccentBar.Dx(), l.AccentBar.Dy(), hex(theme.Accent))
+
+	fmt.Fprintf(bw, `<text fill="%s" font-size="%d">`+"\n", hex(theme.Foreground), l.FontSize)
+	for _, ln := range l.Lines {
+		fmt.Fprintf(bw, `<tspan x="%d" y="%d">%s</tspan>`+"\n", ln.X, ln.Y, escape(ln.Text))
+	}
+	bw.WriteString("</text>\n")
+
+	fmt.Fprintf(bw, `<text fill="%s" font-size="%d" x="%d" y="%d">%s</text>`+"\n",
+		hex(theme.Accent), l.AuthorSize, l.Author.X, l.Author.Y, escape(l.Author.Text))
+	bw.WriteString("</svg>\n")
+
+	return bw.Flush()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
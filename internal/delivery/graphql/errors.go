@@ -0,0 +1,137 @@
+package graphqlserver
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Error codes reported in the extensions of GraphQL errors
+const (
+	CodeParseFailed      = "GRAPHQL_PARSE_FAILED"
+	CodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	CodeDepthLimit       = "QUERY_TOO_DEEP"
+	CodeComplexityLimit  = "QUERY_TOO_COMPLEX"
+	CodeBadRequest       = "BAD_REQUEST"
+	CodeBadUserInput     = "BAD_USER_INPUT"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeUnauthenticated  = "UNAUTHENTICATED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeInternalError    = "INTERNAL_SERVER_ERROR"
+)
+
+// Extension keys
+const (
+	codeExtension           = "code"
+	fieldErrorsExtension    = "fieldErrors"
+	currentVersionExtension = "currentVersion"
+)
+
+// requestError is an error rejecting the whole request before it is executed
+func requestError(code, message string) gqlerrors.FormattedError {
+	err := gqlerrors.NewFormattedError(message)
+	err.Extensions = map[string]interface{}{codeExtension: code}
+	return err
+}
+
+// formatErrors adds a code to every error of an executed request. Errors from the use cases
+// the caller cannot act on are replaced by a generic message, so storage details never
+// leave the server.
+func formatErrors(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, err := range errs {
+		cause := originalError(err)
+		if cause == nil {
+			// Raised by the executor itself, such as a null in a non-null field
+			continue
+		}
+
+		code, message, extensions := classify(cause)
+		if code == CodeInternalError {
+			log.Printf("GraphQL %v: %v", err.Path, cause)
+		}
+		if err.Extensions == nil {
+			err.Extensions = make(map[string]interface{})
+		}
+		for k, v := range extensions {
+			err.Extensions[k] = v
+		}
+		err.Extensions[codeExtension] = code
+		err.Message = message
+		errs[i] = err
+	}
+	return errs
+}
+
+// originalError digs the error returned by a resolver out of the wrappers the executor adds
+func originalError(err error) error {
+	for {
+		var next error
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			next = e.OriginalError()
+		case *gqlerrors.Error:
+			next = e.OriginalError
+		default:
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		err = next
+	}
+}
+
+// classify maps an error from the use cases to a code, a message and extra extensions
+func classify(err error) (string, string, map[string]interface{}) {
+	var verr *domain.ValidationError
+	if errors.As(err, &verr) {
+		fieldErrors := make([]map[string]interface{}, 0, len(verr.Errors))
+		for _, fe := range verr.Errors {
+			fieldErrors = append(fieldErrors, map[string]interface{}{
+				"field":   fieldName(fe.Field),
+				"code":    fe.Code,
+				"message": fe.Message,
+			})
+		}
+		return CodeBadUserInput, domain.MsgValidationFailed, map[string]interface{}{fieldErrorsExtension: fieldErrors}
+	}
+
+	var conflict *domain.VersionConflictError
+	if errors.As(err, &conflict) && conflict.Current != nil {
+		return CodeConflict, domain.MsgVersionConflict, map[string]interface{}{currentVersionExtension: conflict.Current.Version}
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrQuoteNotFound), errors.Is(err, domain.ErrAuthorNotFound):
+		return CodeNotFound, err.Error(), nil
+	case errors.Is(err, domain.ErrVersionConflict):
+		return CodeConflict, domain.MsgVersionConflict, nil
+	case errors.Is(err, domain.ErrInvalidID), errors.Is(err, domain.ErrInvalidVersion),
+		errors.Is(err, domain.ErrInvalidAuthor), errors.Is(err, domain.ErrInvalidQuote), errors.Is(err, domain.ErrInvalidTag),
+		errors.Is(err, domain.ErrInvalidBio), errors.Is(err, domain.ErrInvalidPageSize):
+		return CodeBadUserInput, err.Error(), nil
+	case errors.Is(err, domain.ErrInvalidPageToken):
+		return CodeBadUserInput, domain.MsgInvalidPageToken, nil
+	case errors.Is(err, domain.ErrInvalidQuery):
+		return CodeBadUserInput, domain.MsgInvalidSearchQuery, nil
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return CodeUnauthenticated, domain.MsgInvalidAPIKey, nil
+	case errors.Is(err, domain.ErrForbidden):
+		return CodeForbidden, domain.MsgAdminRequired, nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeInternalError, err.Error(), nil
+	}
+	return CodeInternalError, domain.MsgInternalError, nil
+}
+
+// fieldName translates a JSON field name of the HTTP API to its GraphQL name
+func fieldName(field string) string {
+	if field == "quote" {
+		return "text"
+	}
+	return field
+}
@@ -0,0 +1,223 @@
+// Package graphqlserver serves the quotes, their authors and tags over GraphQL at /graphql
+package graphqlserver
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+//go:embed graphiql.html
+var graphiQLPage []byte
+
+// QuoteUseCase is the part of the quote use cases the GraphQL API is built on
+type QuoteUseCase interface {
+	CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error)
+	UpdateQuote(ctx context.Context, id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error)
+	DeleteQuote(ctx context.Context, id, version int) error
+	GetRandomQuote() (*domain.Quote, error)
+	SearchQuotes(query string, limit int) ([]*domain.Quote, error)
+	ListQuotesPages(reqs []domain.PageRequest) ([]*domain.QuotePage, error)
+	GetQuotesByIDs(ids []int) ([]*domain.Quote, error)
+	GetRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error)
+	GetAuthors(names []string) ([]*domain.Author, error)
+	ListAuthors(after string, limit int) (*domain.AuthorPage, error)
+	SetAuthorBio(ctx context.Context, name, bio string) (*domain.Author, error)
+	GetTags(names []string) ([]*domain.Tag, error)
+	ListTags(after string, limit int) (*domain.TagPage, error)
+}
+
+// Config tunes the GraphQL endpoint
+type Config struct {
+	// MaxDepth bounds how deeply fields may be nested; a negative value disables the check
+	MaxDepth int
+	// MaxComplexity bounds the estimated number of fields a query resolves; a negative value
+	// disables the check
+	MaxComplexity int
+	// MaxBodyBytes caps request bodies
+	MaxBodyBytes int64
+	// GraphiQL serves the GraphiQL IDE to browsers opening the endpoint
+	GraphiQL bool
+}
+
+// DefaultConfig holds the values NewHandler uses for zero fields
+var DefaultConfig = Config{
+	MaxDepth:      10,
+	MaxComplexity: 10000,
+	MaxBodyBytes:  1 << 20,
+}
+
+// Handler serves GraphQL requests over GET and POST
+type Handler struct {
+	uc     QuoteUseCase
+	schema graphql.Schema
+	cfg    Config
+}
+
+// request is a GraphQL request as sent in a POST body or GET query parameters
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type response struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// NewHandler builds the schema and returns the handler for /graphql
+func NewHandler(uc QuoteUseCase, cfg Config) *Handler {
+	if cfg.MaxDepth == 0 {
+		cfg.MaxDepth = DefaultConfig.MaxDepth
+	}
+	if cfg.MaxComplexity == 0 {
+		cfg.MaxComplexity = DefaultConfig.MaxComplexity
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultConfig.MaxBodyBytes
+	}
+
+	schema, err := newSchema(uc)
+	if err != nil {
+		// The schema is static, so this only fails on a programming error
+		panic("graphql: invalid schema: " + err.Error())
+	}
+	return &Handler{uc: uc, schema: schema, cfg: cfg}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if h.cfg.GraphiQL && query.Get("query") == "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(graphiQLPage)
+			return
+		}
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				h.writeErrors(w, http.StatusBadRequest, requestError(CodeBadRequest, "variables must be a JSON object"))
+				return
+			}
+		}
+	case http.MethodPost:
+		if status, err := h.decode(w, r, &req); err != nil {
+			h.writeErrors(w, status, requestError(CodeBadRequest, err.Error()))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		h.writeErrors(w, http.StatusMethodNotAllowed, requestError(CodeBadRequest, "Method not allowed"))
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		h.writeErrors(w, http.StatusBadRequest, requestError(CodeBadRequest, "query is required"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		formatted := gqlerrors.FormatError(err)
+		formatted.Extensions = map[string]interface{}{codeExtension: CodeParseFailed}
+		h.writeErrors(w, http.StatusBadRequest, formatted)
+		return
+	}
+
+	if result := graphql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		for i := range result.Errors {
+			result.Errors[i].Extensions = map[string]interface{}{codeExtension: CodeValidationFailed}
+		}
+		h.writeErrors(w, http.StatusBadRequest, result.Errors...)
+		return
+	}
+
+	// Mutations change state, so they are not accepted over GET where they could be
+	// triggered by a link or cached on the way
+	if op := operation(doc, req.OperationName); r.Method == http.MethodGet && op != nil && op.Operation == ast.OperationTypeMutation {
+		w.Header().Set("Allow", "POST")
+		h.writeErrors(w, http.StatusMethodNotAllowed, requestError(CodeBadRequest, "mutations must be sent with POST"))
+		return
+	}
+
+	if err := checkLimits(&h.schema, doc, req.OperationName, req.Variables, h.cfg); err != nil {
+		h.writeErrors(w, http.StatusBadRequest, *err)
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), newLoaders(h.uc)),
+	})
+	h.writeJSON(w, http.StatusOK, &response{Data: result.Data, Errors: formatErrors(result.Errors)})
+}
+
+// decode reads a JSON request body, returning the status to reply with when it cannot
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, req *request) (int, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return http.StatusUnsupportedMediaType, errors.New("Content-Type header must be application/json")
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.cfg.MaxBodyBytes)
+	if err = json.NewDecoder(r.Body).Decode(req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return http.StatusRequestEntityTooLarge, errors.New("request body too large")
+		}
+		return http.StatusBadRequest, errors.New(domain.MsgInvalidJSON)
+	}
+	return 0, nil
+}
+
+// operation returns the operation of doc that a request names, or its only operation.
+// It returns nil when there is no such operation, which the executor reports.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+func (h *Handler) writeErrors(w http.ResponseWriter, status int, errs ...gqlerrors.FormattedError) {
+	h.writeJSON(w, status, &response{Errors: errs})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, resp *response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,617 @@
+package graphqlserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+type MockQuoteUseCase struct {
+	CreateQuoteFunc          func(req *domain.CreateQuoteRequest) (*domain.Quote, error)
+	UpdateQuoteFunc          func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error)
+	DeleteQuoteFunc          func(id, version int) error
+	GetRandomQuoteFunc       func() (*domain.Quote, error)
+	SearchQuotesFunc         func(query string, limit int) ([]*domain.Quote, error)
+	ListQuotesPagesFunc      func(reqs []domain.PageRequest) ([]*domain.QuotePage, error)
+	GetQuotesByIDsFunc       func(ids []int) ([]*domain.Quote, error)
+	GetRevisionsByQuotesFunc func(quoteIDs []int) ([]*domain.Revision, error)
+	GetAuthorsFunc           func(names []string) ([]*domain.Author, error)
+	ListAuthorsFunc          func(after string, limit int) (*domain.AuthorPage, error)
+	SetAuthorBioFunc         func(name, bio string) (*domain.Author, error)
+	GetTagsFunc              func(names []string) ([]*domain.Tag, error)
+	ListTagsFunc             func(after string, limit int) (*domain.TagPage, error)
+
+	// calls counts the calls of each method
+	calls map[string]int
+}
+
+func (m *MockQuoteUseCase) called(method string) {
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[method]++
+}
+
+func (m *MockQuoteUseCase) CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+	m.called("CreateQuote")
+	if m.CreateQuoteFunc != nil {
+		return m.CreateQuoteFunc(req)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockQuoteUseCase) UpdateQuote(ctx context.Context, id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+	m.called("UpdateQuote")
+	if m.UpdateQuoteFunc != nil {
+		return m.UpdateQuoteFunc(id, req)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockQuoteUseCase) DeleteQuote(ctx context.Context, id, version int) error {
+	m.called("DeleteQuote")
+	if m.DeleteQuoteFunc != nil {
+		return m.DeleteQuoteFunc(id, version)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *MockQuoteUseCase) GetRandomQuote() (*domain.Quote, error) {
+	m.called("GetRandomQuote")
+	if m.GetRandomQuoteFunc != nil {
+		return m.GetRandomQuoteFunc()
+	}
+	return nil, domain.ErrNoQuotesFound
+}
+
+func (m *MockQuoteUseCase) SearchQuotes(query string, limit int) ([]*domain.Quote, error) {
+	m.called("SearchQuotes")
+	if m.SearchQuotesFunc != nil {
+		return m.SearchQuotesFunc(query, limit)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteUseCase) ListQuotesPages(reqs []domain.PageRequest) ([]*domain.QuotePage, error) {
+	m.called("ListQuotesPages")
+	if m.ListQuotesPagesFunc != nil {
+		return m.ListQuotesPagesFunc(reqs)
+	}
+	pages := make([]*domain.QuotePage, len(reqs))
+	for i := range pages {
+		pages[i] = &domain.QuotePage{}
+	}
+	return pages, nil
+}
+
+func (m *MockQuoteUseCase) GetQuotesByIDs(ids []int) ([]*domain.Quote, error) {
+	m.called("GetQuotesByIDs")
+	if m.GetQuotesByIDsFunc != nil {
+		return m.GetQuotesByIDsFunc(ids)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteUseCase) GetRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error) {
+	m.called("GetRevisionsByQuotes")
+	if m.GetRevisionsByQuotesFunc != nil {
+		return m.GetRevisionsByQuotesFunc(quoteIDs)
+	}
+	return []*domain.Revision{}, nil
+}
+
+func (m *MockQuoteUseCase) GetAuthors(names []string) ([]*domain.Author, error) {
+	m.called("GetAuthors")
+	if m.GetAuthorsFunc != nil {
+		return m.GetAuthorsFunc(names)
+	}
+	return []*domain.Author{}, nil
+}
+
+func (m *MockQuoteUseCase) ListAuthors(after string, limit int) (*domain.AuthorPage, error) {
+	m.called("ListAuthors")
+	if m.ListAuthorsFunc != nil {
+		return m.ListAuthorsFunc(after, limit)
+	}
+	return &domain.AuthorPage{}, nil
+}
+
+func (m *MockQuoteUseCase) SetAuthorBio(ctx context.Context, name, bio string) (*domain.Author, error) {
+	m.called("SetAuthorBio")
+	if m.SetAuthorBioFunc != nil {
+		return m.SetAuthorBioFunc(name, bio)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockQuoteUseCase) GetTags(names []string) ([]*domain.Tag, error) {
+	m.called("GetTags")
+	if m.GetTagsFunc != nil {
+		return m.GetTagsFunc(names)
+	}
+	return []*domain.Tag{}, nil
+}
+
+func (m *MockQuoteUseCase) ListTags(after string, limit int) (*domain.TagPage, error) {
+	m.called("ListTags")
+	if m.ListTagsFunc != nil {
+		return m.ListTagsFunc(after, limit)
+	}
+	return &domain.TagPage{}, nil
+}
+
+// gqlResponse is a decoded GraphQL response
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (r *gqlResponse) code(i int) string {
+	if i >= len(r.Errors) {
+		return ""
+	}
+	code, _ := r.Errors[i].Extensions["code"].(string)
+	return code
+}
+
+// post sends a GraphQL request to h and decodes the response
+func post(t *testing.T, h http.Handler, query string, variables map[string]interface{}) (int, *gqlResponse) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	return do(t, h, req)
+}
+
+func do(t *testing.T, h http.Handler, req *http.Request) (int, *gqlResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	resp := &gqlResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func decodeData(t *testing.T, resp *gqlResponse, dst interface{}) {
+	t.Helper()
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+	if err := json.Unmarshal(resp.Data, dst); err != nil {
+		t.Fatalf("invalid data %s: %v", resp.Data, err)
+	}
+}
+
+var (
+	seneca    = &domain.Quote{ID: 1, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity.", Tags: []string{"luck", "stoic"}, Version: 2, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), UpdatedAt: time.Date(2024, 2, 2, 3, 4, 5, 0, time.UTC)}
+	marcus    = &domain.Quote{ID: 2, Author: "Marcus Aurelius", Quote: "The happiness of your life depends upon the quality of your thoughts.", Tags: []string{"stoic"}, Version: 1}
+	confucius = &domain.Quote{ID: 3, Author: "Confucius", Quote: "It does not matter how slowly you go as long as you do not stop."}
+)
+
+func TestHandler_QuotesBatchesNestedFields(t *testing.T) {
+	var authorNames, tagNames []string
+	var revisionIDs []int
+	mock := &MockQuoteUseCase{
+		ListQuotesPagesFunc: func(reqs []domain.PageRequest) ([]*domain.QuotePage, error) {
+			if len(reqs) != 1 || reqs[0] != (domain.PageRequest{Limit: 3}) {
+				t.Errorf("unexpected page requests %+v", reqs)
+			}
+			return []*domain.QuotePage{{Quotes: []*domain.Quote{seneca, marcus, confucius}, NextAfterID: 3}}, nil
+		},
+		GetAuthorsFunc: func(names []string) ([]*domain.Author, error) {
+			authorNames = names
+			return []*domain.Author{{Name: "Seneca", Bio: "Roman Stoic philosopher", QuoteCount: 12}}, nil
+		},
+		GetTagsFunc: func(names []string) ([]*domain.Tag, error) {
+			tagNames = names
+			return []*domain.Tag{{Name: "luck", QuoteCount: 4}, {Name: "stoic", QuoteCount: 9}}, nil
+		},
+		GetRevisionsByQuotesFunc: func(quoteIDs []int) ([]*domain.Revision, error) {
+			revisionIDs = quoteIDs
+			return []*domain.Revision{
+				{QuoteID: 1, Revision: 2, Change: domain.ChangeUpdate, Actor: "admin", Snapshot: seneca},
+				{QuoteID: 1, Revision: 1, Change: domain.ChangeCreate, Actor: "admin", Snapshot: &domain.Quote{Author: "Seneca", Quote: "Luck"}},
+			}, nil
+		},
+	}
+
+	status, resp := post(t, NewHandler(mock, DefaultConfig), `{
+		quotes(first: 3) {
+			edges { cursor node { id } }
+			nodes {
+				id text version createdAt
+				author { name bio quoteCount }
+				tags { name quoteCount }
+				revisions { revision change text }
+			}
+			pageInfo { hasNextPage hasPreviousPage endCursor }
+		}
+	}`, nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var data struct {
+		Quotes struct {
+			Edges []struct {
+				Cursor string
+				Node   struct{ ID string }
+			}
+			Nodes []struct {
+				ID        string
+				Text      string
+				Version   int
+				CreatedAt string
+				Author    struct {
+					Name       string
+					Bio        *string
+					QuoteCount int
+				}
+				Tags []struct {
+					Name       string
+					QuoteCount int
+				}
+				Revisions []struct {
+					Revision int
+					Change   string
+					Text     string
+				}
+			}
+			PageInfo struct {
+				HasNextPage     bool
+				HasPreviousPage bool
+				EndCursor       string
+			}
+		}
+	}
+	decodeData(t, resp, &data)
+
+	quotes := data.Quotes
+	if len(quotes.Nodes) != 3 || quotes.Nodes[0].ID != "1" || quotes.Nodes[0].CreatedAt != "2024-01-02T03:04:05Z" {
+		t.Fatalf("unexpected quotes %+v", quotes.Nodes)
+	}
+	if first := quotes.Nodes[0]; first.Author.Bio == nil || *first.Author.Bio != "Roman Stoic philosopher" || first.Author.QuoteCount != 12 {
+		t.Errorf("expected the bio of Seneca, got %+v", first.Author)
+	}
+	if third := quotes.Nodes[2]; third.Author.Name != "Confucius" || third.Author.Bio != nil || len(third.Tags) != 0 {
+		t.Errorf("expected an author without a bio and no tags, got %+v", third)
+	}
+	if tags := quotes.Nodes[0].Tags; len(tags) != 2 || tags[1].Name != "stoic" || tags[1].QuoteCount != 9 {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+	if revisions := quotes.Nodes[0].Revisions; len(revisions) != 2 || revisions[1].Change != "create" || revisions[1].Text != "Luck" {
+		t.Errorf("unexpected revisions %+v", revisions)
+	}
+	if len(quotes.Nodes[1].Revisions) != 0 {
+		t.Errorf("expected no revisions for quote 2, got %+v", quotes.Nodes[1].Revisions)
+	}
+	if !quotes.PageInfo.HasNextPage || quotes.PageInfo.HasPreviousPage || quotes.PageInfo.EndCursor != quotes.Edges[2].Cursor {
+		t.Errorf("unexpected page info %+v", quotes.PageInfo)
+	}
+
+	for _, method := range []string{"ListQuotesPages", "GetAuthors", "GetTags", "GetRevisionsByQuotes"} {
+		if mock.calls[method] != 1 {
+			t.Errorf("expected one call of %s, got %d", method, mock.calls[method])
+		}
+	}
+	slices.Sort(authorNames)
+	slices.Sort(tagNames)
+	slices.Sort(revisionIDs)
+	if strings.Join(authorNames, ",") != "Confucius,Marcus Aurelius,Seneca" || strings.Join(tagNames, ",") != "luck,stoic" || len(revisionIDs) != 3 {
+		t.Errorf("expected each key once, got %v %v %v", authorNames, tagNames, revisionIDs)
+	}
+}
+
+func TestHandler_AuthorsBatchPagesOfQuotes(t *testing.T) {
+	mock := &MockQuoteUseCase{
+		ListAuthorsFunc: func(after string, limit int) (*domain.AuthorPage, error) {
+			if after != "Aristotle" || limit != 2 {
+				t.Errorf("expected authors after Aristotle, got %q %d", after, limit)
+			}
+			return &domain.AuthorPage{Authors: []*domain.Author{{Name: "Marcus Aurelius", QuoteCount: 1}, {Name: "Seneca", QuoteCount: 1}}}, nil
+		},
+		ListQuotesPagesFunc: func(reqs []domain.PageRequest) ([]*domain.QuotePage, error) {
+			pages := make([]*domain.QuotePage, len(reqs))
+			for i, req := range reqs {
+				if req.Limit != 5 {
+					t.Errorf("expected pages of 5, got %+v", req)
+				}
+				pages[i] = &domain.QuotePage{Quotes: []*domain.Quote{map[string]*domain.Quote{"Seneca": seneca, "Marcus Aurelius": marcus}[req.Author]}}
+			}
+			return pages, nil
+		},
+	}
+
+	_, resp := post(t, NewHandler(mock, DefaultConfig), `query ($after: String) {
+		authors(first: 2, after: $after) {
+			nodes { name quotes(first: 5) { nodes { id } } }
+			pageInfo { hasNextPage hasPreviousPage }
+		}
+	}`, map[string]interface{}{"after": encodeCursor(authorCursor, "Aristotle")})
+
+	var data struct {
+		Authors struct {
+			Nodes []struct {
+				Name   string
+				Quotes struct{ Nodes []struct{ ID string } }
+			}
+			PageInfo struct{ HasNextPage, HasPreviousPage bool }
+		}
+	}
+	decodeData(t, resp, &data)
+
+	authors := data.Authors
+	if len(authors.Nodes) != 2 || authors.Nodes[1].Quotes.Nodes[0].ID != "1" || authors.Nodes[0].Quotes.Nodes[0].ID != "2" {
+		t.Errorf("unexpected authors %+v", authors.Nodes)
+	}
+	if authors.PageInfo.HasNextPage || !authors.PageInfo.HasPreviousPage {
+		t.Errorf("unexpected page info %+v", authors.PageInfo)
+	}
+	if mock.calls["ListQuotesPages"] != 1 {
+		t.Errorf("expected the quotes of both authors in one call, got %d calls", mock.calls["ListQuotesPages"])
+	}
+}
+
+func TestHandler_QueryFields(t *testing.T) {
+	mock := &MockQuoteUseCase{
+		GetQuotesByIDsFunc: func(ids []int) ([]*domain.Quote, error) {
+			if len(ids) != 2 {
+				t.Errorf("expected both aliases in one call, got %v", ids)
+			}
+			return []*domain.Quote{seneca}, nil
+		},
+		SearchQuotesFunc: func(query string, limit int) ([]*domain.Quote, error) {
+			if query != "luck" || limit != domain.DefaultSearchLimit {
+				t.Errorf("unexpected search %q %d", query, limit)
+			}
+			return []*domain.Quote{seneca}, nil
+		},
+		GetTagsFunc: func(names []string) ([]*domain.Tag, error) {
+			return []*domain.Tag{{Name: "stoic", QuoteCount: 9}}, nil
+		},
+	}
+
+	_, resp := post(t, NewHandler(mock, DefaultConfig), `{
+		found: quote(id: 1) { text }
+		missing: quote(id: 42) { text }
+		randomQuote { id }
+		search(query: "luck") { id }
+		tag(name: " Stoic ") { name quoteCount }
+		author(name: "Nobody") { name }
+	}`, nil)
+
+	var data struct {
+		Found       *struct{ Text string }
+		Missing     *struct{ Text string }
+		RandomQuote *struct{ ID string }
+		Search      []struct{ ID string }
+		Tag         *struct {
+			Name       string
+			QuoteCount int
+		}
+		Author *struct{ Name string }
+	}
+	decodeData(t, resp, &data)
+
+	if data.Found == nil || data.Found.Text != seneca.Quote || data.Missing != nil {
+		t.Errorf("expected only quote 1 to be found, got %+v %+v", data.Found, data.Missing)
+	}
+	if data.RandomQuote != nil {
+		t.Errorf("expected no random quote without quotes, got %+v", data.RandomQuote)
+	}
+	if len(data.Search) != 1 || data.Tag == nil || data.Tag.QuoteCount != 9 || data.Author != nil {
+		t.Errorf("unexpected result %+v", data)
+	}
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	mock := &MockQuoteUseCase{
+		CreateQuoteFunc: func(req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+			if err := req.Validate(); err != nil {
+				return nil, err
+			}
+			return &domain.Quote{ID: 7, Author: req.Author, Quote: req.Quote, Tags: req.Tags, Version: 1}, nil
+		},
+		UpdateQuoteFunc: func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+			return nil, &domain.VersionConflictError{Current: &domain.Quote{ID: id, Version: req.Version + 1}}
+		},
+		DeleteQuoteFunc: func(id, version int) error {
+			if id != 7 || version != 1 {
+				t.Errorf("unexpected delete of %d at %d", id, version)
+			}
+			return nil
+		},
+		SetAuthorBioFunc: func(name, bio string) (*domain.Author, error) {
+			return nil, domain.ErrAuthorNotFound
+		},
+	}
+	h := NewHandler(mock, DefaultConfig)
+
+	_, resp := post(t, h, `mutation ($input: CreateQuoteInput!) {
+		createQuote(input: $input) { id text tags { name } author { name } }
+	}`, map[string]interface{}{"input": map[string]interface{}{"author": "Seneca", "text": "Luck", "tags": []string{"luck"}}})
+	var created struct {
+		CreateQuote struct {
+			ID   string
+			Text string
+			Tags []struct{ Name string }
+		}
+	}
+	decodeData(t, resp, &created)
+	if created.CreateQuote.ID != "7" || created.CreateQuote.Tags[0].Name != "luck" {
+		t.Errorf("unexpected quote %+v", created.CreateQuote)
+	}
+
+	_, resp = post(t, h, `mutation { deleteQuote(id: "7", version: 1) }`, nil)
+	if len(resp.Errors) > 0 || !strings.Contains(string(resp.Data), `"deleteQuote":"7"`) {
+		t.Errorf("expected quote 7 to be deleted, got %s %+v", resp.Data, resp.Errors)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"validation", `mutation { createQuote(input: {author: "", text: ""}) { id } }`, CodeBadUserInput},
+		{"conflict", `mutation { updateQuote(id: 7, input: {author: "Seneca", text: "Luck", version: 3}) { id } }`, CodeConflict},
+		{"invalid id", `mutation { deleteQuote(id: "seven") }`, CodeBadUserInput},
+		{"unknown author", `mutation { updateAuthor(name: "Nobody", bio: "") { name } }`, CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := post(t, h, tt.query, nil)
+			if status != http.StatusOK || resp.code(0) != tt.code {
+				t.Fatalf("expected %s, got status %d %+v", tt.code, status, resp.Errors)
+			}
+		})
+	}
+
+	_, resp = post(t, h, tests[0].query, nil)
+	fieldErrors, _ := resp.Errors[0].Extensions["fieldErrors"].([]interface{})
+	if len(fieldErrors) != 2 || fieldErrors[1].(map[string]interface{})["field"] != "text" {
+		t.Errorf("expected errors for author and text, got %+v", resp.Errors[0].Extensions)
+	}
+	_, resp = post(t, h, tests[1].query, nil)
+	if resp.Errors[0].Extensions["currentVersion"] != float64(4) {
+		t.Errorf("expected the current version, got %+v", resp.Errors[0].Extensions)
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	mock := &MockQuoteUseCase{
+		ListTagsFunc: func(after string, limit int) (*domain.TagPage, error) {
+			return nil, errors.New("pq: connection refused")
+		},
+	}
+	h := NewHandler(mock, DefaultConfig)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		code   string
+	}{
+		{"syntax", `{ quotes {`, http.StatusBadRequest, CodeParseFailed},
+		{"unknown field", `{ quotes { nodes { likes } } }`, http.StatusBadRequest, CodeValidationFailed},
+		{"page size", `{ quotes(first: 501) { nodes { id } } }`, http.StatusOK, CodeBadUserInput},
+		{"cursor", `{ quotes(after: "bm9wZQ") { nodes { id } } }`, http.StatusOK, CodeBadUserInput},
+		{"foreign cursor", `{ quotes(after: "` + encodeCursor(tagCursor, "1") + `") { nodes { id } } }`, http.StatusOK, CodeBadUserInput},
+		{"storage", `{ tags { nodes { name } } }`, http.StatusOK, CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := post(t, h, tt.query, nil)
+			if status != tt.status || resp.code(0) != tt.code {
+				t.Fatalf("expected %d %s, got %d %+v", tt.status, tt.code, status, resp.Errors)
+			}
+		})
+	}
+
+	_, resp := post(t, h, `{ tags { nodes { name } } }`, nil)
+	if msg := resp.Errors[0].Message; strings.Contains(msg, "pq") {
+		t.Errorf("expected storage errors to be hidden, got %q", msg)
+	}
+}
+
+func TestHandler_Limits(t *testing.T) {
+	h := NewHandler(&MockQuoteUseCase{}, Config{MaxDepth: 5, MaxComplexity: 200})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		code      string
+	}{
+		{"shallow", `{ quotes(first: 10) { nodes { id author { name } } } }`, nil, ""},
+		{"too deep", `{ quotes { nodes { author { quotes { nodes { id } } } } } }`, nil, CodeDepthLimit},
+		{"too deep through fragments", `{ quotes { ...q } } fragment q on QuoteConnection { nodes { author { ... on Author { quotes { nodes { id } } } } } }`, nil, CodeDepthLimit},
+		{"too complex", `{ quotes(first: 100) { nodes { id text } } }`, nil, CodeComplexityLimit},
+		{"too complex by default", `{ quotes { nodes { id text author { name } tags { name } } } }`, nil, CodeComplexityLimit},
+		{"too complex through variables", `query ($n: Int) { quotes(first: $n) { nodes { id text } } }`, map[string]interface{}{"n": 150}, CodeComplexityLimit},
+		{"introspection", `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := post(t, h, tt.query, tt.variables)
+			if tt.code == "" {
+				if status != http.StatusOK || len(resp.Errors) > 0 {
+					t.Fatalf("expected the query to run, got %d %+v", status, resp.Errors)
+				}
+				return
+			}
+			if status != http.StatusBadRequest || resp.code(0) != tt.code {
+				t.Fatalf("expected 400 %s, got %d %+v", tt.code, status, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestHandler_HTTP(t *testing.T) {
+	mock := &MockQuoteUseCase{
+		GetRandomQuoteFunc: func() (*domain.Quote, error) { return seneca, nil },
+	}
+	h := NewHandler(mock, Config{GraphiQL: true, MaxBodyBytes: 256})
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`{ randomQuote { id } }`), nil)
+	if status, resp := do(t, h, req); status != http.StatusOK || !strings.Contains(string(resp.Data), `"id":"1"`) {
+		t.Errorf("expected a query over GET to run, got %d %s", status, resp.Data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`mutation { deleteQuote(id: 1) }`), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "POST" || mock.calls["DeleteQuote"] != 0 {
+		t.Errorf("expected a mutation over GET to be refused, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || !strings.Contains(rec.Body.String(), "GraphiQL") {
+		t.Errorf("expected GraphiQL, got %q", rec.Header().Get("Content-Type"))
+	}
+	rec = httptest.NewRecorder()
+	NewHandler(mock, DefaultConfig).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected GraphiQL to be off by default, got %d", rec.Code)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"form", http.MethodPost, "application/x-www-form-urlencoded", "query={}", http.StatusUnsupportedMediaType},
+		{"invalid json", http.MethodPost, "application/json", "{", http.StatusBadRequest},
+		{"too large", http.MethodPost, "application/json", `{"query": "` + strings.Repeat(" ", 300) + `{ randomQuote { id } }"}`, http.StatusRequestEntityTooLarge},
+		{"no query", http.MethodPost, "application/json", `{}`, http.StatusBadRequest},
+		{"put", http.MethodPut, "application/json", `{}`, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if status, resp := do(t, h, req); status != tt.status || len(resp.Errors) != 1 {
+				t.Errorf("expected %d with one error, got %d %+v", tt.status, status, resp.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package graphqlserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// limiter measures the depth and the complexity of an operation before it runs.
+// Every field costs one plus the cost of its selections, multiplied by the page size
+// for fields taking a first argument. Introspection fields are free.
+type limiter struct {
+	fragments     map[string]*ast.FragmentDefinition
+	variables     map[string]interface{}
+	maxDepth      int
+	maxComplexity int
+}
+
+// checkLimits returns an error when the selected operation of doc is too deep or too complex.
+// An operation that cannot be found is left for the executor to report.
+func checkLimits(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}, cfg Config) *gqlerrors.FormattedError {
+	l := &limiter{
+		fragments:     make(map[string]*ast.FragmentDefinition),
+		variables:     variables,
+		maxDepth:      cfg.MaxDepth,
+		maxComplexity: cfg.MaxComplexity,
+	}
+
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			l.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	op := operation(doc, operationName)
+	if op == nil {
+		return nil
+	}
+
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	if root == nil {
+		return nil
+	}
+
+	if _, err := l.selections(root, op.SelectionSet, 1); err != nil {
+		return err
+	}
+	return nil
+}
+
+// selections returns the complexity of a selection set whose fields are at the given depth
+func (l *limiter) selections(parent *graphql.Object, set *ast.SelectionSet, depth int) (int, *gqlerrors.FormattedError) {
+	if set == nil {
+		return 0, nil
+	}
+
+	complexity := 0
+	for _, selection := range set.Selections {
+		var cost int
+		var err *gqlerrors.FormattedError
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost, err = l.field(parent, selection, depth)
+		case *ast.InlineFragment:
+			// The schema has no interfaces or unions, so fragments apply to the enclosing type
+			cost, err = l.selections(parent, selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if fragment := l.fragments[selection.Name.Value]; fragment != nil {
+				cost, err = l.selections(parent, fragment.SelectionSet, depth)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		complexity += cost
+		if l.maxComplexity > 0 && complexity > l.maxComplexity {
+			return 0, l.tooComplex()
+		}
+	}
+	return complexity, nil
+}
+
+func (l *limiter) field(parent *graphql.Object, field *ast.Field, depth int) (int, *gqlerrors.FormattedError) {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return 0, nil
+	}
+	def := parent.Fields()[name]
+	if def == nil {
+		return 0, nil
+	}
+
+	if l.maxDepth > 0 && depth > l.maxDepth {
+		err := requestError(CodeDepthLimit, fmt.Sprintf("query is nested deeper than %d fields", l.maxDepth))
+		return 0, &err
+	}
+
+	child, ok := objectType(def.Type)
+	if !ok {
+		return 1, nil
+	}
+	cost, err := l.selections(child, field.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+
+	cost = 1 + l.multiplier(def, field)*cost
+	if l.maxComplexity > 0 && cost > l.maxComplexity {
+		return 0, l.tooComplex()
+	}
+	return cost, nil
+}
+
+// multiplier is the page size requested from a field, 1 for fields without one
+func (l *limiter) multiplier(def *graphql.FieldDefinition, field *ast.Field) int {
+	for _, arg := range def.Args {
+		if arg.Name() != "first" {
+			continue
+		}
+
+		value := arg.DefaultValue
+		for _, given := range field.Arguments {
+			if given.Name.Value != "first" {
+				continue
+			}
+			switch v := given.Value.(type) {
+			case *ast.IntValue:
+				value = v.Value
+			case *ast.Variable:
+				if variable, ok := l.variables[v.Name.Value]; ok {
+					value = variable
+				}
+			}
+		}
+		// Larger pages are rejected when the field resolves, so they count as the largest allowed
+		if n := toInt(value); n > 1 {
+			return min(n, domain.MaxPageSize)
+		}
+	}
+	return 1
+}
+
+func (l *limiter) tooComplex() *gqlerrors.FormattedError {
+	err := requestError(CodeComplexityLimit, fmt.Sprintf("query complexity exceeds %d", l.maxComplexity))
+	return &err
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// objectType unwraps lists and non-nulls down to an object type
+func objectType(t graphql.Type) (*graphql.Object, bool) {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		case *graphql.Object:
+			return wrapped, true
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package graphqlserver
+
+import (
+	"sync"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// loader batches the lookups made while a request resolves one level of the response.
+// Load only queues the key and returns a thunk; the executor calls thunks after every
+// field of the level has been resolved, so the first thunk to run fetches all queued keys
+// with a single call. Results are cached for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		queued:  make(map[K]bool),
+		results: make(map[K]V),
+		errs:    make(map[K]error),
+	}
+}
+
+// Load queues key and returns a function that waits for the batch and yields the value,
+// or the zero value when fetch returned none
+func (l *loader[K, V]) Load(key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		return l.get(key)
+	}
+}
+
+func (l *loader[K, V]) get(key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) > 0 {
+		keys := l.pending
+		l.pending = nil
+		results, err := l.fetch(keys)
+		for _, k := range keys {
+			if err != nil {
+				l.errs[k] = err
+			} else if v, ok := results[k]; ok {
+				l.results[k] = v
+			}
+		}
+	}
+
+	if err := l.errs[key]; err != nil {
+		var zero V
+		return zero, err
+	}
+	return l.results[key], nil
+}
+
+// loaders are the loaders of one request
+type loaders struct {
+	quotes    *loader[int, *domain.Quote]
+	pages     *loader[domain.PageRequest, *domain.QuotePage]
+	authors   *loader[string, *domain.Author]
+	tags      *loader[string, *domain.Tag]
+	revisions *loader[int, []*domain.Revision]
+}
+
+func newLoaders(uc QuoteUseCase) *loaders {
+	return &loaders{
+		quotes: newLoader(func(ids []int) (map[int]*domain.Quote, error) {
+			quotes, err := uc.GetQuotesByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]*domain.Quote, len(quotes))
+			for _, quote := range quotes {
+				byID[quote.ID] = quote
+			}
+			return byID, nil
+		}),
+		pages: newLoader(func(reqs []domain.PageRequest) (map[domain.PageRequest]*domain.QuotePage, error) {
+			pages, err := uc.ListQuotesPages(reqs)
+			if err != nil {
+				return nil, err
+			}
+			byReq := make(map[domain.PageRequest]*domain.QuotePage, len(reqs))
+			for i, req := range reqs {
+				byReq[req] = pages[i]
+			}
+			return byReq, nil
+		}),
+		authors: newLoader(func(names []string) (map[string]*domain.Author, error) {
+			authors, err := uc.GetAuthors(names)
+			if err != nil {
+				return nil, err
+			}
+			byName := make(map[string]*domain.Author, len(authors))
+			for _, author := range authors {
+				byName[author.Name] = author
+			}
+			return byName, nil
+		}),
+		tags: newLoader(func(names []string) (map[string]*domain.Tag, error) {
+			tags, err := uc.GetTags(names)
+			if err != nil {
+				return nil, err
+			}
+			byName := make(map[string]*domain.Tag, len(tags))
+			for _, tag := range tags {
+				byName[tag.Name] = tag
+			}
+			return byName, nil
+		}),
+		revisions: newLoader(func(ids []int) (map[int][]*domain.Revision, error) {
+			revisions, err := uc.GetRevisionsByQuotes(ids)
+			if err != nil {
+				return nil, err
+			}
+			byQuote := make(map[int][]*domain.Revision, len(ids))
+			for _, id := range ids {
+				byQuote[id] = []*domain.Revision{}
+			}
+			for _, revision := range revisions {
+				byQuote[revision.QuoteID] = append(byQuote[revision.QuoteID], revision)
+			}
+			return byQuote, nil
+		}),
+	}
+}
@@ -0,0 +1,582 @@
+package graphqlserver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Cursor prefixes keep cursors of one connection from being accepted by another
+const (
+	quoteCursor  = "quote:"
+	authorCursor = "author:"
+	tagCursor    = "tag:"
+)
+
+// connection is a Relay connection over quotes, authors or tags
+type connection struct {
+	Edges    []*edge
+	PageInfo *pageInfo
+}
+
+type edge struct {
+	Cursor string
+	Node   interface{}
+}
+
+type pageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+func newConnection(edges []*edge, hasNext, hasPrevious bool) *connection {
+	conn := &connection{Edges: edges, PageInfo: &pageInfo{HasNextPage: hasNext, HasPreviousPage: hasPrevious}}
+	if len(edges) > 0 {
+		conn.PageInfo.StartCursor = &edges[0].Cursor
+		conn.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+	return conn
+}
+
+func encodeCursor(prefix, value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(prefix + value))
+}
+
+// decodeCursor returns the value of a cursor made by encodeCursor with the same prefix
+func decodeCursor(prefix, cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), prefix) {
+		return "", domain.ErrInvalidPageToken
+	}
+	return string(raw[len(prefix):]), nil
+}
+
+// resolver answers the fields of the schema from the use case and the loaders of the request
+type resolver struct {
+	uc QuoteUseCase
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// newSchema builds the schema served at /graphql
+func newSchema(uc QuoteUseCase) (graphql.Schema, error) {
+	r := &resolver{uc: uc}
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	pageArgs := func() graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: domain.DefaultPageSize},
+			"after": &graphql.ArgumentConfig{Type: graphql.String},
+		}
+	}
+
+	// Types refer to each other, so their fields are thunks
+	var quoteType, authorType, tagType, quoteConnectionType *graphql.Object
+
+	connectionType := func(name string, node func() *graphql.Object) *graphql.Object {
+		edgeType := graphql.NewObject(graphql.ObjectConfig{
+			Name: name + "Edge",
+			Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+				return graphql.Fields{
+					"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+					"node":   &graphql.Field{Type: graphql.NewNonNull(node())},
+				}
+			}),
+		})
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: name + "Connection",
+			Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+				return graphql.Fields{
+					"edges":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+					"nodes":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(node()))), Resolve: resolveNodes},
+					"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+				}
+			}),
+		})
+	}
+	quoteConnectionType = connectionType("Quote", func() *graphql.Object { return quoteType })
+	authorConnectionType := connectionType("Author", func() *graphql.Object { return authorType })
+	tagConnectionType := connectionType("Tag", func() *graphql.Object { return tagType })
+
+	revisionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Revision",
+		Description: "A recorded state of a quote",
+		Fields: graphql.Fields{
+			"revision":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"change":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: r.revisionChange},
+			"actor":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"author":    &graphql.Field{Type: graphql.String, Resolve: r.revisionSnapshot(func(q *domain.Quote) interface{} { return q.Author })},
+			"text":      &graphql.Field{Type: graphql.String, Resolve: r.revisionSnapshot(func(q *domain.Quote) interface{} { return q.Quote })},
+			"tags":      &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Resolve: r.revisionSnapshot(func(q *domain.Quote) interface{} { return q.Tags })},
+		},
+	})
+
+	quoteType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Quote",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: r.quoteID},
+				"text":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: r.quoteText},
+				"author":    &graphql.Field{Type: graphql.NewNonNull(authorType), Resolve: r.quoteAuthor},
+				"tags":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))), Resolve: r.quoteTags},
+				"version":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"revisions": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(revisionType))),
+					Description: "Revisions of the quote, newest first",
+					Resolve:     r.quoteRevisions,
+				},
+			}
+		}),
+	})
+
+	authorType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"name":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"bio":        &graphql.Field{Type: graphql.String, Resolve: r.authorBio},
+				"quoteCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"quotes": &graphql.Field{
+					Type:    graphql.NewNonNull(quoteConnectionType),
+					Args:    pageArgs(),
+					Resolve: r.authorQuotes,
+				},
+			}
+		}),
+	})
+
+	tagType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"name":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"quoteCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"quotes": &graphql.Field{
+					Type:    graphql.NewNonNull(quoteConnectionType),
+					Args:    pageArgs(),
+					Resolve: r.tagQuotes,
+				},
+			}
+		}),
+	})
+
+	quotesArgs := pageArgs()
+	quotesArgs["author"] = &graphql.ArgumentConfig{Type: graphql.String}
+	quotesArgs["tag"] = &graphql.ArgumentConfig{Type: graphql.String}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"quote": &graphql.Field{
+				Type:    quoteType,
+				Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.quote,
+			},
+			"quotes": &graphql.Field{
+				Type:        graphql.NewNonNull(quoteConnectionType),
+				Description: "Quotes in ID order, optionally by one author or with one tag",
+				Args:        quotesArgs,
+				Resolve:     r.quotes,
+			},
+			"randomQuote": &graphql.Field{
+				Type:        quoteType,
+				Description: "A random quote, null when there are none",
+				Resolve:     r.randomQuote,
+			},
+			"search": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(quoteType))),
+				Description: "The newest quotes whose text, author or tags contain the query",
+				Args: graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: domain.DefaultSearchLimit},
+				},
+				Resolve: r.search,
+			},
+			"author": &graphql.Field{
+				Type:    authorType,
+				Args:    graphql.FieldConfigArgument{"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: r.author,
+			},
+			"authors": &graphql.Field{
+				Type:    graphql.NewNonNull(authorConnectionType),
+				Args:    pageArgs(),
+				Resolve: r.authors,
+			},
+			"tag": &graphql.Field{
+				Type:    tagType,
+				Args:    graphql.FieldConfigArgument{"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: r.tag,
+			},
+			"tags": &graphql.Field{
+				Type:    graphql.NewNonNull(tagConnectionType),
+				Args:    pageArgs(),
+				Resolve: r.tags,
+			},
+		},
+	})
+
+	quoteFields := func() graphql.InputObjectConfigFieldMap {
+		return graphql.InputObjectConfigFieldMap{
+			"author": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"text":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"tags":   &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		}
+	}
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "CreateQuoteInput", Fields: quoteFields()})
+	updateFields := quoteFields()
+	updateFields["version"] = &graphql.InputObjectFieldConfig{
+		Type:        graphql.Int,
+		Description: "Only update the quote if it is still at this version",
+	}
+	updateInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "UpdateQuoteInput", Fields: updateFields})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createQuote": &graphql.Field{
+				Type:    graphql.NewNonNull(quoteType),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createInput)}},
+				Resolve: r.createQuote,
+			},
+			"updateQuote": &graphql.Field{
+				Type: graphql.NewNonNull(quoteType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateInput)},
+				},
+				Resolve: r.updateQuote,
+			},
+			"deleteQuote": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Moves a quote to the trash and returns its ID",
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"version": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: r.deleteQuote,
+			},
+			"updateAuthor": &graphql.Field{
+				Type:        graphql.NewNonNull(authorType),
+				Description: "Replaces the bio of an author; an empty bio clears it",
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"bio":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.updateAuthor,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType, Mutation: mutationType})
+}
+
+func resolveNodes(p graphql.ResolveParams) (interface{}, error) {
+	conn := p.Source.(*connection)
+	nodes := make([]interface{}, len(conn.Edges))
+	for i, e := range conn.Edges {
+		nodes[i] = e.Node
+	}
+	return nodes, nil
+}
+
+// pageSize reads the first argument, which must be between 1 and max
+func pageSize(p graphql.ResolveParams, max int) (int, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > max {
+		return 0, domain.ErrInvalidPageSize
+	}
+	return first, nil
+}
+
+func parseID(value interface{}) (int, error) {
+	s, _ := value.(string)
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrInvalidID
+	}
+	return id, nil
+}
+
+// quotePage loads one page of quotes as a connection, batched with the pages requested
+// alongside it
+func (r *resolver) quotePage(p graphql.ResolveParams, req domain.PageRequest) (interface{}, error) {
+	first, err := pageSize(p, domain.MaxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	after, _ := p.Args["after"].(string)
+	if after != "" {
+		value, err := decodeCursor(quoteCursor, after)
+		if err != nil {
+			return nil, err
+		}
+		if req.AfterID, err = strconv.Atoi(value); err != nil || req.AfterID <= 0 {
+			return nil, domain.ErrInvalidPageToken
+		}
+	}
+	req.Limit = first
+
+	load := loadersFrom(p.Context).pages.Load(req)
+	return func() (interface{}, error) {
+		page, err := load()
+		if err != nil {
+			return nil, err
+		}
+		edges := make([]*edge, len(page.Quotes))
+		for i, quote := range page.Quotes {
+			edges[i] = &edge{Cursor: encodeCursor(quoteCursor, strconv.Itoa(quote.ID)), Node: quote}
+		}
+		return newConnection(edges, page.NextAfterID != 0, after != ""), nil
+	}, nil
+}
+
+// namePage reads the first and after arguments of the authors and tags connections
+func namePage(p graphql.ResolveParams, prefix string) (string, int, error) {
+	first, err := pageSize(p, domain.MaxPageSize)
+	if err != nil {
+		return "", 0, err
+	}
+	var after string
+	if cursor, _ := p.Args["after"].(string); cursor != "" {
+		if after, err = decodeCursor(prefix, cursor); err != nil {
+			return "", 0, err
+		}
+	}
+	return after, first, nil
+}
+
+func (r *resolver) quote(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	load := loadersFrom(p.Context).quotes.Load(id)
+	return func() (interface{}, error) {
+		return load()
+	}, nil
+}
+
+func (r *resolver) quotes(p graphql.ResolveParams) (interface{}, error) {
+	author, _ := p.Args["author"].(string)
+	tag, _ := p.Args["tag"].(string)
+	return r.quotePage(p, domain.PageRequest{Author: author, Tag: tag})
+}
+
+func (r *resolver) randomQuote(p graphql.ResolveParams) (interface{}, error) {
+	quote, err := r.uc.GetRandomQuote()
+	if errors.Is(err, domain.ErrNoQuotesFound) {
+		return nil, nil
+	}
+	return quote, err
+}
+
+func (r *resolver) search(p graphql.ResolveParams) (interface{}, error) {
+	first, err := pageSize(p, domain.MaxSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	return r.uc.SearchQuotes(p.Args["query"].(string), first)
+}
+
+func (r *resolver) author(p graphql.ResolveParams) (interface{}, error) {
+	load := loadersFrom(p.Context).authors.Load(strings.TrimSpace(p.Args["name"].(string)))
+	return func() (interface{}, error) {
+		return load()
+	}, nil
+}
+
+func (r *resolver) authors(p graphql.ResolveParams) (interface{}, error) {
+	after, first, err := namePage(p, authorCursor)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.uc.ListAuthors(after, first)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edge, len(page.Authors))
+	for i, author := range page.Authors {
+		edges[i] = &edge{Cursor: encodeCursor(authorCursor, author.Name), Node: author}
+	}
+	return newConnection(edges, page.HasNext, after != ""), nil
+}
+
+func (r *resolver) tag(p graphql.ResolveParams) (interface{}, error) {
+	load := loadersFrom(p.Context).tags.Load(strings.ToLower(strings.TrimSpace(p.Args["name"].(string))))
+	return func() (interface{}, error) {
+		return load()
+	}, nil
+}
+
+func (r *resolver) tags(p graphql.ResolveParams) (interface{}, error) {
+	after, first, err := namePage(p, tagCursor)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.uc.ListTags(after, first)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edge, len(page.Tags))
+	for i, tag := range page.Tags {
+		edges[i] = &edge{Cursor: encodeCursor(tagCursor, tag.Name), Node: tag}
+	}
+	return newConnection(edges, page.HasNext, after != ""), nil
+}
+
+func (r *resolver) quoteID(p graphql.ResolveParams) (interface{}, error) {
+	return strconv.Itoa(p.Source.(*domain.Quote).ID), nil
+}
+
+func (r *resolver) quoteText(p graphql.ResolveParams) (interface{}, error) {
+	return p.Source.(*domain.Quote).Quote, nil
+}
+
+// quoteAuthor loads the author of a quote. Authors are derived from quotes, so one that
+// is missing from the batch is still returned with just its name.
+func (r *resolver) quoteAuthor(p graphql.ResolveParams) (interface{}, error) {
+	name := p.Source.(*domain.Quote).Author
+	load := loadersFrom(p.Context).authors.Load(name)
+	return func() (interface{}, error) {
+		author, err := load()
+		if err == nil && author == nil {
+			author = &domain.Author{Name: name}
+		}
+		return author, err
+	}, nil
+}
+
+func (r *resolver) quoteTags(p graphql.ResolveParams) (interface{}, error) {
+	names := p.Source.(*domain.Quote).Tags
+	tagLoader := loadersFrom(p.Context).tags
+	loads := make([]func() (*domain.Tag, error), len(names))
+	for i, name := range names {
+		loads[i] = tagLoader.Load(name)
+	}
+	return func() (interface{}, error) {
+		tags := make([]*domain.Tag, len(names))
+		for i, load := range loads {
+			tag, err := load()
+			if err != nil {
+				return nil, err
+			}
+			if tag == nil {
+				tag = &domain.Tag{Name: names[i]}
+			}
+			tags[i] = tag
+		}
+		return tags, nil
+	}, nil
+}
+
+func (r *resolver) quoteRevisions(p graphql.ResolveParams) (interface{}, error) {
+	load := loadersFrom(p.Context).revisions.Load(p.Source.(*domain.Quote).ID)
+	return func() (interface{}, error) {
+		return load()
+	}, nil
+}
+
+func (r *resolver) revisionChange(p graphql.ResolveParams) (interface{}, error) {
+	return string(p.Source.(*domain.Revision).Change), nil
+}
+
+// revisionSnapshot resolves a field of the quote as it was at the revision
+func (r *resolver) revisionSnapshot(field func(*domain.Quote) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		snapshot := p.Source.(*domain.Revision).Snapshot
+		if snapshot == nil {
+			return nil, nil
+		}
+		return field(snapshot), nil
+	}
+}
+
+func (r *resolver) authorBio(p graphql.ResolveParams) (interface{}, error) {
+	if bio := p.Source.(*domain.Author).Bio; bio != "" {
+		return bio, nil
+	}
+	return nil, nil
+}
+
+func (r *resolver) authorQuotes(p graphql.ResolveParams) (interface{}, error) {
+	return r.quotePage(p, domain.PageRequest{Author: p.Source.(*domain.Author).Name})
+}
+
+func (r *resolver) tagQuotes(p graphql.ResolveParams) (interface{}, error) {
+	return r.quotePage(p, domain.PageRequest{Tag: p.Source.(*domain.Tag).Name})
+}
+
+// quoteRequest converts a CreateQuoteInput or UpdateQuoteInput argument
+func quoteRequest(input map[string]interface{}) domain.CreateQuoteRequest {
+	req := domain.CreateQuoteRequest{}
+	req.Author, _ = input["author"].(string)
+	req.Quote, _ = input["text"].(string)
+	if tags, ok := input["tags"].([]interface{}); ok {
+		req.Tags = make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				req.Tags = append(req.Tags, s)
+			}
+		}
+	}
+	return req
+}
+
+func (r *resolver) createQuote(p graphql.ResolveParams) (interface{}, error) {
+	req := quoteRequest(p.Args["input"].(map[string]interface{}))
+	return r.uc.CreateQuote(p.Context, &req)
+}
+
+func (r *resolver) updateQuote(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]interface{})
+	req := &domain.UpdateQuoteRequest{CreateQuoteRequest: quoteRequest(input)}
+	req.Version, _ = input["version"].(int)
+	return r.uc.UpdateQuote(p.Context, id, req)
+}
+
+func (r *resolver) deleteQuote(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	version, _ := p.Args["version"].(int)
+	if err = r.uc.DeleteQuote(p.Context, id, version); err != nil {
+		return nil, err
+	}
+	return strconv.Itoa(id), nil
+}
+
+func (r *resolver) updateAuthor(p graphql.ResolveParams) (interface{}, error) {
+	return r.uc.SetAuthorBio(p.Context, p.Args["name"].(string), p.Args["bio"].(string))
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError maps errors from the use cases to gRPC statuses. Errors the caller cannot act
+// on become INTERNAL with the fallback message, so storage details never leave the server.
+func statusError(err error, fallback string) error {
+	var verr *domain.ValidationError
+	if errors.As(err, &verr) {
+		return validationStatus(verr)
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrQuoteNotFound):
+		return status.Error(codes.NotFound, domain.ErrQuoteNotFound.Error())
+	case errors.Is(err, domain.ErrNoQuotesFound):
+		return status.Error(codes.NotFound, domain.MsgQuotesNotFound)
+	case errors.Is(err, domain.ErrVersionConflict):
+		return status.Error(codes.Aborted, domain.MsgVersionConflict)
+	case errors.Is(err, domain.ErrInvalidID), errors.Is(err, domain.ErrInvalidVersion),
+		errors.Is(err, domain.ErrInvalidAuthor), errors.Is(err, domain.ErrInvalidQuote), errors.Is(err, domain.ErrInvalidTag):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, domain.MsgInvalidPageToken)
+	case errors.Is(err, domain.ErrInvalidQuery):
+		return status.Error(codes.InvalidArgument, domain.MsgInvalidSearchQuery)
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return status.Error(codes.Unauthenticated, domain.MsgInvalidAPIKey)
+	case errors.Is(err, domain.ErrForbidden):
+		return status.Error(codes.PermissionDenied, domain.MsgAdminRequired)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	if s, ok := status.FromError(err); ok {
+		return s.Err()
+	}
+	return status.Error(codes.Internal, fallback)
+}
+
+// validationStatus reports every field error as a BadRequest field violation
+func validationStatus(verr *domain.ValidationError) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, fe := range verr.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldName(fe.Field),
+			Description: fe.Message,
+			Reason:      fe.Code,
+		})
+	}
+
+	s, err := status.New(codes.InvalidArgument, verr.Error()).WithDetails(badRequest)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, verr.Error())
+	}
+	return s.Err()
+}
+
+// fieldName translates a JSON field name of the HTTP API to its protobuf name
+func fieldName(field string) string {
+	if field == "quote" {
+		return "text"
+	}
+	return field
+}
@@ -0,0 +1,163 @@
+package grpcserver
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key carrying the request ID, as X-Request-ID does over HTTP
+const requestIDKey = "x-request-id"
+
+var (
+	// rpcCalls counts finished calls by "<method> <code>"
+	rpcCalls = expvar.NewMap("grpc_calls")
+	// rpcSeconds sums the time spent in calls by method
+	rpcSeconds = expvar.NewMap("grpc_call_seconds")
+)
+
+// Authenticator resolves an API key to the caller it belongs to
+type Authenticator interface {
+	Authenticate(key string) (*domain.Principal, error)
+}
+
+// apiKey returns the key presented with the call, from "authorization: Bearer <key>"
+// or "x-api-key: <key>" metadata
+func apiKey(md metadata.MD) string {
+	for _, value := range md.Get("authorization") {
+		if key, ok := strings.CutPrefix(value, "Bearer "); ok {
+			return strings.TrimSpace(key)
+		}
+	}
+	for _, value := range md.Get("x-api-key") {
+		if key := strings.TrimSpace(value); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// authenticate attaches the caller and the request info to ctx. Calls without a key stay
+// anonymous; calls with an unknown key fail with UNAUTHENTICATED.
+func authenticate(ctx context.Context, authenticator Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var id string
+	if values := md.Get(requestIDKey); len(values) > 0 {
+		id = values[0]
+	}
+	id = middleware.RequestID(id)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	ctx = domain.WithRequestInfo(ctx, domain.RequestInfo{ID: id, ClientIP: clientIP(ctx)})
+
+	key := apiKey(md)
+	if key == "" {
+		return ctx, nil
+	}
+	principal, err := authenticator.Authenticate(key)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, domain.MsgInvalidAPIKey)
+	}
+	return domain.WithPrincipal(ctx, principal), nil
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// AuthUnaryInterceptor authenticates unary calls, see authenticate
+func AuthUnaryInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor authenticates streaming calls, see authenticate
+func AuthStreamInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func logCall(ctx context.Context, method string, err error, duration time.Duration) {
+	addr := "-"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	log.Printf("%s gRPC %s %s %s", addr, method, status.Code(err), duration)
+}
+
+// LoggingUnaryInterceptor logs every unary call with its status code and duration
+func LoggingUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+// LoggingStreamInterceptor logs every streaming call with its status code and duration
+func LoggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(ss.Context(), info.FullMethod, err, time.Since(start))
+	return err
+}
+
+func recordCall(method string, err error, duration time.Duration) {
+	rpcCalls.Add(method+" "+status.Code(err).String(), 1)
+	rpcSeconds.AddFloat(method, duration.Seconds())
+}
+
+// MetricsUnaryInterceptor counts unary calls and their duration in the grpc_calls and
+// grpc_call_seconds expvars
+func MetricsUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	recordCall(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+// MetricsStreamInterceptor counts streaming calls like MetricsUnaryInterceptor
+func MetricsStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	recordCall(info.FullMethod, err, time.Since(start))
+	return err
+}
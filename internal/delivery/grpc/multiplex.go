@@ -0,0 +1,27 @@
+package grpcserver
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Multiplex serves gRPC calls with grpcServer and every other request with next, so both
+// APIs can share one listener. gRPC needs HTTP/2, which the http.Server has to accept
+// without TLS (h2c) unless it terminates TLS itself.
+func Multiplex(grpcServer *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Streaming calls may outlive the timeouts meant for plain HTTP requests
+		rc := http.NewResponseController(w)
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		grpcServer.ServeHTTP(w, r)
+	})
+}
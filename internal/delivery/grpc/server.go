@@ -0,0 +1,195 @@
+// Package grpcserver serves the quote use cases over gRPC, see api/proto/quotes/v1
+package grpcserver
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	quotesv1 "github.com/shoksin/quotes-service/api/gen/quotes/v1"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// QuoteUseCase is the part of usecase.QuoteUseCase served over gRPC
+type QuoteUseCase interface {
+	CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error)
+	GetQuote(id int) (*domain.Quote, error)
+	ListQuotesPage(req domain.PageRequest) (*domain.QuotePage, error)
+	ExportQuotes(fn func(*domain.Quote) error) error
+	GetRandomQuote() (*domain.Quote, error)
+	DeleteQuote(ctx context.Context, id, version int) error
+	SearchQuotes(query string, limit int) ([]*domain.Quote, error)
+}
+
+// QuoteServer implements quotesv1.QuoteServiceServer
+type QuoteServer struct {
+	quotesv1.UnimplementedQuoteServiceServer
+	quoteUseCase QuoteUseCase
+}
+
+func NewQuoteServer(quoteUseCase QuoteUseCase) *QuoteServer {
+	return &QuoteServer{quoteUseCase: quoteUseCase}
+}
+
+// NewServer returns a gRPC server offering QuoteService, the standard health service and
+// reflection. Every call is logged, counted and authenticated.
+func NewServer(quoteUseCase QuoteUseCase, authenticator Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor, MetricsUnaryInterceptor, AuthUnaryInterceptor(authenticator)),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor, MetricsStreamInterceptor, AuthStreamInterceptor(authenticator)),
+	)
+	server := grpc.NewServer(opts...)
+
+	quotesv1.RegisterQuoteServiceServer(server, NewQuoteServer(quoteUseCase))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(quotesv1.QuoteService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+func (s *QuoteServer) CreateQuote(ctx context.Context, req *quotesv1.CreateQuoteRequest) (*quotesv1.Quote, error) {
+	quote, err := s.quoteUseCase.CreateQuote(ctx, &domain.CreateQuoteRequest{
+		Author: req.GetAuthor(),
+		Quote:  req.GetText(),
+		Tags:   req.GetTags(),
+	})
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedCreateQuote)
+	}
+	return toProto(quote), nil
+}
+
+func (s *QuoteServer) GetQuote(ctx context.Context, req *quotesv1.GetQuoteRequest) (*quotesv1.Quote, error) {
+	id, err := quoteID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedGetQuotes)
+	}
+
+	quote, err := s.quoteUseCase.GetQuote(id)
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedGetQuotes)
+	}
+	return toProto(quote), nil
+}
+
+func (s *QuoteServer) ListQuotes(ctx context.Context, req *quotesv1.ListQuotesRequest) (*quotesv1.ListQuotesResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedGetQuotes)
+	}
+
+	page, err := s.quoteUseCase.ListQuotesPage(domain.PageRequest{
+		Author:  req.GetAuthor(),
+		AfterID: afterID,
+		Limit:   int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedGetQuotes)
+	}
+
+	resp := &quotesv1.ListQuotesResponse{Quotes: toProtoList(page.Quotes)}
+	if page.NextAfterID > 0 {
+		resp.NextPageToken = encodePageToken(page.NextAfterID)
+	}
+	return resp, nil
+}
+
+func (s *QuoteServer) StreamQuotes(req *quotesv1.StreamQuotesRequest, stream grpc.ServerStreamingServer[quotesv1.Quote]) error {
+	err := s.quoteUseCase.ExportQuotes(func(quote *domain.Quote) error {
+		if req.GetAuthor() != "" && quote.Author != req.GetAuthor() {
+			return nil
+		}
+		return stream.Send(toProto(quote))
+	})
+	if err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return statusError(err, domain.MsgFailedExportQuotes)
+	}
+	return nil
+}
+
+func (s *QuoteServer) GetRandomQuote(ctx context.Context, req *quotesv1.GetRandomQuoteRequest) (*quotesv1.Quote, error) {
+	quote, err := s.quoteUseCase.GetRandomQuote()
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedGetRandomQuote)
+	}
+	return toProto(quote), nil
+}
+
+func (s *QuoteServer) DeleteQuote(ctx context.Context, req *quotesv1.DeleteQuoteRequest) (*emptypb.Empty, error) {
+	id, err := quoteID(req.GetId())
+	if err == nil {
+		err = s.quoteUseCase.DeleteQuote(ctx, id, int(req.GetVersion()))
+	}
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedDeleteQuote)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *QuoteServer) SearchQuotes(ctx context.Context, req *quotesv1.SearchQuotesRequest) (*quotesv1.SearchQuotesResponse, error) {
+	quotes, err := s.quoteUseCase.SearchQuotes(req.GetQuery(), int(req.GetPageSize()))
+	if err != nil {
+		return nil, statusError(err, domain.MsgFailedSearchQuotes)
+	}
+	return &quotesv1.SearchQuotesResponse{Quotes: toProtoList(quotes)}, nil
+}
+
+// quoteID narrows a wire ID to the int used by the use cases
+func quoteID(id int64) (int, error) {
+	if id <= 0 || int64(int(id)) != id {
+		return 0, domain.ErrInvalidID
+	}
+	return int(id), nil
+}
+
+// Page tokens are opaque to clients; they carry the last ID of the previous page
+func encodePageToken(afterID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(afterID)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, domain.ErrInvalidPageToken
+	}
+	afterID, err := strconv.Atoi(string(raw))
+	if err != nil || afterID <= 0 {
+		return 0, domain.ErrInvalidPageToken
+	}
+	return afterID, nil
+}
+
+func toProto(quote *domain.Quote) *quotesv1.Quote {
+	return &quotesv1.Quote{
+		Id:         int64(quote.ID),
+		Author:     quote.Author,
+		Text:       quote.Quote,
+		Tags:       quote.Tags,
+		Version:    int64(quote.Version),
+		CreateTime: timestamppb.New(quote.CreatedAt),
+		UpdateTime: timestamppb.New(quote.UpdatedAt),
+	}
+}
+
+func toProtoList(quotes []*domain.Quote) []*quotesv1.Quote {
+	list := make([]*quotesv1.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		list = append(list, toProto(quote))
+	}
+	return list
+}
@@ -0,0 +1,474 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	quotesv1 "github.com/shoksin/quotes-service/api/gen/quotes/v1"
+	"github.com/shoksin/quotes-service/internal/auth"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testKey = "svc-secret"
+
+type MockQuoteUseCase struct {
+	CreateQuoteFunc    func(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error)
+	GetQuoteFunc       func(id int) (*domain.Quote, error)
+	ListQuotesPageFunc func(req domain.PageRequest) (*domain.QuotePage, error)
+	ExportQuotesFunc   func(fn func(*domain.Quote) error) error
+	GetRandomQuoteFunc func() (*domain.Quote, error)
+	DeleteQuoteFunc    func(ctx context.Context, id, version int) error
+	SearchQuotesFunc   func(query string, limit int) ([]*domain.Quote, error)
+}
+
+func (m *MockQuoteUseCase) CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+	if m.CreateQuoteFunc != nil {
+		return m.CreateQuoteFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) GetQuote(id int) (*domain.Quote, error) {
+	if m.GetQuoteFunc != nil {
+		return m.GetQuoteFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) ListQuotesPage(req domain.PageRequest) (*domain.QuotePage, error) {
+	if m.ListQuotesPageFunc != nil {
+		return m.ListQuotesPageFunc(req)
+	}
+	return &domain.QuotePage{}, nil
+}
+
+func (m *MockQuoteUseCase) ExportQuotes(fn func(*domain.Quote) error) error {
+	if m.ExportQuotesFunc != nil {
+		return m.ExportQuotesFunc(fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) GetRandomQuote() (*domain.Quote, error) {
+	if m.GetRandomQuoteFunc != nil {
+		return m.GetRandomQuoteFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) DeleteQuote(ctx context.Context, id, version int) error {
+	if m.DeleteQuoteFunc != nil {
+		return m.DeleteQuoteFunc(ctx, id, version)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) SearchQuotes(query string, limit int) ([]*domain.Quote, error) {
+	if m.SearchQuotesFunc != nil {
+		return m.SearchQuotesFunc(query, limit)
+	}
+	return []*domain.Quote{}, nil
+}
+
+// newTestServer serves mock over an in-memory listener and returns a connection to it
+func newTestServer(t *testing.T, mock *MockQuoteUseCase) *grpc.ClientConn {
+	t.Helper()
+	keys, err := auth.ParseKeys([]string{"svc:user:" + testKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(mock, keys)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func testContext(t *testing.T, kv ...string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+func quotesUpTo(n int) []*domain.Quote {
+	quotes := make([]*domain.Quote, 0, n)
+	for id := 1; id <= n; id++ {
+		author := "Seneca"
+		if id%2 == 0 {
+			author = "Epictetus"
+		}
+		quotes = append(quotes, &domain.Quote{ID: id, Author: author, Quote: fmt.Sprintf("quote %d", id), Version: 1})
+	}
+	return quotes
+}
+
+func TestQuoteServer_CreateAndGet(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		CreateQuoteFunc: func(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+			return &domain.Quote{ID: 9, Author: req.Author, Quote: req.Quote, Tags: req.Tags, Version: 1, CreatedAt: created, UpdatedAt: created}, nil
+		},
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			if id != 9 {
+				return nil, domain.ErrQuoteNotFound
+			}
+			return &domain.Quote{ID: 9, Author: "Seneca", Quote: "We suffer more in imagination than in reality.", Version: 3}, nil
+		},
+	}))
+	ctx := testContext(t)
+
+	quote, err := client.CreateQuote(ctx, &quotesv1.CreateQuoteRequest{Author: "Seneca", Text: "We suffer more in imagination than in reality.", Tags: []string{"stoic"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quote.Id != 9 || quote.Text == "" || len(quote.Tags) != 1 || !quote.CreateTime.AsTime().Equal(created) {
+		t.Errorf("unexpected quote %v", quote)
+	}
+
+	if quote, err = client.GetQuote(ctx, &quotesv1.GetQuoteRequest{Id: 9}); err != nil || quote.Version != 3 {
+		t.Errorf("expected quote 9 at version 3, got %v (%v)", quote, err)
+	}
+	if _, err = client.GetQuote(ctx, &quotesv1.GetQuoteRequest{Id: 8}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NOT_FOUND, got %v", err)
+	}
+	if _, err = client.GetQuote(ctx, &quotesv1.GetQuoteRequest{Id: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected INVALID_ARGUMENT, got %v", err)
+	}
+}
+
+func TestQuoteServer_CreateQuote_Validation(t *testing.T) {
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		CreateQuoteFunc: func(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+			return nil, domain.DefaultValidationRules.Validate(req)
+		},
+	}))
+
+	_, err := client.CreateQuote(testContext(t), &quotesv1.CreateQuoteRequest{Author: "Seneca"})
+	s := status.Convert(err)
+	if s.Code() != codes.InvalidArgument {
+		t.Fatalf("expected INVALID_ARGUMENT, got %v", err)
+	}
+	if len(s.Details()) != 1 {
+		t.Fatalf("expected one detail, got %v", s.Details())
+	}
+	badRequest, ok := s.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "text" || badRequest.FieldViolations[0].Reason != domain.CodeRequired {
+		t.Errorf("expected a violation of the text field, got %v", s.Details()[0])
+	}
+}
+
+func TestQuoteServer_ListQuotes(t *testing.T) {
+	var gotReq domain.PageRequest
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		ListQuotesPageFunc: func(req domain.PageRequest) (*domain.QuotePage, error) {
+			gotReq = req
+			quotes := quotesUpTo(5)[req.AfterID:]
+			if len(quotes) > req.Limit {
+				return &domain.QuotePage{Quotes: quotes[:req.Limit], NextAfterID: quotes[req.Limit-1].ID}, nil
+			}
+			return &domain.QuotePage{Quotes: quotes}, nil
+		},
+	}))
+	ctx := testContext(t)
+
+	var ids []int64
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected the listing to end")
+		}
+		resp, err := client.ListQuotes(ctx, &quotesv1.ListQuotesRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, quote := range resp.Quotes {
+			ids = append(ids, quote.Id)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+		if token == "2" || token == "4" {
+			t.Errorf("expected an opaque page token, got %q", token)
+		}
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("expected every quote once, got %v", ids)
+	}
+	if gotReq.AfterID != 4 || gotReq.Limit != 2 {
+		t.Errorf("expected the last page to follow ID 4, got %+v", gotReq)
+	}
+
+	for _, token := range []string{"not base64!", encodePageToken(0), "YWJj"} {
+		if _, err := client.ListQuotes(ctx, &quotesv1.ListQuotesRequest{PageToken: token}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("token %q: expected INVALID_ARGUMENT, got %v", token, err)
+		}
+	}
+}
+
+func TestQuoteServer_StreamQuotes(t *testing.T) {
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		ExportQuotesFunc: func(fn func(*domain.Quote) error) error {
+			for _, quote := range quotesUpTo(5) {
+				if err := fn(quote); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}))
+
+	stream, err := client.StreamQuotes(testContext(t), &quotesv1.StreamQuotesRequest{Author: "Seneca"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []int64
+	for {
+		quote, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, quote.Id)
+	}
+	if fmt.Sprint(ids) != "[1 3 5]" {
+		t.Errorf("expected the quotes by Seneca, got %v", ids)
+	}
+}
+
+func TestQuoteServer_RandomDeleteSearch(t *testing.T) {
+	var gotPrincipal *domain.Principal
+	var gotInfo domain.RequestInfo
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		GetRandomQuoteFunc: func() (*domain.Quote, error) {
+			return nil, domain.ErrNoQuotesFound
+		},
+		DeleteQuoteFunc: func(ctx context.Context, id, version int) error {
+			gotPrincipal = domain.PrincipalFromContext(ctx)
+			gotInfo = domain.RequestInfoFromContext(ctx)
+			if version != 0 && version != 2 {
+				return &domain.VersionConflictError{Current: &domain.Quote{ID: id, Version: 2}}
+			}
+			return nil
+		},
+		SearchQuotesFunc: func(query string, limit int) ([]*domain.Quote, error) {
+			if strings.TrimSpace(query) == "" {
+				return nil, domain.ErrInvalidQuery
+			}
+			return quotesUpTo(limit), nil
+		},
+	}))
+	ctx := testContext(t)
+
+	if _, err := client.GetRandomQuote(ctx, &quotesv1.GetRandomQuoteRequest{}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NOT_FOUND from an empty collection, got %v", err)
+	}
+
+	var header metadata.MD
+	authCtx := testContext(t, "authorization", "Bearer "+testKey, requestIDKey, "req-7")
+	if _, err := client.DeleteQuote(authCtx, &quotesv1.DeleteQuoteRequest{Id: 4, Version: 2}, grpc.Header(&header)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPrincipal == nil || gotPrincipal.Name != "svc" {
+		t.Errorf("expected the delete to be made by svc, got %+v", gotPrincipal)
+	}
+	if gotInfo.ID != "req-7" || gotInfo.ClientIP == "" {
+		t.Errorf("expected the request info to be recorded, got %+v", gotInfo)
+	}
+	if ids := header.Get(requestIDKey); len(ids) != 1 || ids[0] != "req-7" {
+		t.Errorf("expected the request ID to be echoed, got %v", ids)
+	}
+
+	if _, err := client.DeleteQuote(ctx, &quotesv1.DeleteQuoteRequest{Id: 4, Version: 1}); status.Code(err) != codes.Aborted {
+		t.Errorf("expected ABORTED on a version conflict, got %v", err)
+	}
+	if gotPrincipal != nil {
+		t.Errorf("expected an anonymous call, got %+v", gotPrincipal)
+	}
+
+	resp, err := client.SearchQuotes(ctx, &quotesv1.SearchQuotesRequest{Query: "quote", PageSize: 3})
+	if err != nil || len(resp.Quotes) != 3 {
+		t.Errorf("expected 3 results, got %v (%v)", resp, err)
+	}
+	if _, err = client.SearchQuotes(ctx, &quotesv1.SearchQuotesRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected INVALID_ARGUMENT for an empty query, got %v", err)
+	}
+}
+
+func TestAuthInterceptor_RejectsUnknownKeys(t *testing.T) {
+	called := false
+	conn := newTestServer(t, &MockQuoteUseCase{
+		GetRandomQuoteFunc: func() (*domain.Quote, error) {
+			called = true
+			return &domain.Quote{ID: 1}, nil
+		},
+		ExportQuotesFunc: func(fn func(*domain.Quote) error) error {
+			called = true
+			return nil
+		},
+	})
+	client := quotesv1.NewQuoteServiceClient(conn)
+
+	ctx := testContext(t, "x-api-key", "guess")
+	if _, err := client.GetRandomQuote(ctx, &quotesv1.GetRandomQuoteRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected UNAUTHENTICATED, got %v", err)
+	}
+	stream, err := client.StreamQuotes(ctx, &quotesv1.StreamQuotesRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected UNAUTHENTICATED for the stream, got %v", err)
+	}
+	if called {
+		t.Error("expected the use case not to be called")
+	}
+}
+
+func TestNewServer_HealthAndReflection(t *testing.T) {
+	conn := newTestServer(t, &MockQuoteUseCase{})
+	ctx := testContext(t)
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: quotesv1.QuoteService_ServiceDesc.ServiceName})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected QuoteService to be SERVING, got %v (%v)", resp, err)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reply, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var services []string
+	for _, service := range reply.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	if list := strings.Join(services, ","); !strings.Contains(list, "quotes.v1.QuoteService") || !strings.Contains(list, "grpc.health.v1.Health") {
+		t.Errorf("expected QuoteService and Health to be listed, got %v", services)
+	}
+}
+
+func TestMetricsInterceptor_CountsCalls(t *testing.T) {
+	client := quotesv1.NewQuoteServiceClient(newTestServer(t, &MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			return nil, domain.ErrQuoteNotFound
+		},
+	}))
+
+	key := quotesv1.QuoteService_GetQuote_FullMethodName + " NotFound"
+	before := int64(0)
+	if v := rpcCalls.Get(key); v != nil {
+		fmt.Sscan(v.String(), &before)
+	}
+	client.GetQuote(testContext(t), &quotesv1.GetQuoteRequest{Id: 1})
+
+	v := rpcCalls.Get(key)
+	if v == nil || v.String() != fmt.Sprint(before+1) {
+		t.Errorf("expected %s to be counted once more than %d, got %v", key, before, v)
+	}
+	if rpcSeconds.Get(quotesv1.QuoteService_GetQuote_FullMethodName) == nil {
+		t.Error("expected the call duration to be recorded")
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{domain.ErrQuoteNotFound, codes.NotFound},
+		{fmt.Errorf("wrapped: %w", domain.ErrNoQuotesFound), codes.NotFound},
+		{domain.ErrInvalidID, codes.InvalidArgument},
+		{domain.ErrInvalidVersion, codes.InvalidArgument},
+		{domain.ErrInvalidPageToken, codes.InvalidArgument},
+		{&domain.VersionConflictError{}, codes.Aborted},
+		{domain.ErrInvalidAPIKey, codes.Unauthenticated},
+		{domain.ErrForbidden, codes.PermissionDenied},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{errors.New("pq: connection refused"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if code := status.Code(statusError(tt.err, "failed")); code != tt.expected {
+			t.Errorf("%v: expected %s, got %s", tt.err, tt.expected, code)
+		}
+	}
+
+	if msg := status.Convert(statusError(errors.New("pq: connection refused"), domain.MsgFailedGetQuotes)).Message(); msg != domain.MsgFailedGetQuotes {
+		t.Errorf("expected internal errors to be hidden, got %q", msg)
+	}
+}
+
+func TestMultiplex(t *testing.T) {
+	keys, _ := auth.ParseKeys(nil)
+	grpcServer := NewServer(&MockQuoteUseCase{
+		GetRandomQuoteFunc: func() (*domain.Quote, error) {
+			return &domain.Quote{ID: 5, Author: "Seneca"}, nil
+		},
+	}, keys)
+	t.Cleanup(grpcServer.Stop)
+
+	rest := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "rest")
+	})
+	srv := httptest.NewUnstartedServer(Multiplex(grpcServer, rest))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(srv.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	quote, err := quotesv1.NewQuoteServiceClient(conn).GetRandomQuote(testContext(t), &quotesv1.GetRandomQuoteRequest{})
+	if err != nil || quote.Id != 5 {
+		t.Errorf("expected quote 5 over the shared port, got %v (%v)", quote, err)
+	}
+
+	resp, err := http.Get(srv.URL + "/quotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "rest" {
+		t.Errorf("expected plain HTTP to reach the REST handler, got %q", body)
+	}
+}
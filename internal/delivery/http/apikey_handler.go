@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// writeAPIKeyError maps errors from the API key use cases to responses
+func (h *QuoteHandler) writeAPIKeyError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAPIKeyRequest):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		h.writeError(w, http.StatusNotFound, domain.MsgAPIKeyNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		h.writeError(w, http.StatusForbidden, domain.MsgAdminRequired)
+	default:
+		h.writeError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetAPIKeys GET /admin/keys
+func (h *QuoteHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.quoteUseCase.GetAPIKeys(r.Context())
+	if err != nil {
+		h.writeAPIKeyError(w, err, domain.MsgFailedGetAPIKeys)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, keys)
+}
+
+// CreateAPIKey POST /admin/keys
+func (h *QuoteHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req domain.APIKeyRequest
+	if rerr := decodeJSON(w, r, &req, h.maxBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	key, err := h.quoteUseCase.CreateAPIKey(r.Context(), &req)
+	if err != nil {
+		h.writeAPIKeyError(w, err, domain.MsgFailedCreateAPIKey)
+		return
+	}
+
+	w.Header().Set("Location", basePath(r)+"/admin/keys/"+strconv.Itoa(key.ID))
+	h.writeJSON(w, http.StatusCreated, key)
+}
+
+// RevokeAPIKey DELETE /admin/keys/{id}
+func (h *QuoteHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = domain.ErrAPIKeyNotFound
+	} else {
+		err = h.quoteUseCase.RevokeAPIKey(r.Context(), id)
+	}
+	if err != nil {
+		h.writeAPIKeyError(w, err, domain.MsgFailedRevokeAPIKey)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *QuoteHandler) registerAPIKeyRoutes(mux Router) {
+	mux.HandleFunc("/admin/keys", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetAPIKeys(w, r)
+		case http.MethodPost:
+			h.CreateAPIKey(w, r)
+		default:
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/admin/keys/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			h.RevokeAPIKey(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
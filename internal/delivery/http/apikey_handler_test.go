@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_CreateAPIKey(t *testing.T) {
+	var gotReq *domain.APIKeyRequest
+	mux := newRevisionMux(&MockQuoteUseCase{
+		CreateAPIKeyFunc: func(req *domain.APIKeyRequest) (*domain.APIKey, error) {
+			gotReq = req
+			if req.Role == "root" {
+				return nil, domain.ErrInvalidAPIKeyRequest
+			}
+			return &domain.APIKey{ID: 3, Name: req.Name, Role: req.Role, Key: "qk_new"}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodPost, "/admin/keys", []byte(`{"name": "ci", "role": "admin"}`), nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if loc := rec.Header().Get("Location"); loc != "/admin/keys/3" {
+		t.Errorf("expected Location /admin/keys/3, got %q", loc)
+	}
+	if gotReq.Name != "ci" || gotReq.Role != domain.RoleAdmin {
+		t.Errorf("expected the requested name and role, got %+v", gotReq)
+	}
+
+	var key domain.APIKey
+	if err := json.NewDecoder(rec.Body).Decode(&key); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if key.Key != "qk_new" {
+		t.Errorf("expected the key in the create response, got %+v", key)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid request", body: `{"name": "ci", "role": "root"}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"label": "ci"}`, expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(mux, http.MethodPost, "/admin/keys", []byte(tt.body), nil); rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_APIKeys_Errors(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetAPIKeysFunc: func() ([]*domain.APIKey, error) {
+			return nil, domain.ErrForbidden
+		},
+		RevokeAPIKeyFunc: func(id int) error {
+			if id != 1 {
+				return domain.ErrAPIKeyNotFound
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{http.MethodGet, "/admin/keys", http.StatusForbidden},
+		{http.MethodDelete, "/admin/keys/1", http.StatusNoContent},
+		{http.MethodDelete, "/admin/keys/2", http.StatusNotFound},
+		{http.MethodDelete, "/admin/keys/abc", http.StatusNotFound},
+		{http.MethodGet, "/admin/keys/1", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, tt.method, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
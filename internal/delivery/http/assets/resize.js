@@ -0,0 +1,7 @@
+(function () {
+  function post() {
+    parent.postMessage({ type: "quotes-widget:resize", height: document.documentElement.scrollHeight }, "*");
+  }
+  addEventListener("load", post);
+  addEventListener("resize", post);
+})();
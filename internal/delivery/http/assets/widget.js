@@ -0,0 +1,62 @@
+// Quotes widget: replaces every <div class="quotes-widget"> with an iframe showing a quote.
+//
+//   <div class="quotes-widget" data-quote="daily" data-theme="dark"></div>
+//   <script async src="https://quotes.example.com/embed/widget.js"></script>
+//
+// data-quote is "random", "daily" or a quote ID; data-theme and data-width are optional.
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  if (!script) {
+    return;
+  }
+  var origin = new URL(script.src).origin;
+  var frames = [];
+
+  function mount(el) {
+    if (el.getAttribute("data-quotes-mounted")) {
+      return;
+    }
+    var quote = el.getAttribute("data-quote") || "random";
+    if (!/^(random|daily|[1-9][0-9]*)$/.test(quote)) {
+      return;
+    }
+
+    var src = origin + "/embed/quotes/" + quote;
+    var theme = el.getAttribute("data-theme");
+    if (theme) {
+      src += "?theme=" + encodeURIComponent(theme);
+    }
+    var width = parseInt(el.getAttribute("data-width"), 10) || 500;
+
+    var frame = document.createElement("iframe");
+    frame.src = src;
+    frame.title = "Quote";
+    frame.loading = "lazy";
+    // Without allow-same-origin the quote page cannot touch the host page or its cookies
+    frame.setAttribute("sandbox", "allow-scripts allow-popups");
+    frame.style.cssText = "border:0;display:block;width:100%;max-width:" + width + "px;height:200px";
+
+    el.setAttribute("data-quotes-mounted", "1");
+    el.appendChild(frame);
+    frames.push(frame);
+  }
+
+  window.addEventListener("message", function (event) {
+    var data = event.data;
+    if (!data || data.type !== "quotes-widget:resize" || typeof data.height !== "number") {
+      return;
+    }
+    for (var i = 0; i < frames.length; i++) {
+      if (frames[i].contentWindow === event.source) {
+        frames[i].style.height = Math.min(Math.max(data.height, 50), 2000) + "px";
+      }
+    }
+  });
+
+  var widgets = document.querySelectorAll(".quotes-widget");
+  for (var i = 0; i < widgets.length; i++) {
+    mount(widgets[i]);
+  }
+})();
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// parseAuditFilter reads the audit filter from actor, action, quote_id, request_id,
+// since and until (RFC 3339), limit and cursor
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor:     query.Get("actor"),
+		Action:    domain.AuditAction(query.Get("action")),
+		RequestID: query.Get("request_id"),
+	}
+
+	var err error
+	if filter.QuoteID, err = parseIntQuery(r, "quote_id"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = parseIntQuery(r, "limit"); err != nil {
+		return filter, err
+	}
+	cursor, err := parseIntQuery(r, "cursor")
+	if err != nil {
+		return filter, err
+	}
+	filter.BeforeID = int64(cursor)
+	if filter.Since, err = parseTimeQuery(r, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseTimeQuery(r, "until"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseIntQuery(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errors.New("invalid value for " + name + ": expected an integer")
+	}
+	return value, nil
+}
+
+func parseTimeQuery(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errors.New("invalid value for " + name + ": expected an RFC 3339 time")
+	}
+	return value, nil
+}
+
+// writeAuditError maps errors from the audit use cases to responses
+func (h *QuoteHandler) writeAuditError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAuditFilter):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		h.writeError(w, http.StatusForbidden, domain.MsgAdminRequired)
+	default:
+		h.writeError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetAuditEvents GET /admin/audit?actor=&action=&quote_id=&request_id=&since=&until=&limit=&cursor=
+func (h *QuoteHandler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.quoteUseCase.GetAuditEvents(r.Context(), filter)
+	if err != nil {
+		h.writeAuditError(w, err, domain.MsgFailedGetAudit)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, page)
+}
+
+// ExportAuditEvents GET /admin/audit/export streams the matching events as NDJSON, oldest first
+func (h *QuoteHandler) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Large exports outlive the server WriteTimeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	enc := json.NewEncoder(w)
+	exported := 0
+	err = h.quoteUseCase.ExportAuditEvents(r.Context(), filter, func(event *domain.AuditEvent) error {
+		if exported == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+		}
+		exported++
+		return enc.Encode(event)
+	})
+	if err != nil {
+		if exported == 0 {
+			h.writeAuditError(w, err, domain.MsgFailedGetAudit)
+			return
+		}
+		// The status line is already sent, so the truncated body is all the client gets
+		log.Printf("audit export aborted after %d events: %v", exported, err)
+		return
+	}
+
+	if exported == 0 {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// VerifyAuditLog GET /admin/audit/verify
+func (h *QuoteHandler) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	result, err := h.quoteUseCase.VerifyAuditLog(r.Context())
+	if err != nil {
+		h.writeAuditError(w, err, domain.MsgFailedVerifyAudit)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *QuoteHandler) registerAuditRoutes(mux Router) {
+	routes := map[string]http.HandlerFunc{
+		"/admin/audit":        h.GetAuditEvents,
+		"/admin/audit/export": h.ExportAuditEvents,
+		"/admin/audit/verify": h.VerifyAuditLog,
+	}
+	for pattern, handle := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				handle(w, r)
+			} else {
+				h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetAuditEvents(t *testing.T) {
+	var gotFilter domain.AuditFilter
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetAuditEventsFunc: func(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+			gotFilter = filter
+			return &domain.AuditPage{
+				Events:     []*domain.AuditEvent{{ID: 9, Action: domain.ActionQuoteDelete, Actor: "alice", QuoteID: 3}},
+				NextCursor: 9,
+			}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet,
+		"/admin/audit?actor=alice&action=quote.delete&quote_id=3&request_id=r1&since=2024-03-01T00:00:00Z&until=2024-04-01T00:00:00%2B03:00&limit=1&cursor=12", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	want := domain.AuditFilter{
+		Actor:     "alice",
+		Action:    domain.ActionQuoteDelete,
+		QuoteID:   3,
+		RequestID: "r1",
+		Since:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Until:     time.Date(2024, 3, 31, 21, 0, 0, 0, time.UTC),
+		BeforeID:  12,
+		Limit:     1,
+	}
+	if gotFilter.Actor != want.Actor || gotFilter.Action != want.Action || gotFilter.QuoteID != want.QuoteID ||
+		gotFilter.RequestID != want.RequestID || !gotFilter.Since.Equal(want.Since) || !gotFilter.Until.Equal(want.Until) ||
+		gotFilter.BeforeID != want.BeforeID || gotFilter.Limit != want.Limit {
+		t.Errorf("expected filter %+v, got %+v", want, gotFilter)
+	}
+
+	var page domain.AuditPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Events) != 1 || page.NextCursor != 9 {
+		t.Errorf("expected one event and cursor 9, got %+v", page)
+	}
+}
+
+func TestQuoteHandler_GetAuditEvents_Errors(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetAuditEventsFunc: func(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+			switch {
+			case filter.Actor == "bob":
+				return nil, domain.ErrForbidden
+			case filter.Limit > 500:
+				return nil, domain.ErrInvalidAuditFilter
+			}
+			return nil, errors.New("connection refused")
+		},
+	})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/admin/audit?since=yesterday", http.StatusBadRequest},
+		{"/admin/audit?quote_id=x", http.StatusBadRequest},
+		{"/admin/audit?limit=1000", http.StatusBadRequest},
+		{"/admin/audit?actor=bob", http.StatusForbidden},
+		{"/admin/audit", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, http.MethodGet, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
+
+func TestQuoteHandler_ExportAuditEvents(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		ExportAuditEventsFunc: func(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+			if filter.Actor == "bob" {
+				return domain.ErrForbidden
+			}
+			for id := int64(1); id <= 3; id++ {
+				if err := fn(&domain.AuditEvent{ID: id, Action: domain.ActionQuoteCreate, Actor: filter.Actor}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/admin/audit/export?actor=alice", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected NDJSON, got %q", ct)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), rec.Body.String())
+	}
+	var event domain.AuditEvent
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil || event.ID != 3 || event.Actor != "alice" {
+		t.Errorf("expected the third event by alice, got %+v (%v)", event, err)
+	}
+
+	rec = serve(mux, http.MethodGet, "/admin/audit/export?actor=bob", nil, nil)
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Content-Disposition") != "" {
+		t.Errorf("expected a plain 403, got %d with %q", rec.Code, rec.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestQuoteHandler_VerifyAuditLog(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		VerifyAuditLogFunc: func(ctx context.Context) (*domain.AuditVerification, error) {
+			return &domain.AuditVerification{Valid: false, Checked: 10, BrokenAt: 4, LastHash: "abc"}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/admin/audit/verify", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	var result domain.AuditVerification
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Valid || result.BrokenAt != 4 {
+		t.Errorf("expected a broken chain at 4, got %+v", result)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DefaultMaxBatchBodyBytes caps batch request bodies when no limit is configured
+const DefaultMaxBatchBodyBytes int64 = 10 << 20
+
+// WithMaxBatchBodyBytes limits the size of batch request bodies
+func WithMaxBatchBodyBytes(n int64) Option {
+	return func(h *QuoteHandler) {
+		h.maxBatchBodyBytes = n
+	}
+}
+
+// CreateQuotes POST /quotes:batch?atomic=true|false
+// Accepts a JSON array or NDJSON (application/x-ndjson) of quotes.
+func (h *QuoteHandler) CreateQuotes(w http.ResponseWriter, r *http.Request) {
+	atomic, err := parseBoolQuery(r, "atomic", true)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mediaType, rerr := requireContentType(r, "application/json", "application/x-ndjson")
+	if rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	var items []*domain.CreateQuoteRequest
+	if mediaType == "application/x-ndjson" {
+		items, rerr = decodeNDJSON[*domain.CreateQuoteRequest](w, r, h.maxBatchBodyBytes)
+	} else {
+		rerr = decodeJSONBody(w, r, &items, h.maxBatchBodyBytes)
+	}
+	if rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	result, err := h.quoteUseCase.CreateQuotes(r.Context(), &domain.BatchCreateRequest{Quotes: items, Atomic: atomic})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrBatchRejected):
+			h.writeJSON(w, http.StatusUnprocessableEntity, result)
+		case errors.Is(err, domain.ErrEmptyBatch), errors.Is(err, domain.ErrBatchTooLarge):
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedCreateQuotes)
+		}
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	h.writeJSON(w, status, result)
+}
+
+// DeleteQuotes DELETE /quotes?dry_run=true|false
+// The body selects quotes either by {"ids": [...]} or by {"author": "..."}.
+func (h *QuoteHandler) DeleteQuotes(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseBoolQuery(r, "dry_run", false)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var filter domain.DeleteFilter
+	if rerr := decodeJSON(w, r, &filter, h.maxBatchBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	result, err := h.quoteUseCase.DeleteQuotes(r.Context(), &domain.BatchDeleteRequest{Filter: filter, DryRun: dryRun})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDeleteFilter), errors.Is(err, domain.ErrInvalidID), errors.Is(err, domain.ErrBatchTooLarge):
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedDeleteQuotes)
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func parseBoolQuery(r *http.Request, name string, defaultValue bool) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.New("invalid value for " + name + ": expected true or false")
+	}
+	return value, nil
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_CreateQuotes(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		contentType    string
+		body           string
+		mockFunc       func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error)
+		expectedStatus int
+		expectedItems  int
+		expectedAtomic bool
+	}{
+		{
+			name:        "JSON array",
+			url:         "/quotes:batch",
+			contentType: "application/json",
+			body:        `[{"author":"A","quote":"Q1"},{"author":"B","quote":"Q2"}]`,
+			mockFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+				return &domain.BatchCreateResult{Atomic: req.Atomic, Created: len(req.Quotes)}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedItems:  2,
+			expectedAtomic: true,
+		},
+		{
+			name:        "NDJSON non-atomic",
+			url:         "/quotes:batch?atomic=false",
+			contentType: "application/x-ndjson",
+			body:        "{\"author\":\"A\",\"quote\":\"Q1\"}\n\n{\"author\":\"B\",\"quote\":\"Q2\"}\n{\"author\":\"C\",\"quote\":\"Q3\"}\n",
+			mockFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+				return &domain.BatchCreateResult{Created: 2, Failed: 1}, nil
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expectedItems:  3,
+		},
+		{
+			name:           "NDJSON unknown field reports line",
+			url:            "/quotes:batch",
+			contentType:    "application/x-ndjson",
+			body:           "{\"author\":\"A\",\"quote\":\"Q1\"}\n{\"autor\":\"B\",\"quote\":\"Q2\"}\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "NDJSON stray closing bracket",
+			url:            "/quotes:batch",
+			contentType:    "application/x-ndjson",
+			body:           "{\"author\":\"A\",\"quote\":\"Q1\"}]\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "NDJSON stray closing brace",
+			url:            "/quotes:batch",
+			contentType:    "application/x-ndjson",
+			body:           "{\"author\":\"A\",\"quote\":\"Q1\"}}\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid atomic flag",
+			url:            "/quotes:batch?atomic=maybe",
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported content type",
+			url:            "/quotes:batch",
+			contentType:    "text/csv",
+			body:           `author,quote`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "atomic batch rejected",
+			url:         "/quotes:batch",
+			contentType: "application/json",
+			body:        `[{"author":"","quote":"Q1"}]`,
+			mockFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+				return &domain.BatchCreateResult{Atomic: true, Failed: 1}, domain.ErrBatchRejected
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedItems:  1,
+			expectedAtomic: true,
+		},
+		{
+			name:        "batch too large",
+			url:         "/quotes:batch",
+			contentType: "application/json",
+			body:        `[{"author":"A","quote":"Q1"}]`,
+			mockFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+				return nil, domain.ErrBatchTooLarge
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  1,
+			expectedAtomic: true,
+		},
+		{
+			name:        "internal server error",
+			url:         "/quotes:batch",
+			contentType: "application/json",
+			body:        `[{"author":"A","quote":"Q1"}]`,
+			mockFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedItems:  1,
+			expectedAtomic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				CreateQuotesFunc: func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+					if len(req.Quotes) != tt.expectedItems {
+						t.Errorf("expected %d items, got %d", tt.expectedItems, len(req.Quotes))
+					}
+					if req.Atomic != tt.expectedAtomic {
+						t.Errorf("expected atomic %v, got %v", tt.expectedAtomic, req.Atomic)
+					}
+					if tt.mockFunc == nil {
+						t.Fatal("use case should not be called")
+					}
+					return tt.mockFunc(req)
+				},
+			}
+			handler := NewQuoteHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			handler.CreateQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_DeleteQuotes(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		mockFunc       func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error)
+		expectedStatus int
+	}{
+		{
+			name: "delete by IDs",
+			url:  "/quotes",
+			body: `{"ids":[1,2,3]}`,
+			mockFunc: func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+				if req.DryRun || len(req.Filter.IDs) != 3 {
+					t.Errorf("unexpected request: %+v", req)
+				}
+				return &domain.BatchDeleteResult{Deleted: 3, IDs: req.Filter.IDs}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "dry run by author",
+			url:  "/quotes?dry_run=true",
+			body: `{"author":"Confucius"}`,
+			mockFunc: func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+				if !req.DryRun || req.Filter.Author != "Confucius" {
+					t.Errorf("unexpected request: %+v", req)
+				}
+				return &domain.BatchDeleteResult{DryRun: true, Deleted: 1, IDs: []int{7}}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid filter",
+			url:  "/quotes",
+			body: `{}`,
+			mockFunc: func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+				return nil, domain.ErrInvalidDeleteFilter
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown filter field",
+			url:            "/quotes",
+			body:           `{"tag":"wisdom"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			url:  "/quotes",
+			body: `{"ids":[1]}`,
+			mockFunc: func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				DeleteQuotesFunc: tt.mockFunc,
+			}
+			handler := NewQuoteHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodDelete, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.DeleteQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
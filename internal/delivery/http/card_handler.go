@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/shoksin/quotes-service/internal/card"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DefaultCardCacheBytes bounds the memory used by rendered quote cards
+const DefaultCardCacheBytes = 64 << 20
+
+// cardCSP forbids everything but the inline styles and data: font of SVG cards,
+// so a card opened directly in a browser cannot run or load anything
+const cardCSP = "default-src 'none'; style-src 'unsafe-inline'; font-src data:"
+
+// WithCardCache replaces the default in-memory cache of rendered cards
+func WithCardCache(cache *card.Cache) Option {
+	return func(h *QuoteHandler) {
+		h.cards = cache
+	}
+}
+
+// GetQuoteCard GET /quotes/{id}/card.svg and /quotes/{id}/card.png with optional ?theme= and ?size=
+func (h *QuoteHandler) GetQuoteCard(w http.ResponseWriter, r *http.Request, format card.Format) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	opts, err := card.ParseOptions(r.URL.Query().Get("theme"), r.URL.Query().Get("size"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := h.quoteUseCase.GetQuote(id)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetQuotes)
+		return
+	}
+
+	key := card.KeyFor(quote, format, opts)
+	data, ok := h.cards.Get(key)
+	if !ok {
+		var buf bytes.Buffer
+		if err = card.Render(&buf, format, quote, opts); err != nil {
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+			return
+		}
+		data = buf.Bytes()
+		h.cards.Add(key, data)
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Security-Policy", cardCSP)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if notModified(w, r, strongETag(data), quote.UpdatedAt) {
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+func (h *QuoteHandler) registerCardRoutes(mux Router) {
+	for _, format := range []card.Format{card.FormatSVG, card.FormatPNG} {
+		mux.HandleFunc("/quotes/{id}/card."+string(format), func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				h.GetQuoteCard(w, r, format)
+			} else {
+				h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/card"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func cardQuote() *domain.Quote {
+	return &domain.Quote{
+		ID:        1,
+		Author:    "Seneca",
+		Quote:     "Luck is what happens when preparation meets opportunity.",
+		CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestQuoteHandler_GetQuoteCard(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		mockFunc       func(id int) (*domain.Quote, error)
+		expectedStatus int
+		expectedType   string
+	}{
+		{
+			name:           "svg",
+			path:           "/quotes/1/card.svg",
+			mockFunc:       func(id int) (*domain.Quote, error) { return cardQuote(), nil },
+			expectedStatus: http.StatusOK,
+			expectedType:   "image/svg+xml",
+		},
+		{
+			name:           "png with theme and size",
+			path:           "/quotes/1/card.png?theme=dark&size=square",
+			mockFunc:       func(id int) (*domain.Quote, error) { return cardQuote(), nil },
+			expectedStatus: http.StatusOK,
+			expectedType:   "image/png",
+		},
+		{
+			name:           "unknown theme",
+			path:           "/quotes/1/card.png?theme=neon",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown size",
+			path:           "/quotes/1/card.svg?size=a4",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			path:           "/quotes/abc/card.svg",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "quote not found",
+			path:           "/quotes/999/card.svg",
+			mockFunc:       func(id int) (*domain.Quote, error) { return nil, domain.ErrQuoteNotFound },
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewQuoteHandler(&MockQuoteUseCase{GetQuoteFunc: tt.mockFunc}).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.expectedType {
+				t.Errorf("expected Content-Type %s, got %s", tt.expectedType, ct)
+			}
+			if lm := rec.Header().Get("Last-Modified"); lm != "Sat, 02 Mar 2024 09:00:00 GMT" {
+				t.Errorf("expected Last-Modified from updated_at, got %q", lm)
+			}
+			if rec.Header().Get("Content-Security-Policy") == "" {
+				t.Error("expected a Content-Security-Policy header")
+			}
+			if tt.expectedType == "image/png" {
+				img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+				if err != nil {
+					t.Fatalf("response is not a PNG: %v", err)
+				}
+				if img.Bounds().Dx() != 1080 || img.Bounds().Dy() != 1080 {
+					t.Errorf("expected a square card, got %v", img.Bounds())
+				}
+			} else if !strings.Contains(rec.Body.String(), "Seneca") {
+				t.Error("expected the author on the card")
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_GetQuoteCard_Cache(t *testing.T) {
+	quote := cardQuote()
+	cache := card.NewCache(DefaultCardCacheBytes)
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			q := *quote
+			return &q, nil
+		},
+	}, WithCardCache(cache)).RegisterRoutes(mux)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1/card.png", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	if cache.Len() != 1 {
+		t.Fatalf("expected the rendered card to be cached, got %d entries", cache.Len())
+	}
+	if second := get("", ""); !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) || cache.Len() != 1 {
+		t.Error("expected the cached card to be served")
+	}
+
+	if rec := get("If-None-Match", first.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for a matching ETag, got %d", rec.Code)
+	}
+
+	quote.UpdatedAt = quote.UpdatedAt.Add(time.Hour)
+	quote.Quote = "Edited text"
+	if rec := get("", ""); bytes.Equal(first.Body.Bytes(), rec.Body.Bytes()) || cache.Len() != 2 {
+		t.Error("expected an updated quote to be rendered again")
+	}
+}
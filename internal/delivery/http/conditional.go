@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// strongETag returns a quoted entity tag derived from the representation bytes
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// hashETag returns a quoted entity tag derived from the given validator parts
+func hashETag(parts ...string) string {
+	return strongETag([]byte(strings.Join(parts, "\x00")))
+}
+
+// quoteETag identifies one representation of a quote; it changes whenever the quote is updated
+func quoteETag(quote *domain.Quote, contentType string) string {
+	return hashETag(fmt.Sprint(quote.ID), fmt.Sprint(quote.Version), fmt.Sprint(quote.UpdatedAt.UnixNano()), contentType)
+}
+
+// listETag identifies one representation of a list of quotes at a collection version, as
+// served by one API version
+func listETag(version *domain.CollectionVersion, api APIVersion, contentType, query string) string {
+	return hashETag(fmt.Sprint(version.Version), string(api), contentType, query)
+}
+
+// ifMatch reports whether the If-Match header, if any, matches one of the current entity tags.
+// Unlike If-None-Match it uses the strong comparison, so weak tags never match.
+func ifMatch(r *http.Request, etags ...string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		for _, etag := range etags {
+			if candidate == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notModified sets the validators of the representation and reports whether the request's
+// preconditions allow a 304 response, in which case it has already been written.
+// If-None-Match takes precedence over If-Modified-Since (RFC 9110, section 13.2.2).
+func notModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag) {
+			return false
+		}
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		// Last-Modified only has second precision
+		if err != nil || lastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	// A 304 must not carry representation metadata other than the validators
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison If-None-Match requires to a comma-separated list
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+type conditionalStore struct {
+	quote   *domain.Quote
+	version domain.CollectionVersion
+	deleted bool
+}
+
+func newConditionalMux(store *conditionalStore) *http.ServeMux {
+	mockUseCase := &MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			if id != store.quote.ID || store.deleted {
+				return nil, domain.ErrQuoteNotFound
+			}
+			q := *store.quote
+			return &q, nil
+		},
+		UpdateQuoteFunc: func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+			if req.Version != 0 && req.Version != store.quote.Version {
+				q := *store.quote
+				return nil, &domain.VersionConflictError{Current: &q}
+			}
+			store.quote.Author, store.quote.Quote = req.Author, req.Quote
+			store.quote.UpdatedAt = store.quote.UpdatedAt.Add(time.Minute)
+			store.quote.Version++
+			store.version.Version++
+			q := *store.quote
+			return &q, nil
+		},
+		DeleteQuoteFunc: func(id, version int) error {
+			if version != 0 && version != store.quote.Version {
+				q := *store.quote
+				return &domain.VersionConflictError{Current: &q}
+			}
+			store.deleted = true
+			store.version.Version++
+			return nil
+		},
+		GetAllQuotesFunc: func() ([]*domain.Quote, error) {
+			return []*domain.Quote{store.quote}, nil
+		},
+		GetQuotesVersionFunc: func() (*domain.CollectionVersion, error) {
+			v := store.version
+			return &v, nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+	return mux
+}
+
+func newConditionalStore() *conditionalStore {
+	return &conditionalStore{
+		quote: &domain.Quote{
+			ID:        1,
+			Author:    "Seneca",
+			Quote:     "Luck is what happens when preparation meets opportunity.",
+			CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 3, 2, 9, 0, 0, 123, time.UTC),
+			Version:   1,
+		},
+		version: domain.CollectionVersion{Version: 7, UpdatedAt: time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)},
+	}
+}
+
+func serve(mux *http.ServeMux, method, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestQuoteHandler_GetQuote_Conditional(t *testing.T) {
+	mux := newConditionalMux(newConditionalStore())
+
+	first := serve(mux, http.MethodGet, "/quotes/1", nil, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" || first.Header().Get("Last-Modified") != "Sat, 02 Mar 2024 09:00:00 GMT" {
+		t.Fatalf("expected validators, got %v", first.Header())
+	}
+
+	text := serve(mux, http.MethodGet, "/quotes/1", nil, map[string]string{"Accept": "text/plain"})
+	if text.Header().Get("ETag") == etag {
+		t.Error("expected each representation to have its own strong ETag")
+	}
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{name: "matching etag", headers: map[string]string{"If-None-Match": etag}, expectedStatus: http.StatusNotModified},
+		{name: "other representation etag", headers: map[string]string{"If-None-Match": text.Header().Get("ETag")}, expectedStatus: http.StatusOK},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Sat, 02 Mar 2024 09:00:00 GMT"}, expectedStatus: http.StatusNotModified},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Sat, 02 Mar 2024 08:00:00 GMT"}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(mux, http.MethodGet, "/quotes/1", nil, tt.headers)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+
+	if rec := serve(mux, http.MethodGet, "/quotes/2", nil, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing quote, got %d", rec.Code)
+	}
+	if rec := serve(mux, http.MethodGet, "/quotes/abc", nil, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid ID, got %d", rec.Code)
+	}
+}
+
+func TestQuoteHandler_GetQuotes_Conditional(t *testing.T) {
+	store := newConditionalStore()
+	mux := newConditionalMux(store)
+
+	first := serve(mux, http.MethodGet, "/quotes", nil, nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" || first.Header().Get("Last-Modified") != "Sun, 03 Mar 2024 09:00:00 GMT" {
+		t.Fatalf("expected validators from the collection version, got %v", first.Header())
+	}
+
+	if rec := serve(mux, http.MethodGet, "/quotes", nil, map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for an unchanged collection, got %d", rec.Code)
+	}
+	if rec := serve(mux, http.MethodGet, "/quotes?author=Seneca", nil, map[string]string{"If-None-Match": etag}); rec.Code == http.StatusNotModified {
+		t.Error("expected filtered lists to have their own ETag")
+	}
+
+	store.version.Version++
+	if rec := serve(mux, http.MethodGet, "/quotes", nil, map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after the collection changed, got %d", rec.Code)
+	}
+}
+
+func TestQuoteHandler_UpdateQuote(t *testing.T) {
+	store := newConditionalStore()
+	mux := newConditionalMux(store)
+	etag := serve(mux, http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag")
+	textETag := serve(mux, http.MethodGet, "/quotes/1", nil, map[string]string{"Accept": "text/plain"}).Header().Get("ETag")
+	body, _ := json.Marshal(domain.CreateQuoteRequest{Author: "Seneca", Quote: "Edited"})
+
+	if rec := serve(mux, http.MethodPut, "/quotes/1", body, map[string]string{"If-Match": `"stale"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale ETag, got %d", rec.Code)
+	}
+	if store.quote.Quote == "Edited" {
+		t.Fatal("quote must not change when the precondition fails")
+	}
+	if rec := serve(mux, http.MethodPut, "/quotes/1", body, map[string]string{"If-Match": "W/" + etag}); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected weak ETags never to match If-Match, got %d", rec.Code)
+	}
+
+	rec := serve(mux, http.MethodPut, "/quotes/1", body, map[string]string{"If-Match": textETag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	newETag := rec.Header().Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Errorf("expected a new ETag after the update, got %q", newETag)
+	}
+	if got := serve(mux, http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag"); got != newETag {
+		t.Errorf("expected the PUT ETag to match the JSON representation, got %s and %s", newETag, got)
+	}
+
+	if rec = serve(mux, http.MethodPut, "/quotes/1", body, map[string]string{"If-Match": etag}); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected the old ETag to be rejected, got %d", rec.Code)
+	}
+	if rec = serve(mux, http.MethodPut, "/quotes/1", body, nil); rec.Code != http.StatusOK {
+		t.Errorf("expected unconditional updates to succeed, got %d", rec.Code)
+	}
+
+	// A body version must agree with the If-Match one
+	etag = serve(mux, http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag")
+	stale, _ := json.Marshal(domain.UpdateQuoteRequest{
+		CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Stale"},
+		Version:            store.quote.Version - 1,
+	})
+	if rec = serve(mux, http.MethodPut, "/quotes/1", stale, map[string]string{"If-Match": etag}); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 when the body and If-Match versions disagree, got %d", rec.Code)
+	}
+	if store.quote.Quote == "Stale" {
+		t.Error("quote must not change when the versions disagree")
+	}
+	same, _ := json.Marshal(domain.UpdateQuoteRequest{
+		CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Same"},
+		Version:            store.quote.Version,
+	})
+	if rec = serve(mux, http.MethodPut, "/quotes/1", same, map[string]string{"If-Match": etag}); rec.Code != http.StatusOK {
+		t.Errorf("expected agreeing versions to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestQuoteHandler_UpdateQuote_Errors(t *testing.T) {
+	validation := &domain.ValidationError{Errors: []*domain.FieldError{
+		{Field: "author", Code: domain.CodeRequired, Message: "author is required", Err: domain.ErrInvalidAuthor},
+	}}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "validation error", path: "/quotes/1", body: `{"author":"","quote":"x"}`, mockErr: validation, expectedStatus: http.StatusBadRequest},
+		{name: "not found", path: "/quotes/9", body: `{"author":"a","quote":"x"}`, mockErr: domain.ErrQuoteNotFound, expectedStatus: http.StatusNotFound},
+		{name: "invalid id", path: "/quotes/abc", body: `{"author":"a","quote":"x"}`, expectedStatus: http.StatusBadRequest},
+		{name: "malformed body", path: "/quotes/1", body: `{"author":`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid version", path: "/quotes/1", body: `{"author":"a","quote":"x","version":-1}`, mockErr: domain.ErrInvalidVersion, expectedStatus: http.StatusBadRequest},
+		{name: "version conflict", path: "/quotes/1", body: `{"author":"a","quote":"x","version":1}`, mockErr: &domain.VersionConflictError{}, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				UpdateQuoteFunc: func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+					return nil, tt.mockErr
+				},
+			}
+			mux := http.NewServeMux()
+			NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+
+			rec := serve(mux, http.MethodPut, tt.path, []byte(tt.body), nil)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_DeleteQuote_IfMatch(t *testing.T) {
+	store := newConditionalStore()
+	mux := newConditionalMux(store)
+	etag := serve(mux, http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag")
+
+	if rec := serve(mux, http.MethodDelete, "/quotes/1", nil, map[string]string{"If-Match": `"stale"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale ETag, got %d", rec.Code)
+	}
+	if store.deleted {
+		t.Fatal("quote must not be deleted when the precondition fails")
+	}
+
+	if rec := serve(mux, http.MethodDelete, "/quotes/1", nil, map[string]string{"If-Match": etag}); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if rec := serve(mux, http.MethodDelete, "/quotes/1", nil, map[string]string{"If-Match": "*"}); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a deleted quote, got %d", rec.Code)
+	}
+}
+
+func TestQuoteHandler_UpdateQuote_VersionConflict(t *testing.T) {
+	store := newConditionalStore()
+	mux := newConditionalMux(store)
+
+	body, _ := json.Marshal(domain.UpdateQuoteRequest{
+		CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "First"},
+		Version:            1,
+	})
+	rec := serve(mux, http.MethodPut, "/quotes/1", body, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var updated domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("expected version 2 after the update, got %d", updated.Version)
+	}
+
+	// A second writer still holding version 1 loses
+	body, _ = json.Marshal(domain.UpdateQuoteRequest{
+		CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Second"},
+		Version:            1,
+	})
+	rec = serve(mux, http.MethodPut, "/quotes/1", body, nil)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Current == nil || resp.Current.Quote != "First" || resp.Current.Version != 2 {
+		t.Errorf("expected the current quote in the response, got %+v", resp.Current)
+	}
+	if got := serve(mux, http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag"); rec.Header().Get("ETag") != got {
+		t.Errorf("expected the 409 to carry the current ETag %s, got %s", got, rec.Header().Get("ETag"))
+	}
+	if store.quote.Quote != "First" {
+		t.Errorf("expected the conflicting update to be rejected, quote is %q", store.quote.Quote)
+	}
+}
+
+func TestQuoteHandler_IfMatch_PassesVersion(t *testing.T) {
+	store := newConditionalStore()
+	store.quote.Version = 5
+	etag := serve(newConditionalMux(store), http.MethodGet, "/quotes/1", nil, nil).Header().Get("ETag")
+
+	var updateVersion, deleteVersion int
+	mockUseCase := &MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			q := *store.quote
+			return &q, nil
+		},
+		UpdateQuoteFunc: func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+			updateVersion = req.Version
+			q := *store.quote
+			return &q, nil
+		},
+		DeleteQuoteFunc: func(id, version int) error {
+			deleteVersion = version
+			return nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+
+	body := []byte(`{"author":"Seneca","quote":"Edited"}`)
+	if rec := serve(mux, http.MethodPut, "/quotes/1", body, map[string]string{"If-Match": etag}); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if updateVersion != 5 {
+		t.Errorf("expected the update to be conditional on version 5, got %d", updateVersion)
+	}
+
+	if rec := serve(mux, http.MethodDelete, "/quotes/1", nil, map[string]string{"If-Match": etag}); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rec.Code)
+	}
+	if deleteVersion != 5 {
+		t.Errorf("expected the delete to be conditional on version 5, got %d", deleteVersion)
+	}
+
+	if rec := serve(mux, http.MethodDelete, "/quotes/1", nil, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rec.Code)
+	}
+	if deleteVersion != 0 {
+		t.Errorf("expected an unconditional delete without If-Match, got version %d", deleteVersion)
+	}
+}
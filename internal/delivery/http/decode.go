@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DefaultMaxBodyBytes caps request bodies when no limit is configured
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// RequestError is returned by decodeJSON and carries the HTTP status to reply with
+type RequestError struct {
+	Status  int
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+// requireContentType checks the request media type against the allowed list and returns it
+func requireContentType(r *http.Request, allowed ...string) (string, *RequestError) {
+	expected := strings.Join(allowed, " or ")
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return "", &RequestError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type header must be " + expected}
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && slices.Contains(allowed, mediaType) {
+		return mediaType, nil
+	}
+	return "", &RequestError{Status: http.StatusUnsupportedMediaType, Message: fmt.Sprintf("unsupported Content-Type %q, expected %s", contentType, expected)}
+}
+
+// decodeJSON strictly decodes a single JSON value from the request body into dst.
+// The body is capped at maxBytes, unknown fields and trailing data are rejected.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}, maxBytes int64) *RequestError {
+	if _, rerr := requireContentType(r, "application/json"); rerr != nil {
+		return rerr
+	}
+	return decodeJSONBody(w, r, dst, maxBytes)
+}
+
+// decodeJSONBody is decodeJSON without the Content-Type check
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}, maxBytes int64) *RequestError {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return translateDecodeError(err)
+	}
+
+	// Anything other than whitespace after the first value is an error
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return translateDecodeError(err)
+		}
+		return invalidJSON(fmt.Sprintf("unexpected data after JSON value at offset %d", dec.InputOffset()))
+	}
+
+	return nil
+}
+
+func translateDecodeError(err error) *RequestError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &RequestError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("request body must not exceed %d bytes", maxBytesErr.Limit)}
+	case errors.As(err, &syntaxErr):
+		return invalidJSON(fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidJSON("unexpected end of JSON input")
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return invalidJSON(fmt.Sprintf("field %q must be %s at offset %d", typeErr.Field, typeErr.Type, typeErr.Offset))
+		}
+		return invalidJSON(fmt.Sprintf("request body must be a JSON %s", jsonKind(typeErr.Type.Kind().String())))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return invalidJSON(fmt.Sprintf("unknown field %s", field))
+	case errors.Is(err, io.EOF):
+		return invalidJSON("request body must not be empty")
+	default:
+		return invalidJSON(err.Error())
+	}
+}
+
+// decodeNDJSON strictly decodes newline-delimited JSON values from the request body.
+// Blank lines are skipped; errors name the offending line.
+func decodeNDJSON[T any](w http.ResponseWriter, r *http.Request, maxBytes int64) ([]T, *RequestError) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, maxBytes))
+	scanner.Buffer(make([]byte, 0, 64*1024), int(maxBytes))
+
+	var items []T
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var item T
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&item); err != nil {
+			rerr := translateDecodeError(err)
+			rerr.Message = fmt.Sprintf("%s (line %d)", rerr.Message, line)
+			return nil, rerr
+		}
+		// More reports false before a stray '}' or ']', so look for the end of the line instead
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return nil, invalidJSON(fmt.Sprintf("unexpected data after JSON value at offset %d (line %d)", dec.InputOffset(), line))
+		}
+		items = append(items, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || errors.Is(err, bufio.ErrTooLong) {
+			return nil, &RequestError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("request body must not exceed %d bytes", maxBytes)}
+		}
+		return nil, invalidJSON(err.Error())
+	}
+
+	return items, nil
+}
+
+func jsonKind(goKind string) string {
+	switch goKind {
+	case "slice", "array":
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func invalidJSON(detail string) *RequestError {
+	return &RequestError{Status: http.StatusBadRequest, Message: domain.MsgInvalidJSON + ": " + detail}
+}
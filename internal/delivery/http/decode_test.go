@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		contentType     string
+		body            string
+		maxBytes        int64
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:        "valid body",
+			contentType: "application/json",
+			body:        `{"author":"Author","quote":"Quote"}`,
+		},
+		{
+			name:        "content type with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"author":"Author","quote":"Quote"}`,
+		},
+		{
+			name:            "missing content type",
+			body:            `{"author":"Author","quote":"Quote"}`,
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			expectedMessage: "Content-Type header must be application/json",
+		},
+		{
+			name:            "wrong content type",
+			contentType:     "text/plain",
+			body:            `{"author":"Author","quote":"Quote"}`,
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			expectedMessage: `unsupported Content-Type "text/plain", expected application/json`,
+		},
+		{
+			name:            "body too large",
+			contentType:     "application/json",
+			body:            `{"author":"Author","quote":"` + strings.Repeat("a", 100) + `"}`,
+			maxBytes:        32,
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+			expectedMessage: "request body must not exceed 32 bytes",
+		},
+		{
+			name:            "unknown field",
+			contentType:     "application/json",
+			body:            `{"autor":"Author","quote":"Quote"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + `: unknown field "autor"`,
+		},
+		{
+			name:            "trailing data",
+			contentType:     "application/json",
+			body:            `{"author":"Author","quote":"Quote"} {"x":1}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + ": unexpected data after JSON value at offset 37",
+		},
+		{
+			name:        "trailing whitespace",
+			contentType: "application/json",
+			body:        "{\"author\":\"Author\",\"quote\":\"Quote\"}\n\n",
+		},
+		{
+			name:            "syntax error",
+			contentType:     "application/json",
+			body:            `{"author": Author}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + ": malformed JSON at offset 12",
+		},
+		{
+			name:            "truncated body",
+			contentType:     "application/json",
+			body:            `{"author":"Author"`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + ": unexpected end of JSON input",
+		},
+		{
+			name:            "wrong field type",
+			contentType:     "application/json",
+			body:            `{"author":42,"quote":"Quote"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + `: field "author" must be string at offset 12`,
+		},
+		{
+			name:            "empty body",
+			contentType:     "application/json",
+			body:            ``,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: domain.MsgInvalidJSON + ": request body must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			var dst domain.CreateQuoteRequest
+			rerr := decodeJSON(rec, req, &dst, tt.maxBytes)
+
+			if tt.expectedStatus == 0 {
+				if rerr != nil {
+					t.Fatalf("unexpected error: %v", rerr)
+				}
+				if dst.Author != "Author" || dst.Quote != "Quote" {
+					t.Errorf("unexpected decoded value: %+v", dst)
+				}
+				return
+			}
+
+			if rerr == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if rerr.Status != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rerr.Status)
+			}
+			if rerr.Message != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, rerr.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/openapi"
+)
+
+var docsPage = mustReadAsset("assets/swagger.html")
+
+// GetOpenAPI GET /openapi.json serves the OpenAPI description of the API
+func (h *QuoteHandler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.Document)
+}
+
+// GetDocs GET /docs serves Swagger UI for the OpenAPI description
+func (h *QuoteHandler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(docsPage))
+}
+
+func (h *QuoteHandler) registerDocsRoutes(mux Router) {
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetOpenAPI(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetDocs(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
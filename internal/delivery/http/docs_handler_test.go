@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/delivery/http/openapi"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetOpenAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{}).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", ct)
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		t.Errorf("expected an OpenAPI 3.1 document, got %q", doc.OpenAPI)
+	}
+}
+
+func TestQuoteHandler_GetDocs(t *testing.T) {
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{}).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected an HTML page, got %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "/openapi.json") {
+		t.Error("expected the page to load /openapi.json")
+	}
+}
+
+// recordingRouter registers routes on a ServeMux and remembers their patterns
+type recordingRouter struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (r *recordingRouter) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.patterns = append(r.patterns, pattern)
+	r.ServeMux.HandleFunc(pattern, handler)
+}
+
+var (
+	wildcard  = regexp.MustCompile(`\{[^}]+\}`)
+	probeVerb = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+)
+
+// probe reports whether a route serves method on path rather than answering 405. The
+// request context is cancelled so streaming handlers return at once.
+func probe(t *testing.T, mux *http.ServeMux, method, path string) bool {
+	t.Helper()
+	ctx, cancel := context.WithCancel(domain.WithPrincipal(context.Background(), &domain.Principal{Name: "ops", Role: domain.RoleAdmin}))
+	cancel()
+	req := httptest.NewRequest(method, path, nil).WithContext(ctx)
+	if _, pattern := mux.Handler(req); pattern == "" {
+		return false
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec.Code != http.StatusMethodNotAllowed
+}
+
+// TestRoutes_MatchOpenAPI fails when a route is served but not documented, or documented
+// but not served
+func TestRoutes_MatchOpenAPI(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load() error = %v", err)
+	}
+	router := &recordingRouter{ServeMux: http.NewServeMux()}
+	NewQuoteHandler(docsUseCase()).RegisterRoutes(router)
+
+	// Every method a registered route serves is documented
+	for _, pattern := range router.patterns {
+		path := wildcard.ReplaceAllString(pattern, "1")
+		for _, method := range probeVerb {
+			if probe(t, router.ServeMux, method, path) {
+				if op, _ := spec.Find(method, path); op == nil {
+					t.Errorf("%s %s is served but missing from the OpenAPI document", method, pattern)
+				}
+			}
+		}
+	}
+
+	// Every documented operation is served
+	for _, op := range spec.Operations() {
+		path := wildcard.ReplaceAllString(op.Path, "1")
+		if op.Path == "/quotes/{id}/revisions/diff" {
+			path += "?from=1&to=1"
+		}
+		if !probe(t, router.ServeMux, op.Method, path) {
+			t.Errorf("%s %s is documented but not served", op.Method, op.Path)
+		}
+	}
+}
+
+func docsQuote() *domain.Quote {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &domain.Quote{ID: 1, Author: "Jane Austen", Quote: "Q", Tags: []string{"wit"}, CreatedAt: created, UpdatedAt: created, Version: 1}
+}
+
+// docsUseCase answers for quote 1 and knows of nothing else
+func docsUseCase() *MockQuoteUseCase {
+	quote := docsQuote()
+	revision := &domain.Revision{QuoteID: 1, Revision: 1, Change: domain.ChangeCreate, Actor: "ops", CreatedAt: quote.CreatedAt, Snapshot: quote}
+	return &MockQuoteUseCase{
+		CreateQuoteFunc:    func(req *domain.CreateQuoteRequest) (*domain.Quote, error) { return quote, nil },
+		GetAllQuotesFunc:   func() ([]*domain.Quote, error) { return []*domain.Quote{quote}, nil },
+		GetRandomQuoteFunc: func() (*domain.Quote, error) { return quote, nil },
+		GetDailyQuoteFunc:  func(day time.Time) (*domain.Quote, error) { return quote, nil },
+		GetQuotesByAuthorFunc: func(author string) ([]*domain.Quote, error) {
+			return []*domain.Quote{quote}, nil
+		},
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			if id != 1 {
+				return nil, domain.ErrQuoteNotFound
+			}
+			return quote, nil
+		},
+		UpdateQuoteFunc:  func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) { return quote, nil },
+		GetRevisionsFunc: func(id int) ([]*domain.Revision, error) { return []*domain.Revision{revision}, nil },
+		GetRevisionFunc:  func(id, rev int) (*domain.Revision, error) { return revision, nil },
+		RestoreQuoteFunc: func(id int) (*domain.Quote, error) { return quote, nil },
+		GetRecentQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			return []*domain.Quote{quote}, nil
+		},
+		ListQuotesFunc: func(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+			return &domain.QuotePage{Quotes: []*domain.Quote{quote}, Next: domain.QuoteCursor{ID: 1}}, nil
+		},
+		SearchQuotesFunc: func(query string, limit int) ([]*domain.Quote, error) { return []*domain.Quote{quote}, nil },
+		GetStatsFunc: func() (*domain.CollectionStats, error) {
+			day := []*domain.StatsBucket{{Start: quote.CreatedAt, Quotes: 1}}
+			return &domain.CollectionStats{
+				AsOf:           quote.CreatedAt,
+				TotalQuotes:    1,
+				TotalAuthors:   1,
+				QuotesPerDay:   day,
+				QuotesPerWeek:  day,
+				QuotesPerMonth: day,
+				TopAuthors: []*domain.AuthorStats{{Author: quote.Author, Quotes: 1,
+					CommonWords: []*domain.WordCount{{Word: "truth", Uses: 1}}}},
+				Length: domain.LengthStats{Average: 40, Min: 40, Max: 40, P50: 40, P90: 40, P99: 40},
+			}, nil
+		},
+	}
+}
+
+// TestRoutes_ResponsesMatchOpenAPI runs typical requests through the validation middleware
+// with response checks on, so handlers drifting from the document fail here
+func TestRoutes_ResponsesMatchOpenAPI(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load() error = %v", err)
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(docsUseCase(), WithBaseURL("https://quotes.example.com/")).RegisterRoutes(mux)
+
+	oembed := url.Values{"url": {"https://quotes.example.com/quotes/1"}}.Encode()
+	tests := []struct {
+		method string
+		target string
+		accept string
+		body   string
+		admin  bool
+	}{
+		{method: http.MethodGet, target: "/health"},
+		{method: http.MethodHead, target: "/health"},
+		{method: http.MethodGet, target: "/openapi.json"},
+		{method: http.MethodGet, target: "/docs"},
+		{method: http.MethodGet, target: "/quotes"},
+		{method: http.MethodGet, target: "/quotes?author=Jane+Austen"},
+		{method: http.MethodGet, target: "/quotes", accept: "text/markdown"},
+		{method: http.MethodGet, target: "/quotes?limit=1&tag=wit"},
+		{method: http.MethodGet, target: "/v2/quotes?limit=1&tag=wit"},
+		{method: http.MethodGet, target: "/v2/quotes?limit=1&fields=id,quote"},
+		{method: http.MethodGet, target: "/quotes?limit=many"},
+		{method: http.MethodGet, target: "/quotes?sort=-length&created_after=2026-01-02&min_length=10"},
+		{method: http.MethodGet, target: "/quotes?fields=id,quote"},
+		{method: http.MethodGet, target: "/quotes?sort=popularity"},
+		{method: http.MethodGet, target: "/quotes/search?q=austen&limit=5"},
+		{method: http.MethodGet, target: "/quotes/search?q="},
+		{method: http.MethodGet, target: "/quotes/random"},
+		{method: http.MethodGet, target: "/quotes/daily"},
+		{method: http.MethodGet, target: "/quotes/1"},
+		{method: http.MethodGet, target: "/quotes/1", accept: "application/xml"},
+		{method: http.MethodGet, target: "/quotes/2"},
+		{method: http.MethodGet, target: "/quotes/abc"},
+		{method: http.MethodGet, target: "/quotes/1", accept: "image/gif"},
+		{method: http.MethodPost, target: "/quotes", body: `{"author":"Jane Austen","quote":"Q"}`},
+		{method: http.MethodPost, target: "/quotes", body: `{"author":"Jane Austen"}`},
+		{method: http.MethodPut, target: "/quotes/1", body: `{"author":"Jane Austen","quote":"Q"}`},
+		{method: http.MethodDelete, target: "/quotes/1"},
+		{method: http.MethodGet, target: "/quotes/1/revisions"},
+		{method: http.MethodGet, target: "/quotes/1/revisions/1"},
+		{method: http.MethodGet, target: "/quotes/1/card.svg"},
+		{method: http.MethodGet, target: "/stats"},
+		{method: http.MethodGet, target: "/trash", admin: true},
+		{method: http.MethodGet, target: "/trash"},
+		{method: http.MethodGet, target: "/admin/audit", admin: true},
+		{method: http.MethodGet, target: "/admin/audit/verify", admin: true},
+		{method: http.MethodGet, target: "/admin/webhooks", admin: true},
+		{method: http.MethodGet, target: "/admin/webhooks/1", admin: true},
+		{method: http.MethodGet, target: "/admin/keys", admin: true},
+		{method: http.MethodPost, target: "/admin/keys", body: `{"name":"ci","role":"admin"}`, admin: true},
+		{method: http.MethodDelete, target: "/admin/keys/1", admin: true},
+		{method: http.MethodGet, target: "/debug/vars", admin: true},
+		{method: http.MethodGet, target: "/feeds/quotes.atom"},
+		{method: http.MethodGet, target: "/feeds/quotes.rss"},
+		{method: http.MethodGet, target: "/oembed?" + oembed},
+		{method: http.MethodGet, target: "/embed/widget.js"},
+		{method: http.MethodGet, target: "/embed/quotes/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			cfg := middleware.ValidationConfig{
+				Requests:  true,
+				Responses: true,
+				OnResponseError: func(r *http.Request, err error) {
+					t.Errorf("response does not match the document: %v", err)
+				},
+			}
+			handler := middleware.ValidationMiddleware(spec, cfg, mux)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.admin {
+				req = req.WithContext(domain.WithPrincipal(req.Context(), &domain.Principal{Name: "ops", Role: domain.RoleAdmin}))
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"image/color"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/card"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// EmbedConfig describes oEmbed responses and embeddable quote pages
+type EmbedConfig struct {
+	ProviderName string
+	// AllowedOrigins may frame embed pages and read oEmbed responses; "*" allows everyone
+	AllowedOrigins []string
+	// Width and Height are the iframe size suggested by oEmbed before maxwidth/maxheight apply
+	Width  int
+	Height int
+	// CacheAge is how long, in seconds, consumers may cache oEmbed responses
+	CacheAge int
+}
+
+// DefaultEmbedConfig is used unless WithEmbed is given
+var DefaultEmbedConfig = EmbedConfig{
+	ProviderName:   "Quotes",
+	AllowedOrigins: []string{"*"},
+	Width:          500,
+	Height:         220,
+	CacheAge:       3600,
+}
+
+// WithEmbed overrides DefaultEmbedConfig
+func WithEmbed(cfg EmbedConfig) Option {
+	return func(h *QuoteHandler) {
+		h.embed = cfg
+	}
+}
+
+var (
+	widgetScript = mustReadAsset("assets/widget.js")
+	embedScript  = mustReadAsset("assets/resize.js")
+	embedStyle   = themeStyles() + mustReadAsset("assets/embed.css")
+	embedPage    = template.Must(template.ParseFS(assets, "assets/embed.html"))
+
+	// The embed page only runs its own inline style and script, pinned by hash
+	embedCSP = fmt.Sprintf("default-src 'none'; style-src '%s'; script-src '%s'; base-uri 'none'; form-action 'none'",
+		cspHash(embedStyle), cspHash(embedScript))
+)
+
+func mustReadAsset(name string) string {
+	data, err := assets.ReadFile(name)
+	if err != nil {
+		panic("handler: missing embedded asset " + name)
+	}
+	return string(data)
+}
+
+func cspHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// themeStyles defines a CSS class per card theme so embeds and cards share colors
+func themeStyles() string {
+	hex := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+
+	var b strings.Builder
+	for _, theme := range card.Themes {
+		fmt.Fprintf(&b, ".theme-%s{--bg:%s;--fg:%s;--accent:%s}\n",
+			theme.Name, hex(theme.Background), hex(theme.Foreground), hex(theme.Accent))
+	}
+	return b.String()
+}
+
+// frameAncestors returns the CSP frame-ancestors source list for the allowed origins
+func (h *QuoteHandler) frameAncestors() string {
+	for _, origin := range h.embed.AllowedOrigins {
+		if origin == "*" {
+			return "*"
+		}
+	}
+	if len(h.embed.AllowedOrigins) == 0 {
+		return "'none'"
+	}
+	return strings.Join(h.embed.AllowedOrigins, " ")
+}
+
+// allowOrigin sets CORS headers when the request's origin may read the response
+func (h *QuoteHandler) allowOrigin(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	for _, allowed := range h.embed.AllowedOrigins {
+		if allowed == "*" {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			return
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			return
+		}
+	}
+}
+
+type embedData struct {
+	Quote     *domain.Quote
+	Error     string
+	Theme     string
+	OEmbedURL string
+	Style     template.CSS
+	Script    template.JS
+}
+
+// GetEmbed GET /embed/quotes/{quote} where quote is an ID, "random" or "daily", with optional ?theme=
+func (h *QuoteHandler) GetEmbed(w http.ResponseWriter, r *http.Request) {
+	data := embedData{Style: template.CSS(embedStyle), Script: template.JS(embedScript)}
+
+	opts, err := card.ParseOptions(r.URL.Query().Get("theme"), "")
+	data.Theme = opts.Theme.Name
+	if err != nil {
+		data.Error = err.Error()
+		h.writeEmbed(w, r, http.StatusBadRequest, data)
+		return
+	}
+
+	var quote *domain.Quote
+	which := r.PathValue("quote")
+	switch which {
+	case "random":
+		quote, err = h.quoteUseCase.GetRandomQuote()
+	case "daily":
+		quote, err = h.quoteUseCase.GetDailyQuote(time.Now())
+	default:
+		id, convErr := strconv.Atoi(which)
+		if convErr != nil {
+			data.Error = domain.MsgInvalidQuoteID
+			h.writeEmbed(w, r, http.StatusBadRequest, data)
+			return
+		}
+		quote, err = h.quoteUseCase.GetQuote(id)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidID):
+			data.Error = err.Error()
+			h.writeEmbed(w, r, http.StatusBadRequest, data)
+		case errors.Is(err, domain.ErrQuoteNotFound), errors.Is(err, domain.ErrNoQuotesFound):
+			data.Error = domain.MsgQuotesNotFound
+			h.writeEmbed(w, r, http.StatusNotFound, data)
+		default:
+			data.Error = domain.MsgFailedGetQuotes
+			h.writeEmbed(w, r, http.StatusInternalServerError, data)
+		}
+		return
+	}
+
+	data.Quote = quote
+	data.OEmbedURL = h.baseURL + "/oembed?url=" + url.QueryEscape(h.quoteURL(quote.ID))
+
+	// Random quotes must not be cached, everything else may be for a short while
+	if which == "random" {
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	}
+	h.writeEmbed(w, r, http.StatusOK, data)
+}
+
+func (h *QuoteHandler) writeEmbed(w http.ResponseWriter, r *http.Request, status int, data embedData) {
+	var buf bytes.Buffer
+	if err := embedPage.Execute(&buf, data); err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", embedCSP+"; frame-ancestors "+h.frameAncestors())
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
+
+// GetWidgetScript GET /embed/widget.js
+func (h *QuoteHandler) GetWidgetScript(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Let pages that require CORP on subresources load the script
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+	if notModified(w, r, strongETag([]byte(widgetScript)), time.Time{}) {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write([]byte(widgetScript))
+	}
+}
+
+func (h *QuoteHandler) quoteURL(id int) string {
+	return fmt.Sprintf("%s/quotes/%d", h.baseURL, id)
+}
+
+func (h *QuoteHandler) registerEmbedRoutes(mux Router) {
+	routes := map[string]http.HandlerFunc{
+		"/oembed":               h.GetOEmbed,
+		"/embed/widget.js":      h.GetWidgetScript,
+		"/embed/quotes/{quote}": h.GetEmbed,
+	}
+	for pattern, serve := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				serve(w, r)
+			} else {
+				h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func embedQuote() *domain.Quote {
+	return &domain.Quote{ID: 42, Author: "Seneca <the Younger>", Quote: "Luck is what happens\nwhen preparation meets opportunity."}
+}
+
+func embedMux(cfg EmbedConfig) *http.ServeMux {
+	mockUseCase := &MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			if id != 42 {
+				return nil, domain.ErrQuoteNotFound
+			}
+			return embedQuote(), nil
+		},
+		GetRandomQuoteFunc: func() (*domain.Quote, error) { return embedQuote(), nil },
+		GetDailyQuoteFunc:  func(day time.Time) (*domain.Quote, error) { return embedQuote(), nil },
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase, WithBaseURL("https://quotes.example.com/"), WithEmbed(cfg)).RegisterRoutes(mux)
+	return mux
+}
+
+func oEmbedRequest(mux *http.ServeMux, params url.Values) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oembed?"+params.Encode(), nil))
+	return rec
+}
+
+func TestQuoteHandler_GetOEmbed(t *testing.T) {
+	mux := embedMux(DefaultEmbedConfig)
+
+	tests := []struct {
+		name           string
+		params         url.Values
+		expectedStatus int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			name:           "quote url",
+			params:         url.Values{"url": {"https://quotes.example.com/quotes/42"}},
+			expectedStatus: http.StatusOK,
+			expectedWidth:  500,
+			expectedHeight: 220,
+		},
+		{
+			name:           "card url with maxwidth",
+			params:         url.Values{"url": {"http://QUOTES.example.com/quotes/42/card.png"}, "maxwidth": {"320"}, "maxheight": {"1000"}},
+			expectedStatus: http.StatusOK,
+			expectedWidth:  320,
+			expectedHeight: 220,
+		},
+		{name: "missing url", params: url.Values{}, expectedStatus: http.StatusBadRequest},
+		{name: "foreign host", params: url.Values{"url": {"https://evil.example.com/quotes/42"}}, expectedStatus: http.StatusNotFound},
+		{name: "not a quote url", params: url.Values{"url": {"https://quotes.example.com/feeds/quotes.atom"}}, expectedStatus: http.StatusNotFound},
+		{name: "unknown quote", params: url.Values{"url": {"https://quotes.example.com/quotes/7"}}, expectedStatus: http.StatusNotFound},
+		{name: "invalid maxwidth", params: url.Values{"url": {"https://quotes.example.com/quotes/42"}, "maxwidth": {"-1"}}, expectedStatus: http.StatusBadRequest},
+		{name: "unsupported format", params: url.Values{"url": {"https://quotes.example.com/quotes/42"}, "format": {"yaml"}}, expectedStatus: http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := oEmbedRequest(mux, tt.params)
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response OEmbedResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Type != "rich" || response.Version != "1.0" || response.AuthorName != "Seneca <the Younger>" {
+				t.Errorf("unexpected response %+v", response)
+			}
+			if response.Width != tt.expectedWidth || response.Height != tt.expectedHeight {
+				t.Errorf("expected %dx%d, got %dx%d", tt.expectedWidth, tt.expectedHeight, response.Width, response.Height)
+			}
+			if !strings.Contains(response.HTML, `src="https://quotes.example.com/embed/quotes/42"`) ||
+				!strings.Contains(response.HTML, "Seneca &lt;the Younger&gt;") {
+				t.Errorf("unexpected html %s", response.HTML)
+			}
+			if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+				t.Error("expected oEmbed to be readable from any origin by default")
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_GetOEmbed_XML(t *testing.T) {
+	rec := oEmbedRequest(embedMux(DefaultEmbedConfig), url.Values{"url": {"https://quotes.example.com/quotes/42"}, "format": {"xml"}})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/xml") {
+		t.Errorf("expected text/xml, got %s", ct)
+	}
+	var response OEmbedResponse
+	if err := xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.XMLName.Local != "oembed" || response.Type != "rich" || !strings.HasPrefix(response.HTML, "<iframe") {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestQuoteHandler_GetEmbed(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedCache  string
+	}{
+		{name: "specific quote", path: "/embed/quotes/42?theme=dark", expectedStatus: http.StatusOK, expectedCache: "public, max-age=300"},
+		{name: "daily quote", path: "/embed/quotes/daily", expectedStatus: http.StatusOK, expectedCache: "public, max-age=300"},
+		{name: "random quote", path: "/embed/quotes/random", expectedStatus: http.StatusOK, expectedCache: "no-store"},
+		{name: "unknown quote", path: "/embed/quotes/7", expectedStatus: http.StatusNotFound},
+		{name: "invalid quote", path: "/embed/quotes/latest", expectedStatus: http.StatusBadRequest},
+		{name: "unknown theme", path: "/embed/quotes/42?theme=neon", expectedStatus: http.StatusBadRequest},
+	}
+
+	mux := embedMux(EmbedConfig{AllowedOrigins: []string{"https://blog.example.org", "https://*.example.net"}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+				t.Errorf("expected an HTML page, got %s", ct)
+			}
+
+			csp := rec.Header().Get("Content-Security-Policy")
+			if !strings.Contains(csp, "default-src 'none'") ||
+				!strings.HasSuffix(csp, "frame-ancestors https://blog.example.org https://*.example.net") {
+				t.Errorf("unexpected Content-Security-Policy %q", csp)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			body := rec.Body.String()
+			if !strings.Contains(body, "Seneca &lt;the Younger&gt;") || strings.Contains(body, "<the Younger>") {
+				t.Errorf("expected escaped author in page:\n%s", body)
+			}
+			if rec.Header().Get("Cache-Control") != tt.expectedCache {
+				t.Errorf("expected Cache-Control %q, got %q", tt.expectedCache, rec.Header().Get("Cache-Control"))
+			}
+			checkInlineHashes(t, csp, body)
+		})
+	}
+}
+
+// checkInlineHashes verifies that every inline style and script is allowed by the CSP
+func checkInlineHashes(t *testing.T, csp, body string) {
+	t.Helper()
+	inline := regexp.MustCompile(`(?s)<(style|script)>(.*?)</(?:style|script)>`)
+	matches := inline.FindAllStringSubmatch(body, -1)
+	if len(matches) != 2 {
+		t.Fatalf("expected one inline style and one inline script, got %d", len(matches))
+	}
+	for _, m := range matches {
+		if !strings.Contains(csp, "'"+cspHash(m[2])+"'") {
+			t.Errorf("inline %s is not allowed by the CSP", m[1])
+		}
+	}
+}
+
+func TestQuoteHandler_GetWidgetScript(t *testing.T) {
+	mux := embedMux(DefaultEmbedConfig)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/embed/widget.js", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/javascript; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %s", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "/embed/quotes/") {
+		t.Error("expected the widget to point iframes at the embed pages")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/embed/widget.js", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	cached := httptest.NewRecorder()
+	mux.ServeHTTP(cached, req)
+	if cached.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for a cached widget, got %d", cached.Code)
+	}
+}
+
+func TestQuoteHandler_AllowOrigin(t *testing.T) {
+	mux := embedMux(EmbedConfig{AllowedOrigins: []string{"https://blog.example.org"}, Width: 500, Height: 220})
+	params := url.Values{"url": {"https://quotes.example.com/quotes/42"}}
+
+	for origin, expected := range map[string]string{
+		"https://blog.example.org":  "https://blog.example.org",
+		"https://other.example.org": "",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/oembed?"+params.Encode(), nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != expected {
+			t.Errorf("origin %s: expected Access-Control-Allow-Origin %q, got %q", origin, expected, got)
+		}
+	}
+}
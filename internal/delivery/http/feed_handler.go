@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/feed"
+)
+
+// DefaultFeedEntries is the number of newest quotes included in a feed
+const DefaultFeedEntries = 20
+
+// FeedConfig describes the feeds served under /feeds
+type FeedConfig struct {
+	Title       string
+	Description string
+	Entries     int
+}
+
+// DefaultFeedConfig is used unless WithFeed is given
+var DefaultFeedConfig = FeedConfig{
+	Title:       "Quotes",
+	Description: "Newest quotes",
+	Entries:     DefaultFeedEntries,
+}
+
+// WithFeed overrides DefaultFeedConfig
+func WithFeed(cfg FeedConfig) Option {
+	return func(h *QuoteHandler) {
+		h.feed = cfg
+	}
+}
+
+type feedWriter func(w io.Writer, meta feed.Meta, quotes []*domain.Quote) error
+
+// serveFeed GET /feeds/quotes.{atom,rss}, /feeds/authors/{author}/quotes.{atom,rss}
+// and /feeds/tags/{tag}/quotes.{atom,rss}
+func (h *QuoteHandler) serveFeed(w http.ResponseWriter, r *http.Request, contentType string, write feedWriter) {
+	filter := domain.QuoteFilter{Author: r.PathValue("author"), Tag: r.PathValue("tag"), Limit: h.feed.Entries}
+
+	// Deleting a quote changes the feed without changing any entry left in it, so the feed
+	// is as new as the collection. The version is read first, so a concurrent write can only
+	// make Last-Modified older than the body.
+	var lastModified time.Time
+	if version, err := h.quoteUseCase.GetQuotesVersion(); err == nil {
+		lastModified = version.UpdatedAt
+	}
+
+	quotes, err := h.quoteUseCase.GetRecentQuotes(filter)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedGetQuotes)
+		return
+	}
+
+	meta := feed.Meta{
+		Title:       feedTitle(h.feed.Title, filter),
+		Description: h.feed.Description,
+		BaseURL:     h.baseURL,
+		Path:        r.URL.EscapedPath(),
+	}
+
+	var buf bytes.Buffer
+	if err = write(&buf, meta, quotes); err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if notModified(w, r, strongETag(buf.Bytes()), lastModified) {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
+
+func feedTitle(title string, filter domain.QuoteFilter) string {
+	switch {
+	case filter.Author != "":
+		return title + ": " + filter.Author
+	case filter.Tag != "":
+		return title + ": #" + filter.Tag
+	default:
+		return title
+	}
+}
+
+func (h *QuoteHandler) registerFeedRoutes(mux Router) {
+	feeds := map[string]struct {
+		contentType string
+		write       feedWriter
+	}{
+		"atom": {feed.AtomContentType, feed.Atom},
+		"rss":  {feed.RSSContentType, feed.RSS},
+	}
+
+	for ext, f := range feeds {
+		serve := func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				h.serveFeed(w, r, f.contentType, f.write)
+			} else {
+				h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+		}
+		mux.HandleFunc("/feeds/quotes."+ext, serve)
+		mux.HandleFunc("/feeds/authors/{author}/quotes."+ext, serve)
+		mux.HandleFunc("/feeds/tags/{tag}/quotes."+ext, serve)
+	}
+}
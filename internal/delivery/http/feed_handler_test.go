@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/feed"
+)
+
+func feedQuotes() []*domain.Quote {
+	return []*domain.Quote{
+		{ID: 2, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity.", Tags: []string{"stoicism"}, CreatedAt: time.Date(2024, 3, 2, 9, 0, 0, 500, time.UTC)},
+		{ID: 1, Author: "Confucius", Quote: "Life is really simple.", CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestQuoteHandler_Feeds(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedType   string
+		expectedFilter domain.QuoteFilter
+	}{
+		{name: "atom", path: "/feeds/quotes.atom", expectedType: feed.AtomContentType, expectedFilter: domain.QuoteFilter{Limit: 5}},
+		{name: "rss", path: "/feeds/quotes.rss", expectedType: feed.RSSContentType, expectedFilter: domain.QuoteFilter{Limit: 5}},
+		{name: "author atom", path: "/feeds/authors/Mark%20Twain/quotes.atom", expectedType: feed.AtomContentType, expectedFilter: domain.QuoteFilter{Author: "Mark Twain", Limit: 5}},
+		{name: "tag rss", path: "/feeds/tags/stoicism/quotes.rss", expectedType: feed.RSSContentType, expectedFilter: domain.QuoteFilter{Tag: "stoicism", Limit: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter domain.QuoteFilter
+			mockUseCase := &MockQuoteUseCase{
+				GetRecentQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					gotFilter = filter
+					return feedQuotes(), nil
+				},
+				GetQuotesVersionFunc: func() (*domain.CollectionVersion, error) {
+					return &domain.CollectionVersion{Version: 7, UpdatedAt: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)}, nil
+				},
+			}
+			h := NewQuoteHandler(mockUseCase, WithBaseURL("https://quotes.example.com"), WithFeed(FeedConfig{Title: "Quotes", Entries: 5}))
+			mux := http.NewServeMux()
+			h.RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.expectedType {
+				t.Errorf("expected Content-Type %s, got %s", tt.expectedType, ct)
+			}
+			if gotFilter != tt.expectedFilter {
+				t.Errorf("unexpected filter %+v", gotFilter)
+			}
+			if lm := rec.Header().Get("Last-Modified"); lm != "Tue, 05 Mar 2024 12:00:00 GMT" {
+				t.Errorf("unexpected Last-Modified %q", lm)
+			}
+			if etag := rec.Header().Get("ETag"); !strings.HasPrefix(etag, `"`) {
+				t.Errorf("expected a strong ETag, got %q", etag)
+			}
+			if err := xml.Unmarshal(rec.Body.Bytes(), new(struct{})); err != nil {
+				t.Errorf("feed is not well-formed XML: %v", err)
+			}
+			if !strings.Contains(rec.Body.String(), "https://quotes.example.com"+tt.path) {
+				t.Errorf("expected self link to %s", tt.path)
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_Feeds_ConditionalGet(t *testing.T) {
+	mockUseCase := &MockQuoteUseCase{
+		GetRecentQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			return feedQuotes(), nil
+		},
+		// A quote was deleted after the newest entry was written
+		GetQuotesVersionFunc: func() (*domain.CollectionVersion, error) {
+			return &domain.CollectionVersion{Version: 7, UpdatedAt: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)}, nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+
+	first := httptest.NewRecorder()
+	mux.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/feeds/quotes.atom", nil))
+	etag := first.Header().Get("ETag")
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{name: "matching etag", headers: map[string]string{"If-None-Match": etag}, expectedStatus: http.StatusNotModified},
+		{name: "etag in list", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, expectedStatus: http.StatusNotModified},
+		{name: "stale etag", headers: map[string]string{"If-None-Match": `"other"`}, expectedStatus: http.StatusOK},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 12:00:00 GMT"}, expectedStatus: http.StatusNotModified},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 11:59:59 GMT"}, expectedStatus: http.StatusOK},
+		{name: "deleted since the newest entry", headers: map[string]string{"If-Modified-Since": "Sat, 02 Mar 2024 09:00:00 GMT"}, expectedStatus: http.StatusOK},
+		{name: "etag takes precedence", headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Wed, 06 Mar 2024 00:00:00 GMT"}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/feeds/quotes.atom", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("expected ETag %s, got %s", etag, rec.Header().Get("ETag"))
+			}
+			if tt.expectedStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("expected empty body for 304, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_Feeds_Error(t *testing.T) {
+	mockUseCase := &MockQuoteUseCase{
+		GetRecentQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feeds/quotes.rss", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+}
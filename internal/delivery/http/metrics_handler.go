@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"expvar"
+	"net/http"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// GetMetrics GET /debug/vars serves the counters published with expvar, such as the gRPC
+// call metrics, in its JSON format. Only admins may read them.
+func (h *QuoteHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	if !domain.PrincipalFromContext(r.Context()).IsAdmin() {
+		h.writeError(w, http.StatusForbidden, domain.MsgAdminRequired)
+		return
+	}
+
+	expvar.Handler().ServeHTTP(w, r)
+}
+
+func (h *QuoteHandler) registerMetricsRoutes(mux Router) {
+	mux.HandleFunc("/debug/vars", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetMetrics(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
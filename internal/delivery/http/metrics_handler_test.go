@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetMetrics(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{})
+
+	if rec := serve(mux, http.MethodGet, "/debug/vars", nil, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("expected anonymous callers to get 403, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	req = req.WithContext(domain.WithPrincipal(context.Background(), &domain.Principal{Name: "ops", Role: domain.RoleAdmin}))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&vars); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Errorf("expected the standard expvars, got %d keys", len(vars))
+	}
+}
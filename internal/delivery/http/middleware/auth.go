@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Authenticator resolves an API key to the caller it belongs to
+type Authenticator interface {
+	Authenticate(key string) (*domain.Principal, error)
+}
+
+// websocketPath matches the WebSocket endpoint, with or without a version prefix
+var websocketPath = regexp.MustCompile(`^(/v[0-9]+)?/ws$`)
+
+// APIKey returns the key presented with the request, from "Authorization: Bearer <key>",
+// "X-API-Key: <key>" or, for WebSocket handshakes from browsers that cannot set those
+// headers, a "bearer.<key>" entry of Sec-WebSocket-Protocol. The subprotocol is only read
+// on a GET upgrade to the WebSocket endpoint.
+func APIKey(r *http.Request) string {
+	if key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(key)
+	}
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key
+	}
+	if !isWebSocketHandshake(r) {
+		return ""
+	}
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if key, ok := strings.CutPrefix(strings.TrimSpace(protocol), "bearer."); ok {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// isWebSocketHandshake reports whether r asks to upgrade to a WebSocket at the WebSocket endpoint
+func isWebSocketHandshake(r *http.Request) bool {
+	if r.Method != http.MethodGet || !websocketPath.MatchString(r.URL.Path) {
+		return false
+	}
+	for _, header := range r.Header.Values("Upgrade") {
+		for _, protocol := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(protocol), "websocket") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuthMiddleware attaches the caller identified by the request's API key to its context.
+// Requests without a key stay anonymous; requests with an unknown key are rejected with 401.
+func AuthMiddleware(authenticator Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := APIKey(r)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(key)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="quotes"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": domain.MsgInvalidAPIKey})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+type staticAuthenticator map[string]*domain.Principal
+
+func (a staticAuthenticator) Authenticate(key string) (*domain.Principal, error) {
+	if principal, ok := a[key]; ok {
+		return principal, nil
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	authenticator := staticAuthenticator{
+		"secret": {Name: "alice", Role: domain.RoleAdmin},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+		expectedActor  string
+		expectedAdmin  bool
+	}{
+		{name: "anonymous", expectedStatus: http.StatusOK, expectedActor: domain.AnonymousActor},
+		{name: "bearer token", headers: map[string]string{"Authorization": "Bearer secret"}, expectedStatus: http.StatusOK, expectedActor: "alice", expectedAdmin: true},
+		{name: "api key header", headers: map[string]string{"X-API-Key": "secret"}, expectedStatus: http.StatusOK, expectedActor: "alice", expectedAdmin: true},
+		{name: "websocket subprotocol", path: "/ws", headers: map[string]string{"Upgrade": "websocket", "Sec-WebSocket-Protocol": "quotes.v1, bearer.secret"}, expectedStatus: http.StatusOK, expectedActor: "alice", expectedAdmin: true},
+		{name: "versioned websocket subprotocol", path: "/v1/ws", headers: map[string]string{"Upgrade": "WebSocket", "Sec-WebSocket-Protocol": "quotes.v1, bearer.secret"}, expectedStatus: http.StatusOK, expectedActor: "alice", expectedAdmin: true},
+		{name: "subprotocol without upgrade", path: "/ws", headers: map[string]string{"Sec-WebSocket-Protocol": "quotes.v1, bearer.secret"}, expectedStatus: http.StatusOK, expectedActor: domain.AnonymousActor},
+		{name: "subprotocol outside websocket endpoint", headers: map[string]string{"Upgrade": "websocket", "Sec-WebSocket-Protocol": "bearer.secret"}, expectedStatus: http.StatusOK, expectedActor: domain.AnonymousActor},
+		{name: "subprotocol on non-GET", method: http.MethodPost, path: "/ws", headers: map[string]string{"Upgrade": "websocket", "Sec-WebSocket-Protocol": "bearer.secret"}, expectedStatus: http.StatusOK, expectedActor: domain.AnonymousActor},
+		{name: "unknown key", headers: map[string]string{"X-API-Key": "guess"}, expectedStatus: http.StatusUnauthorized},
+		{name: "other scheme", headers: map[string]string{"Authorization": "Basic c2VjcmV0"}, expectedStatus: http.StatusOK, expectedActor: domain.AnonymousActor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor string
+			var admin bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor = domain.ActorFromContext(r.Context())
+				admin = domain.PrincipalFromContext(r.Context()).IsAdmin()
+			})
+
+			method, path := http.MethodGet, "/quotes"
+			if tt.method != "" {
+				method = tt.method
+			}
+			if tt.path != "" {
+				path = tt.path
+			}
+			req := httptest.NewRequest(method, path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			AuthMiddleware(authenticator, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") == "" {
+					t.Error("expected a WWW-Authenticate challenge")
+				}
+				return
+			}
+			if actor != tt.expectedActor || admin != tt.expectedAdmin {
+				t.Errorf("expected actor %q (admin %v), got %q (admin %v)", tt.expectedActor, tt.expectedAdmin, actor, admin)
+			}
+		})
+	}
+}
@@ -31,6 +31,16 @@ func (lrw *loggingResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Flush implements http.Flusher for handlers that stream their response, such as server-sent events
+func (lrw *loggingResponseWriter) Flush() {
+	http.NewResponseController(lrw.w).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.w
+}
+
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
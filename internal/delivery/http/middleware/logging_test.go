@@ -37,6 +37,24 @@ func TestLoggingResponseWriter(t *testing.T) {
 	}
 }
 
+func TestLoggingMiddleware_Flusher(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the wrapped writer to implement http.Flusher")
+		}
+		w.Write([]byte("data: 1\n\n"))
+		flusher.Flush()
+	})
+
+	rec := httptest.NewRecorder()
+	LoggingMiddleware(testHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if !rec.Flushed {
+		t.Error("expected the flush to reach the underlying writer")
+	}
+}
+
 func TestLoggingMiddleware(t *testing.T) {
 	var logBuffer bytes.Buffer
 	originalOutput := log.Writer()
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware attaches the request ID and client IP to the request context, where
+// the audit log picks them up. A well-formed X-Request-ID from the client is kept, otherwise
+// a random ID is generated; either way it is echoed in the response.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := RequestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+
+		clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			clientIP = r.RemoteAddr
+		}
+
+		ctx := domain.WithRequestInfo(r.Context(), domain.RequestInfo{ID: id, ClientIP: clientIP})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns id when it is a well-formed request ID supplied by a client,
+// otherwise a new random ID
+func RequestID(id string) string {
+	if !validRequestID(id) {
+		return newRequestID()
+	}
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
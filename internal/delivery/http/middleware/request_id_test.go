@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestID  string
+		expectKept bool
+	}{
+		{name: "generated", requestID: ""},
+		{name: "kept", requestID: "req-42.a:b_c", expectKept: true},
+		{name: "invalid characters", requestID: "req 42\n"},
+		{name: "too long", requestID: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info domain.RequestInfo
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				info = domain.RequestInfoFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/quotes", nil)
+			req.RemoteAddr = "192.0.2.7:51234"
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			RequestIDMiddleware(next).ServeHTTP(rec, req)
+
+			if info.ClientIP != "192.0.2.7" {
+				t.Errorf("expected client IP 192.0.2.7, got %q", info.ClientIP)
+			}
+			if info.ID == "" || rec.Header().Get(RequestIDHeader) != info.ID {
+				t.Errorf("expected the request ID %q to be echoed, got %q", info.ID, rec.Header().Get(RequestIDHeader))
+			}
+			if kept := info.ID == tt.requestID; kept != tt.expectKept {
+				t.Errorf("expected kept=%v, got request ID %q", tt.expectKept, info.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/openapi"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// ValidationConfig selects what ValidationMiddleware checks
+type ValidationConfig struct {
+	// Requests rejects requests that do not match the specification with 400, or 415 for
+	// a body of an unsupported media type
+	Requests bool
+	// Responses checks responses too, keeping JSON bodies in memory until the handler is done;
+	// it is meant for tests and staging
+	Responses bool
+	// MaxBodyBytes bounds the request bodies read for validation; larger ones are passed on unchecked
+	MaxBodyBytes int64
+	// OnResponseError is told about every response that does not match; by default it is logged
+	OnResponseError func(r *http.Request, err error)
+}
+
+// validationErrorResponse has the shape of the handlers' error responses
+type validationErrorResponse struct {
+	Error   string               `json:"error"`
+	Details []*domain.FieldError `json:"details"`
+}
+
+// ValidationMiddleware checks requests, and optionally responses, against the OpenAPI
+// specification. Requests for routes the specification does not describe are passed on as they are.
+func ValidationMiddleware(spec *openapi.Spec, cfg ValidationConfig, next http.Handler) http.Handler {
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.OnResponseError == nil {
+		cfg.OnResponseError = func(r *http.Request, err error) {
+			log.Printf("response to %s %s does not match the API specification: %v", r.Method, r.URL.Path, err)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, params := spec.Find(r.Method, r.URL.Path)
+		if op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if cfg.Requests {
+			err := op.ValidateRequest(r, params, cfg.MaxBodyBytes)
+			var verr *openapi.ValidationError
+			if errors.As(err, &verr) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(verr.Status)
+				json.NewEncoder(w).Encode(validationErrorResponse{Error: domain.MsgRequestMismatch, Details: verr.Errors})
+				return
+			}
+		}
+
+		// Upgraded connections have no response to check
+		if !cfg.Responses || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		vrw := &validatingResponseWriter{w: w, op: op, head: r.Method == http.MethodHead}
+		next.ServeHTTP(vrw, r)
+		if err := vrw.finish(); err != nil {
+			cfg.OnResponseError(r, fmt.Errorf("status %d: %w", vrw.status, err))
+		}
+	})
+}
+
+// validatingResponseWriter holds back bodies the specification has a schema for, so they
+// can be checked before they are sent. Other bodies, such as streams, go out as written.
+type validatingResponseWriter struct {
+	w           http.ResponseWriter
+	op          *openapi.Operation
+	head        bool
+	status      int
+	wroteHeader bool
+	hold        bool
+	body        bytes.Buffer
+}
+
+func (vrw *validatingResponseWriter) Header() http.Header {
+	return vrw.w.Header()
+}
+
+func (vrw *validatingResponseWriter) WriteHeader(code int) {
+	if vrw.wroteHeader {
+		return
+	}
+	vrw.status = code
+	vrw.wroteHeader = true
+	vrw.hold = !vrw.head && vrw.op.HasSchema(code, vrw.Header().Get("Content-Type"))
+	if !vrw.hold {
+		vrw.w.WriteHeader(code)
+	}
+}
+
+func (vrw *validatingResponseWriter) Write(b []byte) (int, error) {
+	if !vrw.wroteHeader {
+		vrw.WriteHeader(http.StatusOK)
+	}
+	if vrw.hold {
+		return vrw.body.Write(b)
+	}
+	return vrw.w.Write(b)
+}
+
+// Flush implements http.Flusher; held bodies are only sent once the handler is done
+func (vrw *validatingResponseWriter) Flush() {
+	if !vrw.hold {
+		http.NewResponseController(vrw.w).Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (vrw *validatingResponseWriter) Unwrap() http.ResponseWriter {
+	return vrw.w
+}
+
+// finish sends a held body and checks the response
+func (vrw *validatingResponseWriter) finish() error {
+	if !vrw.wroteHeader {
+		vrw.WriteHeader(http.StatusOK)
+	}
+	if !vrw.hold {
+		return vrw.op.ValidateResponse(vrw.status, vrw.Header(), nil)
+	}
+
+	body := vrw.body.Bytes()
+	vrw.w.WriteHeader(vrw.status)
+	vrw.w.Write(body)
+	return vrw.op.ValidateResponse(vrw.status, vrw.Header(), body)
+}
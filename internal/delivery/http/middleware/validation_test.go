@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/openapi"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func loadSpec(t *testing.T) *openapi.Spec {
+	t.Helper()
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load() error = %v", err)
+	}
+	return spec
+}
+
+func TestValidationMiddleware_Requests(t *testing.T) {
+	spec := loadSpec(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedField  string
+		expectedCalled bool
+	}{
+		{name: "valid request", method: http.MethodGet, target: "/quotes/1", expectedStatus: http.StatusOK, expectedCalled: true},
+		{name: "invalid path parameter", method: http.MethodGet, target: "/quotes/abc", expectedStatus: http.StatusBadRequest, expectedField: "path.id"},
+		{
+			name: "invalid body", method: http.MethodPost, target: "/quotes", contentType: "application/json",
+			body: `{"author":"A"}`, expectedStatus: http.StatusBadRequest, expectedField: "body",
+		},
+		{
+			name: "unsupported media type", method: http.MethodPost, target: "/quotes", contentType: "text/plain",
+			body: "A: Q", expectedStatus: http.StatusUnsupportedMediaType, expectedField: "header.Content-Type",
+		},
+		{name: "undocumented route", method: http.MethodGet, target: "/nowhere", expectedStatus: http.StatusOK, expectedCalled: true},
+		{name: "undocumented method", method: http.MethodPatch, target: "/quotes/1", expectedStatus: http.StatusOK, expectedCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			ValidationMiddleware(spec, ValidationConfig{Requests: true}, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if called != tt.expectedCalled {
+				t.Errorf("expected handler called %v, got %v", tt.expectedCalled, called)
+			}
+			if tt.expectedField == "" {
+				return
+			}
+
+			var resp struct {
+				Error   string               `json:"error"`
+				Details []*domain.FieldError `json:"details"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error != domain.MsgRequestMismatch {
+				t.Errorf("expected error %q, got %q", domain.MsgRequestMismatch, resp.Error)
+			}
+			if len(resp.Details) == 0 || resp.Details[0].Field != tt.expectedField {
+				t.Errorf("expected details for %s, got %v", tt.expectedField, resp.Details)
+			}
+		})
+	}
+}
+
+func TestValidationMiddleware_Responses(t *testing.T) {
+	spec := loadSpec(t)
+	quote := `{"id":1,"author":"A","quote":"Q","created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z","version":1}`
+
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		status        int
+		contentType   string
+		body          string
+		expectedError bool
+	}{
+		{name: "matching body", method: http.MethodGet, target: "/quotes/1", status: http.StatusOK, contentType: "application/json", body: quote},
+		{name: "mismatching body", method: http.MethodGet, target: "/quotes/1", status: http.StatusOK, contentType: "application/json", body: `{"id":"1"}`, expectedError: true},
+		{name: "undocumented status", method: http.MethodGet, target: "/quotes/1", status: http.StatusTeapot, contentType: "application/json", body: `{}`, expectedError: true},
+		{name: "stream", method: http.MethodGet, target: "/quotes/stream", status: http.StatusOK, contentType: "text/event-stream", body: "data: {}\n\n"},
+		{name: "head", method: http.MethodHead, target: "/quotes/1/card.svg", status: http.StatusOK, contentType: "image/svg+xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			var reported error
+			cfg := ValidationConfig{Responses: true, OnResponseError: func(r *http.Request, err error) { reported = err }}
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rec := httptest.NewRecorder()
+			ValidationMiddleware(spec, cfg, next).ServeHTTP(rec, req)
+
+			if (reported != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, reported)
+			}
+			// The client gets the response either way
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidationMiddleware_FlushesStreams(t *testing.T) {
+	spec := loadSpec(t)
+
+	flushed := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {}\n\n"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+		flushed = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/quotes/stream", nil)
+	rec := httptest.NewRecorder()
+	ValidationMiddleware(spec, ValidationConfig{Responses: true}, next).ServeHTTP(rec, req)
+
+	if !flushed || !rec.Flushed {
+		t.Error("expected the stream to be flushed through")
+	}
+}
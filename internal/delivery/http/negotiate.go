@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/render"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// WithRenderers replaces the default set of response formats
+func WithRenderers(renderers *render.Registry) Option {
+	return func(h *QuoteHandler) {
+		h.renderers = renderers
+	}
+}
+
+// negotiate selects a renderer from the ?format= override or the Accept header.
+// It replies 406 and returns false when no registered format is acceptable.
+func (h *QuoteHandler) negotiate(w http.ResponseWriter, r *http.Request) (render.Renderer, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	var renderer render.Renderer
+	var ok bool
+	if name := r.URL.Query().Get("format"); name != "" {
+		renderer, ok = h.renderers.ByName(name)
+	} else {
+		renderer, ok = h.renderers.Negotiate(r.Header.Get("Accept"))
+	}
+
+	if !ok {
+		h.writeError(w, http.StatusNotAcceptable, domain.MsgNotAcceptable+", supported: "+strings.Join(h.renderers.MediaTypes(), ", "))
+		return nil, false
+	}
+	return renderer, true
+}
+
+func (h *QuoteHandler) writeQuote(w http.ResponseWriter, status int, renderer render.Renderer, quote *domain.Quote) {
+	var buf bytes.Buffer
+	if err := renderer.Quote(&buf, quote); err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+		return
+	}
+	h.writeRendered(w, status, renderer, buf.Bytes())
+}
+
+func (h *QuoteHandler) writeQuotes(w http.ResponseWriter, status int, renderer render.Renderer, quotes []*domain.Quote) {
+	var buf bytes.Buffer
+	if err := renderer.Quotes(&buf, quotes); err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+		return
+	}
+	h.writeRendered(w, status, renderer, buf.Bytes())
+}
+
+func (h *QuoteHandler) writeRendered(w http.ResponseWriter, status int, renderer render.Renderer, body []byte) {
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// sparseRenderer narrows a JSON renderer to the ?fields= of the request. It replies 400 and
+// returns false when the fieldset is invalid or the negotiated format is not JSON.
+func (h *QuoteHandler) sparseRenderer(w http.ResponseWriter, r *http.Request, renderer render.Renderer) (render.Renderer, bool) {
+	fields, err := render.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if _, ok := renderer.(render.JSON); !ok {
+		h.writeError(w, http.StatusBadRequest, "fields is only supported for application/json")
+		return nil, false
+	}
+	return render.SparseJSON{Fields: fields}, true
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// oEmbedPath matches the quote URLs the oEmbed endpoint accepts
+var oEmbedPath = regexp.MustCompile(`^/(?:embed/)?quotes/([1-9][0-9]*)(?:/card\.(?:svg|png))?/?$`)
+
+// OEmbedResponse is a "rich" oEmbed 1.0 response (https://oembed.com)
+type OEmbedResponse struct {
+	XMLName      xml.Name `json:"-" xml:"oembed"`
+	Type         string   `json:"type" xml:"type"`
+	Version      string   `json:"version" xml:"version"`
+	Title        string   `json:"title" xml:"title"`
+	AuthorName   string   `json:"author_name" xml:"author_name"`
+	AuthorURL    string   `json:"author_url" xml:"author_url"`
+	ProviderName string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL  string   `json:"provider_url" xml:"provider_url"`
+	CacheAge     int      `json:"cache_age" xml:"cache_age"`
+	HTML         string   `json:"html" xml:"html"`
+	Width        int      `json:"width" xml:"width"`
+	Height       int      `json:"height" xml:"height"`
+}
+
+// GetOEmbed GET /oembed?url=...&format=json|xml with optional maxwidth and maxheight
+func (h *QuoteHandler) GetOEmbed(w http.ResponseWriter, r *http.Request) {
+	h.allowOrigin(w, r)
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "xml" {
+		h.writeError(w, http.StatusNotImplemented, fmt.Sprintf("unsupported oEmbed format %q", format))
+		return
+	}
+
+	width, height := h.embed.Width, h.embed.Height
+	for _, bound := range []struct {
+		name  string
+		value *int
+	}{{"maxwidth", &width}, {"maxheight", &height}} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			h.writeError(w, http.StatusBadRequest, bound.name+" must be a positive integer")
+			return
+		}
+		*bound.value = min(*bound.value, n)
+	}
+
+	rawURL := query.Get("url")
+	if rawURL == "" {
+		h.writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	id, ok := h.quoteIDFromURL(rawURL)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "url does not refer to a quote of this service")
+		return
+	}
+
+	quote, err := h.quoteUseCase.GetQuote(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrQuoteNotFound):
+			h.writeError(w, http.StatusNotFound, domain.MsgQuotesNotFound)
+		default:
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedGetQuotes)
+		}
+		return
+	}
+
+	response := &OEmbedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        fmt.Sprintf("%s — %s", quote.Quote, quote.Author),
+		AuthorName:   quote.Author,
+		AuthorURL:    h.baseURL + "/quotes?author=" + url.QueryEscape(quote.Author),
+		ProviderName: h.embed.ProviderName,
+		ProviderURL:  h.baseURL,
+		CacheAge:     h.embed.CacheAge,
+		HTML:         h.embedIframe(quote, width, height),
+		Width:        width,
+		Height:       height,
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", h.embed.CacheAge))
+	if format == "json" {
+		h.writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>` + "\n"))
+	xml.NewEncoder(w).Encode(response)
+}
+
+// quoteIDFromURL extracts the quote ID from a URL pointing at this service
+func (h *QuoteHandler) quoteIDFromURL(raw string) (int, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return 0, false
+	}
+	base, err := url.Parse(h.baseURL)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return 0, false
+	}
+
+	match := oEmbedPath.FindStringSubmatch(strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/")))
+	if match == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(match[1])
+	return id, err == nil
+}
+
+func (h *QuoteHandler) embedIframe(quote *domain.Quote, width, height int) string {
+	return fmt.Sprintf(`<iframe src="%s/embed/quotes/%d" width="%d" height="%d" title="%s" `+
+		`style="border:0" loading="lazy" sandbox="allow-scripts allow-popups"></iframe>`,
+		h.baseURL, quote.ID, width, height, template.HTMLEscapeString("Quote by "+quote.Author))
+}
@@ -0,0 +1,419 @@
+// Package openapi holds the OpenAPI 3.1 description of the HTTP API and checks requests
+// and responses against it
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Document is the OpenAPI document served at /openapi.json
+//
+//go:embed openapi.json
+var Document []byte
+
+// documentURL names the document for the schema compiler, which resolves $ref against it
+const documentURL = "file:///openapi.json"
+
+// methods are the operation keys of a path item, in the order operations are listed
+var methods = []string{"get", "head", "post", "put", "patch", "delete", "options", "trace"}
+
+// Spec is the parsed document with its schemas compiled
+type Spec struct {
+	paths []*pathItem
+}
+
+type pathItem struct {
+	template string
+	segments []segment
+	// bases are the paths of the servers the path is served under, such as /v1, with ""
+	// for the root
+	bases      []string
+	operations map[string]*Operation
+}
+
+// segment is a part of a path template between slashes: literal text, or a parameter
+// that may be surrounded by literal text as in {id}:restore
+type segment struct {
+	literal string
+	param   string
+	prefix  string
+	suffix  string
+}
+
+// Operation is what the document says about one method of a path
+type Operation struct {
+	// Method is the HTTP method in upper case
+	Method string
+	// Path is the path template, such as /quotes/{id}
+	Path string
+	// ID is the operationId
+	ID string
+
+	params    []*parameter
+	body      *requestBody
+	responses map[string]*response
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	// typ is the JSON type the schema declares, used to read the value from its text
+	typ    string
+	schema *jsonschema.Schema
+}
+
+type requestBody struct {
+	required bool
+	// content maps media types to their schema, nil when the body is not described
+	content map[string]*jsonschema.Schema
+}
+
+type response struct {
+	content map[string]*jsonschema.Schema
+}
+
+// Load parses Document and compiles the schemas it contains
+func Load() (*Spec, error) {
+	return parse(Document)
+}
+
+// parser walks the document, following $ref and compiling schemas by their location
+type parser struct {
+	doc      interface{}
+	compiler *jsonschema.Compiler
+}
+
+func parse(data []byte) (*Spec, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat()
+	if err := compiler.AddResource(documentURL, doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	p := &parser{doc: doc, compiler: compiler}
+
+	paths, err := p.lookup("/paths")
+	if err != nil {
+		return nil, err
+	}
+	root, _ := doc.(map[string]interface{})
+	bases := basePaths(root, []string{""})
+	spec := &Spec{}
+	for template := range paths {
+		item, err := p.pathItem(template, bases)
+		if err != nil {
+			return nil, err
+		}
+		spec.paths = append(spec.paths, item)
+	}
+	sort.Slice(spec.paths, func(i, j int) bool { return spec.paths[i].template < spec.paths[j].template })
+	return spec, nil
+}
+
+// basePaths returns the paths of the servers listed in node, or defaults when it lists
+// none. A path item may list servers of its own in place of those of the document.
+func basePaths(node map[string]interface{}, defaults []string) []string {
+	servers, _ := node["servers"].([]interface{})
+	var bases []string
+	for _, s := range servers {
+		server, _ := s.(map[string]interface{})
+		raw, _ := server["url"].(string)
+		if u, err := url.Parse(raw); err == nil {
+			bases = append(bases, strings.TrimRight(u.Path, "/"))
+		}
+	}
+	if len(bases) == 0 {
+		return defaults
+	}
+	return bases
+}
+
+func (p *parser) pathItem(template string, bases []string) (*pathItem, error) {
+	ptr := "/paths/" + escapeToken(template)
+	node, err := p.lookup(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &pathItem{template: template, bases: basePaths(node, bases), operations: make(map[string]*Operation)}
+	for _, part := range strings.Split(strings.TrimPrefix(template, "/"), "/") {
+		item.segments = append(item.segments, parseSegment(part))
+	}
+
+	shared, err := p.parameters(node, ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, method := range methods {
+		if _, ok := node[method]; !ok {
+			continue
+		}
+		op, err := p.operation(ptr+"/"+method, shared)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(method), template, err)
+		}
+		op.Method = strings.ToUpper(method)
+		op.Path = template
+		item.operations[op.Method] = op
+	}
+	return item, nil
+}
+
+func (p *parser) operation(ptr string, shared []*parameter) (*Operation, error) {
+	node, err := p.lookup(ptr)
+	if err != nil {
+		return nil, err
+	}
+	op := &Operation{responses: make(map[string]*response)}
+	op.ID, _ = node["operationId"].(string)
+
+	own, err := p.parameters(node, ptr)
+	if err != nil {
+		return nil, err
+	}
+	// Parameters of the operation override those of the path with the same name and location
+	for _, param := range shared {
+		overridden := false
+		for _, o := range own {
+			overridden = overridden || (o.name == param.name && o.in == param.in)
+		}
+		if !overridden {
+			op.params = append(op.params, param)
+		}
+	}
+	op.params = append(op.params, own...)
+
+	if _, ok := node["requestBody"]; ok {
+		bodyNode, bodyPtr, err := p.resolve(ptr + "/requestBody")
+		if err != nil {
+			return nil, err
+		}
+		op.body = &requestBody{}
+		op.body.required, _ = bodyNode["required"].(bool)
+		if op.body.content, err = p.content(bodyNode, bodyPtr); err != nil {
+			return nil, err
+		}
+	}
+
+	responses, _ := node["responses"].(map[string]interface{})
+	for status := range responses {
+		respNode, respPtr, err := p.resolve(ptr + "/responses/" + escapeToken(status))
+		if err != nil {
+			return nil, err
+		}
+		resp := &response{}
+		if resp.content, err = p.content(respNode, respPtr); err != nil {
+			return nil, err
+		}
+		op.responses[status] = resp
+	}
+	return op, nil
+}
+
+func (p *parser) parameters(node map[string]interface{}, ptr string) ([]*parameter, error) {
+	list, _ := node["parameters"].([]interface{})
+	params := make([]*parameter, 0, len(list))
+	for i := range list {
+		paramNode, paramPtr, err := p.resolve(fmt.Sprintf("%s/parameters/%d", ptr, i))
+		if err != nil {
+			return nil, err
+		}
+
+		param := &parameter{}
+		param.name, _ = paramNode["name"].(string)
+		param.in, _ = paramNode["in"].(string)
+		param.required, _ = paramNode["required"].(bool)
+		if _, ok := paramNode["schema"]; ok {
+			schemaNode, _, err := p.resolve(paramPtr + "/schema")
+			if err != nil {
+				return nil, err
+			}
+			param.typ, _ = schemaNode["type"].(string)
+			if param.schema, err = p.schema(paramPtr + "/schema"); err != nil {
+				return nil, err
+			}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// content compiles the schemas of the media types a request body or a response may have
+func (p *parser) content(node map[string]interface{}, ptr string) (map[string]*jsonschema.Schema, error) {
+	media, _ := node["content"].(map[string]interface{})
+	content := make(map[string]*jsonschema.Schema, len(media))
+	for mediaType, value := range media {
+		var schema *jsonschema.Schema
+		if m, _ := value.(map[string]interface{}); m["schema"] != nil {
+			var err error
+			if schema, err = p.schema(ptr + "/content/" + escapeToken(mediaType) + "/schema"); err != nil {
+				return nil, err
+			}
+		}
+		content[mediaType] = schema
+	}
+	return content, nil
+}
+
+func (p *parser) schema(ptr string) (*jsonschema.Schema, error) {
+	tokens := strings.Split(ptr, "/")
+	for i, token := range tokens {
+		tokens[i] = url.PathEscape(token)
+	}
+	schema, err := p.compiler.Compile(documentURL + "#" + strings.Join(tokens, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("openapi: schema at %s: %w", ptr, err)
+	}
+	return schema, nil
+}
+
+// resolve returns the object at ptr, following $ref to other parts of the document,
+// along with the location it was found at
+func (p *parser) resolve(ptr string) (map[string]interface{}, string, error) {
+	for range 10 {
+		node, err := p.lookup(ptr)
+		if err != nil {
+			return nil, "", err
+		}
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, ptr, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, "", fmt.Errorf("openapi: unsupported $ref %q at %s", ref, ptr)
+		}
+		ptr = ref[1:]
+	}
+	return nil, "", fmt.Errorf("openapi: $ref cycle at %s", ptr)
+}
+
+// lookup returns the object a JSON pointer refers to
+func (p *parser) lookup(ptr string) (map[string]interface{}, error) {
+	node := p.doc
+	for _, token := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[token]
+		case []interface{}:
+			var i int
+			if _, err := fmt.Sscanf(token, "%d", &i); err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("openapi: no %s in the document", ptr)
+			}
+			node = n[i]
+		default:
+			node = nil
+		}
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("openapi: no object at %s in the document", ptr)
+	}
+	return object, nil
+}
+
+func escapeToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func parseSegment(part string) segment {
+	open := strings.IndexByte(part, '{')
+	end := strings.IndexByte(part, '}')
+	if open < 0 || end < open {
+		return segment{literal: part}
+	}
+	return segment{param: part[open+1 : end], prefix: part[:open], suffix: part[end+1:]}
+}
+
+// Operations lists every operation in the document, ordered by path and method
+func (s *Spec) Operations() []*Operation {
+	var ops []*Operation
+	for _, item := range s.paths {
+		for _, method := range methods {
+			if op := item.operations[strings.ToUpper(method)]; op != nil {
+				ops = append(ops, op)
+			}
+		}
+	}
+	return ops
+}
+
+// Find returns the operation serving method on path, along with the path parameters taken
+// from it. The path may start with the path of a server, such as /v1. When several
+// templates match, the one with literal text earliest wins, so /quotes/random is
+// preferred to /quotes/{id} and /quotes/{id}:restore to /quotes/{id}.
+// It returns nil when the best matching path has no such operation or no path matches.
+func (s *Spec) Find(method, path string) (*Operation, map[string]string) {
+	var best *pathItem
+	var bestParams map[string]string
+	var bestRank []int
+	for _, item := range s.paths {
+		for _, base := range item.bases {
+			rest, ok := strings.CutPrefix(path, base)
+			if !ok || !strings.HasPrefix(rest, "/") {
+				continue
+			}
+			params, rank, ok := item.match(strings.Split(rest[1:], "/"))
+			if !ok || (best != nil && !outranks(rank, bestRank)) {
+				continue
+			}
+			best, bestParams, bestRank = item, params, rank
+		}
+	}
+	if best == nil || best.operations[method] == nil {
+		return nil, nil
+	}
+	return best.operations[method], bestParams
+}
+
+// match reports whether the template matches the path segments, returning the parameters
+// and how specific every segment is: 2 for literal text, 1 for a parameter with text around it
+func (item *pathItem) match(parts []string) (map[string]string, []int, bool) {
+	if len(parts) != len(item.segments) {
+		return nil, nil, false
+	}
+
+	params := make(map[string]string)
+	rank := make([]int, len(parts))
+	for i, seg := range item.segments {
+		part := parts[i]
+		if seg.param == "" {
+			if part != seg.literal {
+				return nil, nil, false
+			}
+			rank[i] = 2
+			continue
+		}
+
+		if len(part) <= len(seg.prefix)+len(seg.suffix) || !strings.HasPrefix(part, seg.prefix) || !strings.HasSuffix(part, seg.suffix) {
+			return nil, nil, false
+		}
+		params[seg.param] = part[len(seg.prefix) : len(part)-len(seg.suffix)]
+		if seg.prefix != "" || seg.suffix != "" {
+			rank[i] = 1
+		}
+	}
+	return params, rank, true
+}
+
+func outranks(rank, other []int) bool {
+	for i := range rank {
+		if rank[i] != other[i] {
+			return rank[i] > other[i]
+		}
+	}
+	return false
+}
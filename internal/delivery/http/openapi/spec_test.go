@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func loadSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return spec
+}
+
+func TestLoad_CompilesEveryOperation(t *testing.T) {
+	spec := loadSpec(t)
+
+	seen := make(map[string]bool)
+	for _, op := range spec.Operations() {
+		if op.ID == "" {
+			t.Errorf("%s %s has no operationId", op.Method, op.Path)
+		}
+		if seen[op.ID] {
+			t.Errorf("operationId %q is used twice", op.ID)
+		}
+		seen[op.ID] = true
+		if len(op.responses) == 0 {
+			t.Errorf("%s %s documents no responses", op.Method, op.Path)
+		}
+	}
+	if len(seen) == 0 {
+		t.Fatal("Operations() returned nothing")
+	}
+}
+
+func TestSpec_Find(t *testing.T) {
+	spec := loadSpec(t)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+		params map[string]string
+	}{
+		{"GET", "/quotes/random", "/quotes/random", nil},
+		{"GET", "/quotes/42", "/quotes/{id}", map[string]string{"id": "42"}},
+		{"POST", "/quotes/42:restore", "/quotes/{id}:restore", map[string]string{"id": "42"}},
+		{"GET", "/quotes/42/revisions/diff", "/quotes/{id}/revisions/diff", map[string]string{"id": "42"}},
+		{"POST", "/quotes/42/revisions/3:restore", "/quotes/{id}/revisions/{rev}:restore", map[string]string{"id": "42", "rev": "3"}},
+		{"HEAD", "/quotes/42/card.png", "/quotes/{id}/card.png", map[string]string{"id": "42"}},
+		{"POST", "/quotes:batch", "/quotes:batch", nil},
+		{"GET", "/feeds/authors/Jane Austen/quotes.atom", "/feeds/authors/{author}/quotes.atom", map[string]string{"author": "Jane Austen"}},
+		{"POST", "/quotes/42", "", nil},
+		{"GET", "/unknown", "", nil},
+		{"GET", "/quotes/", "", nil},
+		{"GET", "/v1/quotes/42", "/quotes/{id}", map[string]string{"id": "42"}},
+		{"POST", "/v2/quotes:batch", "/quotes:batch", nil},
+		{"GET", "/health", "/health", nil},
+		{"GET", "/v1/health", "", nil},
+		{"GET", "/v3/quotes/42", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			op, params := spec.Find(tt.method, tt.path)
+			if tt.want == "" {
+				if op != nil {
+					t.Fatalf("Find() = %s, want nil", op.Path)
+				}
+				return
+			}
+			if op == nil || op.Path != tt.want || op.Method != tt.method {
+				t.Fatalf("Find() = %v, want %s %s", op, tt.method, tt.want)
+			}
+			for name, value := range tt.params {
+				if params[name] != value {
+					t.Errorf("param %s = %q, want %q", name, params[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestOperation_ValidateRequest(t *testing.T) {
+	spec := loadSpec(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantField   string
+	}{
+		{name: "valid query", method: "GET", target: "/admin/audit?limit=10&since=2024-01-02T03:04:05Z&action=quote.create"},
+		{name: "integer parameter", method: "GET", target: "/admin/audit?limit=ten", wantStatus: 400, wantField: "query.limit"},
+		{name: "date-time parameter", method: "GET", target: "/admin/audit?since=yesterday", wantStatus: 400, wantField: "query.since"},
+		{name: "enum parameter", method: "GET", target: "/admin/audit?action=quote.rename", wantStatus: 400, wantField: "query.action"},
+		{name: "boolean parameter", method: "DELETE", target: "/quotes/1?hard=maybe", wantStatus: 400, wantField: "query.hard"},
+		{name: "path parameter", method: "GET", target: "/quotes/abc", wantStatus: 400, wantField: "path.id"},
+		{name: "required parameter", method: "GET", target: "/quotes/1/revisions/diff?from=1", wantStatus: 400, wantField: "query.to"},
+		{name: "embed accepts keywords", method: "GET", target: "/embed/quotes/daily"},
+		{name: "embed accepts IDs", method: "GET", target: "/embed/quotes/7"},
+		{name: "embed rejects others", method: "GET", target: "/embed/quotes/latest", wantStatus: 400, wantField: "path.quote"},
+		{
+			name: "valid body", method: "POST", target: "/quotes",
+			contentType: "application/json", body: `{"author":"A","quote":"Q","tags":["t"]}`,
+		},
+		{
+			name: "missing property", method: "POST", target: "/quotes",
+			contentType: "application/json", body: `{"author":"A"}`, wantStatus: 400, wantField: "body",
+		},
+		{
+			name: "wrong property type", method: "POST", target: "/quotes",
+			contentType: "application/json", body: `{"author":"A","quote":"Q","tags":[1]}`, wantStatus: 400, wantField: "body.tags.0",
+		},
+		{
+			name: "unknown property", method: "PUT", target: "/quotes/1",
+			contentType: "application/json", body: `{"author":"A","quote":"Q","likes":3}`, wantStatus: 400, wantField: "body",
+		},
+		{
+			name: "unsupported media type", method: "POST", target: "/quotes",
+			contentType: "text/plain", body: `A: Q`, wantStatus: 415, wantField: "header.Content-Type",
+		},
+		{name: "missing body", method: "POST", target: "/quotes", wantStatus: 400, wantField: "body"},
+		{
+			name: "invalid JSON is left to the handler", method: "POST", target: "/quotes",
+			contentType: "application/json", body: `{"author":`,
+		},
+		{
+			name: "NDJSON batch is not parsed", method: "POST", target: "/quotes:batch",
+			contentType: "application/x-ndjson", body: "{\"author\":\"A\",\"quote\":\"Q\"}\n",
+		},
+		{
+			name: "import accepts any media type", method: "POST", target: "/quotes/import?format=csv",
+			contentType: "application/x-www-form-urlencoded", body: "author,quote\nA,Q\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			op, params := spec.Find(req.Method, req.URL.Path)
+			if op == nil {
+				t.Fatalf("no operation for %s %s", tt.method, tt.target)
+			}
+			err := op.ValidateRequest(req, params, 1<<20)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("ValidateRequest() error = %v", err)
+				}
+			} else {
+				verr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf("ValidateRequest() error = %v, want *ValidationError", err)
+				}
+				if verr.Status != tt.wantStatus {
+					t.Errorf("Status = %d, want %d", verr.Status, tt.wantStatus)
+				}
+				if len(verr.Errors) == 0 || verr.Errors[0].Field != tt.wantField {
+					t.Errorf("Errors = %v, want one for %s", verr, tt.wantField)
+				}
+			}
+
+			// The handler still gets the whole body
+			if tt.body != "" {
+				data, _ := io.ReadAll(req.Body)
+				if string(data) != tt.body {
+					t.Errorf("body left for the handler = %q, want %q", data, tt.body)
+				}
+			}
+		})
+	}
+}
+
+func TestOperation_ValidateResponse(t *testing.T) {
+	spec := loadSpec(t)
+	op, _ := spec.Find("GET", "/quotes/1")
+	quote := `{"id":1,"author":"A","quote":"Q","created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z","version":1}`
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{name: "valid JSON", status: 200, contentType: "application/json", body: quote},
+		{name: "other representation", status: 200, contentType: "text/markdown; charset=utf-8", body: "> Q"},
+		{name: "error", status: 404, contentType: "application/json", body: `{"error":"no found quotes"}`},
+		{name: "not modified", status: 304},
+		{name: "undocumented status", status: 418, contentType: "application/json", body: `{"error":"teapot"}`, wantErr: true},
+		{name: "undocumented media type", status: 200, contentType: "image/png", body: "PNG", wantErr: true},
+		{name: "schema mismatch", status: 200, contentType: "application/json", body: `{"id":"1","author":"A"}`, wantErr: true},
+		{name: "not JSON", status: 200, contentType: "application/json", body: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			var body []byte
+			if tt.body != "" {
+				body = []byte(tt.body)
+			}
+			err := op.ValidateResponse(tt.status, header, body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
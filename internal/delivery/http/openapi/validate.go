@@ -0,0 +1,274 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// printer formats the messages of schema errors
+var printer = message.NewPrinter(language.English)
+
+// ValidationError lists the ways a request or a response does not match the document
+type ValidationError struct {
+	// Status is the response a request error calls for: 400, or 415 for a body of a media
+	// type the operation does not accept
+	Status int
+	Errors []*domain.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		messages[i] = fe.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateRequest checks the parameters of r and, when its media type has a schema, its
+// JSON body. The body is read up to maxBodyBytes and left for the handler to read again;
+// larger bodies and bodies that are not JSON at all are left for the handler to reject.
+func (op *Operation) ValidateRequest(r *http.Request, pathParams map[string]string, maxBodyBytes int64) error {
+	verr := &ValidationError{Status: http.StatusBadRequest}
+
+	query := r.URL.Query()
+	for _, param := range op.params {
+		var raw string
+		var present bool
+		switch param.in {
+		case "path":
+			raw, present = pathParams[param.name]
+		case "query":
+			present = query.Has(param.name)
+			raw = query.Get(param.name)
+		case "header":
+			raw = r.Header.Get(param.name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		field := param.in + "." + param.name
+		if !present {
+			if param.required {
+				verr.add(field, "required", "is required")
+			}
+			continue
+		}
+		if param.schema != nil {
+			verr.addSchemaErrors(field, param.schema.Validate(paramValue(raw, param.typ)))
+		}
+	}
+
+	if op.body != nil {
+		if err := op.validateBody(r, maxBodyBytes, verr); err != nil {
+			return err
+		}
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func (op *Operation) validateBody(r *http.Request, maxBodyBytes int64, verr *ValidationError) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		if op.body.required {
+			verr.add("body", "required", "request body is required")
+		}
+		return nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	schema, ok := mediaSchema(op.body.content, contentType)
+	if !ok {
+		allowed := make([]string, 0, len(op.body.content))
+		for mediaType := range op.body.content {
+			allowed = append(allowed, mediaType)
+		}
+		sort.Strings(allowed)
+		return &ValidationError{Status: http.StatusUnsupportedMediaType, Errors: []*domain.FieldError{{
+			Field:   "header.Content-Type",
+			Code:    "media_type",
+			Message: fmt.Sprintf("unsupported Content-Type %q, expected %s", contentType, strings.Join(allowed, " or ")),
+		}}}
+	}
+	if schema == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+	if err != nil || int64(len(data)) > maxBodyBytes {
+		return nil
+	}
+	body, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	verr.addSchemaErrors("body", schema.Validate(body))
+	return nil
+}
+
+// readCloser reads the buffered start of a body followed by the rest, closing the original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ValidateResponse checks that status is documented for the operation and that the
+// Content-Type in header is listed for it. A non-nil body is also checked against the
+// schema of its media type; pass nil for bodies that were not kept, such as streams.
+func (op *Operation) ValidateResponse(status int, header http.Header, body []byte) error {
+	resp := op.response(status)
+	if resp == nil {
+		return &ValidationError{Errors: []*domain.FieldError{{
+			Field:   "status",
+			Code:    "undocumented",
+			Message: fmt.Sprintf("status %d is not documented", status),
+		}}}
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" || len(resp.content) == 0 {
+		return nil
+	}
+	schema, ok := mediaSchema(resp.content, contentType)
+	if !ok {
+		return &ValidationError{Errors: []*domain.FieldError{{
+			Field:   "header.Content-Type",
+			Code:    "undocumented",
+			Message: fmt.Sprintf("Content-Type %q is not documented for status %d", contentType, status),
+		}}}
+	}
+	if schema == nil || body == nil {
+		return nil
+	}
+
+	verr := &ValidationError{}
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		verr.add("body", "json", err.Error())
+	} else {
+		verr.addSchemaErrors("body", schema.Validate(value))
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// HasSchema reports whether bodies of the given media type are described by a schema for
+// the response status, so it is worth keeping them for ValidateResponse
+func (op *Operation) HasSchema(status int, contentType string) bool {
+	resp := op.response(status)
+	if resp == nil {
+		return false
+	}
+	schema, _ := mediaSchema(resp.content, contentType)
+	return schema != nil
+}
+
+// response returns the documented response for a status, falling back to its class, such as
+// 4XX, and to the default response
+func (op *Operation) response(status int) *response {
+	for _, key := range []string{strconv.Itoa(status), strconv.Itoa(status/100) + "XX", "default"} {
+		if resp := op.responses[key]; resp != nil {
+			return resp
+		}
+	}
+	return nil
+}
+
+// mediaSchema finds the entry of content for a Content-Type, trying the exact media type
+// and then the type/* and */* ranges. A missing Content-Type is taken as
+// application/octet-stream.
+func mediaSchema(content map[string]*jsonschema.Schema, contentType string) (*jsonschema.Schema, bool) {
+	mediaType := "application/octet-stream"
+	if contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, false
+		}
+	}
+
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, candidate := range []string{mediaType, major + "/*", "*/*"} {
+		if schema, ok := content[candidate]; ok {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
+// paramValue reads the text of a parameter as the JSON type its schema declares. Text that
+// is not of that type stays a string, so the schema reports the mismatch. Schemas that do
+// not declare a type get numbers as numbers and anything else as a string.
+func paramValue(raw, typ string) interface{} {
+	switch typ {
+	case "string":
+		return raw
+	case "boolean":
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+		return raw
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return json.Number(raw)
+	}
+	return raw
+}
+
+func (e *ValidationError) add(field, code, message string) {
+	e.Errors = append(e.Errors, &domain.FieldError{Field: field, Code: code, Message: message})
+}
+
+// addSchemaErrors adds an error for every keyword the value failed, naming the field by
+// the location of the value within the parameter or body, such as body.tags.0
+func (e *ValidationError) addSchemaErrors(field string, err error) {
+	if err == nil {
+		return
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		e.add(field, "invalid", err.Error())
+		return
+	}
+	e.addCauses(field, verr)
+}
+
+// addCauses walks down to the keywords that failed; the errors above them, such as for
+// $ref or anyOf, only say that something below failed
+func (e *ValidationError) addCauses(field string, verr *jsonschema.ValidationError) {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			e.addCauses(field, cause)
+		}
+		return
+	}
+
+	name := field
+	for _, token := range verr.InstanceLocation {
+		name += "." + token
+	}
+	keywords := verr.ErrorKind.KeywordPath()
+	code := "invalid"
+	if len(keywords) > 0 {
+		code = keywords[len(keywords)-1]
+	}
+	e.add(name, code, verr.ErrorKind.LocalizedString(printer))
+}
@@ -1,33 +1,127 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"github.com/shoksin/quotes-service/internal/card"
+	"github.com/shoksin/quotes-service/internal/delivery/http/render"
 	"github.com/shoksin/quotes-service/internal/domain"
+	"iter"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type QuoteUseCase interface {
-	CreateQuote(req *domain.CreateQuoteRequest) (*domain.Quote, error)
+	CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error)
 	GetAllQuotes() ([]*domain.Quote, error)
 	GetQuotesByAuthor(author string) ([]*domain.Quote, error)
 	GetRandomQuote() (*domain.Quote, error)
-	DeleteQuote(id int) error
+	GetQuote(id int) (*domain.Quote, error)
+	UpdateQuote(ctx context.Context, id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error)
+	GetQuotesVersion() (*domain.CollectionVersion, error)
+	GetDailyQuote(day time.Time) (*domain.Quote, error)
+	DeleteQuote(ctx context.Context, id, version int) error
+	CreateQuotes(ctx context.Context, req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error)
+	DeleteQuotes(ctx context.Context, req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error)
+	ExportQuotes(fn func(*domain.Quote) error) error
+	ImportQuotes(ctx context.Context, records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error)
+	GetRecentQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error)
+	GetRevisions(id int) ([]*domain.Revision, error)
+	GetRevision(id, revision int) (*domain.Revision, error)
+	DiffRevisions(id, from, to int) (*domain.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, id, revision, version int) (*domain.Quote, error)
+	GetTrash(ctx context.Context) ([]*domain.Quote, error)
+	GetQuoteWithDeleted(ctx context.Context, id int) (*domain.Quote, error)
+	RestoreQuote(ctx context.Context, id int) (*domain.Quote, error)
+	HardDeleteQuote(ctx context.Context, id, version int) error
+	GetAuditEvents(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)
+	ExportAuditEvents(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error
+	VerifyAuditLog(ctx context.Context) (*domain.AuditVerification, error)
+	GetWebhooks(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, id int) (*domain.WebhookSubscription, error)
+	CreateWebhook(ctx context.Context, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, id int, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, subscriptionID int, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error)
+	StreamEvents(ctx context.Context, lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error)
+	ListQuotes(filter domain.QuoteFilter) (*domain.QuotePage, error)
+	GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error)
+	CreateAPIKey(ctx context.Context, req *domain.APIKeyRequest) (*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	FilterQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error)
+	GetStats() (*domain.CollectionStats, error)
+	SearchQuotes(query string, limit int) ([]*domain.Quote, error)
 }
 
 type QuoteHandler struct {
-	quoteUseCase QuoteUseCase
+	quoteUseCase       QuoteUseCase
+	maxBodyBytes       int64
+	maxBatchBodyBytes  int64
+	maxImportBodyBytes int64
+	renderers          *render.Registry
+	baseURL            string
+	feed               FeedConfig
+	embed              EmbedConfig
+	cards              *card.Cache
+	stream             StreamConfig
+	websocket          WebSocketConfig
+	versions           VersionConfig
+	statsMaxAge        time.Duration
 }
 
-func NewQuoteHandler(quoteUseCase QuoteUseCase) *QuoteHandler {
-	return &QuoteHandler{
-		quoteUseCase: quoteUseCase,
+// Option configures optional QuoteHandler settings
+type Option func(*QuoteHandler)
+
+// DefaultBaseURL is the public root of the service used unless WithBaseURL is given
+const DefaultBaseURL = "http://localhost:8080"
+
+// WithBaseURL sets the public root of the service used for absolute links in feeds and embeds
+func WithBaseURL(baseURL string) Option {
+	return func(h *QuoteHandler) {
+		h.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithMaxBodyBytes limits the size of JSON request bodies
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *QuoteHandler) {
+		h.maxBodyBytes = n
 	}
 }
 
+func NewQuoteHandler(quoteUseCase QuoteUseCase, opts ...Option) *QuoteHandler {
+	h := &QuoteHandler{
+		quoteUseCase:       quoteUseCase,
+		maxBodyBytes:       DefaultMaxBodyBytes,
+		maxBatchBodyBytes:  DefaultMaxBatchBodyBytes,
+		maxImportBodyBytes: DefaultMaxImportBodyBytes,
+		renderers:          render.Default(),
+		baseURL:            DefaultBaseURL,
+		feed:               DefaultFeedConfig,
+		embed:              DefaultEmbedConfig,
+		cards:              card.NewCache(DefaultCardCacheBytes),
+		stream:             DefaultStreamConfig,
+		websocket:          DefaultWebSocketConfig,
+		versions:           DefaultVersionConfig,
+		statsMaxAge:        DefaultStatsMaxAge,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string               `json:"error"`
+	Details []*domain.FieldError `json:"details,omitempty"`
+	// Current is the stored quote a conflicting write was rejected against
+	Current *domain.Quote `json:"current,omitempty"`
+	// Report is what an aborted import committed before it failed
+	Report *domain.ImportReport `json:"report,omitempty"`
 }
 
 func (h *QuoteHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -47,13 +141,18 @@ func (h *QuoteHandler) writeError(w http.ResponseWriter, status int, message str
 func (h *QuoteHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
 	var req domain.CreateQuoteRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidJSON)
+	if rerr := decodeJSON(w, r, &req, h.maxBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
 		return
 	}
 
-	quote, err := h.quoteUseCase.CreateQuote(&req)
+	quote, err := h.quoteUseCase.CreateQuote(r.Context(), &req)
 	if err != nil {
+		var verr *domain.ValidationError
+		if errors.As(err, &verr) {
+			h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: domain.MsgValidationFailed, Details: verr.Errors})
+			return
+		}
 		switch err {
 		case domain.ErrInvalidAuthor, domain.ErrInvalidQuote:
 			h.writeError(w, http.StatusBadRequest, err.Error())
@@ -68,10 +167,38 @@ func (h *QuoteHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
 
 // GetQuotes GET /quotes с опциональным фильтром ?author=Name
 func (h *QuoteHandler) GetQuotes(w http.ResponseWriter, r *http.Request) {
+	renderer, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Has("fields") {
+		if renderer, ok = h.sparseRenderer(w, r, renderer); !ok {
+			return
+		}
+	}
+
+	// The collection version is read before the list, so a concurrent write can only make
+	// the validators older than the body and never lets a client keep a stale list
+	version, err := h.quoteUseCase.GetQuotesVersion()
+	if err == nil {
+		etag := listETag(version, requestVersion(r), renderer.ContentType(), r.URL.RawQuery)
+		if notModified(w, r, etag, version.UpdatedAt) {
+			return
+		}
+	}
+
+	if paged(r.URL.Query()) {
+		h.getQuotesPage(w, r, renderer)
+		return
+	}
+	if filtered(r.URL.Query()) {
+		h.getFilteredQuotes(w, r, renderer)
+		return
+	}
+
 	author := r.URL.Query().Get("author")
 
 	var quotes []*domain.Quote
-	var err error
 
 	if author != "" {
 		quotes, err = h.quoteUseCase.GetQuotesByAuthor(author)
@@ -88,15 +215,16 @@ func (h *QuoteHandler) GetQuotes(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	if len(quotes) == 0 {
-		h.writeJSON(w, http.StatusOK, struct{}{})
-	} else {
-		h.writeJSON(w, http.StatusOK, quotes)
-	}
+	h.writeQuotes(w, http.StatusOK, renderer, quotes)
 }
 
 // GetRandomQuote GET /quotes/random
 func (h *QuoteHandler) GetRandomQuote(w http.ResponseWriter, r *http.Request) {
+	renderer, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+
 	quote, err := h.quoteUseCase.GetRandomQuote()
 	if err != nil {
 		switch err {
@@ -108,36 +236,205 @@ func (h *QuoteHandler) GetRandomQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, quote)
+	h.writeQuote(w, http.StatusOK, renderer, quote)
 }
 
-// DeleteQuote DELETE /quotes/{id}
-func (h *QuoteHandler) DeleteQuote(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/quotes/")
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+// GetDailyQuote GET /quotes/daily
+func (h *QuoteHandler) GetDailyQuote(w http.ResponseWriter, r *http.Request) {
+	renderer, ok := h.negotiate(w, r)
+	if !ok {
 		return
 	}
 
-	err = h.quoteUseCase.DeleteQuote(id)
+	quote, err := h.quoteUseCase.GetDailyQuote(time.Now())
 	if err != nil {
 		switch err {
-		case domain.ErrInvalidID:
-			h.writeError(w, http.StatusBadRequest, err.Error())
-		case domain.ErrQuoteNotFound:
+		case domain.ErrNoQuotesFound:
 			h.writeError(w, http.StatusNotFound, domain.MsgQuotesNotFound)
 		default:
-			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedDeleteQuote)
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedGetQuotes)
+		}
+		return
+	}
+
+	h.writeQuote(w, http.StatusOK, renderer, quote)
+}
+
+// GetQuote GET /quotes/{id}
+func (h *QuoteHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	renderer, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	quote, err := h.quoteUseCase.GetQuote(id)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetQuotes)
+		return
+	}
+
+	if notModified(w, r, quoteETag(quote, renderer.ContentType()), quote.UpdatedAt) {
+		return
+	}
+	h.writeQuote(w, http.StatusOK, renderer, quote)
+}
+
+// UpdateQuote PUT /quotes/{id}
+func (h *QuoteHandler) UpdateQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	var req domain.UpdateQuoteRequest
+	if rerr := decodeJSON(w, r, &req, h.maxBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	version, ok := h.checkIfMatch(w, r, id, false)
+	if !ok {
+		return
+	}
+	// Neither precondition may silently override the other
+	if req.Version != 0 && version != 0 && req.Version != version {
+		h.writeError(w, http.StatusBadRequest, domain.MsgVersionMismatch)
+		return
+	}
+	if req.Version == 0 {
+		req.Version = version
+	}
+
+	quote, err := h.quoteUseCase.UpdateQuote(r.Context(), id, &req)
+	if err != nil {
+		var verr *domain.ValidationError
+		if errors.As(err, &verr) {
+			h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: domain.MsgValidationFailed, Details: verr.Errors})
+			return
+		}
+		h.writeQuoteError(w, err, domain.MsgFailedUpdateQuote)
+		return
+	}
+
+	h.writeWrittenQuote(w, quote)
+}
+
+// writeWrittenQuote answers a successful write with the quote as JSON and its new validators
+func (h *QuoteHandler) writeWrittenQuote(w http.ResponseWriter, quote *domain.Quote) {
+	w.Header().Set("ETag", quoteETag(quote, render.JSON{}.ContentType()))
+	w.Header().Set("Last-Modified", quote.UpdatedAt.UTC().Format(http.TimeFormat))
+	h.writeJSON(w, http.StatusOK, quote)
+}
+
+// checkIfMatch enforces the If-Match precondition against the current quote, which may be
+// in the trash when withDeleted is set and the caller may see the trash. It writes the
+// response and returns false when the request must not proceed. On a match it returns the
+// version the ETag was taken at, so the write that follows can be made conditional on it
+// and a concurrent change in between is still detected.
+func (h *QuoteHandler) checkIfMatch(w http.ResponseWriter, r *http.Request, id int, withDeleted bool) (int, bool) {
+	if r.Header.Get("If-Match") == "" {
+		return 0, true
+	}
+
+	current, err := h.quoteUseCase.GetQuote(id)
+	if withDeleted && errors.Is(err, domain.ErrQuoteNotFound) {
+		// Only admins see the trash; to anyone else the quote stays not found
+		if trashed, trashErr := h.quoteUseCase.GetQuoteWithDeleted(r.Context(), id); !errors.Is(trashErr, domain.ErrForbidden) {
+			current, err = trashed, trashErr
 		}
+	}
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetQuotes)
+		return 0, false
+	}
+
+	// The client may hold the ETag of any representation it fetched
+	renderers := h.renderers.Renderers()
+	etags := make([]string, 0, len(renderers))
+	for _, renderer := range renderers {
+		etags = append(etags, quoteETag(current, renderer.ContentType()))
+	}
+
+	if !ifMatch(r, etags...) {
+		h.writeError(w, http.StatusPreconditionFailed, domain.MsgPreconditionFailed)
+		return 0, false
+	}
+	return current.Version, true
+}
+
+// writeQuoteError maps errors about a single quote to responses
+func (h *QuoteHandler) writeQuoteError(w http.ResponseWriter, err error, fallback string) {
+	var conflict *domain.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		h.writeConflict(w, conflict.Current)
+	case errors.Is(err, domain.ErrInvalidID), errors.Is(err, domain.ErrInvalidVersion), errors.Is(err, domain.ErrInvalidRevision):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrQuoteNotFound):
+		h.writeError(w, http.StatusNotFound, domain.MsgQuotesNotFound)
+	case errors.Is(err, domain.ErrRevisionNotFound):
+		h.writeError(w, http.StatusNotFound, domain.MsgRevisionNotFound)
+	case errors.Is(err, domain.ErrNotInTrash):
+		h.writeError(w, http.StatusNotFound, domain.MsgNotInTrash)
+	case errors.Is(err, domain.ErrForbidden):
+		h.writeError(w, http.StatusForbidden, domain.MsgAdminRequired)
+	default:
+		h.writeError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// writeConflict answers a write made against a stale version with 409 and the current
+// quote, along with its validators so the client can retry with If-Match
+func (h *QuoteHandler) writeConflict(w http.ResponseWriter, current *domain.Quote) {
+	if current != nil {
+		w.Header().Set("ETag", quoteETag(current, render.JSON{}.ContentType()))
+		w.Header().Set("Last-Modified", current.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	h.writeJSON(w, http.StatusConflict, ErrorResponse{Error: domain.MsgVersionConflict, Current: current})
+}
+
+// DeleteQuote DELETE /quotes/{id}?hard=true|false
+// Quotes are moved to the trash unless an admin asks for a hard delete.
+func (h *QuoteHandler) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	hard, err := parseBoolQuery(r, "hard", false)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A hard delete is mostly used to empty the trash, so it is matched against trashed quotes too
+	version, ok := h.checkIfMatch(w, r, id, hard)
+	if !ok {
+		return
+	}
+
+	if hard {
+		err = h.quoteUseCase.HardDeleteQuote(r.Context(), id, version)
+	} else {
+		err = h.quoteUseCase.DeleteQuote(r.Context(), id, version)
+	}
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedDeleteQuote)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// HealthCheck handles health check endpoint
+// HealthCheck handles health check endpoint; HEAD requests get the headers only
 func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := map[string]string{
 		"status":  "healthy",
@@ -146,13 +443,39 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	if r.Method != http.MethodHead {
+		json.NewEncoder(w).Encode(response)
+	}
 }
 
-// RegisterRoutes register all handler for QuoteHandler
-func (h *QuoteHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/health", HealthCheck)
+// Router is where RegisterRoutes adds the routes; *http.ServeMux is one
+type Router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// RegisterRoutes register all handler for QuoteHandler. The API is served under /v1 and
+// /v2, and at the root by the default version; health checks, metrics and the API
+// documentation are not versioned.
+func (h *QuoteHandler) RegisterRoutes(mux Router) {
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			HealthCheck(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+	h.registerMetricsRoutes(mux)
+	h.registerDocsRoutes(mux)
 
+	for _, version := range APIVersions {
+		h.registerAPIRoutes(versionRouter{mux: mux, h: h, route: versionRoute{version: version, base: "/" + string(version)}})
+	}
+	h.registerAPIRoutes(versionRouter{mux: mux, h: h, route: versionRoute{version: h.versions.Default}})
+}
+
+// registerAPIRoutes registers the routes of one version. Handlers that answer differently
+// in another version tell them apart with requestVersion.
+func (h *QuoteHandler) registerAPIRoutes(mux Router) {
 	mux.HandleFunc("/quotes/random", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			h.GetRandomQuote(w, r)
@@ -161,22 +484,81 @@ func (h *QuoteHandler) RegisterRoutes(mux *http.ServeMux) {
 		}
 	})
 
+	mux.HandleFunc("/quotes/daily", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetDailyQuote(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
 	mux.HandleFunc("/quotes", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
 			h.CreateQuote(w, r)
 		case http.MethodGet:
 			h.GetQuotes(w, r)
+		case http.MethodDelete:
+			h.DeleteQuotes(w, r)
 		default:
 			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		}
 	})
 
+	mux.HandleFunc("/quotes:batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			h.CreateQuotes(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/quotes/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.ExportQuotes(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/quotes/import", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			h.ImportQuotes(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
 	mux.HandleFunc("/quotes/{id}", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodDelete {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetQuote(w, r)
+		case http.MethodPut:
+			h.UpdateQuote(w, r)
+		case http.MethodDelete:
 			h.DeleteQuote(w, r)
-		} else {
+		case http.MethodPost:
+			// POST /quotes/{id}:restore shares the segment with the ID
+			if strings.HasSuffix(r.PathValue("id"), restoreSuffix) {
+				h.RestoreQuote(w, r)
+				return
+			}
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		default:
 			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		}
 	})
+
+	h.registerSearchRoutes(mux)
+	h.registerRevisionRoutes(mux)
+	h.registerTrashRoutes(mux)
+	h.registerAuditRoutes(mux)
+	h.registerWebhookRoutes(mux)
+	h.registerAPIKeyRoutes(mux)
+	h.registerStreamRoutes(mux)
+	h.registerWebSocketRoutes(mux)
+	h.registerFeedRoutes(mux)
+	h.registerCardRoutes(mux)
+	h.registerEmbedRoutes(mux)
+	h.registerStatsRoutes(mux)
 }
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"iter"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -17,10 +19,45 @@ type MockQuoteUseCase struct {
 	GetAllQuotesFunc      func() ([]*domain.Quote, error)
 	GetQuotesByAuthorFunc func(author string) ([]*domain.Quote, error)
 	GetRandomQuoteFunc    func() (*domain.Quote, error)
-	DeleteQuoteFunc       func(id int) error
+	GetQuoteFunc          func(id int) (*domain.Quote, error)
+	GetDailyQuoteFunc     func(day time.Time) (*domain.Quote, error)
+	UpdateQuoteFunc       func(id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error)
+	GetQuotesVersionFunc  func() (*domain.CollectionVersion, error)
+	DeleteQuoteFunc       func(id, version int) error
+	CreateQuotesFunc      func(req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error)
+	DeleteQuotesFunc      func(req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error)
+	ExportQuotesFunc      func(fn func(*domain.Quote) error) error
+	ImportQuotesFunc      func(records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error)
+	GetRecentQuotesFunc   func(filter domain.QuoteFilter) ([]*domain.Quote, error)
+	GetRevisionsFunc      func(id int) ([]*domain.Revision, error)
+	GetRevisionFunc       func(id, revision int) (*domain.Revision, error)
+	DiffRevisionsFunc     func(id, from, to int) (*domain.RevisionDiff, error)
+	RestoreRevisionFunc   func(id, revision, version int) (*domain.Quote, error)
+	GetTrashFunc          func(ctx context.Context) ([]*domain.Quote, error)
+	GetWithDeletedFunc    func(ctx context.Context, id int) (*domain.Quote, error)
+	RestoreQuoteFunc      func(id int) (*domain.Quote, error)
+	HardDeleteQuoteFunc   func(ctx context.Context, id, version int) error
+	GetAuditEventsFunc    func(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)
+	ExportAuditEventsFunc func(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error
+	VerifyAuditLogFunc    func(ctx context.Context) (*domain.AuditVerification, error)
+	GetWebhooksFunc       func() ([]*domain.WebhookSubscription, error)
+	GetWebhookFunc        func(id int) (*domain.WebhookSubscription, error)
+	CreateWebhookFunc     func(req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	UpdateWebhookFunc     func(id int, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error)
+	DeleteWebhookFunc     func(id int) error
+	GetDeliveriesFunc     func(subscriptionID int, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+	RedeliverWebhookFunc  func(subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error)
+	StreamEventsFunc      func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error)
+	ListQuotesFunc        func(filter domain.QuoteFilter) (*domain.QuotePage, error)
+	GetAPIKeysFunc        func() ([]*domain.APIKey, error)
+	CreateAPIKeyFunc      func(req *domain.APIKeyRequest) (*domain.APIKey, error)
+	RevokeAPIKeyFunc      func(id int) error
+	FilterQuotesFunc      func(filter domain.QuoteFilter) ([]*domain.Quote, error)
+	GetStatsFunc          func() (*domain.CollectionStats, error)
+	SearchQuotesFunc      func(query string, limit int) ([]*domain.Quote, error)
 }
 
-func (m *MockQuoteUseCase) CreateQuote(req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+func (m *MockQuoteUseCase) CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
 	if m.CreateQuoteFunc != nil {
 		return m.CreateQuoteFunc(req)
 	}
@@ -48,13 +85,260 @@ func (m *MockQuoteUseCase) GetRandomQuote() (*domain.Quote, error) {
 	return nil, nil
 }
 
-func (m *MockQuoteUseCase) DeleteQuote(id int) error {
+func (m *MockQuoteUseCase) DeleteQuote(ctx context.Context, id, version int) error {
 	if m.DeleteQuoteFunc != nil {
-		return m.DeleteQuoteFunc(id)
+		return m.DeleteQuoteFunc(id, version)
 	}
 	return nil
 }
 
+func (m *MockQuoteUseCase) CreateQuotes(ctx context.Context, req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+	if m.CreateQuotesFunc != nil {
+		return m.CreateQuotesFunc(req)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) DeleteQuotes(ctx context.Context, req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+	if m.DeleteQuotesFunc != nil {
+		return m.DeleteQuotesFunc(req)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) ExportQuotes(fn func(*domain.Quote) error) error {
+	if m.ExportQuotesFunc != nil {
+		return m.ExportQuotesFunc(fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) ImportQuotes(ctx context.Context, records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error) {
+	if m.ImportQuotesFunc != nil {
+		return m.ImportQuotesFunc(records)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) GetQuote(id int) (*domain.Quote, error) {
+	if m.GetQuoteFunc != nil {
+		return m.GetQuoteFunc(id)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteUseCase) UpdateQuote(ctx context.Context, id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+	if m.UpdateQuoteFunc != nil {
+		return m.UpdateQuoteFunc(id, req)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteUseCase) GetQuotesVersion() (*domain.CollectionVersion, error) {
+	if m.GetQuotesVersionFunc != nil {
+		return m.GetQuotesVersionFunc()
+	}
+	return &domain.CollectionVersion{}, nil
+}
+
+func (m *MockQuoteUseCase) GetDailyQuote(day time.Time) (*domain.Quote, error) {
+	if m.GetDailyQuoteFunc != nil {
+		return m.GetDailyQuoteFunc(day)
+	}
+	return nil, domain.ErrNoQuotesFound
+}
+
+func (m *MockQuoteUseCase) GetRecentQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	if m.GetRecentQuotesFunc != nil {
+		return m.GetRecentQuotesFunc(filter)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteUseCase) GetRevisions(id int) ([]*domain.Revision, error) {
+	if m.GetRevisionsFunc != nil {
+		return m.GetRevisionsFunc(id)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteUseCase) GetRevision(id, revision int) (*domain.Revision, error) {
+	if m.GetRevisionFunc != nil {
+		return m.GetRevisionFunc(id, revision)
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+
+func (m *MockQuoteUseCase) DiffRevisions(id, from, to int) (*domain.RevisionDiff, error) {
+	if m.DiffRevisionsFunc != nil {
+		return m.DiffRevisionsFunc(id, from, to)
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+
+func (m *MockQuoteUseCase) RestoreRevision(ctx context.Context, id, revision, version int) (*domain.Quote, error) {
+	if m.RestoreRevisionFunc != nil {
+		return m.RestoreRevisionFunc(id, revision, version)
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+
+func (m *MockQuoteUseCase) GetTrash(ctx context.Context) ([]*domain.Quote, error) {
+	if m.GetTrashFunc != nil {
+		return m.GetTrashFunc(ctx)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteUseCase) GetQuoteWithDeleted(ctx context.Context, id int) (*domain.Quote, error) {
+	if m.GetWithDeletedFunc != nil {
+		return m.GetWithDeletedFunc(ctx, id)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteUseCase) RestoreQuote(ctx context.Context, id int) (*domain.Quote, error) {
+	if m.RestoreQuoteFunc != nil {
+		return m.RestoreQuoteFunc(id)
+	}
+	return nil, domain.ErrNotInTrash
+}
+
+func (m *MockQuoteUseCase) HardDeleteQuote(ctx context.Context, id, version int) error {
+	if m.HardDeleteQuoteFunc != nil {
+		return m.HardDeleteQuoteFunc(ctx, id, version)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) GetAuditEvents(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if m.GetAuditEventsFunc != nil {
+		return m.GetAuditEventsFunc(ctx, filter)
+	}
+	return &domain.AuditPage{Events: []*domain.AuditEvent{}}, nil
+}
+
+func (m *MockQuoteUseCase) ExportAuditEvents(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	if m.ExportAuditEventsFunc != nil {
+		return m.ExportAuditEventsFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) VerifyAuditLog(ctx context.Context) (*domain.AuditVerification, error) {
+	if m.VerifyAuditLogFunc != nil {
+		return m.VerifyAuditLogFunc(ctx)
+	}
+	return &domain.AuditVerification{Valid: true}, nil
+}
+
+func (m *MockQuoteUseCase) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	if m.GetAPIKeysFunc != nil {
+		return m.GetAPIKeysFunc()
+	}
+	return []*domain.APIKey{}, nil
+}
+
+func (m *MockQuoteUseCase) CreateAPIKey(ctx context.Context, req *domain.APIKeyRequest) (*domain.APIKey, error) {
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(req)
+	}
+	return &domain.APIKey{ID: 1, Name: req.Name, Role: domain.RoleUser, Key: "qk_new"}, nil
+}
+
+func (m *MockQuoteUseCase) RevokeAPIKey(ctx context.Context, id int) error {
+	if m.RevokeAPIKeyFunc != nil {
+		return m.RevokeAPIKeyFunc(id)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) GetWebhooks(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	if m.GetWebhooksFunc != nil {
+		return m.GetWebhooksFunc()
+	}
+	return []*domain.WebhookSubscription{}, nil
+}
+
+func (m *MockQuoteUseCase) GetWebhook(ctx context.Context, id int) (*domain.WebhookSubscription, error) {
+	if m.GetWebhookFunc != nil {
+		return m.GetWebhookFunc(id)
+	}
+	return nil, domain.ErrWebhookNotFound
+}
+
+func (m *MockQuoteUseCase) CreateWebhook(ctx context.Context, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	if m.CreateWebhookFunc != nil {
+		return m.CreateWebhookFunc(req)
+	}
+	return &domain.WebhookSubscription{ID: 1, URL: req.URL}, nil
+}
+
+func (m *MockQuoteUseCase) UpdateWebhook(ctx context.Context, id int, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	if m.UpdateWebhookFunc != nil {
+		return m.UpdateWebhookFunc(id, req)
+	}
+	return &domain.WebhookSubscription{ID: id, URL: req.URL}, nil
+}
+
+func (m *MockQuoteUseCase) DeleteWebhook(ctx context.Context, id int) error {
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(id)
+	}
+	return nil
+}
+
+func (m *MockQuoteUseCase) GetDeliveries(ctx context.Context, subscriptionID int, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	if m.GetDeliveriesFunc != nil {
+		return m.GetDeliveriesFunc(subscriptionID, status)
+	}
+	return []*domain.WebhookDelivery{}, nil
+}
+
+func (m *MockQuoteUseCase) RedeliverWebhook(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+	if m.RedeliverWebhookFunc != nil {
+		return m.RedeliverWebhookFunc(subscriptionID, deliveryID)
+	}
+	return nil, domain.ErrDeliveryNotFound
+}
+
+func (m *MockQuoteUseCase) StreamEvents(ctx context.Context, lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+	if m.StreamEventsFunc != nil {
+		return m.StreamEventsFunc(lastEventID, filter)
+	}
+	events := make(chan *domain.WebhookEvent)
+	close(events)
+	return events, nil
+}
+
+func (m *MockQuoteUseCase) GetStats() (*domain.CollectionStats, error) {
+	if m.GetStatsFunc != nil {
+		return m.GetStatsFunc()
+	}
+	return &domain.CollectionStats{}, nil
+}
+
+func (m *MockQuoteUseCase) ListQuotes(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+	if m.ListQuotesFunc != nil {
+		return m.ListQuotesFunc(filter)
+	}
+	return &domain.QuotePage{}, nil
+}
+
+func (m *MockQuoteUseCase) FilterQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	if m.FilterQuotesFunc != nil {
+		return m.FilterQuotesFunc(filter)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteUseCase) SearchQuotes(query string, limit int) ([]*domain.Quote, error) {
+	if m.SearchQuotesFunc != nil {
+		return m.SearchQuotesFunc(query, limit)
+	}
+	return []*domain.Quote{}, nil
+}
+
 func TestQuoteHandler_CreateQuote(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -84,7 +368,7 @@ func TestQuoteHandler_CreateQuote(t *testing.T) {
 			name:           "invalid JSON",
 			requestBody:    "invalid json",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"error": domain.MsgInvalidJSON},
+			expectedBody:   map[string]string{"error": domain.MsgInvalidJSON + ": request body must be a JSON object"},
 		},
 		{
 			name: "invalid author error",
@@ -110,6 +394,24 @@ func TestQuoteHandler_CreateQuote(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   map[string]string{"error": domain.MsgFailedCreateQuote},
 		},
+		{
+			name: "structured validation error",
+			requestBody: map[string]string{
+				"author": "",
+				"quote":  "",
+			},
+			mockFunc: func(req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+				return nil, domain.DefaultValidationRules.Validate(req)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error": domain.MsgValidationFailed,
+				"details": []map[string]string{
+					{"field": "author", "code": domain.CodeRequired, "message": "must not be empty"},
+					{"field": "quote", "code": domain.CodeRequired, "message": "must not be empty"},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,6 +423,7 @@ func TestQuoteHandler_CreateQuote(t *testing.T) {
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/quotes", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 
 			handler.CreateQuote(rec, req)
@@ -280,17 +583,94 @@ func TestQuoteHandler_GetRandomQuote(t *testing.T) {
 	}
 }
 
+func TestQuoteHandler_GetRandomQuote_ContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		url                 string
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "default JSON",
+			url:                 "/quotes/random",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "plain text by Accept",
+			url:                 "/quotes/random",
+			accept:              "text/plain",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "\"Random Quote\" — Random Author\n",
+		},
+		{
+			name:                "format override wins over Accept",
+			url:                 "/quotes/random?format=md",
+			accept:              "text/plain",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedBody:        "> Random Quote\n>\n> — Random Author\n",
+		},
+		{
+			name:           "unsupported Accept",
+			url:            "/quotes/random",
+			accept:         "image/png",
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "unsupported format",
+			url:            "/quotes/random?format=yaml",
+			expectedStatus: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				GetRandomQuoteFunc: func() (*domain.Quote, error) {
+					return &domain.Quote{ID: 1, Author: "Random Author", Quote: "Random Quote"}, nil
+				},
+			}
+			handler := NewQuoteHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.GetRandomQuote(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if rec.Header().Get("Vary") != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", rec.Header().Get("Vary"))
+			}
+			if tt.expectedContentType != "" && rec.Header().Get("Content-Type") != tt.expectedContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedContentType, rec.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
 func TestQuoteHandler_DeleteQuote(t *testing.T) {
 	tests := []struct {
 		name           string
 		url            string
-		mockFunc       func(id int) error
+		mockFunc       func(id, version int) error
 		expectedStatus int
 	}{
 		{
 			name: "successful deletion",
 			url:  "/quotes/1",
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return nil
 			},
 			expectedStatus: http.StatusNoContent,
@@ -303,7 +683,7 @@ func TestQuoteHandler_DeleteQuote(t *testing.T) {
 		{
 			name: "invalid ID error",
 			url:  "/quotes/0",
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return domain.ErrInvalidID
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -311,7 +691,7 @@ func TestQuoteHandler_DeleteQuote(t *testing.T) {
 		{
 			name: "quote not found",
 			url:  "/quotes/999",
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return domain.ErrQuoteNotFound
 			},
 			expectedStatus: http.StatusNotFound,
@@ -319,7 +699,7 @@ func TestQuoteHandler_DeleteQuote(t *testing.T) {
 		{
 			name: "internal server error",
 			url:  "/quotes/1",
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return errors.New("database error")
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -331,12 +711,12 @@ func TestQuoteHandler_DeleteQuote(t *testing.T) {
 			mockUseCase := &MockQuoteUseCase{
 				DeleteQuoteFunc: tt.mockFunc,
 			}
-			handler := NewQuoteHandler(mockUseCase)
+			mux := newRevisionMux(mockUseCase)
 
 			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
 			rec := httptest.NewRecorder()
 
-			handler.DeleteQuote(rec, req)
+			mux.ServeHTTP(rec, req)
 
 			if rec.Code != tt.expectedStatus {
 				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
@@ -365,6 +745,60 @@ func TestHealthCheck(t *testing.T) {
 	if response["service"] != "quotes-service" {
 		t.Errorf("expected service 'quotes-service', got '%s'", response["service"])
 	}
+
+	req = httptest.NewRequest(http.MethodHead, "/health", nil)
+	rec = httptest.NewRecorder()
+
+	HealthCheck(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("expected HEAD to answer %d without a body, got %d %q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+func TestQuoteHandler_GetDailyQuote(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockFunc       func(day time.Time) (*domain.Quote, error)
+		expectedStatus int
+	}{
+		{
+			name: "daily quote",
+			mockFunc: func(day time.Time) (*domain.Quote, error) {
+				return &domain.Quote{ID: 1, Author: "Author", Quote: "Quote"}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no quotes",
+			mockFunc: func(day time.Time) (*domain.Quote, error) {
+				return nil, domain.ErrNoQuotesFound
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "repository error",
+			mockFunc: func(day time.Time) (*domain.Quote, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewQuoteHandler(&MockQuoteUseCase{GetDailyQuoteFunc: tt.mockFunc})
+			mux := http.NewServeMux()
+			handler.RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/quotes/daily", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
 }
 
 func TestQuoteHandler_RegisterRoutes(t *testing.T) {
@@ -384,6 +818,36 @@ func TestQuoteHandler_RegisterRoutes(t *testing.T) {
 		{http.MethodGet, "/quotes"},
 		{http.MethodGet, "/quotes/random"},
 		{http.MethodDelete, "/quotes/1"},
+		{http.MethodPatch, "/quotes/1"},
+		{http.MethodPost, "/quotes:batch"},
+		{http.MethodDelete, "/quotes"},
+		{http.MethodGet, "/quotes/export"},
+		{http.MethodPost, "/quotes/import"},
+		{http.MethodGet, "/feeds/quotes.atom"},
+		{http.MethodGet, "/feeds/quotes.rss"},
+		{http.MethodGet, "/feeds/authors/Seneca/quotes.atom"},
+		{http.MethodGet, "/feeds/tags/stoicism/quotes.rss"},
+		{http.MethodPost, "/quotes/1/card.svg"},
+		{http.MethodPost, "/quotes/1/card.png"},
+		{http.MethodPost, "/quotes/daily"},
+		{http.MethodGet, "/oembed"},
+		{http.MethodGet, "/embed/widget.js"},
+		{http.MethodPost, "/embed/quotes/daily"},
+		{http.MethodPost, "/quotes/1/revisions"},
+		{http.MethodPost, "/quotes/1/revisions/diff"},
+		{http.MethodGet, "/quotes/1/revisions/2:restore"},
+		{http.MethodGet, "/trash"},
+		{http.MethodGet, "/quotes/1:restore"},
+		{http.MethodGet, "/admin/audit"},
+		{http.MethodGet, "/admin/audit/export"},
+		{http.MethodPost, "/admin/audit/verify"},
+		{http.MethodGet, "/admin/webhooks"},
+		{http.MethodPatch, "/admin/webhooks/1"},
+		{http.MethodGet, "/admin/webhooks/1/deliveries"},
+		{http.MethodGet, "/admin/webhooks/1/deliveries/2:redeliver"},
+		{http.MethodPost, "/quotes/stream"},
+		{http.MethodPost, "/ws"},
+		{http.MethodPost, "/debug/vars"},
 	}
 
 	for _, tt := range tests {
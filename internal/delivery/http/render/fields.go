@@ -0,0 +1,88 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// QuoteFields are the JSON fields of a quote a sparse fieldset can select, in output order
+var QuoteFields = []string{"id", "author", "quote", "tags", "created_at", "updated_at", "version"}
+
+// ParseFields reads a comma-separated fieldset such as id,quote
+func ParseFields(raw string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(QuoteFields, field) {
+			return nil, fmt.Errorf("invalid value for fields: %q is not one of %s", field, strings.Join(QuoteFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// SparseJSON renders quotes like JSON, keeping only Fields of each
+type SparseJSON struct {
+	Fields []string
+}
+
+func (SparseJSON) ContentType() string { return JSON{}.ContentType() }
+
+func (s SparseJSON) Quote(w io.Writer, quote *domain.Quote) error {
+	object, err := s.object(quote)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(object)
+}
+
+func (s SparseJSON) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	if len(quotes) == 0 {
+		return JSON{}.Quotes(w, quotes)
+	}
+	objects := make([]json.RawMessage, len(quotes))
+	for i, quote := range quotes {
+		object, err := s.object(quote)
+		if err != nil {
+			return err
+		}
+		objects[i] = object
+	}
+	return json.NewEncoder(w).Encode(objects)
+}
+
+// object encodes the selected fields of quote in QuoteFields order
+func (s SparseJSON) object(quote *domain.Quote) (json.RawMessage, error) {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, field := range QuoteFields {
+		value, ok := all[field]
+		if !ok || !slices.Contains(s.Fields, field) {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(field)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package render
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// JSON renders quotes the same way the API always has
+type JSON struct{}
+
+func (JSON) ContentType() string { return "application/json" }
+
+func (JSON) Quote(w io.Writer, quote *domain.Quote) error {
+	return json.NewEncoder(w).Encode(quote)
+}
+
+func (JSON) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	if len(quotes) == 0 {
+		return json.NewEncoder(w).Encode(struct{}{})
+	}
+	return json.NewEncoder(w).Encode(quotes)
+}
+
+// Text renders `"quote" — author`, one quote per paragraph
+type Text struct{}
+
+func (Text) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (Text) Quote(w io.Writer, quote *domain.Quote) error {
+	_, err := fmt.Fprintf(w, "\"%s\" — %s\n", quote.Quote, quote.Author)
+	return err
+}
+
+func (t Text) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	for i, quote := range quotes {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := t.Quote(w, quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Markdown renders each quote as a blockquote with the author on the last line
+type Markdown struct{}
+
+func (Markdown) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (Markdown) Quote(w io.Writer, quote *domain.Quote) error {
+	var b strings.Builder
+	for _, line := range strings.Split(quote.Quote, "\n") {
+		b.WriteString(strings.TrimRight("> "+escapeMarkdown(line), " "))
+		b.WriteByte('\n')
+	}
+	b.WriteString(">\n> — ")
+	b.WriteString(escapeMarkdown(quote.Author))
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m Markdown) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	for i, quote := range quotes {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := m.Quote(w, quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// HTML renders a minimal document using <figure>, <blockquote> and <cite>
+type HTML struct{}
+
+func (HTML) ContentType() string { return "text/html; charset=utf-8" }
+
+var htmlTemplate = template.Must(template.New("quotes").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+{{- range .Quotes}}
+<figure id="quote-{{.ID}}">
+<blockquote>{{range .Paragraphs}}<p>{{.}}</p>{{end}}</blockquote>
+<figcaption>&mdash; <cite>{{.Author}}</cite></figcaption>
+</figure>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlQuote struct {
+	ID         int
+	Author     string
+	Paragraphs []string
+}
+
+func (h HTML) Quote(w io.Writer, quote *domain.Quote) error {
+	return h.render(w, quote.Author, []*domain.Quote{quote})
+}
+
+func (h HTML) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	return h.render(w, "Quotes", quotes)
+}
+
+func (HTML) render(w io.Writer, title string, quotes []*domain.Quote) error {
+	data := struct {
+		Title  string
+		Quotes []htmlQuote
+	}{Title: title}
+	for _, quote := range quotes {
+		data.Quotes = append(data.Quotes, htmlQuote{
+			ID:         quote.ID,
+			Author:     quote.Author,
+			Paragraphs: strings.Split(quote.Quote, "\n"),
+		})
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// XML renders <quote> elements, wrapped in <quotes> for lists
+type XML struct{}
+
+func (XML) ContentType() string { return "application/xml; charset=utf-8" }
+
+type xmlQuote struct {
+	XMLName   xml.Name `xml:"quote"`
+	ID        int      `xml:"id,attr"`
+	Author    string   `xml:"author"`
+	Text      string   `xml:"text"`
+	Tags      *xmlTags `xml:"tags,omitempty"`
+	CreatedAt string   `xml:"created_at"`
+}
+
+type xmlTags struct {
+	Tags []string `xml:"tag"`
+}
+
+type xmlQuotes struct {
+	XMLName xml.Name    `xml:"quotes"`
+	Quotes  []*xmlQuote `xml:"quote"`
+}
+
+func toXMLQuote(quote *domain.Quote) *xmlQuote {
+	x := &xmlQuote{
+		ID:        quote.ID,
+		Author:    quote.Author,
+		Text:      quote.Quote,
+		CreatedAt: quote.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(quote.Tags) > 0 {
+		x.Tags = &xmlTags{Tags: quote.Tags}
+	}
+	return x
+}
+
+func (x XML) Quote(w io.Writer, quote *domain.Quote) error {
+	return x.encode(w, toXMLQuote(quote))
+}
+
+func (x XML) Quotes(w io.Writer, quotes []*domain.Quote) error {
+	list := xmlQuotes{Quotes: make([]*xmlQuote, 0, len(quotes))}
+	for _, quote := range quotes {
+		list.Quotes = append(list.Quotes, toXMLQuote(quote))
+	}
+	return x.encode(w, list)
+}
+
+func (XML) encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
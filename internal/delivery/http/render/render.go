@@ -0,0 +1,160 @@
+// Package render turns quotes into the response formats negotiated with the client.
+package render
+
+import (
+	"io"
+	"mime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Renderer writes quotes in a single media type
+type Renderer interface {
+	// ContentType is the full Content-Type header value, including charset when relevant
+	ContentType() string
+	Quote(w io.Writer, quote *domain.Quote) error
+	Quotes(w io.Writer, quotes []*domain.Quote) error
+}
+
+type entry struct {
+	names     []string
+	mediaType string
+	renderer  Renderer
+}
+
+// Registry maps media types and ?format= names to renderers.
+// The first registered renderer is the default for requests without Accept.
+type Registry struct {
+	entries []entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a renderer for mediaType, reachable by any of the given format names
+func (reg *Registry) Register(mediaType string, renderer Renderer, names ...string) {
+	reg.entries = append(reg.entries, entry{names: names, mediaType: mediaType, renderer: renderer})
+}
+
+// MediaTypes lists the registered media types in preference order
+func (reg *Registry) MediaTypes() []string {
+	types := make([]string, 0, len(reg.entries))
+	for _, e := range reg.entries {
+		types = append(types, e.mediaType)
+	}
+	return types
+}
+
+// Renderers returns every registered renderer in preference order
+func (reg *Registry) Renderers() []Renderer {
+	renderers := make([]Renderer, 0, len(reg.entries))
+	for _, e := range reg.entries {
+		renderers = append(renderers, e.renderer)
+	}
+	return renderers
+}
+
+// ByName returns the renderer registered under a ?format= name
+func (reg *Registry) ByName(name string) (Renderer, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, e := range reg.entries {
+		if slices.Contains(e.names, name) {
+			return e.renderer, true
+		}
+	}
+	return nil, false
+}
+
+// Negotiate picks the renderer best matching an Accept header value.
+// An empty header selects the default renderer.
+func (reg *Registry) Negotiate(accept string) (Renderer, bool) {
+	if len(reg.entries) == 0 {
+		return nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return reg.entries[0].renderer, true
+	}
+
+	for _, rng := range parseAccept(accept) {
+		for _, e := range reg.entries {
+			if rng.matches(e.mediaType) {
+				return e.renderer, true
+			}
+		}
+	}
+	return nil, false
+}
+
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+	order        int
+}
+
+func (m mediaRange) matches(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	return (m.typ == "*" || m.typ == typ) && (m.subtype == "*" || m.subtype == subtype)
+}
+
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+// parseAccept returns the acceptable media ranges, most preferred first.
+// Ranges with q=0 are dropped.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for i, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if q == 0 {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q, order: i})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+// Default returns a registry with JSON (the default), plain text, Markdown, HTML and XML
+func Default() *Registry {
+	reg := NewRegistry()
+	reg.Register("application/json", JSON{}, "json")
+	reg.Register("text/plain", Text{}, "text", "txt", "plain")
+	reg.Register("text/markdown", Markdown{}, "markdown", "md")
+	reg.Register("text/html", HTML{}, "html")
+	reg.Register("application/xml", XML{}, "xml")
+	reg.Register("text/xml", XML{})
+	return reg
+}
@@ -0,0 +1,183 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func testQuote() *domain.Quote {
+	return &domain.Quote{
+		ID:        7,
+		Author:    "Confucius",
+		Quote:     "Life is simple,\nbut we insist on making it <complicated>.",
+		CreatedAt: time.Date(2023, 12, 7, 10, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	reg := Default()
+
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"text/plain", "text/plain; charset=utf-8"},
+		{"text/*", "text/plain; charset=utf-8"},
+		{"text/html;q=0.9, text/markdown", "text/markdown; charset=utf-8"},
+		{"application/xml;q=0.5, */*;q=0.1", "application/xml; charset=utf-8"},
+		{"text/xml", "application/xml; charset=utf-8"},
+		{"image/png, */*;q=0.2", "application/json"},
+		{"text/html, application/json;q=0", "text/html; charset=utf-8"},
+		{"*/*;q=0.5, text/html", "text/html; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			renderer, ok := reg.Negotiate(tt.accept)
+			if !ok {
+				t.Fatalf("Negotiate(%q) found no renderer", tt.accept)
+			}
+			if renderer.ContentType() != tt.expected {
+				t.Errorf("Negotiate(%q) = %s, want %s", tt.accept, renderer.ContentType(), tt.expected)
+			}
+		})
+	}
+
+	for _, accept := range []string{"image/png", "application/pdf, image/*", "*/*;q=0"} {
+		if _, ok := reg.Negotiate(accept); ok {
+			t.Errorf("Negotiate(%q) should fail", accept)
+		}
+	}
+}
+
+func TestRegistry_ByName(t *testing.T) {
+	reg := Default()
+	for name, expected := range map[string]string{
+		"json": "application/json",
+		"TXT":  "text/plain; charset=utf-8",
+		"md":   "text/markdown; charset=utf-8",
+		"html": "text/html; charset=utf-8",
+		"xml":  "application/xml; charset=utf-8",
+	} {
+		renderer, ok := reg.ByName(name)
+		if !ok || renderer.ContentType() != expected {
+			t.Errorf("ByName(%q) = %v, %v", name, renderer, ok)
+		}
+	}
+	if _, ok := reg.ByName("yaml"); ok {
+		t.Error("ByName(yaml) should fail")
+	}
+}
+
+func TestRenderers_Quote(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer Renderer
+		expected string
+	}{
+		{
+			name:     "text",
+			renderer: Text{},
+			expected: "\"Life is simple,\nbut we insist on making it <complicated>.\" — Confucius\n",
+		},
+		{
+			name:     "markdown",
+			renderer: Markdown{},
+			expected: "> Life is simple,\n> but we insist on making it \\<complicated\\>.\n>\n> — Confucius\n",
+		},
+		{
+			name:     "html",
+			renderer: HTML{},
+			expected: `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Confucius</title>
+</head>
+<body>
+<figure id="quote-7">
+<blockquote><p>Life is simple,</p><p>but we insist on making it &lt;complicated&gt;.</p></blockquote>
+<figcaption>&mdash; <cite>Confucius</cite></figcaption>
+</figure>
+</body>
+</html>
+`,
+		},
+		{
+			name:     "xml",
+			renderer: XML{},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<quote id="7">
+  <author>Confucius</author>
+  <text>Life is simple,&#xA;but we insist on making it &lt;complicated&gt;.</text>
+  <created_at>2023-12-07T10:30:00Z</created_at>
+</quote>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer.Quote(&buf, testQuote()); err != nil {
+				t.Fatalf("Quote() error = %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderers_Quotes(t *testing.T) {
+	quotes := []*domain.Quote{testQuote(), {ID: 8, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity."}}
+
+	for _, renderer := range []Renderer{JSON{}, Text{}, Markdown{}, HTML{}, XML{}} {
+		var buf bytes.Buffer
+		if err := renderer.Quotes(&buf, quotes); err != nil {
+			t.Fatalf("%T.Quotes() error = %v", renderer, err)
+		}
+		if !strings.Contains(buf.String(), "Seneca") || !strings.Contains(buf.String(), "Confucius") {
+			t.Errorf("%T.Quotes() missing authors:\n%s", renderer, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (XML{}).Quotes(&buf, nil); err != nil || !strings.Contains(buf.String(), "<quotes></quotes>") {
+		t.Errorf("empty XML list = %q, %v", buf.String(), err)
+	}
+}
+
+func TestSparseJSON(t *testing.T) {
+	fields, err := ParseFields("quote, id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := (SparseJSON{Fields: fields}).Quotes(&buf, []*domain.Quote{testQuote()}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"id":7,"quote":"Life is simple,\nbut we insist on making it \u003ccomplicated\u003e."}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %s, got %s", expected, buf.String())
+	}
+
+	// Tags are left out of quotes without any, as in the full representation
+	buf.Reset()
+	(SparseJSON{Fields: []string{"tags"}}).Quote(&buf, testQuote())
+	if buf.String() != "{}\n" {
+		t.Errorf("expected an empty object, got %s", buf.String())
+	}
+
+	for _, raw := range []string{"", "id,", "id,deleted_at", "ID"} {
+		if _, err := ParseFields(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
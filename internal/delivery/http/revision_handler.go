@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// restoreSuffix marks the custom method that restores a revision
+const restoreSuffix = ":restore"
+
+// GetRevisions GET /quotes/{id}/revisions
+func (h *QuoteHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	revisions, err := h.quoteUseCase.GetRevisions(id)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetRevisions)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, revisions)
+}
+
+// GetRevision GET /quotes/{id}/revisions/{rev}
+func (h *QuoteHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+	rev, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRevision.Error())
+		return
+	}
+
+	revision, err := h.quoteUseCase.GetRevision(id, rev)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetRevisions)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, revision)
+}
+
+// DiffRevisions GET /quotes/{id}/revisions/diff?from=&to=
+func (h *QuoteHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	query := r.URL.Query()
+	from, fromErr := strconv.Atoi(query.Get("from"))
+	to, toErr := strconv.Atoi(query.Get("to"))
+	if fromErr != nil || toErr != nil {
+		h.writeError(w, http.StatusBadRequest, "from and to must be revision numbers")
+		return
+	}
+
+	d, err := h.quoteUseCase.DiffRevisions(id, from, to)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetRevisions)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, d)
+}
+
+// RestoreRevision POST /quotes/{id}/revisions/{rev}:restore
+func (h *QuoteHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+	rev, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("rev"), restoreSuffix))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRevision.Error())
+		return
+	}
+
+	version, ok := h.checkIfMatch(w, r, id, true)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteUseCase.RestoreRevision(r.Context(), id, rev, version)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedRestoreQuote)
+		return
+	}
+
+	h.writeWrittenQuote(w, quote)
+}
+
+func (h *QuoteHandler) registerRevisionRoutes(mux Router) {
+	mux.HandleFunc("/quotes/{id}/revisions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetRevisions(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/quotes/{id}/revisions/diff", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.DiffRevisions(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// The restore method shares the path segment with the revision number
+	mux.HandleFunc("/quotes/{id}/revisions/{rev}", func(w http.ResponseWriter, r *http.Request) {
+		restore := strings.HasSuffix(r.PathValue("rev"), restoreSuffix)
+		switch {
+		case r.Method == http.MethodGet && !restore:
+			h.GetRevision(w, r)
+		case r.Method == http.MethodPost && restore:
+			h.RestoreRevision(w, r)
+		default:
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/render"
+	"github.com/shoksin/quotes-service/internal/diff"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func newRevisionMux(mockUseCase *MockQuoteUseCase) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewQuoteHandler(mockUseCase).RegisterRoutes(mux)
+	return mux
+}
+
+func TestQuoteHandler_GetRevisions(t *testing.T) {
+	revisions := []*domain.Revision{
+		{QuoteID: 1, Revision: 2, Change: domain.ChangeUpdate, Actor: "anonymous", Snapshot: &domain.Quote{ID: 1, Quote: "New"}},
+		{QuoteID: 1, Revision: 1, Change: domain.ChangeCreate, Actor: "anonymous", Snapshot: &domain.Quote{ID: 1, Quote: "Old"}},
+	}
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetRevisionsFunc: func(id int) ([]*domain.Revision, error) {
+			if id != 1 {
+				return nil, domain.ErrQuoteNotFound
+			}
+			return revisions, nil
+		},
+		GetRevisionFunc: func(id, revision int) (*domain.Revision, error) {
+			for _, rev := range revisions {
+				if rev.QuoteID == id && rev.Revision == revision {
+					return rev, nil
+				}
+			}
+			return nil, domain.ErrRevisionNotFound
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/quotes/1/revisions", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got []*domain.Revision
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 2 || got[0].Revision != 2 || got[0].Change != domain.ChangeUpdate || got[1].Snapshot.Quote != "Old" {
+		t.Errorf("unexpected revisions %+v", got)
+	}
+
+	rec = serve(mux, http.MethodGet, "/quotes/1/revisions/1", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	var one domain.Revision
+	if err := json.NewDecoder(rec.Body).Decode(&one); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if one.Revision != 1 || one.Snapshot.Quote != "Old" {
+		t.Errorf("unexpected revision %+v", one)
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/quotes/9/revisions", http.StatusNotFound},
+		{"/quotes/abc/revisions", http.StatusBadRequest},
+		{"/quotes/1/revisions/5", http.StatusNotFound},
+		{"/quotes/1/revisions/first", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, http.MethodGet, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
+
+func TestQuoteHandler_DiffRevisions(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		DiffRevisionsFunc: func(id, from, to int) (*domain.RevisionDiff, error) {
+			if from != 1 || to != 3 {
+				return nil, domain.ErrRevisionNotFound
+			}
+			return &domain.RevisionDiff{
+				QuoteID: id,
+				From:    from,
+				To:      to,
+				Quote:   diff.Words("a b c", "a x c"),
+			}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/quotes/1/revisions/diff?from=1&to=3", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got struct {
+		From  int `json:"from"`
+		To    int `json:"to"`
+		Quote []struct {
+			Op   string `json:"op"`
+			Text string `json:"text"`
+		} `json:"quote"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.From != 1 || got.To != 3 || len(got.Quote) != 4 || got.Quote[1].Op != "delete" || got.Quote[2].Text != "x" {
+		t.Errorf("unexpected diff %+v", got)
+	}
+
+	if rec = serve(mux, http.MethodGet, "/quotes/1/revisions/diff?from=1", nil, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without to, got %d", rec.Code)
+	}
+	if rec = serve(mux, http.MethodGet, "/quotes/1/revisions/diff?from=1&to=2", nil, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing revision, got %d", rec.Code)
+	}
+}
+
+func TestQuoteHandler_RestoreRevision(t *testing.T) {
+	current := &domain.Quote{
+		ID:        1,
+		Author:    "Seneca",
+		Quote:     "Edited",
+		UpdatedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC),
+		Version:   3,
+	}
+	var gotRevision, gotVersion int
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			q := *current
+			return &q, nil
+		},
+		RestoreRevisionFunc: func(id, revision, version int) (*domain.Quote, error) {
+			gotRevision, gotVersion = revision, version
+			if revision == 9 {
+				return nil, domain.ErrRevisionNotFound
+			}
+			if version != 0 && version != current.Version {
+				q := *current
+				return nil, &domain.VersionConflictError{Current: &q}
+			}
+			return &domain.Quote{ID: id, Author: "Seneca", Quote: "Original", UpdatedAt: current.UpdatedAt.Add(time.Minute), Version: 4}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodPost, "/quotes/1/revisions/1:restore", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var restored domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&restored); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if restored.Quote != "Original" || restored.Version != 4 || rec.Header().Get("ETag") == "" {
+		t.Errorf("expected the restored quote with an ETag, got %+v", restored)
+	}
+	if gotRevision != 1 || gotVersion != 0 {
+		t.Errorf("expected an unconditional restore of revision 1, got revision %d version %d", gotRevision, gotVersion)
+	}
+
+	etag := quoteETag(current, "application/json")
+	if rec = serve(mux, http.MethodPost, "/quotes/1/revisions/2:restore", nil, map[string]string{"If-Match": etag}); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if gotVersion != current.Version {
+		t.Errorf("expected the restore to be conditional on version %d, got %d", current.Version, gotVersion)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{name: "stale If-Match", method: http.MethodPost, path: "/quotes/1/revisions/1:restore", headers: map[string]string{"If-Match": `"stale"`}, expectedStatus: http.StatusPreconditionFailed},
+		{name: "missing revision", method: http.MethodPost, path: "/quotes/1/revisions/9:restore", expectedStatus: http.StatusNotFound},
+		{name: "invalid revision", method: http.MethodPost, path: "/quotes/1/revisions/x:restore", expectedStatus: http.StatusBadRequest},
+		{name: "missing custom method", method: http.MethodPost, path: "/quotes/1/revisions/1", expectedStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(mux, tt.method, tt.path, nil, tt.headers); rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_RestoreRevision_IfMatchInTrash(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	trashed := &domain.Quote{ID: 4, Author: "Seneca", Quote: "Gone", Version: 3, UpdatedAt: deletedAt, DeletedAt: &deletedAt}
+	var gotVersion int
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetWithDeletedFunc: func(ctx context.Context, id int) (*domain.Quote, error) {
+			if !domain.PrincipalFromContext(ctx).IsAdmin() {
+				return nil, domain.ErrForbidden
+			}
+			return trashed, nil
+		},
+		RestoreRevisionFunc: func(id, revision, version int) (*domain.Quote, error) {
+			gotVersion = version
+			if revision == 2 {
+				// Changed again between the precondition and the restore
+				q := *trashed
+				q.Version++
+				return nil, &domain.VersionConflictError{Current: &q}
+			}
+			return &domain.Quote{ID: id, Author: "Seneca", Quote: "Original", UpdatedAt: deletedAt.Add(time.Minute), Version: 4}, nil
+		},
+	})
+	etag := quoteETag(trashed, render.JSON{}.ContentType())
+
+	restore := func(principal *domain.Principal, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if principal != nil {
+			req = req.WithContext(domain.WithPrincipal(req.Context(), principal))
+		}
+		req.Header.Set("If-Match", etag)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	admin := &domain.Principal{Name: "root", Role: domain.RoleAdmin}
+
+	if rec := restore(admin, "/quotes/4/revisions/1:restore"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotVersion != trashed.Version {
+		t.Errorf("expected the restore to be conditional on version %d, got %d", trashed.Version, gotVersion)
+	}
+	if rec := restore(admin, "/quotes/4/revisions/2:restore"); rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a conflict in the trash, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// The trash is hidden from everyone else
+	if rec := restore(nil, "/quotes/4/revisions/1:restore"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an anonymous caller, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/render"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// paged reports whether GET /quotes asks for one page rather than the whole collection
+func paged(query url.Values) bool {
+	return query.Has("limit") || query.Has("cursor")
+}
+
+// filtered reports whether GET /quotes narrows or sorts the collection beyond an author
+func filtered(query url.Values) bool {
+	for _, name := range []string{"tag", "sort", "created_after", "created_before", "min_length", "max_length"} {
+		if query.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteCursorToken is the cursor of a sorted listing before encoding
+type quoteCursorToken struct {
+	Sort domain.QuoteSort `json:"sort"`
+	Key  string           `json:"key"`
+	ID   int              `json:"id"`
+}
+
+// encodeQuoteCursor returns the cursor continuing a listing sorted by sort after cursor.
+// Listings in ID order keep the bare ID; sorted ones carry the sort key as base64url JSON.
+func encodeQuoteCursor(sort domain.QuoteSort, cursor domain.QuoteCursor) string {
+	if sort == "" {
+		return strconv.Itoa(cursor.ID)
+	}
+	raw, _ := json.Marshal(quoteCursorToken{Sort: sort, Key: cursor.Key, ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeQuoteCursor reverses encodeQuoteCursor, rejecting the cursor of another sort order
+func decodeQuoteCursor(token string, sort domain.QuoteSort) (domain.QuoteCursor, error) {
+	if token == "" {
+		return domain.QuoteCursor{}, nil
+	}
+	if sort == "" {
+		id, err := strconv.Atoi(token)
+		if err != nil {
+			return domain.QuoteCursor{}, domain.ErrInvalidPageToken
+		}
+		return domain.QuoteCursor{ID: id}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return domain.QuoteCursor{}, domain.ErrInvalidPageToken
+	}
+	var decoded quoteCursorToken
+	if err = json.Unmarshal(raw, &decoded); err != nil || decoded.Sort != sort {
+		return domain.QuoteCursor{}, domain.ErrInvalidPageToken
+	}
+	return domain.QuoteCursor{Key: decoded.Key, ID: decoded.ID}, nil
+}
+
+// parseDateQuery reads a date such as 2026-01-02, meaning its start in UTC, or an RFC 3339 time
+func parseDateQuery(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if value, err := time.Parse(time.DateOnly, raw); err == nil {
+		return value, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errors.New("invalid value for " + name + ": expected a date such as 2026-01-02 or an RFC 3339 time")
+	}
+	return value, nil
+}
+
+// parseQuoteFilter reads the filters, sort and page of GET /quotes; the cursor is the one
+// linked from the previous page
+func parseQuoteFilter(r *http.Request) (domain.QuoteFilter, error) {
+	query := r.URL.Query()
+	filter := domain.QuoteFilter{
+		Author: query.Get("author"),
+		Tag:    query.Get("tag"),
+		Sort:   domain.QuoteSort(query.Get("sort")),
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseDateQuery(r, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseDateQuery(r, "created_before"); err != nil {
+		return filter, err
+	}
+	if filter.MinLength, err = parseIntQuery(r, "min_length"); err != nil {
+		return filter, err
+	}
+	if filter.MaxLength, err = parseIntQuery(r, "max_length"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = parseIntQuery(r, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.Limit < 0 {
+		return filter, errors.New("invalid value for limit: expected a positive integer")
+	}
+	if filter.After, err = decodeQuoteCursor(query.Get("cursor"), filter.Sort); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// quotePage is the JSON body of a page of quotes from v2 on
+type quotePage struct {
+	Quotes     json.RawMessage `json:"quotes"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// getQuotesPage answers GET /quotes?limit=&cursor= with one page of the quotes selected by
+// the other filters, in ID order unless sorted. The next page, if any, is linked from a Link header.
+// In v1 the body stays a plain list; from v2 on JSON pages are wrapped in a quotePage.
+func (h *QuoteHandler) getQuotesPage(w http.ResponseWriter, r *http.Request, renderer render.Renderer) {
+	filter, err := parseQuoteFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.quoteUseCase.ListQuotes(filter)
+	if err != nil {
+		h.writeListError(w, err)
+		return
+	}
+
+	var cursor string
+	if !page.Next.IsZero() {
+		cursor = encodeQuoteCursor(filter.Sort, page.Next)
+		next := r.URL.Query()
+		next.Set("cursor", cursor)
+		w.Header().Add("Link", "<"+r.URL.Path+"?"+next.Encode()+`>; rel="next"`)
+	}
+
+	if requestVersion(r) == APIv1 || !isJSON(renderer) {
+		h.writeQuotes(w, http.StatusOK, renderer, page.Quotes)
+		return
+	}
+
+	body := &quotePage{Quotes: json.RawMessage("[]"), NextCursor: cursor}
+	if len(page.Quotes) > 0 {
+		var buf bytes.Buffer
+		if err = renderer.Quotes(&buf, page.Quotes); err != nil {
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedRender)
+			return
+		}
+		body.Quotes = buf.Bytes()
+	}
+	h.writeJSON(w, http.StatusOK, body)
+}
+
+// getFilteredQuotes answers GET /quotes with the tag, sort, date or length filters and
+// without limit or cursor: every quote they select, unpaged like the whole collection
+func (h *QuoteHandler) getFilteredQuotes(w http.ResponseWriter, r *http.Request, renderer render.Renderer) {
+	filter, err := parseQuoteFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quotes, err := h.quoteUseCase.FilterQuotes(filter)
+	if err != nil {
+		h.writeListError(w, err)
+		return
+	}
+	h.writeQuotes(w, http.StatusOK, renderer, quotes)
+}
+
+func (h *QuoteHandler) writeListError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPageToken):
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidPageToken)
+	case errors.Is(err, domain.ErrInvalidQuoteFilter):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedGetQuotes)
+	}
+}
+
+// isJSON reports whether renderer writes JSON, narrowed to a fieldset or not
+func isJSON(renderer render.Renderer) bool {
+	switch renderer.(type) {
+	case render.JSON, render.SparseJSON:
+		return true
+	}
+	return false
+}
+
+// SearchQuotes GET /quotes/search?q=&limit=
+func (h *QuoteHandler) SearchQuotes(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntQuery(r, "limit")
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quotes, err := h.quoteUseCase.SearchQuotes(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidQuery):
+			h.writeError(w, http.StatusBadRequest, domain.MsgInvalidSearchQuery)
+		default:
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedSearchQuotes)
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, quotes)
+}
+
+func (h *QuoteHandler) registerSearchRoutes(mux Router) {
+	mux.HandleFunc("/quotes/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.SearchQuotes(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetQuotes_Paged(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		page           *domain.QuotePage
+		err            error
+		expectedStatus int
+		expectedFilter domain.QuoteFilter
+		expectedLink   string
+	}{
+		{
+			name:           "first page links the next one",
+			query:          "?limit=2&author=Jane+Austen",
+			page:           &domain.QuotePage{Quotes: []*domain.Quote{{ID: 3}, {ID: 7}}, Next: domain.QuoteCursor{ID: 7}},
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{Author: "Jane Austen", Limit: 2},
+			expectedLink:   `</quotes?author=Jane+Austen&cursor=7&limit=2>; rel="next"`,
+		},
+		{
+			name:           "last page has no link",
+			query:          "?tag=wit&cursor=7",
+			page:           &domain.QuotePage{Quotes: []*domain.Quote{{ID: 9}}},
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{Tag: "wit", After: domain.QuoteCursor{ID: 7}},
+		},
+		{
+			name:           "sorted and filtered",
+			query:          "?sort=-length&created_after=2026-01-02&created_before=2026-02-01T12:00:00Z&min_length=10&max_length=80&limit=2",
+			page:           &domain.QuotePage{Quotes: []*domain.Quote{{ID: 4}, {ID: 2}}, Next: domain.QuoteCursor{Key: "12", ID: 2}},
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{
+				Sort:          "-length",
+				CreatedAfter:  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+				MinLength:     10,
+				MaxLength:     80,
+				Limit:         2,
+			},
+			expectedLink: `</quotes?created_after=2026-01-02&created_before=2026-02-01T12%3A00%3A00Z&cursor=eyJzb3J0IjoiLWxlbmd0aCIsImtleSI6IjEyIiwiaWQiOjJ9&limit=2&max_length=80&min_length=10&sort=-length>; rel="next"`,
+		},
+		{
+			name:           "sorted cursor carries the sort key",
+			query:          "?sort=author&cursor=eyJzb3J0IjoiYXV0aG9yIiwia2V5IjoiSmFuZSBBdXN0ZW4iLCJpZCI6NX0",
+			page:           &domain.QuotePage{Quotes: []*domain.Quote{{ID: 8}}},
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{Sort: "author", After: domain.QuoteCursor{Key: "Jane Austen", ID: 5}},
+		},
+		{
+			name:           "cursor of another sort",
+			query:          "?sort=length&cursor=eyJzb3J0IjoiYXV0aG9yIiwia2V5IjoiSmFuZSBBdXN0ZW4iLCJpZCI6NX0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "sorted cursor is not a token",
+			query:          "?sort=length&cursor=7",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown sort",
+			query:          "?sort=popularity&limit=10",
+			err:            fmt.Errorf("%w: sort must be one of created_at, author, length", domain.ErrInvalidQuoteFilter),
+			expectedStatus: http.StatusBadRequest,
+			expectedFilter: domain.QuoteFilter{Sort: "popularity", Limit: 10},
+		},
+		{
+			name:           "date is not a date",
+			query:          "?created_after=yesterday&limit=10",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "length is not a number",
+			query:          "?min_length=short&limit=10",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit is not a number",
+			query:          "?limit=ten",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative limit",
+			query:          "?limit=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative cursor",
+			query:          "?cursor=-5",
+			err:            domain.ErrInvalidPageToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedFilter: domain.QuoteFilter{After: domain.QuoteCursor{ID: -5}},
+		},
+		{
+			name:           "repository error",
+			query:          "?limit=5",
+			err:            errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedFilter: domain.QuoteFilter{Limit: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *domain.QuoteFilter
+			mockUseCase := &MockQuoteUseCase{
+				ListQuotesFunc: func(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+					got = &filter
+					return tt.page, tt.err
+				},
+				GetAllQuotesFunc: func() ([]*domain.Quote, error) {
+					t.Error("GetAllQuotes called for a paged request")
+					return nil, nil
+				},
+				FilterQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					t.Error("FilterQuotes called for a paged request")
+					return nil, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/quotes"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			NewQuoteHandler(mockUseCase).GetQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if link := rec.Header().Get("Link"); link != tt.expectedLink {
+				t.Errorf("Link = %q, want %q", link, tt.expectedLink)
+			}
+			if tt.expectedStatus == http.StatusOK || tt.err != nil {
+				if got == nil || *got != tt.expectedFilter {
+					t.Errorf("ListQuotes(%+v), want %+v", got, tt.expectedFilter)
+				}
+			}
+			if tt.expectedStatus == http.StatusOK {
+				var quotes []*domain.Quote
+				if err := json.NewDecoder(rec.Body).Decode(&quotes); err != nil || len(quotes) != len(tt.page.Quotes) {
+					t.Errorf("body = %v, %v, want %d quotes", quotes, err, len(tt.page.Quotes))
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_GetQuotes_Fields(t *testing.T) {
+	quotes := []*domain.Quote{
+		{ID: 1, Author: "Jane Austen", Quote: "Pride", Tags: []string{"wit"}, Version: 2},
+		{ID: 2, Author: "Seneca", Quote: "Luck"},
+	}
+	mockUseCase := &MockQuoteUseCase{
+		GetAllQuotesFunc: func() ([]*domain.Quote, error) { return quotes, nil },
+		ListQuotesFunc: func(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+			return &domain.QuotePage{Quotes: quotes[:1], Next: domain.QuoteCursor{ID: 1}}, nil
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "whole collection",
+			query:          "?fields=quote,id",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":1,"quote":"Pride"},{"id":2,"quote":"Luck"}]`,
+		},
+		{
+			name:           "one page",
+			query:          "?limit=1&fields=author,tags",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"author":"Jane Austen","tags":["wit"]}]`,
+		},
+		{
+			name:           "unknown field",
+			query:          "?fields=id,deleted_at",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"invalid value for fields: \"deleted_at\" is not one of`,
+		},
+		{
+			name:           "empty fieldset",
+			query:          "?fields=",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not JSON",
+			query:          "?fields=id&format=text",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "only supported for application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/quotes"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			NewQuoteHandler(mockUseCase).GetQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.expectedBody) {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.expectedBody)
+			}
+			if tt.expectedStatus == http.StatusOK && rec.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_GetQuotes_Filtered(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		err            error
+		expectedStatus int
+		expectedFilter domain.QuoteFilter
+	}{
+		{
+			name:           "sorted and filtered without a page",
+			query:          "?tag=wit&sort=-length&min_length=10",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{Tag: "wit", Sort: "-length", MinLength: 10},
+		},
+		{
+			name:           "author with a tag",
+			query:          "?author=Jane+Austen&tag=wit",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.QuoteFilter{Author: "Jane Austen", Tag: "wit"},
+		},
+		{
+			name:           "unknown sort",
+			query:          "?sort=popularity",
+			err:            fmt.Errorf("%w: sort must be one of created_at, author, length", domain.ErrInvalidQuoteFilter),
+			expectedStatus: http.StatusBadRequest,
+			expectedFilter: domain.QuoteFilter{Sort: "popularity"},
+		},
+	}
+
+	quotes := make([]*domain.Quote, 60)
+	for i := range quotes {
+		quotes[i] = &domain.Quote{ID: i + 1}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *domain.QuoteFilter
+			mockUseCase := &MockQuoteUseCase{
+				FilterQuotesFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					got = &filter
+					return quotes, tt.err
+				},
+				ListQuotesFunc: func(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+					t.Error("ListQuotes called without limit or cursor")
+					return nil, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/quotes"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			NewQuoteHandler(mockUseCase).GetQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if got == nil || *got != tt.expectedFilter {
+				t.Errorf("FilterQuotes(%+v), want %+v", got, tt.expectedFilter)
+			}
+			if link := rec.Header().Get("Link"); link != "" {
+				t.Errorf("Link = %q, want none", link)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				var body []*domain.Quote
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || len(body) != len(quotes) {
+					t.Errorf("body = %d quotes, %v, want all %d", len(body), err, len(quotes))
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_SearchQuotes(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockFunc       func(query string, limit int) ([]*domain.Quote, error)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:  "matches",
+			query: "?q=pride&limit=5",
+			mockFunc: func(query string, limit int) ([]*domain.Quote, error) {
+				if query != "pride" || limit != 5 {
+					t.Errorf("SearchQuotes(%q, %d), want (pride, 5)", query, limit)
+				}
+				return []*domain.Quote{{ID: 1, Author: "Jane Austen", Quote: "Pride"}}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "empty query",
+			query: "?q=",
+			mockFunc: func(query string, limit int) ([]*domain.Quote, error) {
+				return nil, domain.ErrInvalidQuery
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  domain.MsgInvalidSearchQuery,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?q=pride&limit=all",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid value for limit: expected an integer",
+		},
+		{
+			name:  "repository error",
+			query: "?q=pride",
+			mockFunc: func(query string, limit int) ([]*domain.Quote, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  domain.MsgFailedSearchQuotes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewQuoteHandler(&MockQuoteUseCase{SearchQuotesFunc: tt.mockFunc}).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/quotes/search"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedError != "" {
+				var resp ErrorResponse
+				json.NewDecoder(rec.Body).Decode(&resp)
+				if resp.Error != tt.expectedError {
+					t.Errorf("error = %q, want %q", resp.Error, tt.expectedError)
+				}
+			}
+		})
+	}
+}
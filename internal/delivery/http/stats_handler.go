@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DefaultStatsMaxAge is how long clients may reuse the statistics unless WithStatsMaxAge is given
+const DefaultStatsMaxAge = time.Minute
+
+// WithStatsMaxAge sets how long clients and proxies may reuse the statistics
+func WithStatsMaxAge(maxAge time.Duration) Option {
+	return func(h *QuoteHandler) {
+		h.statsMaxAge = maxAge
+	}
+}
+
+// GetStats GET /stats. The statistics are validated by their as_of time, which only
+// changes when they are computed again.
+func (h *QuoteHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.quoteUseCase.GetStats()
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedGetStats)
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.statsMaxAge.Seconds())))
+	if notModified(w, r, hashETag("stats", fmt.Sprint(stats.AsOf.UnixNano())), stats.AsOf) {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *QuoteHandler) registerStatsRoutes(mux Router) {
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetStats(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
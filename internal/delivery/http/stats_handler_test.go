@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetStats(t *testing.T) {
+	asOf := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stats := &domain.CollectionStats{
+		AsOf:          asOf,
+		TotalQuotes:   3,
+		TotalAuthors:  2,
+		QuotesPerDay:  []*domain.StatsBucket{{Start: asOf.Truncate(24 * time.Hour), Quotes: 3}},
+		TopAuthors:    []*domain.AuthorStats{{Author: "Seneca", Quotes: 2, CommonWords: []*domain.WordCount{{Word: "luck", Uses: 2}}}},
+		Length:        domain.LengthStats{Average: 42.5, Min: 20, Max: 65, P50: 42, P90: 60, P99: 64.5},
+		QuotesPerWeek: []*domain.StatsBucket{},
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{
+		GetStatsFunc: func() (*domain.CollectionStats, error) { return stats, nil },
+	}, WithStatsMaxAge(5*time.Minute)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=300" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != asOf.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want the as_of time", got)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["as_of"] != "2026-03-01T12:00:00Z" || body["total_quotes"] != float64(3) {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+
+	// The same snapshot is not sent twice
+	etag := rec.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/v1/stats", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for the same as_of, got %d", rec.Code)
+	}
+
+	stats.AsOf = asOf.Add(time.Minute)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("expected fresh stats to change the ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestQuoteHandler_GetStats_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{
+		GetStatsFunc: func() (*domain.CollectionStats, error) { return nil, errors.New("database error") },
+	}).RegisterRoutes(mux)
+
+	tests := []struct {
+		method         string
+		expectedStatus int
+	}{
+		{http.MethodGet, http.StatusInternalServerError},
+		{http.MethodPost, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, "/stats", nil))
+		if rec.Code != tt.expectedStatus {
+			t.Errorf("%s /stats: expected status %d, got %d", tt.method, tt.expectedStatus, rec.Code)
+		}
+	}
+}
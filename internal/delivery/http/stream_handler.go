@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// streamWriteTimeout bounds each write to an event stream. Streams outlive the server
+// WriteTimeout, so a client that stops reading is detected per write instead.
+const streamWriteTimeout = 10 * time.Second
+
+// StreamConfig describes the server-sent event stream at /quotes/stream
+type StreamConfig struct {
+	// Heartbeat is how often a comment is sent on an idle stream, so proxies keep it open
+	Heartbeat time.Duration
+	// Retry is the reconnection delay suggested to clients
+	Retry time.Duration
+}
+
+// DefaultStreamConfig is used unless WithStream is given
+var DefaultStreamConfig = StreamConfig{
+	Heartbeat: 15 * time.Second,
+	Retry:     3 * time.Second,
+}
+
+// WithStream overrides DefaultStreamConfig
+func WithStream(cfg StreamConfig) Option {
+	return func(h *QuoteHandler) {
+		h.stream = cfg
+	}
+}
+
+// lastEventID reads the Last-Event-ID header a reconnecting EventSource sends, or the
+// last_event_id query parameter for the first connection; 0 when neither is set
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, domain.ErrInvalidEventID
+	}
+	return id, nil
+}
+
+// StreamQuotes GET /quotes/stream?author=Name&tag=tag
+func (h *QuoteHandler) StreamQuotes(w http.ResponseWriter, r *http.Request) {
+	afterID, err := lastEventID(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidLastEventID)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := domain.StreamFilter{Author: query.Get("author"), Tag: query.Get("tag")}
+	events, err := h.quoteUseCase.StreamEvents(r.Context(), afterID, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidEventID) {
+			h.writeError(w, http.StatusBadRequest, domain.MsgInvalidLastEventID)
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, domain.MsgFailedStream)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// flush sends what was written so far, giving up on a client that does not read it in time
+	flush := func() error {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return rc.Flush()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stops nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", h.stream.Retry.Milliseconds())
+	if err := flush(); err != nil {
+		log.Printf("event stream unavailable: %v", err)
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if h.stream.Heartbeat > 0 {
+		ticker := time.NewTicker(h.stream.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The client reconnects after Retry and resumes from the last ID it received
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("failed to encode event %d: %v", event.ID, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-heartbeat:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (h *QuoteHandler) registerStreamRoutes(mux Router) {
+	mux.HandleFunc("/quotes/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.StreamQuotes(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
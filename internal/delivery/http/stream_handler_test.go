@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_StreamQuotes(t *testing.T) {
+	var gotID int64
+	var gotFilter domain.StreamFilter
+	mux := newRevisionMux(&MockQuoteUseCase{
+		StreamEventsFunc: func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+			gotID, gotFilter = lastEventID, filter
+			events := make(chan *domain.WebhookEvent, 2)
+			events <- &domain.WebhookEvent{ID: 8, Type: domain.EventQuoteCreated, Data: json.RawMessage(`{"id":1}`)}
+			events <- &domain.WebhookEvent{ID: 9, Type: domain.EventQuoteDeleted, Data: json.RawMessage(`{"id":2}`)}
+			close(events)
+			return events, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/quotes/stream?author=Seneca&tag=stoic", nil, map[string]string{"Last-Event-ID": "7"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+	if !rec.Flushed {
+		t.Error("expected the stream to be flushed")
+	}
+	if gotID != 7 || gotFilter.Author != "Seneca" || gotFilter.Tag != "stoic" {
+		t.Errorf("expected resumption after 7 for Seneca/stoic, got %d %+v", gotID, gotFilter)
+	}
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "retry: 3000\n\n") {
+		t.Errorf("expected the stream to open with a retry hint, got %q", body)
+	}
+	for _, frame := range []string{
+		"id: 8\nevent: quote.created\ndata: {\"id\":8,\"type\":\"quote.created\",\"occurred_at\":\"0001-01-01T00:00:00Z\",\"data\":{\"id\":1}}\n\n",
+		"id: 9\nevent: quote.deleted\n",
+	} {
+		if !strings.Contains(body, frame) {
+			t.Errorf("expected frame %q in %q", frame, body)
+		}
+	}
+
+	// EventSource cannot set headers on its first connection
+	serve(mux, http.MethodGet, "/quotes/stream?last_event_id=4", nil, nil)
+	if gotID != 4 {
+		t.Errorf("expected the query parameter to be used, got %d", gotID)
+	}
+}
+
+func TestQuoteHandler_StreamQuotes_Errors(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		StreamEventsFunc: func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+			if lastEventID < 0 {
+				return nil, domain.ErrInvalidEventID
+			}
+			return nil, errors.New("database is down")
+		},
+	})
+
+	tests := []struct {
+		lastEventID    string
+		expectedStatus int
+	}{
+		{"abc", http.StatusBadRequest},
+		{"-1", http.StatusBadRequest},
+		{"5", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		rec := serve(mux, http.MethodGet, "/quotes/stream", nil, map[string]string{"Last-Event-ID": tt.lastEventID})
+		if rec.Code != tt.expectedStatus {
+			t.Errorf("Last-Event-ID %q: expected status %d, got %d", tt.lastEventID, tt.expectedStatus, rec.Code)
+		}
+	}
+}
+
+func TestQuoteHandler_StreamQuotes_OutlivesWriteTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	NewQuoteHandler(&MockQuoteUseCase{
+		StreamEventsFunc: func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+			return make(chan *domain.WebhookEvent), nil
+		},
+	}, WithStream(StreamConfig{Heartbeat: 20 * time.Millisecond, Retry: time.Second})).RegisterRoutes(mux)
+
+	srv := httptest.NewUnstartedServer(middleware.LoggingMiddleware(mux))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/quotes/stream", nil)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	start := time.Now()
+	heartbeats := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if scanner.Text() == ": heartbeat" {
+			heartbeats++
+		}
+		if time.Since(start) > 4*srv.Config.WriteTimeout && heartbeats > 2 {
+			return
+		}
+	}
+	t.Fatalf("stream ended after %v and %d heartbeats: %v", time.Since(start), heartbeats, scanner.Err())
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/transfer"
+)
+
+// DefaultMaxImportBodyBytes caps import uploads when no limit is configured
+const DefaultMaxImportBodyBytes int64 = 50 << 20
+
+// transferTimeout bounds each read of an import upload and each write of an export. Both
+// outlive the server timeouts, so a client that stalls is detected per read or write instead.
+const transferTimeout = 30 * time.Second
+
+// deadlineWriter moves the write deadline of a response forward before every write
+type deadlineWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (d deadlineWriter) Write(p []byte) (int, error) {
+	_ = d.rc.SetWriteDeadline(time.Now().Add(transferTimeout))
+	return d.w.Write(p)
+}
+
+// deadlineReader moves the read deadline of a request forward before every read of its body
+type deadlineReader struct {
+	r  io.Reader
+	rc *http.ResponseController
+}
+
+func (d deadlineReader) Read(p []byte) (int, error) {
+	_ = d.rc.SetReadDeadline(time.Now().Add(transferTimeout))
+	return d.r.Read(p)
+}
+
+// WithMaxImportBodyBytes limits the size of import uploads
+func WithMaxImportBodyBytes(n int64) Option {
+	return func(h *QuoteHandler) {
+		h.maxImportBodyBytes = n
+	}
+}
+
+// ExportQuotes GET /quotes/export?format=csv|jsonl|json|fortune
+func (h *QuoteHandler) ExportQuotes(w http.ResponseWriter, r *http.Request) {
+	format := transfer.FormatJSON
+	if name := r.URL.Query().Get("format"); name != "" {
+		var err error
+		if format, err = transfer.ParseFormat(name); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="quotes.%s"`, format.Extension()))
+
+	// Large exports outlive the server WriteTimeout, so each write gets a deadline of its own
+	enc, err := transfer.NewEncoder(format, deadlineWriter{w: w, rc: http.NewResponseController(w)})
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	exported := 0
+	err = h.quoteUseCase.ExportQuotes(func(quote *domain.Quote) error {
+		exported++
+		return enc.Encode(quote)
+	})
+	if err != nil {
+		if exported == 0 {
+			w.Header().Del("Content-Disposition")
+			h.writeError(w, http.StatusInternalServerError, domain.MsgFailedExportQuotes)
+			return
+		}
+		// The status line is already sent, so the truncated body is all the client gets
+		log.Printf("export aborted after %d quotes: %v", exported, err)
+		return
+	}
+
+	if err = enc.Close(); err != nil {
+		log.Printf("failed to finish export: %v", err)
+	}
+}
+
+// ImportQuotes POST /quotes/import?format=csv|jsonl|json|fortune&default_author=Name
+// The format may also be derived from Content-Type (text/csv, application/x-ndjson,
+// application/json, text/plain for fortune files).
+func (h *QuoteHandler) ImportQuotes(w http.ResponseWriter, r *http.Request) {
+	var format transfer.Format
+	if name := r.URL.Query().Get("format"); name != "" {
+		var err error
+		if format, err = transfer.ParseFormat(name); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		var ok bool
+		if format, ok = transfer.FormatFromContentType(r.Header.Get("Content-Type")); !ok {
+			h.writeError(w, http.StatusUnsupportedMediaType, "unable to determine import format, use ?format=csv|jsonl|json|fortune")
+			return
+		}
+	}
+
+	maxBytes := h.maxImportBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxImportBodyBytes
+	}
+	body := http.MaxBytesReader(w, r.Body, maxBytes)
+	// Large uploads outlive the server ReadTimeout, so each read gets a deadline of its own
+	records := transfer.Records(format, deadlineReader{r: body, rc: http.NewResponseController(w)}, transfer.DecodeOptions{
+		DefaultAuthor: r.URL.Query().Get("default_author"),
+	})
+
+	report, err := h.quoteUseCase.ImportQuotes(r.Context(), records)
+	if err != nil {
+		// Chunks written before the failure stay, so the client learns how far the import got
+		resp := ErrorResponse{Error: domain.MsgFailedImportQuotes, Report: report}
+		status := http.StatusInternalServerError
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			status, resp.Error = http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not exceed %d bytes", maxBytesErr.Limit)
+		case errors.Is(err, domain.ErrInvalidImport):
+			status, resp.Error = http.StatusBadRequest, err.Error()
+		}
+		h.writeJSON(w, status, resp)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_ExportQuotes(t *testing.T) {
+	quotes := []*domain.Quote{
+		{ID: 1, Author: "Confucius", Quote: "Quote one", CreatedAt: time.Date(2023, 12, 7, 10, 30, 0, 0, time.UTC), UpdatedAt: time.Date(2023, 12, 7, 10, 30, 0, 0, time.UTC), Version: 1},
+		{ID: 2, Author: "Seneca", Quote: "Quote two", CreatedAt: time.Date(2023, 12, 8, 10, 30, 0, 0, time.UTC), UpdatedAt: time.Date(2023, 12, 9, 10, 30, 0, 0, time.UTC), Version: 2},
+	}
+
+	tests := []struct {
+		name                string
+		url                 string
+		streamErr           error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "default json",
+			url:                 "/quotes/export",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: "[\n" +
+				`{"id":1,"author":"Confucius","quote":"Quote one","created_at":"2023-12-07T10:30:00Z","updated_at":"2023-12-07T10:30:00Z","version":1},` + "\n" +
+				`{"id":2,"author":"Seneca","quote":"Quote two","created_at":"2023-12-08T10:30:00Z","updated_at":"2023-12-09T10:30:00Z","version":2}` + "\n]\n",
+		},
+		{
+			name:                "csv",
+			url:                 "/quotes/export?format=csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,author,quote,created_at\n" +
+				"1,Confucius,Quote one,2023-12-07T10:30:00Z\n" +
+				"2,Seneca,Quote two,2023-12-08T10:30:00Z\n",
+		},
+		{
+			name:                "fortune",
+			url:                 "/quotes/export?format=fortune",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Quote one\n\t\t-- Confucius\n%\nQuote two\n\t\t-- Seneca\n%\n",
+		},
+		{
+			name:           "unsupported format",
+			url:            "/quotes/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error before first row",
+			url:            "/quotes/export?format=jsonl",
+			streamErr:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				ExportQuotesFunc: func(fn func(*domain.Quote) error) error {
+					if tt.streamErr != nil {
+						return tt.streamErr
+					}
+					for _, quote := range quotes {
+						if err := fn(quote); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			handler := NewQuoteHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ExportQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedContentType != "" && rec.Header().Get("Content-Type") != tt.expectedContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedContentType, rec.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_ImportQuotes(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		contentType    string
+		body           string
+		importErr      error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "fortune by query format",
+			url:            "/quotes/import?format=fortune",
+			body:           "First\n\t-- A\n%\nSecond\n\t-- B\n%\n",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "csv by content type",
+			url:            "/quotes/import",
+			contentType:    "text/csv",
+			body:           "author,quote\nA,First\n",
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "unknown content type",
+			url:            "/quotes/import",
+			contentType:    "application/octet-stream",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "invalid file",
+			url:            "/quotes/import?format=json",
+			body:           `{}`,
+			importErr:      domain.ErrInvalidImport,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error",
+			url:            "/quotes/import?format=jsonl",
+			body:           `{"author":"A","quote":"Q"}`,
+			importErr:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockQuoteUseCase{
+				ImportQuotesFunc: func(records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error) {
+					if tt.importErr != nil {
+						return nil, tt.importErr
+					}
+					report := &domain.ImportReport{}
+					for _, err := range records {
+						if err != nil {
+							t.Fatalf("unexpected record error: %v", err)
+						}
+						report.Total++
+						report.Imported++
+					}
+					return report, nil
+				},
+			}
+			handler := NewQuoteHandler(mockUseCase)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ImportQuotes(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedCount > 0 && !strings.Contains(rec.Body.String(), fmt.Sprintf(`"imported":%d`, tt.expectedCount)) {
+				t.Errorf("expected %d imported, got %s", tt.expectedCount, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_ImportQuotes_PartialImport(t *testing.T) {
+	mockUseCase := &MockQuoteUseCase{
+		ImportQuotesFunc: func(records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error) {
+			return &domain.ImportReport{Total: 600, Imported: 500, Errors: []*domain.ImportError{}}, errors.New("database error")
+		},
+	}
+	handler := NewQuoteHandler(mockUseCase)
+
+	rec := httptest.NewRecorder()
+	handler.ImportQuotes(rec, httptest.NewRequest(http.MethodPost, "/quotes/import?format=jsonl", strings.NewReader(`{"author":"A","quote":"Q"}`)))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"report":{"total":600,"imported":500`) {
+		t.Errorf("expected the committed part to be reported, got %s", rec.Body.String())
+	}
+}
+
+func TestQuoteHandler_ImportQuotes_BodyTooLarge(t *testing.T) {
+	mockUseCase := &MockQuoteUseCase{
+		ImportQuotesFunc: func(records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error) {
+			for _, err := range records {
+				if err != nil && !errors.Is(err, domain.ErrMalformedRecord) {
+					return nil, err
+				}
+			}
+			return &domain.ImportReport{}, nil
+		},
+	}
+	handler := NewQuoteHandler(mockUseCase, WithMaxImportBodyBytes(16))
+
+	req := httptest.NewRequest(http.MethodPost, "/quotes/import?format=jsonl", strings.NewReader(strings.Repeat(`{"author":"A","quote":"Q"}`+"\n", 4)))
+	rec := httptest.NewRecorder()
+
+	handler.ImportQuotes(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// GetTrash GET /trash
+func (h *QuoteHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	quotes, err := h.quoteUseCase.GetTrash(r.Context())
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedGetTrash)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, quotes)
+}
+
+// RestoreQuote POST /quotes/{id}:restore
+func (h *QuoteHandler) RestoreQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("id"), restoreSuffix))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.MsgInvalidQuoteID)
+		return
+	}
+
+	quote, err := h.quoteUseCase.RestoreQuote(r.Context(), id)
+	if err != nil {
+		h.writeQuoteError(w, err, domain.MsgFailedRestoreQuote)
+		return
+	}
+
+	h.writeWrittenQuote(w, quote)
+}
+
+func (h *QuoteHandler) registerTrashRoutes(mux Router) {
+	mux.HandleFunc("/trash", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetTrash(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/delivery/http/render"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_GetTrash(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetTrashFunc: func(ctx context.Context) ([]*domain.Quote, error) {
+			return []*domain.Quote{{ID: 4, Author: "Seneca", Quote: "Gone", DeletedAt: &deletedAt}}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/trash", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	var quotes []*domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&quotes); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(quotes) != 1 || quotes[0].DeletedAt == nil || !quotes[0].DeletedAt.Equal(deletedAt) {
+		t.Errorf("expected the trashed quote with deleted_at, got %+v", quotes)
+	}
+}
+
+func TestQuoteHandler_Trash_Forbidden(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetTrashFunc: func(ctx context.Context) ([]*domain.Quote, error) {
+			return nil, domain.ErrForbidden
+		},
+		RestoreQuoteFunc: func(id int) (*domain.Quote, error) {
+			return nil, domain.ErrForbidden
+		},
+	})
+
+	if rec := serve(mux, http.MethodGet, "/trash", nil, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /trash: expected status 403, got %d", rec.Code)
+	}
+	if rec := serve(mux, http.MethodPost, "/quotes/4:restore", nil, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /quotes/4:restore: expected status 403, got %d", rec.Code)
+	}
+}
+
+func TestQuoteHandler_RestoreQuote(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		RestoreQuoteFunc: func(id int) (*domain.Quote, error) {
+			if id != 4 {
+				return nil, domain.ErrNotInTrash
+			}
+			return &domain.Quote{ID: 4, Author: "Seneca", Quote: "Back", Version: 3}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodPost, "/quotes/4:restore", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var quote domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&quote); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if quote.ID != 4 || quote.DeletedAt != nil || rec.Header().Get("ETag") == "" {
+		t.Errorf("expected the restored quote with an ETag, got %+v", quote)
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/quotes/5:restore", http.StatusNotFound},
+		{"/quotes/abc:restore", http.StatusBadRequest},
+		{"/quotes/4", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, http.MethodPost, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("POST %s: expected status %d, got %d", tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
+
+func TestQuoteHandler_DeleteQuote_Hard(t *testing.T) {
+	var soft, hard int
+	mockUseCase := &MockQuoteUseCase{
+		DeleteQuoteFunc: func(id, version int) error {
+			soft++
+			return nil
+		},
+		HardDeleteQuoteFunc: func(ctx context.Context, id, version int) error {
+			if !domain.PrincipalFromContext(ctx).IsAdmin() {
+				return domain.ErrForbidden
+			}
+			hard++
+			return nil
+		},
+	}
+	mux := newRevisionMux(mockUseCase)
+
+	tests := []struct {
+		name           string
+		path           string
+		principal      *domain.Principal
+		expectedStatus int
+		expectedSoft   int
+		expectedHard   int
+	}{
+		{name: "soft by default", path: "/quotes/1", expectedStatus: http.StatusNoContent, expectedSoft: 1},
+		{name: "explicit soft", path: "/quotes/1?hard=false", expectedStatus: http.StatusNoContent, expectedSoft: 1},
+		{name: "hard as admin", path: "/quotes/1?hard=true", principal: &domain.Principal{Name: "root", Role: domain.RoleAdmin}, expectedStatus: http.StatusNoContent, expectedHard: 1},
+		{name: "hard as user", path: "/quotes/1?hard=true", principal: &domain.Principal{Name: "bob", Role: domain.RoleUser}, expectedStatus: http.StatusForbidden},
+		{name: "hard anonymously", path: "/quotes/1?hard=true", expectedStatus: http.StatusForbidden},
+		{name: "invalid flag", path: "/quotes/1?hard=yes", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			soft, hard = 0, 0
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			if tt.principal != nil {
+				req = req.WithContext(domain.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if soft != tt.expectedSoft || hard != tt.expectedHard {
+				t.Errorf("expected %d soft and %d hard deletes, got %d and %d", tt.expectedSoft, tt.expectedHard, soft, hard)
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_DeleteQuote_HardIfMatchInTrash(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	trashed := &domain.Quote{ID: 4, Author: "Seneca", Quote: "Gone", Version: 3, UpdatedAt: deletedAt, DeletedAt: &deletedAt}
+	var gotVersion int
+	mux := newRevisionMux(&MockQuoteUseCase{
+		// The live lookup does not see quotes in the trash
+		GetQuoteFunc: func(id int) (*domain.Quote, error) {
+			return nil, domain.ErrQuoteNotFound
+		},
+		GetWithDeletedFunc: func(ctx context.Context, id int) (*domain.Quote, error) {
+			if !domain.PrincipalFromContext(ctx).IsAdmin() {
+				return nil, domain.ErrForbidden
+			}
+			return trashed, nil
+		},
+		HardDeleteQuoteFunc: func(ctx context.Context, id, version int) error {
+			gotVersion = version
+			return nil
+		},
+	})
+	admin := &domain.Principal{Name: "root", Role: domain.RoleAdmin}
+	etag := quoteETag(trashed, render.JSON{}.ContentType())
+
+	deleteQuote := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/4?hard=true", nil)
+		req = req.WithContext(domain.WithPrincipal(req.Context(), admin))
+		req.Header.Set("If-Match", ifMatch)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := deleteQuote(`"stale"`); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale ETag, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := deleteQuote(etag); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotVersion != trashed.Version {
+		t.Errorf("expected the hard delete to be conditional on version %d, got %d", trashed.Version, gotVersion)
+	}
+}
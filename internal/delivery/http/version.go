@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// APIVersion is a major version of the HTTP API, the first segment of its paths
+type APIVersion string
+
+const (
+	APIv1 APIVersion = "v1"
+	APIv2 APIVersion = "v2"
+)
+
+// APIVersions lists the served versions, oldest first
+var APIVersions = []APIVersion{APIv1, APIv2}
+
+// ParseAPIVersion validates a version name such as "v1"
+func ParseAPIVersion(name string) (APIVersion, error) {
+	for _, v := range APIVersions {
+		if string(v) == name {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown API version %q, expected one of %v", name, APIVersions)
+}
+
+// Deprecation announces the retirement of a version to its callers
+type Deprecation struct {
+	// At is when the version was, or will be, deprecated; sent in the Deprecation header
+	At time.Time
+	// Sunset is when the version stops being served; sent in the Sunset header when set
+	Sunset time.Time
+	// Link points at the migration guide; sent as a Link with rel="deprecation" when set
+	Link string
+}
+
+// VersionConfig selects the version of unversioned paths and the versions on their way out
+type VersionConfig struct {
+	// Default serves the paths without a version prefix
+	Default APIVersion
+	// Deprecations are keyed by version; versions without an entry are current
+	Deprecations map[APIVersion]Deprecation
+}
+
+// DefaultVersionConfig keeps unversioned paths on v1, the API as it was before versioning
+var DefaultVersionConfig = VersionConfig{Default: APIv1}
+
+var (
+	// apiRequests counts requests by the version that served them
+	apiRequests = expvar.NewMap("http_api_requests")
+	// apiUnversionedRequests counts the requests that reached a version through an
+	// unversioned path, which changes meaning when the default does
+	apiUnversionedRequests = expvar.NewMap("http_api_unversioned_requests")
+	// apiCallers counts requests by "<version> <caller>", the name of the API key or
+	// anonymous, to tell who still has to move off a version
+	apiCallers = expvar.NewMap("http_api_callers")
+)
+
+type versionKey struct{}
+
+// versionRoute is how a request reached its handler
+type versionRoute struct {
+	version APIVersion
+	// base is the path prefix of the route group, "/v1" or "" for unversioned paths
+	base string
+}
+
+// requestVersion returns the version serving r. Handlers whose responses differ between
+// versions branch on it; so far only the pages of GET /quotes do.
+func requestVersion(r *http.Request) APIVersion {
+	if route, ok := r.Context().Value(versionKey{}).(versionRoute); ok {
+		return route.version
+	}
+	return DefaultVersionConfig.Default
+}
+
+// basePath returns the prefix of the route group serving r, for links that stay within
+// the version the caller chose
+func basePath(r *http.Request) string {
+	route, _ := r.Context().Value(versionKey{}).(versionRoute)
+	return route.base
+}
+
+// versionRouter registers routes under the prefix of a version
+type versionRouter struct {
+	mux   Router
+	h     *QuoteHandler
+	route versionRoute
+}
+
+func (vr versionRouter) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	vr.mux.HandleFunc(vr.route.base+pattern, func(w http.ResponseWriter, r *http.Request) {
+		vr.h.serveVersion(w, r, vr.route, handler)
+	})
+}
+
+// serveVersion announces the version of the response and its deprecation, and counts the request
+func (h *QuoteHandler) serveVersion(w http.ResponseWriter, r *http.Request, route versionRoute, handler func(http.ResponseWriter, *http.Request)) {
+	w.Header().Set("API-Version", string(route.version))
+	if d, ok := h.versions.Deprecations[route.version]; ok {
+		// Deprecation is a structured date (RFC 9745), Sunset an HTTP date (RFC 8594)
+		if !d.At.IsZero() {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.At.Unix(), 10))
+		}
+		if !d.Sunset.IsZero() {
+			w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Link != "" {
+			w.Header().Add("Link", "<"+d.Link+`>; rel="deprecation"; type="text/html"`)
+		}
+	}
+
+	caller := "anonymous"
+	if principal := domain.PrincipalFromContext(r.Context()); principal != nil {
+		caller = principal.Name
+	}
+	apiRequests.Add(string(route.version), 1)
+	if route.base == "" {
+		apiUnversionedRequests.Add(string(route.version), 1)
+	}
+	apiCallers.Add(string(route.version)+" "+caller, 1)
+
+	handler(w, r.WithContext(context.WithValue(r.Context(), versionKey{}, route)))
+}
+
+// WithVersions sets the default version and announces deprecated ones
+func WithVersions(cfg VersionConfig) Option {
+	return func(h *QuoteHandler) {
+		h.versions = cfg
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	if v, err := ParseAPIVersion("v2"); err != nil || v != APIv2 {
+		t.Errorf("ParseAPIVersion(v2) = %q, %v", v, err)
+	}
+	for _, name := range []string{"", "V1", "v3", "1"} {
+		if _, err := ParseAPIVersion(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestQuoteHandler_Versions(t *testing.T) {
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	NewQuoteHandler(docsUseCase(), WithVersions(VersionConfig{
+		Default: APIv2,
+		Deprecations: map[APIVersion]Deprecation{
+			APIv1: {At: deprecated, Sunset: sunset, Link: "https://example.com/migrate"},
+		},
+	})).RegisterRoutes(mux)
+
+	tests := []struct {
+		path    string
+		version string
+		old     bool
+	}{
+		{"/v1/quotes/1", "v1", true},
+		{"/v2/quotes/1", "v2", false},
+		{"/quotes/1", "v2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := serve(mux, http.MethodGet, tt.path, nil, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("API-Version"); got != tt.version {
+				t.Errorf("expected API-Version %s, got %q", tt.version, got)
+			}
+
+			wantDeprecation, wantSunset, wantLink := "", "", ""
+			if tt.old {
+				wantDeprecation = "@" + strconv.FormatInt(deprecated.Unix(), 10)
+				wantSunset = "Wed, 01 Jul 2026 00:00:00 GMT"
+				wantLink = `<https://example.com/migrate>; rel="deprecation"; type="text/html"`
+			}
+			if got := rec.Header().Get("Deprecation"); got != wantDeprecation {
+				t.Errorf("expected Deprecation %q, got %q", wantDeprecation, got)
+			}
+			if got := rec.Header().Get("Sunset"); got != wantSunset {
+				t.Errorf("expected Sunset %q, got %q", wantSunset, got)
+			}
+			if got := rec.Header().Get("Link"); got != wantLink {
+				t.Errorf("expected Link %q, got %q", wantLink, got)
+			}
+		})
+	}
+
+	t.Run("unversioned routes", func(t *testing.T) {
+		if rec := serve(mux, http.MethodGet, "/health", nil, nil); rec.Code != http.StatusOK || rec.Header().Get("API-Version") != "" {
+			t.Errorf("expected /health outside the versions, got %d %q", rec.Code, rec.Header().Get("API-Version"))
+		}
+		for _, path := range []string{"/v1/health", "/v3/quotes/1"} {
+			if rec := serve(mux, http.MethodGet, path, nil, nil); rec.Code != http.StatusNotFound {
+				t.Errorf("%s: expected status 404, got %d", path, rec.Code)
+			}
+		}
+	})
+
+	t.Run("paged list keeps both links", func(t *testing.T) {
+		rec := serve(mux, http.MethodGet, "/v1/quotes?limit=1", nil, nil)
+		links := rec.Header().Values("Link")
+		if len(links) != 2 || !strings.HasPrefix(links[1], "</v1/quotes?cursor=1&limit=1>") {
+			t.Errorf("expected the deprecation and next links, got %q", links)
+		}
+	})
+
+	t.Run("pages are wrapped from v2 on", func(t *testing.T) {
+		v1 := serve(mux, http.MethodGet, "/v1/quotes?limit=1", nil, nil)
+		var list []domain.Quote
+		if err := json.Unmarshal(v1.Body.Bytes(), &list); err != nil || len(list) != 1 {
+			t.Errorf("expected v1 to send the list alone, got %s (%v)", v1.Body, err)
+		}
+
+		v2 := serve(mux, http.MethodGet, "/v2/quotes?limit=1", nil, nil)
+		var page struct {
+			Quotes     []domain.Quote `json:"quotes"`
+			NextCursor string         `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(v2.Body.Bytes(), &page); err != nil || len(page.Quotes) != 1 || page.NextCursor != "1" {
+			t.Errorf("expected v2 to wrap the page with its cursor, got %s (%v)", v2.Body, err)
+		}
+		if v1.Header().Get("ETag") == v2.Header().Get("ETag") {
+			t.Errorf("expected the versions to have their own ETag, got %q for both", v1.Header().Get("ETag"))
+		}
+
+		xml := serve(mux, http.MethodGet, "/v2/quotes?limit=1", nil, map[string]string{"Accept": "application/xml"})
+		if !strings.HasPrefix(xml.Body.String(), "<") {
+			t.Errorf("expected other formats to stay unwrapped, got %s", xml.Body)
+		}
+	})
+}
+
+// counter reads a counter of an expvar map, 0 when it was never added to
+func counter(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestQuoteHandler_VersionMetrics(t *testing.T) {
+	mux := newRevisionMux(docsUseCase())
+	v1Ops, unversioned, v2 := counter(apiCallers, "v1 ops"), counter(apiUnversionedRequests, "v1"), counter(apiRequests, "v2")
+
+	req := httptest.NewRequest(http.MethodGet, "/quotes/1", nil)
+	req = req.WithContext(domain.WithPrincipal(context.Background(), &domain.Principal{Name: "ops", Role: domain.RoleAdmin}))
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	serve(mux, http.MethodGet, "/v2/quotes/1", nil, nil)
+
+	if got := counter(apiCallers, "v1 ops"); got != v1Ops+1 {
+		t.Errorf("expected the v1 request of ops to be counted, got %d after %d", got, v1Ops)
+	}
+	if got := counter(apiUnversionedRequests, "v1"); got != unversioned+1 {
+		t.Errorf("expected the unversioned request to be counted, got %d after %d", got, unversioned)
+	}
+	if got := counter(apiRequests, "v2"); got != v2+1 {
+		t.Errorf("expected the v2 request to be counted, got %d after %d", got, v2)
+	}
+}
+
+func TestQuoteHandler_CreateWebhook_VersionedLocation(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		CreateWebhookFunc: func(req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+			return &domain.WebhookSubscription{ID: 4, URL: req.URL}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodPost, "/v2/admin/webhooks", []byte(`{"url": "https://example.com/hook"}`), nil)
+	if loc := rec.Header().Get("Location"); loc != "/v2/admin/webhooks/4" {
+		t.Errorf("expected Location /v2/admin/webhooks/4, got %q", loc)
+	}
+}
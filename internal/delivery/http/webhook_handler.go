@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// redeliverSuffix marks the custom method that queues a delivery again
+const redeliverSuffix = ":redeliver"
+
+// writeWebhookError maps errors from the webhook use cases to responses
+func (h *QuoteHandler) writeWebhookError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		h.writeError(w, http.StatusNotFound, domain.MsgWebhookNotFound)
+	case errors.Is(err, domain.ErrDeliveryNotFound):
+		h.writeError(w, http.StatusNotFound, domain.MsgDeliveryNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		h.writeError(w, http.StatusForbidden, domain.MsgAdminRequired)
+	default:
+		h.writeError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+func webhookID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, domain.ErrWebhookNotFound
+	}
+	return id, nil
+}
+
+// GetWebhooks GET /admin/webhooks
+func (h *QuoteHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.quoteUseCase.GetWebhooks(r.Context())
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedGetWebhooks)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, subs)
+}
+
+// CreateWebhook POST /admin/webhooks
+func (h *QuoteHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req domain.WebhookSubscriptionRequest
+	if rerr := decodeJSON(w, r, &req, h.maxBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	sub, err := h.quoteUseCase.CreateWebhook(r.Context(), &req)
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedSaveWebhook)
+		return
+	}
+
+	w.Header().Set("Location", basePath(r)+"/admin/webhooks/"+strconv.Itoa(sub.ID))
+	h.writeJSON(w, http.StatusCreated, sub)
+}
+
+// GetWebhook GET /admin/webhooks/{id}
+func (h *QuoteHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookID(r)
+	if err == nil {
+		var sub *domain.WebhookSubscription
+		if sub, err = h.quoteUseCase.GetWebhook(r.Context(), id); err == nil {
+			h.writeJSON(w, http.StatusOK, sub)
+			return
+		}
+	}
+
+	h.writeWebhookError(w, err, domain.MsgFailedGetWebhooks)
+}
+
+// UpdateWebhook PUT /admin/webhooks/{id}
+func (h *QuoteHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookID(r)
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedSaveWebhook)
+		return
+	}
+
+	var req domain.WebhookSubscriptionRequest
+	if rerr := decodeJSON(w, r, &req, h.maxBodyBytes); rerr != nil {
+		h.writeError(w, rerr.Status, rerr.Message)
+		return
+	}
+
+	sub, err := h.quoteUseCase.UpdateWebhook(r.Context(), id, &req)
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedSaveWebhook)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, sub)
+}
+
+// DeleteWebhook DELETE /admin/webhooks/{id}
+func (h *QuoteHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookID(r)
+	if err == nil {
+		err = h.quoteUseCase.DeleteWebhook(r.Context(), id)
+	}
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedDeleteWebhook)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries GET /admin/webhooks/{id}/deliveries?status=pending|delivered|dead
+func (h *QuoteHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookID(r)
+	if err == nil {
+		var deliveries []*domain.WebhookDelivery
+		status := domain.DeliveryStatus(r.URL.Query().Get("status"))
+		if deliveries, err = h.quoteUseCase.GetDeliveries(r.Context(), id, status); err == nil {
+			h.writeJSON(w, http.StatusOK, deliveries)
+			return
+		}
+	}
+
+	h.writeWebhookError(w, err, domain.MsgFailedGetWebhooks)
+}
+
+// RedeliverWebhook POST /admin/webhooks/{id}/deliveries/{delivery}:redeliver
+func (h *QuoteHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookID(r)
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedRedeliver)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(strings.TrimSuffix(r.PathValue("delivery"), redeliverSuffix), 10, 64)
+	if err != nil {
+		h.writeWebhookError(w, domain.ErrDeliveryNotFound, domain.MsgFailedRedeliver)
+		return
+	}
+
+	delivery, err := h.quoteUseCase.RedeliverWebhook(r.Context(), id, deliveryID)
+	if err != nil {
+		h.writeWebhookError(w, err, domain.MsgFailedRedeliver)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, delivery)
+}
+
+func (h *QuoteHandler) registerWebhookRoutes(mux Router) {
+	mux.HandleFunc("/admin/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetWebhooks(w, r)
+		case http.MethodPost:
+			h.CreateWebhook(w, r)
+		default:
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/admin/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetWebhook(w, r)
+		case http.MethodPut:
+			h.UpdateWebhook(w, r)
+		case http.MethodDelete:
+			h.DeleteWebhook(w, r)
+		default:
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/admin/webhooks/{id}/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.GetDeliveries(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// The redeliver method shares the path segment with the delivery ID
+	mux.HandleFunc("/admin/webhooks/{id}/deliveries/{delivery}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.PathValue("delivery"), redeliverSuffix) {
+			h.RedeliverWebhook(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
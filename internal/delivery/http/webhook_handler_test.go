@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteHandler_CreateWebhook(t *testing.T) {
+	var gotReq *domain.WebhookSubscriptionRequest
+	mux := newRevisionMux(&MockQuoteUseCase{
+		CreateWebhookFunc: func(req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+			gotReq = req
+			if req.URL == "ftp://example.com" {
+				return nil, domain.ErrInvalidWebhook
+			}
+			return &domain.WebhookSubscription{ID: 4, URL: req.URL, Secret: "whsec_new", Events: req.Events, Active: true}, nil
+		},
+	})
+
+	body := []byte(`{"url": "https://example.com/hook", "events": ["quote.created", "quote.deleted"]}`)
+	rec := serve(mux, http.MethodPost, "/admin/webhooks", body, nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if loc := rec.Header().Get("Location"); loc != "/admin/webhooks/4" {
+		t.Errorf("expected Location /admin/webhooks/4, got %q", loc)
+	}
+	if len(gotReq.Events) != 2 || gotReq.Events[1] != domain.EventQuoteDeleted {
+		t.Errorf("expected the requested events, got %+v", gotReq.Events)
+	}
+
+	var sub domain.WebhookSubscription
+	if err := json.NewDecoder(rec.Body).Decode(&sub); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if sub.Secret != "whsec_new" {
+		t.Errorf("expected the secret in the create response, got %+v", sub)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid subscription", body: `{"url": "ftp://example.com"}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"uri": "https://example.com"}`, expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(mux, http.MethodPost, "/admin/webhooks", []byte(tt.body), nil); rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_Webhooks_Errors(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetWebhooksFunc: func() ([]*domain.WebhookSubscription, error) {
+			return nil, domain.ErrForbidden
+		},
+		DeleteWebhookFunc: func(id int) error {
+			if id != 1 {
+				return domain.ErrWebhookNotFound
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{http.MethodGet, "/admin/webhooks", http.StatusForbidden},
+		{http.MethodGet, "/admin/webhooks/9", http.StatusNotFound},
+		{http.MethodGet, "/admin/webhooks/abc", http.StatusNotFound},
+		{http.MethodDelete, "/admin/webhooks/1", http.StatusNoContent},
+		{http.MethodDelete, "/admin/webhooks/2", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, tt.method, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
+
+func TestQuoteHandler_GetDeliveries(t *testing.T) {
+	var gotStatus domain.DeliveryStatus
+	mux := newRevisionMux(&MockQuoteUseCase{
+		GetDeliveriesFunc: func(subscriptionID int, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+			gotStatus = status
+			return []*domain.WebhookDelivery{{ID: 8, SubscriptionID: subscriptionID, Status: domain.DeliveryDead, Attempts: 8, LastStatusCode: 500}}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodGet, "/admin/webhooks/1/deliveries?status=dead", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if gotStatus != domain.DeliveryDead {
+		t.Errorf("expected the dead filter, got %q", gotStatus)
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	if err := json.NewDecoder(rec.Body).Decode(&deliveries); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != 8 || deliveries[0].LastStatusCode != 500 {
+		t.Errorf("unexpected deliveries %+v", deliveries)
+	}
+}
+
+func TestQuoteHandler_RedeliverWebhook(t *testing.T) {
+	mux := newRevisionMux(&MockQuoteUseCase{
+		RedeliverWebhookFunc: func(subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+			if subscriptionID != 1 || deliveryID != 8 {
+				return nil, domain.ErrDeliveryNotFound
+			}
+			return &domain.WebhookDelivery{ID: 8, SubscriptionID: 1, Status: domain.DeliveryPending}, nil
+		},
+	})
+
+	rec := serve(mux, http.MethodPost, "/admin/webhooks/1/deliveries/8:redeliver", nil, nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var delivery domain.WebhookDelivery
+	if err := json.NewDecoder(rec.Body).Decode(&delivery); err != nil || delivery.Status != domain.DeliveryPending {
+		t.Errorf("expected a pending delivery, got %+v (%v)", delivery, err)
+	}
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{http.MethodPost, "/admin/webhooks/1/deliveries/9:redeliver", http.StatusNotFound},
+		{http.MethodPost, "/admin/webhooks/1/deliveries/x:redeliver", http.StatusNotFound},
+		{http.MethodPost, "/admin/webhooks/1/deliveries/8", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/admin/webhooks/1/deliveries/8:redeliver", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux, tt.method, tt.path, nil, nil); rec.Code != tt.expectedStatus {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.expectedStatus, rec.Code)
+		}
+	}
+}
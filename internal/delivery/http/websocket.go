@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// WebSocketProtocol is the subprotocol spoken at /ws. Browsers, which cannot set headers
+// on a WebSocket handshake, may offer their API key as a second subprotocol "bearer.<key>".
+const WebSocketProtocol = "quotes.v1"
+
+const (
+	// wsWriteTimeout bounds each message written to a connection
+	wsWriteTimeout = 10 * time.Second
+	// wsReadLimit caps the size of a client message
+	wsReadLimit = 4 << 10
+	// wsMaxTopics caps the subscriptions of one connection
+	wsMaxTopics = 32
+)
+
+// Message types of the /ws protocol
+const (
+	wsSubscribe   = "subscribe"
+	wsUnsubscribe = "unsubscribe"
+	wsRandom      = "random"
+	wsAck         = "ack"
+	wsPing        = "ping"
+
+	wsOK    = "ok"
+	wsError = "error"
+	wsEvent = "event"
+	wsDaily = "daily"
+	wsQuote = "quote"
+	wsPong  = "pong"
+)
+
+// Topics a connection may subscribe to: author:<name>, tag:<tag> and daily
+const (
+	topicAuthor = "author:"
+	topicTag    = "tag:"
+	topicDaily  = "daily"
+)
+
+// WebSocketConfig describes the WebSocket endpoint at /ws
+type WebSocketConfig struct {
+	// PingInterval is how often the client is pinged; one that does not answer in time is disconnected
+	PingInterval time.Duration
+	// QueueSize bounds the messages waiting to be written to a connection. A connection whose
+	// queue overflows is closed with status 1013, try again later.
+	QueueSize int
+	// AckWindow is the number of pushed messages a client may leave unacknowledged
+	// before no more are pushed to it
+	AckWindow int
+	// AllowedOrigins are host patterns of other sites whose pages may connect
+	AllowedOrigins []string
+}
+
+// DefaultWebSocketConfig is used unless WithWebSocket is given
+var DefaultWebSocketConfig = WebSocketConfig{
+	PingInterval: 30 * time.Second,
+	QueueSize:    64,
+	AckWindow:    32,
+}
+
+// WithWebSocket overrides DefaultWebSocketConfig
+func WithWebSocket(cfg WebSocketConfig) Option {
+	return func(h *QuoteHandler) {
+		h.websocket = cfg
+	}
+}
+
+// wsMessage is a message of the /ws protocol in either direction
+type wsMessage struct {
+	Type string `json:"type"`
+	// ID is chosen by the client and echoed in the reply to its request
+	ID string `json:"id,omitempty"`
+	// Seq numbers the messages pushed to the client, which acknowledges them with an ack
+	// carrying the highest Seq it has processed
+	Seq    int64                `json:"seq,omitempty"`
+	Topic  string               `json:"topic,omitempty"`
+	Topics []string             `json:"topics,omitempty"`
+	Event  *domain.WebhookEvent `json:"event,omitempty"`
+	Quote  *domain.Quote        `json:"quote,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// parseTopic normalizes a topic name the way the stream filters do
+func parseTopic(topic string) (string, error) {
+	switch {
+	case topic == topicDaily:
+		return topic, nil
+	case strings.HasPrefix(topic, topicAuthor):
+		if author := strings.TrimSpace(strings.TrimPrefix(topic, topicAuthor)); author != "" {
+			return topicAuthor + author, nil
+		}
+	case strings.HasPrefix(topic, topicTag):
+		if tag := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(topic, topicTag))); tag != "" {
+			return topicTag + tag, nil
+		}
+	}
+	return "", fmt.Errorf("unknown topic %q, expected author:<name>, tag:<tag> or daily", topic)
+}
+
+// topicFilter returns the stream filter selecting the events of an author or tag topic
+func topicFilter(topic string) domain.StreamFilter {
+	if author, ok := strings.CutPrefix(topic, topicAuthor); ok {
+		return domain.StreamFilter{Author: author}
+	}
+	return domain.StreamFilter{Tag: strings.TrimPrefix(topic, topicTag)}
+}
+
+// ServeWebSocket GET /ws
+func (h *QuoteHandler) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	principal := domain.PrincipalFromContext(r.Context())
+	if principal == nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="quotes"`)
+		h.writeError(w, http.StatusUnauthorized, domain.MsgAPIKeyRequired)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{WebSocketProtocol},
+		OriginPatterns: h.websocket.AllowedOrigins,
+	})
+	if err != nil {
+		// Accept has already answered the handshake
+		log.Printf("websocket handshake from %s failed: %v", principal.Name, err)
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+
+	s := &wsSession{
+		h:      h,
+		conn:   conn,
+		send:   make(chan *wsMessage, h.websocket.QueueSize),
+		done:   make(chan struct{}),
+		topics: make(map[string]domain.StreamFilter),
+	}
+	s.run(r.Context())
+}
+
+// wsSession serves one WebSocket connection. The run loop owns the session state; reads,
+// writes and pings happen on their own goroutines.
+type wsSession struct {
+	h    *QuoteHandler
+	conn *websocket.Conn
+	// send is the bounded queue of messages waiting to be written
+	send chan *wsMessage
+
+	done        chan struct{}
+	closeOnce   sync.Once
+	closeStatus websocket.StatusCode
+	closeReason string
+
+	topics       map[string]domain.StreamFilter
+	daily        bool
+	events       <-chan *domain.WebhookEvent
+	stopEvents   context.CancelFunc
+	seq, acked   int64
+	dailyTimer   *time.Timer
+	dailyPending <-chan time.Time
+}
+
+// abort ends the session, closing the connection with status and reason
+func (s *wsSession) abort(status websocket.StatusCode, reason string) {
+	s.closeOnce.Do(func() {
+		s.closeStatus, s.closeReason = status, reason
+		close(s.done)
+	})
+}
+
+func (s *wsSession) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	incoming := make(chan *wsMessage)
+	go s.readLoop(ctx, incoming)
+	go s.writeLoop(ctx)
+	if s.h.websocket.PingInterval > 0 {
+		go s.pingLoop(ctx)
+	}
+
+	defer func() {
+		s.stopStream()
+		if s.dailyTimer != nil {
+			s.dailyTimer.Stop()
+		}
+		s.conn.Close(s.closeStatus, s.closeReason)
+	}()
+
+	for {
+		// Pushing pauses once the client has this many messages to acknowledge. The event stream
+		// then backs up, and ends once it falls too far behind, which closes the connection.
+		var events <-chan *domain.WebhookEvent
+		if s.seq-s.acked < int64(s.h.websocket.AckWindow) {
+			events = s.events
+		}
+
+		select {
+		case <-ctx.Done():
+			s.abort(websocket.StatusGoingAway, "server shutting down")
+			return
+		case <-s.done:
+			return
+		case msg := <-incoming:
+			s.handle(ctx, msg)
+		case event, ok := <-events:
+			if !ok {
+				s.abort(websocket.StatusTryAgainLater, "event stream ended")
+				return
+			}
+			s.pushEvent(event)
+		case <-s.dailyPending:
+			s.pushDaily()
+			s.scheduleDaily()
+		}
+	}
+}
+
+func (s *wsSession) readLoop(ctx context.Context, incoming chan<- *wsMessage) {
+	for {
+		typ, data, err := s.conn.Read(ctx)
+		if err != nil {
+			s.abort(websocket.StatusNormalClosure, "")
+			return
+		}
+
+		msg := &wsMessage{}
+		if typ != websocket.MessageText || json.Unmarshal(data, msg) != nil {
+			s.enqueue(&wsMessage{Type: wsError, Error: domain.MsgInvalidJSON})
+			continue
+		}
+
+		select {
+		case incoming <- msg:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *wsSession) writeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.send:
+			data, err := json.Marshal(msg)
+			if err != nil {
+				log.Printf("failed to encode websocket message: %v", err)
+				continue
+			}
+			wctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+			err = s.conn.Write(wctx, websocket.MessageText, data)
+			cancel()
+			if err != nil {
+				s.abort(websocket.StatusGoingAway, "write failed")
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) pingLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.h.websocket.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(ctx, s.h.websocket.PingInterval)
+			err := s.conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				s.abort(websocket.StatusPolicyViolation, "ping timeout")
+				return
+			}
+		}
+	}
+}
+
+// enqueue queues msg for writing, closing the connection if its queue is full
+func (s *wsSession) enqueue(msg *wsMessage) {
+	select {
+	case s.send <- msg:
+	default:
+		s.abort(websocket.StatusTryAgainLater, "send queue full")
+	}
+}
+
+func (s *wsSession) reply(req *wsMessage, msg *wsMessage) {
+	msg.ID = req.ID
+	s.enqueue(msg)
+}
+
+func (s *wsSession) replyError(req *wsMessage, message string) {
+	s.reply(req, &wsMessage{Type: wsError, Error: message})
+}
+
+func (s *wsSession) handle(ctx context.Context, msg *wsMessage) {
+	switch msg.Type {
+	case wsSubscribe:
+		s.subscribe(ctx, msg)
+	case wsUnsubscribe:
+		s.unsubscribe(msg)
+	case wsRandom:
+		quote, err := s.h.quoteUseCase.GetRandomQuote()
+		switch {
+		case err == nil:
+			s.reply(msg, &wsMessage{Type: wsQuote, Quote: quote})
+		case errors.Is(err, domain.ErrNoQuotesFound):
+			s.replyError(msg, domain.MsgQuotesNotFound)
+		default:
+			s.replyError(msg, domain.MsgFailedGetRandomQuote)
+		}
+	case wsAck:
+		if msg.Seq > s.seq {
+			s.replyError(msg, fmt.Sprintf("ack for unsent message %d", msg.Seq))
+		} else if msg.Seq > s.acked {
+			s.acked = msg.Seq
+		}
+	case wsPing:
+		s.reply(msg, &wsMessage{Type: wsPong})
+	default:
+		s.replyError(msg, fmt.Sprintf("unknown message type %q", msg.Type))
+	}
+}
+
+func (s *wsSession) subscriptions() []string {
+	topics := make([]string, 0, len(s.topics)+1)
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	if s.daily {
+		topics = append(topics, topicDaily)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+func (s *wsSession) subscribe(ctx context.Context, msg *wsMessage) {
+	topic, err := parseTopic(msg.Topic)
+	if err != nil {
+		s.replyError(msg, err.Error())
+		return
+	}
+	if _, ok := s.topics[topic]; ok || (topic == topicDaily && s.daily) {
+		s.reply(msg, &wsMessage{Type: wsOK, Topics: s.subscriptions()})
+		return
+	}
+	if len(s.subscriptions()) >= wsMaxTopics {
+		s.replyError(msg, fmt.Sprintf("at most %d subscriptions per connection", wsMaxTopics))
+		return
+	}
+
+	if topic == topicDaily {
+		s.daily = true
+		s.reply(msg, &wsMessage{Type: wsOK, Topics: s.subscriptions()})
+		s.pushDaily()
+		s.scheduleDaily()
+		return
+	}
+
+	if s.events == nil {
+		streamCtx, stop := context.WithCancel(ctx)
+		events, err := s.h.quoteUseCase.StreamEvents(streamCtx, 0, domain.StreamFilter{})
+		if err != nil {
+			stop()
+			s.replyError(msg, domain.MsgFailedStream)
+			return
+		}
+		s.events, s.stopEvents = events, stop
+	}
+	s.topics[topic] = topicFilter(topic)
+	s.reply(msg, &wsMessage{Type: wsOK, Topics: s.subscriptions()})
+}
+
+func (s *wsSession) unsubscribe(msg *wsMessage) {
+	topic, err := parseTopic(msg.Topic)
+	if err != nil {
+		s.replyError(msg, err.Error())
+		return
+	}
+
+	if topic == topicDaily {
+		s.daily = false
+		if s.dailyTimer != nil {
+			s.dailyTimer.Stop()
+		}
+		s.dailyPending = nil
+	} else {
+		delete(s.topics, topic)
+		if len(s.topics) == 0 {
+			s.stopStream()
+		}
+	}
+	s.reply(msg, &wsMessage{Type: wsOK, Topics: s.subscriptions()})
+}
+
+func (s *wsSession) stopStream() {
+	if s.stopEvents != nil {
+		s.stopEvents()
+	}
+	s.events, s.stopEvents = nil, nil
+}
+
+// pushEvent sends an event to the client once, listing every subscribed topic it matches
+func (s *wsSession) pushEvent(event *domain.WebhookEvent) {
+	var quote domain.Quote
+	if err := json.Unmarshal(event.Data, &quote); err != nil {
+		log.Printf("failed to decode event %d: %v", event.ID, err)
+		return
+	}
+
+	var matched []string
+	for topic, filter := range s.topics {
+		if filter.MatchQuote(&quote) {
+			matched = append(matched, topic)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	slices.Sort(matched)
+
+	s.seq++
+	s.enqueue(&wsMessage{Type: wsEvent, Seq: s.seq, Topics: matched, Event: event})
+}
+
+func (s *wsSession) pushDaily() {
+	quote, err := s.h.quoteUseCase.GetDailyQuote(time.Now())
+	if err != nil {
+		if !errors.Is(err, domain.ErrNoQuotesFound) {
+			log.Printf("failed to get the daily quote: %v", err)
+		}
+		return
+	}
+
+	s.seq++
+	s.enqueue(&wsMessage{Type: wsDaily, Seq: s.seq, Topic: topicDaily, Quote: quote})
+}
+
+// scheduleDaily arms the push of the next quote of the day, which changes at UTC midnight
+func (s *wsSession) scheduleDaily() {
+	next := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if s.dailyTimer == nil {
+		s.dailyTimer = time.NewTimer(time.Until(next))
+	} else {
+		s.dailyTimer.Reset(time.Until(next))
+	}
+	s.dailyPending = s.dailyTimer.C
+}
+
+func (h *QuoteHandler) registerWebSocketRoutes(mux Router) {
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.ServeWebSocket(w, r)
+		} else {
+			h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/shoksin/quotes-service/internal/auth"
+	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+const wsTestKey = "kiosk-secret"
+
+// newWebSocketServer serves the routes of a handler over mock behind the auth middleware
+func newWebSocketServer(t *testing.T, mock *MockQuoteUseCase, cfg WebSocketConfig) *httptest.Server {
+	t.Helper()
+	keys, err := auth.ParseKeys([]string{"kiosk:user:" + wsTestKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewQuoteHandler(mock, WithWebSocket(cfg)).RegisterRoutes(mux)
+
+	srv := httptest.NewServer(middleware.AuthMiddleware(keys, mux))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dialWebSocket(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", &websocket.DialOptions{
+		HTTPHeader: http.Header{"Authorization": {"Bearer " + wsTestKey}},
+	})
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+func wsSend(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func wsReceive(t *testing.T, conn *websocket.Conn) *wsMessage {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	msg := &wsMessage{}
+	if err := wsjson.Read(ctx, conn, msg); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	return msg
+}
+
+func quoteEvent(id int64, author string, tags ...string) *domain.WebhookEvent {
+	data, _ := json.Marshal(domain.Quote{ID: int(id), Author: author, Quote: "text", Tags: tags})
+	return &domain.WebhookEvent{ID: id, Type: domain.EventQuoteCreated, Data: data}
+}
+
+func TestQuoteHandler_WebSocket_Handshake(t *testing.T) {
+	srv := newWebSocketServer(t, &MockQuoteUseCase{}, DefaultWebSocketConfig)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, resp, err := websocket.Dial(ctx, url, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected an anonymous handshake to be rejected with 401, got %v", err)
+	}
+
+	_, resp, err = websocket.Dial(ctx, url, &websocket.DialOptions{HTTPHeader: http.Header{"X-API-Key": {"guess"}}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected an unknown key to be rejected with 401, got %v", err)
+	}
+
+	// Browsers offer the key as a subprotocol, which is never selected
+	conn, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{Subprotocols: []string{WebSocketProtocol, "bearer." + wsTestKey}})
+	if err != nil {
+		t.Fatalf("expected the subprotocol key to be accepted: %v", err)
+	}
+	defer conn.CloseNow()
+	if conn.Subprotocol() != WebSocketProtocol {
+		t.Errorf("expected subprotocol %q, got %q", WebSocketProtocol, conn.Subprotocol())
+	}
+}
+
+func TestQuoteHandler_WebSocket_Requests(t *testing.T) {
+	srv := newWebSocketServer(t, &MockQuoteUseCase{
+		GetRandomQuoteFunc: func() (*domain.Quote, error) {
+			return &domain.Quote{ID: 7, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity."}, nil
+		},
+	}, DefaultWebSocketConfig)
+	conn := dialWebSocket(t, srv)
+
+	wsSend(t, conn, `{"type": "random", "id": "r1"}`)
+	if msg := wsReceive(t, conn); msg.Type != wsQuote || msg.ID != "r1" || msg.Quote == nil || msg.Quote.ID != 7 {
+		t.Errorf("expected quote 7 in reply to r1, got %+v", msg)
+	}
+
+	tests := []struct {
+		request string
+		reply   string
+		error   string
+	}{
+		{`{"type": "ping", "id": "p1"}`, wsPong, ""},
+		{`{"type": "subscribe", "id": "s1", "topic": "planet:mars"}`, wsError, "unknown topic"},
+		{`{"type": "subscribe", "id": "s2", "topic": "author: "}`, wsError, "unknown topic"},
+		{`{"type": "ack", "id": "a1", "seq": 3}`, wsError, "unsent message"},
+		{`{"type": "shout", "id": "x1"}`, wsError, "unknown message type"},
+		{`not json`, wsError, domain.MsgInvalidJSON},
+	}
+	for _, tt := range tests {
+		wsSend(t, conn, tt.request)
+		msg := wsReceive(t, conn)
+		if msg.Type != tt.reply || !strings.Contains(msg.Error, tt.error) {
+			t.Errorf("%s: expected %s %q, got %+v", tt.request, tt.reply, tt.error, msg)
+		}
+	}
+}
+
+func TestQuoteHandler_WebSocket_Subscriptions(t *testing.T) {
+	events := make(chan *domain.WebhookEvent, 8)
+	srv := newWebSocketServer(t, &MockQuoteUseCase{
+		StreamEventsFunc: func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+			if lastEventID != 0 || filter != (domain.StreamFilter{}) {
+				t.Errorf("expected one unfiltered live stream, got %d %+v", lastEventID, filter)
+			}
+			return events, nil
+		},
+		GetDailyQuoteFunc: func(day time.Time) (*domain.Quote, error) {
+			return &domain.Quote{ID: 3, Author: "Confucius"}, nil
+		},
+	}, WebSocketConfig{QueueSize: 16, AckWindow: 2})
+	conn := dialWebSocket(t, srv)
+
+	wsSend(t, conn, `{"type": "subscribe", "id": "1", "topic": "author:Seneca"}`)
+	if msg := wsReceive(t, conn); msg.Type != wsOK || msg.ID != "1" || len(msg.Topics) != 1 {
+		t.Fatalf("expected the subscription to be confirmed, got %+v", msg)
+	}
+	wsSend(t, conn, `{"type": "subscribe", "id": "2", "topic": "tag:Stoic"}`)
+	if msg := wsReceive(t, conn); msg.Type != wsOK || strings.Join(msg.Topics, ",") != "author:Seneca,tag:stoic" {
+		t.Fatalf("expected both topics, got %+v", msg)
+	}
+
+	events <- quoteEvent(10, "Confucius")
+	events <- quoteEvent(11, "Seneca", "stoic")
+	events <- quoteEvent(12, "Marcus Aurelius", "stoic")
+	events <- quoteEvent(13, "Seneca")
+
+	msg := wsReceive(t, conn)
+	if msg.Type != wsEvent || msg.Seq != 1 || msg.Event.ID != 11 || strings.Join(msg.Topics, ",") != "author:Seneca,tag:stoic" {
+		t.Errorf("expected event 11 once for both topics, got %+v", msg)
+	}
+	if msg = wsReceive(t, conn); msg.Seq != 2 || msg.Event.ID != 12 {
+		t.Errorf("expected event 12, got %+v", msg)
+	}
+
+	// The window of two is used up, so event 13 waits for an acknowledgement
+	wsSend(t, conn, `{"type": "ping", "id": "p"}`)
+	if msg = wsReceive(t, conn); msg.Type != wsPong {
+		t.Fatalf("expected the pong before any further event, got %+v", msg)
+	}
+	wsSend(t, conn, `{"type": "ack", "seq": 2}`)
+	if msg = wsReceive(t, conn); msg.Seq != 3 || msg.Event.ID != 13 {
+		t.Errorf("expected event 13 after the ack, got %+v", msg)
+	}
+
+	wsSend(t, conn, `{"type": "ack", "seq": 3}`)
+	wsSend(t, conn, `{"type": "subscribe", "id": "3", "topic": "daily"}`)
+	if msg = wsReceive(t, conn); msg.Type != wsOK || msg.ID != "3" {
+		t.Fatalf("expected the daily subscription to be confirmed, got %+v", msg)
+	}
+	if msg = wsReceive(t, conn); msg.Type != wsDaily || msg.Seq != 4 || msg.Quote == nil || msg.Quote.ID != 3 {
+		t.Errorf("expected the quote of the day to be pushed, got %+v", msg)
+	}
+
+	wsSend(t, conn, `{"type": "unsubscribe", "id": "4", "topic": "author:Seneca"}`)
+	if msg = wsReceive(t, conn); msg.Type != wsOK || strings.Join(msg.Topics, ",") != "daily,tag:stoic" {
+		t.Errorf("expected the author topic to be dropped, got %+v", msg)
+	}
+}
+
+func TestQuoteHandler_WebSocket_ClosesWhenEventsEnd(t *testing.T) {
+	events := make(chan *domain.WebhookEvent)
+	srv := newWebSocketServer(t, &MockQuoteUseCase{
+		StreamEventsFunc: func(lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+			return events, nil
+		},
+	}, DefaultWebSocketConfig)
+	conn := dialWebSocket(t, srv)
+
+	wsSend(t, conn, `{"type": "subscribe", "id": "1", "topic": "tag:stoic"}`)
+	wsReceive(t, conn)
+
+	// The stream ends when the client falls too far behind
+	close(events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, _, err := conn.Read(ctx)
+	if status := websocket.CloseStatus(err); status != websocket.StatusTryAgainLater {
+		t.Errorf("expected close status %d, got %d (%v)", websocket.StatusTryAgainLater, status, err)
+	}
+}
+
+func TestQuoteHandler_WebSocket_PingTimeout(t *testing.T) {
+	srv := newWebSocketServer(t, &MockQuoteUseCase{}, WebSocketConfig{PingInterval: 20 * time.Millisecond, QueueSize: 4, AckWindow: 4})
+	conn := dialWebSocket(t, srv)
+
+	// Pongs are only sent while the client reads, so a client that stops reading is dropped
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, _, err := conn.Read(ctx)
+	if status := websocket.CloseStatus(err); status != websocket.StatusPolicyViolation {
+		t.Errorf("expected close status %d, got %d (%v)", websocket.StatusPolicyViolation, status, err)
+	}
+}
+
+func TestWsSession_QueueOverflow(t *testing.T) {
+	s := &wsSession{send: make(chan *wsMessage, 1), done: make(chan struct{})}
+
+	s.enqueue(&wsMessage{Type: wsPong})
+	s.enqueue(&wsMessage{Type: wsPong})
+
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("expected an overflowing queue to end the session")
+	}
+	if s.closeStatus != websocket.StatusTryAgainLater {
+		t.Errorf("expected close status %d, got %d", websocket.StatusTryAgainLater, s.closeStatus)
+	}
+}
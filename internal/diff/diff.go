@@ -0,0 +1,93 @@
+// Package diff computes word-level differences between two texts
+package diff
+
+import "strings"
+
+// Op is the kind of an edit
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Edit is a run of consecutive words that share an Op
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Words returns the edits that turn a into b, comparing whitespace-separated words.
+// Runs of words are joined with single spaces, so differences in whitespace alone are not reported.
+func Words(a, b string) []Edit {
+	return Tokens(strings.Fields(a), strings.Fields(b))
+}
+
+// Tokens returns the edits that turn a into b using a longest common subsequence.
+// Deletions are reported before insertions where both replace the same words.
+func Tokens(a, b []string) []Edit {
+	// Strip the common prefix and suffix so the table only covers the changed middle
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	add := func(op Op, token string) {
+		if n := len(edits); n > 0 && edits[n-1].Op == op {
+			edits[n-1].Text += " " + token
+			return
+		}
+		edits = append(edits, Edit{Op: op, Text: token})
+	}
+
+	for _, token := range a[:prefix] {
+		add(Equal, token)
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := lcsTable(midA, midB)
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			add(Equal, midA[i])
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			add(Delete, midA[i])
+			i++
+		default:
+			add(Insert, midB[j])
+			j++
+		}
+	}
+
+	for _, token := range a[len(a)-suffix:] {
+		add(Equal, token)
+	}
+	return edits
+}
+
+// lcsTable returns t where t[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+func lcsTable(a, b []string) [][]int {
+	t := make([][]int, len(a)+1)
+	for i := range t {
+		t[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				t[i][j] = t[i+1][j+1] + 1
+			} else {
+				t[i][j] = max(t[i+1][j], t[i][j+1])
+			}
+		}
+	}
+	return t
+}
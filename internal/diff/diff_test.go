@@ -0,0 +1,99 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{
+			name: "identical",
+			a:    "to be or not to be",
+			b:    "to be or not to be",
+			want: []Edit{{Equal, "to be or not to be"}},
+		},
+		{
+			name: "both empty",
+			a:    "",
+			b:    "  ",
+			want: nil,
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "new text",
+			want: []Edit{{Insert, "new text"}},
+		},
+		{
+			name: "to empty",
+			a:    "old text",
+			b:    "",
+			want: []Edit{{Delete, "old text"}},
+		},
+		{
+			name: "replaced word",
+			a:    "Luck is what happens when preparation meets opportunity",
+			b:    "Luck is what happens when practice meets opportunity",
+			want: []Edit{
+				{Equal, "Luck is what happens when"},
+				{Delete, "preparation"},
+				{Insert, "practice"},
+				{Equal, "meets opportunity"},
+			},
+		},
+		{
+			name: "inserted and deleted runs",
+			a:    "the quick brown fox jumps",
+			b:    "the fox jumps over the dog",
+			want: []Edit{
+				{Equal, "the"},
+				{Delete, "quick brown"},
+				{Equal, "fox jumps"},
+				{Insert, "over the dog"},
+			},
+		},
+		{
+			name: "whitespace only",
+			a:    "a  b\n c",
+			b:    "a b c",
+			want: []Edit{{Equal, "a b c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Words(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWords_Reconstructs(t *testing.T) {
+	a := "It is not the man who has too little, but the man who craves more, that is poor."
+	b := "It is not the man who has little, but he who craves more, that is poor indeed."
+
+	var from, to []string
+	for _, edit := range Words(a, b) {
+		if edit.Op != Insert {
+			from = append(from, edit.Text)
+		}
+		if edit.Op != Delete {
+			to = append(to, edit.Text)
+		}
+	}
+
+	if got := strings.Join(from, " "); got != a {
+		t.Errorf("expected equal and deleted runs to rebuild the old text, got %q", got)
+	}
+	if got := strings.Join(to, " "); got != b {
+		t.Errorf("expected equal and inserted runs to rebuild the new text, got %q", got)
+	}
+}
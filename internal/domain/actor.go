@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+// AnonymousActor is recorded for changes made by unidentified callers
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+// WithActor returns a context attributing the writes made with it to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or AnonymousActor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxAPIKeyName bounds the name of a stored API key
+const maxAPIKeyName = 64
+
+// APIKey is an API key created through the admin API. Key is only returned when the key
+// is created; the service keeps its digest alone.
+type APIKey struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	Key       string    `json:"key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKeyRequest creates an API key. An empty Role creates a user key.
+type APIKeyRequest struct {
+	Name string `json:"name"`
+	Role Role   `json:"role,omitempty"`
+}
+
+// Validate checks the name and the role. Names follow the rules of API_KEYS entries, so a
+// key can be moved between the two.
+func (r *APIKeyRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" || len(name) > maxAPIKeyName || strings.Contains(name, ":") {
+		return fmt.Errorf("%w: name must be 1-%d characters without ':'", ErrInvalidAPIKeyRequest, maxAPIKeyName)
+	}
+	if r.Role != "" && r.Role != RoleUser && r.Role != RoleAdmin {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidAPIKeyRequest, r.Role)
+	}
+	return nil
+}
+
+// APIKeyDigest returns the hex SHA-256 digest a key is stored and looked up by
+func APIKeyDigest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
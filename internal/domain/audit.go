@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditAction names the operation an audit event was recorded for
+type AuditAction string
+
+const (
+	ActionQuoteCreate     AuditAction = "quote.create"
+	ActionQuoteUpdate     AuditAction = "quote.update"
+	ActionQuoteDelete     AuditAction = "quote.delete"
+	ActionQuoteHardDelete AuditAction = "quote.hard_delete"
+	ActionQuoteRestore    AuditAction = "quote.restore"
+	ActionQuoteRevert     AuditAction = "quote.revert"
+	ActionBatchCreate     AuditAction = "quote.batch_create"
+	ActionBatchDelete     AuditAction = "quote.batch_delete"
+	ActionImport          AuditAction = "quote.import"
+	ActionTrashPurge      AuditAction = "trash.purge"
+	ActionAuthorUpdate    AuditAction = "author.update"
+)
+
+// AuditEntry names who made a change and on whose request. The use case fills it in and the
+// repository writes one audit event per changed quote or author from it, in the transaction of the change.
+type AuditEntry struct {
+	Action    AuditAction
+	Actor     string
+	RequestID string
+	ClientIP  string
+}
+
+// AuditEvent is one row of the append-only audit log. Each event carries the hash of the
+// event before it, so removing or editing a row breaks the chain after it. Events are
+// chained after they commit, so the newest may not have a position or hashes yet.
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	Action     AuditAction     `json:"action"`
+	Actor      string          `json:"actor"`
+	QuoteID    int             `json:"quote_id,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	ClientIP   string          `json:"client_ip,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	// Position is the place of the event in the hash chain, 0 until it is chained
+	Position int64  `json:"position,omitempty"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// auditTimeLayout is the timestamp format hashed for an event, in microseconds as stored
+const auditTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// ComputeHash returns the hash the event should carry given PrevHash: the hex SHA-256 of its
+// fields, each prefixed with its length in bytes
+func (e *AuditEvent) ComputeHash() string {
+	quoteID := ""
+	if e.QuoteID != 0 {
+		quoteID = strconv.Itoa(e.QuoteID)
+	}
+
+	var b strings.Builder
+	for _, field := range []string{
+		e.PrevHash,
+		strconv.FormatInt(e.ID, 10),
+		e.OccurredAt.UTC().Format(auditTimeLayout),
+		e.Actor,
+		string(e.Action),
+		quoteID,
+		e.RequestID,
+		e.ClientIP,
+		string(e.Before),
+		string(e.After),
+	} {
+		b.WriteString(strconv.Itoa(len(field)))
+		b.WriteByte(':')
+		b.WriteString(field)
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditFilter selects audit events. Zero fields do not filter.
+type AuditFilter struct {
+	Actor     string
+	Action    AuditAction
+	QuoteID   int
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+	// BeforeID pages backwards: only events with a smaller ID are returned
+	BeforeID int64
+	Limit    int
+}
+
+// AuditPage is a page of audit events, newest first
+type AuditPage struct {
+	Events []*AuditEvent `json:"events"`
+	// NextCursor is passed as cursor to get the next page; zero on the last page
+	NextCursor int64 `json:"next_cursor,omitempty"`
+}
+
+// AuditVerification is the result of checking the audit hash chain
+type AuditVerification struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	// BrokenAt is the first event whose hash or link to the previous event does not match
+	BrokenAt int64 `json:"broken_at,omitempty"`
+	// LastHash is the hash of the newest chained event. Keeping a copy elsewhere also makes
+	// removal of the newest events detectable.
+	LastHash string `json:"last_hash,omitempty"`
+	// Pending is the number of events not chained yet, which are not checked
+	Pending int64 `json:"pending"`
+}
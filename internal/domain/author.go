@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxBioLength bounds author bios, in characters
+const MaxBioLength = 5000
+
+// Author is a person quotes are attributed to
+type Author struct {
+	Name string `json:"name"`
+	Bio  string `json:"bio,omitempty"`
+	// QuoteCount is the number of live quotes by the author
+	QuoteCount int `json:"quote_count"`
+}
+
+// Tag is a tag with the number of live quotes carrying it
+type Tag struct {
+	Name       string `json:"name"`
+	QuoteCount int    `json:"quote_count"`
+}
+
+// AuthorPage is one page of authors ordered by name
+type AuthorPage struct {
+	Authors []*Author
+	HasNext bool
+}
+
+// TagPage is one page of tags ordered by name
+type TagPage struct {
+	Tags    []*Tag
+	HasNext bool
+}
+
+// ValidateBio checks an author bio, which may be empty to clear it
+func ValidateBio(bio string) error {
+	if !utf8.ValidString(bio) || utf8.RuneCountInString(strings.TrimSpace(bio)) > MaxBioLength {
+		return ErrInvalidBio
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+// BatchCreateRequest is the input of a bulk insert
+type BatchCreateRequest struct {
+	Quotes []*CreateQuoteRequest
+	// Atomic rejects the whole batch when any item is invalid
+	Atomic bool
+}
+
+// BatchItemResult reports the outcome for a single item of a batch
+type BatchItemResult struct {
+	Index   int           `json:"index"`
+	Quote   *Quote        `json:"quote,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Details []*FieldError `json:"details,omitempty"`
+}
+
+// BatchCreateResult summarises a bulk insert
+type BatchCreateResult struct {
+	Atomic  bool               `json:"atomic"`
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Items   []*BatchItemResult `json:"items"`
+}
+
+// DeleteFilter selects quotes for a bulk delete. Exactly one of IDs or Author must be set.
+type DeleteFilter struct {
+	IDs    []int  `json:"ids,omitempty"`
+	Author string `json:"author,omitempty"`
+}
+
+// BatchDeleteRequest is the input of a bulk delete
+type BatchDeleteRequest struct {
+	Filter DeleteFilter
+	// DryRun only reports which quotes would be deleted
+	DryRun bool
+}
+
+// BatchDeleteResult lists the quotes removed (or matched, for a dry run)
+type BatchDeleteResult struct {
+	DryRun  bool  `json:"dry_run"`
+	Deleted int   `json:"deleted"`
+	IDs     []int `json:"ids"`
+}
+
+// BatchLimits caps the number of items accepted by bulk operations
+type BatchLimits struct {
+	MaxCreateItems int
+	MaxDeleteItems int
+}
+
+// DefaultBatchLimits is used when no limits are configured
+var DefaultBatchLimits = BatchLimits{
+	MaxCreateItems: 1000,
+	MaxDeleteItems: 1000,
+}
@@ -1,11 +1,72 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrQuoteNotFound = errors.New("quote not found")
 	ErrInvalidAuthor = errors.New("invalid author")
 	ErrInvalidQuote  = errors.New("invalid quote")
 	ErrInvalidID     = errors.New("invalid quote ID")
+	ErrInvalidTag    = errors.New("invalid tag")
 	ErrNoQuotesFound = errors.New("no quotes found")
+
+	ErrVersionConflict = errors.New("version conflict")
+	ErrInvalidVersion  = errors.New("invalid quote version")
+
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrInvalidRevision  = errors.New("invalid revision")
+
+	ErrNotInTrash = errors.New("quote is not in the trash")
+
+	ErrInvalidAPIKey        = errors.New("invalid API key")
+	ErrForbidden            = errors.New("forbidden")
+	ErrAPIKeyNotFound       = errors.New("API key not found")
+	ErrInvalidAPIKeyRequest = errors.New("invalid API key request")
+
+	ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrLeaseLost        = errors.New("webhook delivery lease lost")
+	ErrInvalidWebhook   = errors.New("invalid webhook subscription")
+
+	ErrInvalidEventID = errors.New("invalid event ID")
+
+	ErrInvalidQuery     = errors.New("invalid search query")
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidPageSize  = errors.New("invalid page size")
+
+	ErrInvalidQuoteFilter = errors.New("invalid quote filter")
+
+	ErrAuthorNotFound = errors.New("author not found")
+	ErrInvalidBio     = errors.New("invalid author bio")
+
+	ErrEmptyBatch          = errors.New("batch is empty")
+	ErrBatchTooLarge       = errors.New("batch exceeds the maximum number of items")
+	ErrBatchRejected       = errors.New("batch rejected: one or more items are invalid")
+	ErrInvalidDeleteFilter = errors.New("delete filter must contain either ids or author")
+
+	ErrMalformedRecord = errors.New("malformed record")
+	ErrInvalidImport   = errors.New("invalid import file")
 )
+
+// VersionConflictError reports that a quote changed after the version the caller expected.
+// It wraps ErrVersionConflict.
+type VersionConflictError struct {
+	// Current is the quote as it is now stored
+	Current *Quote
+}
+
+func (e *VersionConflictError) Error() string {
+	if e.Current == nil {
+		return ErrVersionConflict.Error()
+	}
+	return fmt.Sprintf("%s: quote %d is at version %d", ErrVersionConflict, e.Current.ID, e.Current.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
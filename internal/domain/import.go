@@ -0,0 +1,22 @@
+package domain
+
+// ImportError describes a record that was not imported
+type ImportError struct {
+	Record  int           `json:"record"`
+	Error   string        `json:"error"`
+	Details []*FieldError `json:"details,omitempty"`
+}
+
+// ImportReport summarises an import run
+type ImportReport struct {
+	Total      int            `json:"total"`
+	Imported   int            `json:"imported"`
+	Duplicates int            `json:"duplicates"`
+	Failed     int            `json:"failed"`
+	Errors     []*ImportError `json:"errors"`
+}
+
+// DuplicateKey identifies quotes that are considered the same on import
+func (q *Quote) DuplicateKey() string {
+	return q.Author + "\x00" + q.Quote
+}
@@ -8,4 +8,43 @@ var (
 	MsgFailedGetRandomQuote = "failed to get random quote"
 	MsgInvalidQuoteID       = "invalid quote ID"
 	MsgFailedDeleteQuote    = "failed to delete quote"
+	MsgValidationFailed     = "validation failed"
+	MsgFailedCreateQuotes   = "failed to create quotes"
+	MsgFailedDeleteQuotes   = "failed to delete quotes"
+	MsgFailedImportQuotes   = "failed to import quotes"
+	MsgFailedExportQuotes   = "failed to export quotes"
+	MsgNotAcceptable        = "none of the requested media types is available"
+	MsgFailedRender         = "failed to render response"
+	MsgFailedUpdateQuote    = "failed to update quote"
+	MsgPreconditionFailed   = "quote has been modified, If-Match does not match its current ETag"
+	MsgVersionConflict      = "quote has been modified since the given version"
+	MsgVersionMismatch      = "version in the body does not match the version of the If-Match ETag"
+	MsgRevisionNotFound     = "revision not found"
+	MsgFailedGetRevisions   = "failed to get revisions"
+	MsgFailedRestoreQuote   = "failed to restore quote"
+	MsgNotInTrash           = "quote not found in trash"
+	MsgFailedGetTrash       = "failed to get trash"
+	MsgInvalidAPIKey        = "invalid API key"
+	MsgAPIKeyRequired       = "API key required"
+	MsgAdminRequired        = "admin role required"
+	MsgAPIKeyNotFound       = "API key not found"
+	MsgFailedGetAPIKeys     = "failed to get API keys"
+	MsgFailedCreateAPIKey   = "failed to create API key"
+	MsgFailedRevokeAPIKey   = "failed to revoke API key"
+	MsgFailedGetAudit       = "failed to get audit events"
+	MsgFailedVerifyAudit    = "failed to verify audit log"
+	MsgWebhookNotFound      = "webhook subscription not found"
+	MsgDeliveryNotFound     = "webhook delivery not found"
+	MsgFailedGetWebhooks    = "failed to get webhook subscriptions"
+	MsgFailedSaveWebhook    = "failed to save webhook subscription"
+	MsgFailedDeleteWebhook  = "failed to delete webhook subscription"
+	MsgFailedRedeliver      = "failed to redeliver webhook"
+	MsgInvalidLastEventID   = "Last-Event-ID must be a non-negative event ID"
+	MsgFailedStream         = "failed to open event stream"
+	MsgFailedSearchQuotes   = "failed to search quotes"
+	MsgFailedGetStats       = "failed to get stats"
+	MsgInvalidPageToken     = "invalid page token"
+	MsgInvalidSearchQuery   = "search query must not be empty"
+	MsgInternalError        = "internal server error"
+	MsgRequestMismatch      = "request does not match the API specification"
 )
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Page sizes for keyset-paginated listings
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// Search result limits
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// PageRequest selects one page of quotes in ID order
+type PageRequest struct {
+	// Author restricts the page to one author when set
+	Author string
+	// Tag restricts the page to quotes carrying the tag when set
+	Tag string
+	// AfterID is the last ID of the previous page, 0 for the first page
+	AfterID int
+	// Limit is the page size; 0 selects DefaultPageSize
+	Limit int
+}
+
+// QuotePage is one page of quotes
+type QuotePage struct {
+	Quotes []*Quote
+	// NextAfterID continues the listing after this page, 0 on the last page
+	NextAfterID int
+	// Next continues a filtered listing after this page in its sort order, zero on the last page
+	Next QuoteCursor
+}
+
+// QuoteSort orders a quote listing by one of QuoteSortKeys, descending when prefixed
+// with "-". The empty sort lists in ID order.
+type QuoteSort string
+
+// QuoteSortKeys are the keys a listing can be sorted by
+var QuoteSortKeys = []string{"created_at", "author", "length"}
+
+// Key returns the sort key without the direction
+func (s QuoteSort) Key() string {
+	return strings.TrimPrefix(string(s), "-")
+}
+
+// Descending reports whether the listing runs from the largest key down
+func (s QuoteSort) Descending() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// Valid reports whether s is empty or sorts by one of QuoteSortKeys
+func (s QuoteSort) Valid() bool {
+	return s == "" || slices.Contains(QuoteSortKeys, s.Key())
+}
+
+// QuoteFilter selects live quotes. Zero fields do not filter.
+type QuoteFilter struct {
+	Author string
+	// Tag keeps quotes carrying the tag
+	Tag string
+	// CreatedAfter and CreatedBefore bound the creation time, both exclusive
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// MinLength and MaxLength bound the length of the text in characters, both inclusive
+	MinLength int
+	MaxLength int
+	Sort      QuoteSort
+	// After is the last quote of the previous page; the next page follows it in Sort order
+	After QuoteCursor
+	// Limit caps the number of quotes; the repository treats 0 as no cap
+	Limit int
+}
+
+// QuoteCursor is the position of a quote in a sorted listing: the value of its sort key,
+// as text, and its ID breaking ties. Listings in ID order leave Key empty.
+type QuoteCursor struct {
+	Key string
+	ID  int
+}
+
+// NewQuoteCursor returns the position of quote in a listing sorted by sort
+func NewQuoteCursor(quote *Quote, sort QuoteSort) QuoteCursor {
+	cursor := QuoteCursor{ID: quote.ID}
+	switch sort.Key() {
+	case "created_at":
+		cursor.Key = quote.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "author":
+		cursor.Key = quote.Author
+	case "length":
+		cursor.Key = strconv.Itoa(utf8.RuneCountInString(quote.Quote))
+	}
+	return cursor
+}
+
+// IsZero reports whether c is the start of a listing
+func (c QuoteCursor) IsZero() bool {
+	return c == QuoteCursor{}
+}
+
+// ValidFor reports whether c is a position in a listing sorted by sort
+func (c QuoteCursor) ValidFor(sort QuoteSort) bool {
+	if c.ID <= 0 {
+		return false
+	}
+	switch sort.Key() {
+	case "":
+		return c.Key == ""
+	case "created_at":
+		_, err := time.Parse(time.RFC3339Nano, c.Key)
+		return err == nil
+	case "author":
+		return c.Key != ""
+	case "length":
+		n, err := strconv.Atoi(c.Key)
+		return err == nil && n >= 0
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "context"
+
+// Role grants a set of permissions to an API key
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// IsAdmin reports whether the principal may perform administrative actions
+func (p *Principal) IsAdmin() bool {
+	return p != nil && p.Role == RoleAdmin
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller, who is also
+// recorded as the actor of the writes made with it
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return WithActor(context.WithValue(ctx, principalKey{}, principal), principal.Name)
+}
+
+// PrincipalFromContext returns the caller set by WithPrincipal, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
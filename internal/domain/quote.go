@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -8,20 +9,57 @@ type Quote struct {
 	ID        int       `json:"id" db:"id"`
 	Author    string    `json:"author" db:"author"`
 	Quote     string    `json:"quote" db:"quote"`
+	Tags      []string  `json:"tags,omitempty" db:"tags"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Version   int       `json:"version" db:"version"`
+	// DeletedAt is set while the quote is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// UpdateQuoteRequest replaces a quote. A non-zero Version makes the update conditional:
+// it fails with a VersionConflictError unless the quote is still at that version.
+type UpdateQuoteRequest struct {
+	CreateQuoteRequest
+	Version int `json:"version,omitempty"`
 }
 
 type CreateQuoteRequest struct {
-	Author string `json:"author" db:"author"`
-	Quote  string `json:"quote" db:"quote"`
+	Author string   `json:"author" db:"author"`
+	Quote  string   `json:"quote" db:"quote"`
+	Tags   []string `json:"tags,omitempty" db:"tags"`
 }
 
+// Validate checks the request against DefaultValidationRules
 func (r *CreateQuoteRequest) Validate() error {
-	if r.Author == "" {
-		return ErrInvalidAuthor
+	return DefaultValidationRules.Validate(r)
+}
+
+// Normalize trims surrounding whitespace from all fields and
+// lowercases tags, dropping duplicates
+func (r *CreateQuoteRequest) Normalize() {
+	r.Author = strings.TrimSpace(r.Author)
+	r.Quote = strings.TrimSpace(r.Quote)
+	r.Tags = NormalizeTags(r.Tags)
+}
+
+// NormalizeTags trims and lowercases tags, dropping empty values and duplicates
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
 	}
-	if r.Quote == "" {
-		return ErrInvalidQuote
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
 	}
-	return nil
+	return normalized
 }
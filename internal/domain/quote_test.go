@@ -2,7 +2,12 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 func TestCreateQuoteRequest_Validate(t *testing.T) {
@@ -31,6 +36,66 @@ func TestCreateQuoteRequest_Validate(t *testing.T) {
 			req:     CreateQuoteRequest{Author: "", Quote: ""},
 			wantErr: ErrInvalidAuthor, //before ErrInvalidQuote
 		},
+		{
+			name:    "whitespace only author",
+			req:     CreateQuoteRequest{Author: " \t ", Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "whitespace only quote",
+			req:     CreateQuoteRequest{Author: "Author", Quote: "\n  "},
+			wantErr: ErrInvalidQuote,
+		},
+		{
+			name:    "author too long",
+			req:     CreateQuoteRequest{Author: strings.Repeat("a", 256), Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "author at limit in runes",
+			req:     CreateQuoteRequest{Author: strings.Repeat("ж", 255), Quote: "Some quote"},
+			wantErr: nil,
+		},
+		{
+			name:    "quote too long",
+			req:     CreateQuoteRequest{Author: "Author", Quote: strings.Repeat("a", 2001)},
+			wantErr: ErrInvalidQuote,
+		},
+		{
+			name:    "invalid utf-8 in author",
+			req:     CreateQuoteRequest{Author: "Auth\xffor", Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "control character in author",
+			req:     CreateQuoteRequest{Author: "Auth\x00or", Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "newline in author",
+			req:     CreateQuoteRequest{Author: "Auth\nor", Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "newline in quote",
+			req:     CreateQuoteRequest{Author: "Author", Quote: "First line\nSecond line"},
+			wantErr: nil,
+		},
+		{
+			name:    "zero-width character in quote",
+			req:     CreateQuoteRequest{Author: "Author", Quote: "Some\u200bquote"},
+			wantErr: ErrInvalidQuote,
+		},
+		{
+			name:    "bidi override in author",
+			req:     CreateQuoteRequest{Author: "Auth\u202eor", Quote: "Some quote"},
+			wantErr: ErrInvalidAuthor,
+		},
+		{
+			name:    "soft hyphen in quote",
+			req:     CreateQuoteRequest{Author: "Author", Quote: "Some\u00adquote"},
+			wantErr: ErrInvalidQuote,
+		},
 	}
 
 	for _, tt := range tests {
@@ -42,3 +107,121 @@ func TestCreateQuoteRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationRules_Validate_ReportsAllFields(t *testing.T) {
+	req := &CreateQuoteRequest{Author: "", Quote: "bad\x07quote"}
+
+	err := DefaultValidationRules.Validate(req)
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if len(verr.Errors) != 2 {
+		t.Fatalf("expected 2 field errors, got %d", len(verr.Errors))
+	}
+	if verr.Errors[0].Field != "author" || verr.Errors[0].Code != CodeRequired {
+		t.Errorf("unexpected first error: %+v", verr.Errors[0])
+	}
+	if verr.Errors[1].Field != "quote" || verr.Errors[1].Code != CodeControlCharacter {
+		t.Errorf("unexpected second error: %+v", verr.Errors[1])
+	}
+	if !errors.Is(err, ErrInvalidAuthor) || !errors.Is(err, ErrInvalidQuote) {
+		t.Error("validation error should match both field sentinels")
+	}
+}
+
+func FuzzValidationRules_Validate(f *testing.F) {
+	f.Add("Albert Einstein", "Life is like riding a bicycle.")
+	f.Add("", "")
+	f.Add("  ", "\n")
+	f.Add("Auth\xffor", "quote")
+	f.Add("Author", "zero\u200bwidth")
+	f.Add("Author", "bidi\u202eoverride")
+	f.Add("Лев Толстой", "Все счастливые семьи похожи друг на друга.")
+
+	rules := ValidationRules{MaxAuthorLength: 32, MaxQuoteLength: 64}
+
+	f.Fuzz(func(t *testing.T, author, quote string) {
+		req := &CreateQuoteRequest{Author: author, Quote: quote}
+		err := rules.Validate(req)
+		if err == nil {
+			req.Normalize()
+			checkValidField(t, "author", req.Author, rules.MaxAuthorLength, false)
+			checkValidField(t, "quote", req.Quote, rules.MaxQuoteLength, true)
+			return
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) || len(verr.Errors) == 0 {
+			t.Fatalf("expected non-empty *ValidationError, got %v", err)
+		}
+		for _, fe := range verr.Errors {
+			if fe.Field != "author" && fe.Field != "quote" {
+				t.Fatalf("unexpected field %q", fe.Field)
+			}
+		}
+	})
+}
+
+func checkValidField(t *testing.T, field, value string, maxLength int, multiline bool) {
+	t.Helper()
+	if value == "" {
+		t.Fatalf("%s accepted empty after trimming", field)
+	}
+	if !utf8.ValidString(value) {
+		t.Fatalf("%s accepted invalid UTF-8 %q", field, value)
+	}
+	if n := utf8.RuneCountInString(value); n > maxLength {
+		t.Fatalf("%s accepted %d runes, limit %d", field, n, maxLength)
+	}
+	for _, r := range value {
+		if isFormatCharacter(r) {
+			t.Fatalf("%s accepted format character %U", field, r)
+		}
+		if unicode.IsControl(r) && !(multiline && (r == '\n' || r == '\t' || r == '\r')) {
+			t.Fatalf("%s accepted control character %U", field, r)
+		}
+	}
+}
+
+func TestVersionConflictError(t *testing.T) {
+	err := fmt.Errorf("update: %w", &VersionConflictError{Current: &Quote{ID: 7, Version: 3}})
+
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected errors.Is to match ErrVersionConflict")
+	}
+
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) || conflict.Current.Version != 3 {
+		t.Fatalf("expected errors.As to expose the current quote, got %v", conflict)
+	}
+	if got, want := conflict.Error(), "version conflict: quote 7 is at version 3"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestAuditEvent_ComputeHash(t *testing.T) {
+	event := &AuditEvent{
+		ID:         1,
+		Action:     ActionQuoteUpdate,
+		Actor:      "alice",
+		QuoteID:    7,
+		RequestID:  "req-1",
+		ClientIP:   "10.0.0.1",
+		Before:     []byte(`{"quote": "Старое"}`),
+		After:      []byte(`{"quote": "Новое"}`),
+		OccurredAt: time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.FixedZone("MSK", 3*60*60)),
+	}
+
+	// Computed independently from the length-prefixed fields, with the time in UTC
+	const want = "8f754f1d529ec29a079f72934c58c0ba83cdb1d46e71336f13fe3576d05bc972"
+	if got := event.ComputeHash(); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	event.PrevHash = want
+	if event.ComputeHash() == want {
+		t.Error("expected the previous hash to change the hash")
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// RequestInfo identifies the API request a write was made for
+type RequestInfo struct {
+	ID       string
+	ClientIP string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a context carrying the request the writes made with it belong to
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the info set by WithRequestInfo, or a zero RequestInfo
+// outside of API requests
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
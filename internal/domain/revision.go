@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/diff"
+)
+
+// ChangeType is the kind of change a revision records
+type ChangeType string
+
+const (
+	ChangeCreate  ChangeType = "create"
+	ChangeUpdate  ChangeType = "update"
+	ChangeDelete  ChangeType = "delete"
+	ChangeRestore ChangeType = "restore"
+	// ChangePurge removes a quote for good, whether from the trash or directly
+	ChangePurge ChangeType = "purge"
+)
+
+// Revision is the state of a quote after one change. For purges Snapshot holds
+// the quote as it was just before it was removed.
+type Revision struct {
+	QuoteID   int        `json:"quote_id"`
+	Revision  int        `json:"revision"`
+	Change    ChangeType `json:"change"`
+	Actor     string     `json:"actor"`
+	CreatedAt time.Time  `json:"created_at"`
+	Snapshot  *Quote     `json:"snapshot"`
+}
+
+// RevisionDiff describes how a quote changed between two revisions
+type RevisionDiff struct {
+	QuoteID     int         `json:"quote_id"`
+	From        int         `json:"from"`
+	To          int         `json:"to"`
+	Author      []diff.Edit `json:"author"`
+	Quote       []diff.Edit `json:"quote"`
+	TagsAdded   []string    `json:"tags_added"`
+	TagsRemoved []string    `json:"tags_removed"`
+}
+
+// DiffRevisions compares the snapshots of two revisions word by word
+func DiffRevisions(from, to *Revision) *RevisionDiff {
+	d := &RevisionDiff{
+		QuoteID:     to.QuoteID,
+		From:        from.Revision,
+		To:          to.Revision,
+		Author:      diff.Words(from.Snapshot.Author, to.Snapshot.Author),
+		Quote:       diff.Words(from.Snapshot.Quote, to.Snapshot.Quote),
+		TagsAdded:   []string{},
+		TagsRemoved: []string{},
+	}
+
+	old := make(map[string]bool, len(from.Snapshot.Tags))
+	for _, tag := range from.Snapshot.Tags {
+		old[tag] = true
+	}
+	current := make(map[string]bool, len(to.Snapshot.Tags))
+	for _, tag := range to.Snapshot.Tags {
+		current[tag] = true
+		if !old[tag] {
+			d.TagsAdded = append(d.TagsAdded, tag)
+		}
+	}
+	for _, tag := range from.Snapshot.Tags {
+		if !current[tag] {
+			d.TagsRemoved = append(d.TagsRemoved, tag)
+		}
+	}
+
+	return d
+}
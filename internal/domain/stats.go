@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// CollectionStats describes the live quotes as of a point in time
+type CollectionStats struct {
+	// AsOf is when the statistics were computed, the last refresh when they are materialized
+	AsOf         time.Time `json:"as_of"`
+	TotalQuotes  int64     `json:"total_quotes"`
+	TotalAuthors int64     `json:"total_authors"`
+	// QuotesPerDay, QuotesPerWeek and QuotesPerMonth count the quotes created in each of the
+	// last periods up to AsOf, oldest first; weeks start on Monday and periods are in UTC
+	QuotesPerDay   []*StatsBucket `json:"quotes_per_day"`
+	QuotesPerWeek  []*StatsBucket `json:"quotes_per_week"`
+	QuotesPerMonth []*StatsBucket `json:"quotes_per_month"`
+	// TopAuthors are the authors with the most quotes, most first
+	TopAuthors []*AuthorStats `json:"top_authors"`
+	Length     LengthStats    `json:"length"`
+}
+
+// StatsBucket is the number of quotes created in the period starting at Start
+type StatsBucket struct {
+	Start  time.Time `json:"start"`
+	Quotes int64     `json:"quotes"`
+}
+
+// AuthorStats is an author with their number of quotes and most used words
+type AuthorStats struct {
+	Author string `json:"author"`
+	Quotes int64  `json:"quotes"`
+	// CommonWords leaves out the words of StopWords, most used first
+	CommonWords []*WordCount `json:"common_words"`
+}
+
+// WordCount is how many times a word is used
+type WordCount struct {
+	Word string `json:"word"`
+	Uses int64  `json:"uses"`
+}
+
+// LengthStats describes the lengths of quote texts, in characters
+type LengthStats struct {
+	Average float64 `json:"average"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+}
+
+// StatsPolicy shapes the collection statistics
+type StatsPolicy struct {
+	// Days, Weeks and Months are the lengths of the time series
+	Days   int
+	Weeks  int
+	Months int
+	// TopAuthors is the number of authors ranked, TopWords the number of words listed for each
+	TopAuthors int
+	TopWords   int
+	// Materialized reads the statistics from views refreshed on a schedule rather than
+	// aggregating the quotes on every read
+	Materialized bool
+}
+
+// DefaultStatsPolicy is used when no policy is configured
+var DefaultStatsPolicy = StatsPolicy{
+	Days:         30,
+	Weeks:        12,
+	Months:       12,
+	TopAuthors:   10,
+	TopWords:     10,
+	Materialized: true,
+}
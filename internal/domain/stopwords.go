@@ -0,0 +1,40 @@
+package domain
+
+// EnglishStopWords and RussianStopWords are left out of the common words of authors.
+// Words are lowercase and split at anything but a letter, so contractions lose their
+// apostrophe: "don't" counts as "don".
+var (
+	EnglishStopWords = []string{
+		"about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "aren",
+		"as", "at", "be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+		"can", "cannot", "could", "couldn", "did", "didn", "do", "does", "doesn", "doing", "don", "down",
+		"during", "each", "few", "for", "from", "further", "had", "hadn", "has", "hasn", "have", "haven",
+		"having", "he", "her", "here", "hers", "herself", "him", "himself", "his", "how", "if", "in",
+		"into", "is", "isn", "it", "its", "itself", "just", "ll", "me", "more", "most", "must", "my",
+		"myself", "no", "nor", "not", "now", "of", "off", "on", "once", "one", "only", "or", "other",
+		"our", "ours", "ourselves", "out", "over", "own", "re", "same", "shall", "she", "should",
+		"shouldn", "so", "some", "such", "than", "that", "the", "their", "theirs", "them", "themselves",
+		"then", "there", "these", "they", "this", "those", "through", "to", "too", "under", "until", "up",
+		"upon", "us", "ve", "very", "was", "wasn", "we", "were", "weren", "what", "when", "where",
+		"which", "while", "who", "whom", "why", "will", "with", "won", "would", "wouldn", "you", "your",
+		"yours", "yourself", "yourselves",
+	}
+
+	RussianStopWords = []string{
+		"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас", "весь", "во",
+		"вот", "все", "всегда", "всего", "всех", "вы", "где", "да", "даже", "для", "до", "его", "ее",
+		"её", "если", "есть", "еще", "ещё", "же", "за", "здесь", "и", "из", "или", "им", "их", "к",
+		"как", "какой", "когда", "кто", "ли", "либо", "между", "меня", "мне", "может", "мой", "мы", "на",
+		"над", "надо", "наш", "не", "него", "нее", "неё", "нет", "ни", "них", "но", "ну", "о", "об",
+		"один", "он", "она", "они", "оно", "от", "очень", "по", "под", "после", "потом", "потому",
+		"почти", "при", "про", "раз", "с", "сам", "свой", "себе", "себя", "со", "так", "также", "такой",
+		"там", "тебя", "тем", "теперь", "то", "тогда", "того", "тоже", "только", "том", "тот", "тут",
+		"ты", "у", "уж", "уже", "хоть", "чего", "чем", "через", "что", "чтоб", "чтобы", "чуть", "эта",
+		"эти", "это", "этого", "этой", "этом", "этот", "я",
+	}
+)
+
+// StopWords returns the stop words of every supported language
+func StopWords() []string {
+	return append(append([]string{}, EnglishStopWords...), RussianStopWords...)
+}
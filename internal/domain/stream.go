@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// StreamFilter narrows a change stream to quotes by one author and/or with one tag; empty fields match everything
+type StreamFilter struct {
+	Author string
+	Tag    string
+}
+
+// Match reports whether the quote carried by e passes the filter. Updates and deletes
+// are matched against the quote as it is after the change.
+func (f StreamFilter) Match(e *WebhookEvent) bool {
+	if f.Author == "" && f.Tag == "" {
+		return true
+	}
+
+	var quote Quote
+	if err := json.Unmarshal(e.Data, &quote); err != nil {
+		return false
+	}
+	return f.MatchQuote(&quote)
+}
+
+// MatchQuote reports whether quote passes the filter
+func (f StreamFilter) MatchQuote(quote *Quote) bool {
+	return (f.Author == "" || quote.Author == f.Author) && (f.Tag == "" || slices.Contains(quote.Tags, f.Tag))
+}
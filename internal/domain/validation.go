@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Validation error codes reported in FieldError.Code
+const (
+	CodeRequired           = "required"
+	CodeTooLong            = "too_long"
+	CodeInvalidUTF8        = "invalid_utf8"
+	CodeControlCharacter   = "control_character"
+	CodeZeroWidthCharacter = "zero_width_character"
+	CodeTooMany            = "too_many"
+	CodeInvalidCharacter   = "invalid_character"
+)
+
+// ValidationRules holds the limits applied to incoming quote data.
+// Lengths are measured in runes, not bytes.
+type ValidationRules struct {
+	MaxAuthorLength int
+	MaxQuoteLength  int
+	MaxTags         int
+	MaxTagLength    int
+}
+
+// DefaultValidationRules matches the limits of the quotes table
+var DefaultValidationRules = ValidationRules{
+	MaxAuthorLength: 255,
+	MaxQuoteLength:  2000,
+	MaxTags:         10,
+	MaxTagLength:    50,
+}
+
+// FieldError describes a single invalid field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError collects every field error found in a request
+type ValidationError struct {
+	Errors []*FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		messages = append(messages, fe.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap exposes the field errors so errors.Is matches ErrInvalidAuthor / ErrInvalidQuote
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+func (e *ValidationError) add(field, code, message string, err error) {
+	e.Errors = append(e.Errors, &FieldError{Field: field, Code: code, Message: message, Err: err})
+}
+
+// Validate checks the request against the rules and returns a *ValidationError
+// listing all problems, or nil when the request is valid
+func (rules ValidationRules) Validate(req *CreateQuoteRequest) error {
+	verr := &ValidationError{}
+
+	rules.validateText(verr, "author", req.Author, rules.MaxAuthorLength, false, ErrInvalidAuthor)
+	rules.validateText(verr, "quote", req.Quote, rules.MaxQuoteLength, true, ErrInvalidQuote)
+	rules.validateTags(verr, req.Tags)
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func (rules ValidationRules) validateText(verr *ValidationError, field, value string, maxLength int, multiline bool, sentinel error) {
+	if !utf8.ValidString(value) {
+		verr.add(field, CodeInvalidUTF8, "must be valid UTF-8", sentinel)
+		return
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		verr.add(field, CodeRequired, "must not be empty", sentinel)
+		return
+	}
+
+	if maxLength > 0 {
+		if n := utf8.RuneCountInString(value); n > maxLength {
+			verr.add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters, got %d", maxLength, n), sentinel)
+		}
+	}
+
+	for _, r := range value {
+		if isFormatCharacter(r) {
+			verr.add(field, CodeZeroWidthCharacter, fmt.Sprintf("must not contain invisible format character %U", r), sentinel)
+			break
+		}
+		if unicode.IsControl(r) && !(multiline && (r == '\n' || r == '\t' || r == '\r')) {
+			verr.add(field, CodeControlCharacter, fmt.Sprintf("must not contain control character %U", r), sentinel)
+			break
+		}
+	}
+}
+
+// validateTags allows letters, digits, spaces, '-' and '_' in tags
+func (rules ValidationRules) validateTags(verr *ValidationError, tags []string) {
+	if rules.MaxTags > 0 && len(tags) > rules.MaxTags {
+		verr.add("tags", CodeTooMany, fmt.Sprintf("must contain at most %d tags, got %d", rules.MaxTags, len(tags)), ErrInvalidTag)
+		return
+	}
+
+	for i, tag := range tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		if !utf8.ValidString(tag) {
+			verr.add(field, CodeInvalidUTF8, "must be valid UTF-8", ErrInvalidTag)
+			continue
+		}
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			verr.add(field, CodeRequired, "must not be empty", ErrInvalidTag)
+			continue
+		}
+		if rules.MaxTagLength > 0 {
+			if n := utf8.RuneCountInString(tag); n > rules.MaxTagLength {
+				verr.add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters, got %d", rules.MaxTagLength, n), ErrInvalidTag)
+				continue
+			}
+		}
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != ' ' {
+				verr.add(field, CodeInvalidCharacter, fmt.Sprintf("must not contain %q", r), ErrInvalidTag)
+				break
+			}
+		}
+	}
+}
+
+// isFormatCharacter reports invisible format characters such as zero-width spaces, the soft
+// hyphen and bidi overrides. They are all reported as CodeZeroWidthCharacter.
+func isFormatCharacter(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
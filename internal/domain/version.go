@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// CollectionVersion identifies the state of the whole quotes collection.
+// Version grows with every write, including deletes.
+type CollectionVersion struct {
+	Version   int64
+	UpdatedAt time.Time
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// EventType names a change announced to webhook subscribers
+type EventType string
+
+const (
+	EventQuoteCreated EventType = "quote.created"
+	EventQuoteUpdated EventType = "quote.updated"
+	EventQuoteDeleted EventType = "quote.deleted"
+	// EventAuthorUpdated carries the author row; it is not part of the quote change stream
+	EventAuthorUpdated EventType = "author.updated"
+)
+
+// EventTypes lists every event type a subscription may select
+var EventTypes = []EventType{EventQuoteCreated, EventQuoteUpdated, EventQuoteDeleted, EventAuthorUpdated}
+
+// WebhookEvent is the body POSTed to subscribers
+type WebhookEvent struct {
+	ID         int64           `json:"id"`
+	Type       EventType       `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookSubscription registers a URL for quote events. Secret signs the deliveries;
+// it is only returned when the subscription is created or the secret is rotated.
+type WebhookSubscription struct {
+	ID     int         `json:"id"`
+	URL    string      `json:"url"`
+	Secret string      `json:"secret,omitempty"`
+	Events []EventType `json:"events"`
+	Active bool        `json:"active"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookSubscriptionRequest creates or replaces a subscription. An empty Events list
+// subscribes to every event type; an empty Secret keeps the current one, or generates one
+// for a new subscription.
+type WebhookSubscriptionRequest struct {
+	URL    string      `json:"url"`
+	Secret string      `json:"secret,omitempty"`
+	Events []EventType `json:"events,omitempty"`
+	Active *bool       `json:"active,omitempty"`
+}
+
+// Validate checks that the URL is absolute http(s) and every event type is known
+func (r *WebhookSubscriptionRequest) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	for _, event := range r.Events {
+		if !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// DeliveryStatus is the state of one event's delivery to one subscription
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead gives up after the last attempt failed; only a manual redeliver retries it
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery tracks the attempts to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             int64          `json:"id"`
+	SubscriptionID int            `json:"subscription_id"`
+	EventID        int64          `json:"event_id"`
+	EventType      EventType      `json:"event_type"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+
+	// URL, Secret and Event are loaded for the dispatcher only
+	URL    string        `json:"-"`
+	Secret string        `json:"-"`
+	Event  *WebhookEvent `json:"-"`
+	// LeaseOwner identifies the claim the dispatcher holds the delivery under
+	LeaseOwner string `json:"-"`
+}
+
+// DeliveryAttempt is the outcome of one attempt, recorded by the dispatcher
+type DeliveryAttempt struct {
+	DeliveryID int64
+	// LeaseOwner is the claim the attempt was made under; it is only recorded while the lease holds
+	LeaseOwner    string
+	Status        DeliveryStatus
+	StatusCode    int
+	Error         string
+	NextAttemptAt time.Time
+}
+
+// WebhookPolicy controls how the dispatcher retries deliveries
+type WebhookPolicy struct {
+	// MaxAttempts is the number of attempts before a delivery is dead
+	MaxAttempts int
+	// BackoffBase is the delay after the first failure; it doubles after each further one
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// BatchSize is the number of deliveries claimed and sent concurrently per round
+	BatchSize int
+	// Lease keeps claimed deliveries from other dispatchers; it must outlast a send
+	Lease time.Duration
+}
+
+// DefaultWebhookPolicy is used when no policy is configured
+var DefaultWebhookPolicy = WebhookPolicy{
+	MaxAttempts: 8,
+	BackoffBase: 30 * time.Second,
+	BackoffMax:  6 * time.Hour,
+	BatchSize:   20,
+	Lease:       time.Minute,
+}
+
+// Backoff returns the delay before the attempt that follows failed attempt number attempts
+func (p WebhookPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BackoffBase
+	for i := 1; i < attempts && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, p.BackoffMax)
+}
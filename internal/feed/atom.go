@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// AtomContentType is the media type of Atom documents
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+type atomFeed struct {
+	XMLName   xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Subtitle  string       `xml:"subtitle,omitempty"`
+	Updated   string       `xml:"updated"`
+	Links     []atomLink   `xml:"link"`
+	Generator string       `xml:"generator"`
+	Entries   []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     atomPerson     `xml:"author"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom writes an Atom 1.0 feed with one entry per quote, in the given order
+func Atom(w io.Writer, meta Meta, quotes []*domain.Quote) error {
+	feed := &atomFeed{
+		ID:       meta.FeedID(),
+		Title:    meta.Title,
+		Subtitle: meta.Description,
+		Updated:  Updated(quotes).Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: meta.url(meta.Path)},
+			{Rel: "alternate", Type: "application/json", Href: meta.url("/quotes")},
+		},
+		Generator: "quotes-service",
+	}
+
+	for _, quote := range quotes {
+		entry := &atomEntry{
+			ID:        meta.EntryID(quote.ID),
+			Title:     entryTitle(quote),
+			Updated:   entryUpdated(quote).UTC().Format(time.RFC3339),
+			Published: quote.CreatedAt.UTC().Format(time.RFC3339),
+			Author:    atomPerson{Name: quote.Author},
+			Link:      atomLink{Rel: "alternate", Href: meta.url(fmt.Sprintf("/quotes/%d", quote.ID))},
+			Content:   atomContent{Type: "text", Body: quote.Quote},
+		}
+		for _, tag := range quote.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeXML(w, feed)
+}
@@ -0,0 +1,95 @@
+// Package feed renders quotes as Atom 1.0 (RFC 4287) and RSS 2.0 documents.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// tagDate is the date component of the tag: URIs (RFC 4151) used as entry IDs.
+// It must never change, otherwise every entry ID changes with it.
+const tagDate = "2025"
+
+// titleLength is the maximum number of runes of the quote used in an entry title
+const titleLength = 60
+
+// Meta describes the feed itself
+type Meta struct {
+	Title       string
+	Description string
+	// BaseURL is the public root of the service, e.g. https://quotes.example.com
+	BaseURL string
+	// Path is the feed path relative to BaseURL, e.g. /feeds/quotes.atom
+	Path string
+}
+
+func (m Meta) url(path string) string {
+	return strings.TrimRight(m.BaseURL, "/") + path
+}
+
+func (m Meta) authority() string {
+	u, err := url.Parse(m.BaseURL)
+	if err != nil || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// FeedID returns the stable identifier of the feed
+func (m Meta) FeedID() string {
+	return fmt.Sprintf("tag:%s,%s:%s", m.authority(), tagDate, strings.TrimPrefix(m.Path, "/"))
+}
+
+// EntryID returns the stable identifier of a quote, derived only from its ID
+func (m Meta) EntryID(quoteID int) string {
+	return fmt.Sprintf("tag:%s,%s:quotes/%d", m.authority(), tagDate, quoteID)
+}
+
+// Updated returns the newest change time among quotes, or the Unix epoch when there are none
+func Updated(quotes []*domain.Quote) time.Time {
+	updated := time.Unix(0, 0).UTC()
+	for _, quote := range quotes {
+		if changed := entryUpdated(quote); changed.After(updated) {
+			updated = changed
+		}
+	}
+	return updated.UTC()
+}
+
+// entryUpdated is when a quote last changed, falling back to its creation for quotes
+// read without an update time
+func entryUpdated(quote *domain.Quote) time.Time {
+	if quote.UpdatedAt.After(quote.CreatedAt) {
+		return quote.UpdatedAt
+	}
+	return quote.CreatedAt
+}
+
+func entryTitle(quote *domain.Quote) string {
+	text := strings.Join(strings.Fields(quote.Quote), " ")
+	if utf8.RuneCountInString(text) > titleLength {
+		runes := []rune(text)
+		text = strings.TrimSpace(string(runes[:titleLength])) + "…"
+	}
+	return quote.Author + ": " + text
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,236 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func testMeta(path string) Meta {
+	return Meta{
+		Title:       "Quotes",
+		Description: "Newest quotes",
+		BaseURL:     "https://quotes.example.com/",
+		Path:        path,
+	}
+}
+
+func testQuotes() []*domain.Quote {
+	return []*domain.Quote{
+		{
+			ID:        2,
+			Author:    "Seneca",
+			Quote:     "Luck is what happens when preparation meets opportunity, and <this> needs & escaping.",
+			Tags:      []string{"luck", "stoicism"},
+			CreatedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 3, 5, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			ID:        1,
+			Author:    "Confucius",
+			Quote:     "Life is simple,\nbut we insist on making it complicated.",
+			CreatedAt: time.Date(2024, 3, 1, 8, 30, 0, 0, time.FixedZone("MSK", 3*60*60)),
+		},
+	}
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// parsedAtom mirrors the elements RFC 4287 requires
+type parsedAtom struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Author  struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Content struct {
+			Type string `xml:"type,attr"`
+			Body string `xml:",chardata"`
+		} `xml:"content"`
+	} `xml:"entry"`
+}
+
+func validateAtom(t *testing.T, data []byte, expectedEntries int) {
+	t.Helper()
+	var feed parsedAtom
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("Atom feed is not well-formed: %v", err)
+	}
+	if feed.ID == "" || feed.Title == "" {
+		t.Error("atom:feed must contain atom:id and atom:title")
+	}
+	if _, err := time.Parse(time.RFC3339, feed.Updated); err != nil {
+		t.Errorf("atom:updated must be an RFC 3339 date: %v", err)
+	}
+	hasSelf := false
+	for _, link := range feed.Links {
+		hasSelf = hasSelf || (link.Rel == "self" && link.Href != "")
+	}
+	if !hasSelf {
+		t.Error("atom:feed should contain a rel=self link")
+	}
+	if len(feed.Entries) != expectedEntries {
+		t.Fatalf("expected %d entries, got %d", expectedEntries, len(feed.Entries))
+	}
+	seen := map[string]bool{}
+	for _, entry := range feed.Entries {
+		if entry.ID == "" || entry.Title == "" || entry.Author.Name == "" {
+			t.Errorf("atom:entry must contain id, title and author: %+v", entry)
+		}
+		if seen[entry.ID] {
+			t.Errorf("duplicate entry id %s", entry.ID)
+		}
+		seen[entry.ID] = true
+		if _, err := time.Parse(time.RFC3339, entry.Updated); err != nil {
+			t.Errorf("entry updated must be an RFC 3339 date: %v", err)
+		}
+		if entry.Content.Type != "text" || entry.Content.Body == "" {
+			t.Errorf("entry content must be non-empty text: %+v", entry.Content)
+		}
+	}
+}
+
+// parsedRSS mirrors the elements the RSS 2.0 specification requires
+type parsedRSS struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title string `xml:"title"`
+		// Links also collects atom:link, so the RSS link is the one without a namespace
+		Links []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:"link"`
+		Description   string `xml:"description"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Items         []struct {
+			Title       string `xml:"title"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+			GUID        struct {
+				IsPermaLink string `xml:"isPermaLink,attr"`
+				Value       string `xml:",chardata"`
+			} `xml:"guid"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func validateRSS(t *testing.T, data []byte, expectedItems int) {
+	t.Helper()
+	var doc parsedRSS
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("RSS feed is not well-formed: %v", err)
+	}
+	if doc.Version != "2.0" {
+		t.Errorf("expected rss version 2.0, got %q", doc.Version)
+	}
+	ch := doc.Channel
+	link := ""
+	for _, l := range ch.Links {
+		if l.XMLName.Space == "" {
+			link = l.Value
+		}
+	}
+	if ch.Title == "" || link == "" || ch.Description == "" {
+		t.Error("channel must contain title, link and description")
+	}
+	if _, err := time.Parse(time.RFC1123Z, ch.LastBuildDate); err != nil {
+		t.Errorf("lastBuildDate must be an RFC 822 date: %v", err)
+	}
+	if len(ch.Items) != expectedItems {
+		t.Fatalf("expected %d items, got %d", expectedItems, len(ch.Items))
+	}
+	for _, item := range ch.Items {
+		if item.Title == "" && item.Description == "" {
+			t.Error("item must contain a title or a description")
+		}
+		if item.GUID.Value == "" || item.GUID.IsPermaLink != "false" {
+			t.Errorf("item guid must be a non-permalink identifier: %+v", item.GUID)
+		}
+		if _, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil {
+			t.Errorf("pubDate must be an RFC 822 date: %v", err)
+		}
+	}
+}
+
+func TestAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Atom(&buf, testMeta("/feeds/quotes.atom"), testQuotes()); err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+	validateAtom(t, buf.Bytes(), 2)
+	checkGolden(t, "quotes.atom.golden", buf.Bytes())
+}
+
+func TestAtom_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Atom(&buf, testMeta("/feeds/tags/none/quotes.atom"), nil); err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+	validateAtom(t, buf.Bytes(), 0)
+	checkGolden(t, "empty.atom.golden", buf.Bytes())
+}
+
+func TestRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RSS(&buf, testMeta("/feeds/quotes.rss"), testQuotes()); err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+	validateRSS(t, buf.Bytes(), 2)
+	checkGolden(t, "quotes.rss.golden", buf.Bytes())
+}
+
+func TestEntryID_Stable(t *testing.T) {
+	atom := testMeta("/feeds/quotes.atom")
+	byAuthor := testMeta("/feeds/authors/Seneca/quotes.rss")
+	if atom.EntryID(42) != byAuthor.EntryID(42) {
+		t.Error("entry IDs must not depend on the feed they appear in")
+	}
+	if atom.EntryID(42) != "tag:quotes.example.com,2025:quotes/42" {
+		t.Errorf("unexpected entry ID %s", atom.EntryID(42))
+	}
+	if atom.FeedID() == byAuthor.FeedID() {
+		t.Error("feed variants must have distinct IDs")
+	}
+}
+
+func TestEntryTitle_Truncates(t *testing.T) {
+	quote := &domain.Quote{Author: "A", Quote: "Это очень длинная цитата, которая    не помещается в заголовок записи ленты целиком"}
+	title := entryTitle(quote)
+	if want := "A: Это очень длинная цитата, которая не помещается в заголовок…"; title != want {
+		t.Errorf("entryTitle() = %q, want %q", title, want)
+	}
+}
@@ -0,0 +1,88 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// RSSContentType is the media type of RSS documents
+const RSSContentType = "application/rss+xml; charset=utf-8"
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	Generator     string     `xml:"generator"`
+	SelfLink      rssAtomRef `xml:"atom:link"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssAtomRef struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Creator     string   `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS writes an RSS 2.0 feed with one item per quote, in the given order
+func RSS(w io.Writer, meta Meta, quotes []*domain.Quote) error {
+	description := meta.Description
+	if description == "" {
+		description = meta.Title
+	}
+
+	doc := &rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         meta.Title,
+			Link:          meta.url("/quotes"),
+			Description:   description,
+			LastBuildDate: Updated(quotes).Format(time.RFC1123Z),
+			Generator:     "quotes-service",
+			SelfLink:      rssAtomRef{Href: meta.url(meta.Path), Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, quote := range quotes {
+		doc.Channel.Items = append(doc.Channel.Items, &rssItem{
+			Title:       entryTitle(quote),
+			Link:        meta.url(fmt.Sprintf("/quotes/%d", quote.ID)),
+			Description: quote.Quote,
+			Creator:     quote.Author,
+			Categories:  quote.Tags,
+			GUID:        rssGUID{IsPermaLink: false, Value: meta.EntryID(quote.ID)},
+			PubDate:     quote.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return writeXML(w, doc)
+}
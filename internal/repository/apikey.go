@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// apiKeyColumns is the column list understood by scanAPIKey
+const apiKeyColumns = `id, name, role, created_at`
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	if err := row.Scan(&key.ID, &key.Name, &key.Role, &key.CreatedAt); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns every stored API key, without the keys themselves
+func (r *QuoteRepository) ListAPIKeys() ([]*domain.APIKey, error) {
+	rows, err := r.db.Query(`SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetAPIKeyByDigest returns the stored API key with the given digest
+func (r *QuoteRepository) GetAPIKeyByDigest(digest string) (*domain.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE digest = $1`, digest))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return key, nil
+}
+
+// CreateAPIKey stores a new API key under the digest of its key
+func (r *QuoteRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey, digest string) (*domain.APIKey, error) {
+	query := `INSERT INTO api_keys (name, role, digest) VALUES ($1, $2, $3) RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(r.db.QueryRowContext(ctx, query, key.Name, string(key.Role), digest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteAPIKey removes a stored API key, which stops authenticating at once
+func (r *QuoteRepository) DeleteAPIKey(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM api_keys WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// auditColumns is the column list understood by scanAuditEvent
+const auditColumns = `id, action, actor, quote_id, request_id, client_ip, before, after, occurred_at, position, prev_hash, hash`
+
+// auditChainLock is the advisory lock held by the one transaction extending the hash chain
+const auditChainLock = `hashtext('audit_events_chain')`
+
+// auditFilterClause matches the AuditFilter fields from $1 to $7; zero values match everything
+const auditFilterClause = `($1 = '' OR actor = $1)
+		AND ($2 = '' OR action = $2)
+		AND ($3 = 0 OR quote_id = $3)
+		AND ($4 = '' OR request_id = $4)
+		AND ($5::TIMESTAMPTZ IS NULL OR occurred_at >= $5)
+		AND ($6::TIMESTAMPTZ IS NULL OR occurred_at < $6)
+		AND ($7::BIGINT = 0 OR id < $7)`
+
+func auditFilterArgs(filter domain.AuditFilter) []interface{} {
+	return []interface{}{
+		filter.Actor, string(filter.Action), filter.QuoteID, filter.RequestID,
+		sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		filter.BeforeID,
+	}
+}
+
+func scanAuditEvent(row rowScanner) (*domain.AuditEvent, error) {
+	event := &domain.AuditEvent{}
+	var quoteID, position sql.NullInt64
+	var before, after []byte
+	err := row.Scan(&event.ID, &event.Action, &event.Actor, &quoteID, &event.RequestID, &event.ClientIP,
+		&before, &after, &event.OccurredAt, &position, &event.PrevHash, &event.Hash)
+	if err != nil {
+		return nil, err
+	}
+	event.QuoteID = int(quoteID.Int64)
+	event.Position = position.Int64
+	// The JSONB text is kept byte for byte, as it is part of the hashed fields
+	if before != nil {
+		event.Before = json.RawMessage(before)
+	}
+	if after != nil {
+		event.After = json.RawMessage(after)
+	}
+	return event, nil
+}
+
+// ListAuditEvents returns up to filter.Limit events matching the filter, newest first
+func (r *QuoteRepository) ListAuditEvents(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	query := `SELECT ` + auditColumns + ` FROM audit_events WHERE ` + auditFilterClause + ` ORDER BY id DESC LIMIT $8`
+
+	rows, err := r.db.Query(query, append(auditFilterArgs(filter), filter.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// StreamAuditEvents calls fn for every event matching the filter, oldest first, reading
+// streamFetchSize rows at a time. filter.Limit is ignored. Iteration stops at the first error from fn.
+func (r *QuoteRepository) StreamAuditEvents(filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	query := `SELECT ` + auditColumns + ` FROM audit_events WHERE ` + auditFilterClause + ` AND id > $8 ORDER BY id LIMIT $9`
+
+	var after int64
+	for {
+		rows, err := r.db.Query(query, append(auditFilterArgs(filter), after, streamFetchSize)...)
+		if err != nil {
+			return fmt.Errorf("failed to get audit events: %w", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			event, err := scanAuditEvent(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan audit event: %w", err)
+			}
+			fetched++
+			after = event.ID
+			if err = fn(event); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("error iterating rows: %w", err)
+		}
+		if fetched < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// StreamAuditChain calls fn for every chained event in chain order, reading streamFetchSize
+// rows at a time. Iteration stops at the first error from fn.
+func (r *QuoteRepository) StreamAuditChain(fn func(*domain.AuditEvent) error) error {
+	query := `SELECT ` + auditColumns + ` FROM audit_events WHERE position > $1 ORDER BY position LIMIT $2`
+
+	var after int64
+	for {
+		rows, err := r.db.Query(query, after, streamFetchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get audit events: %w", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			event, err := scanAuditEvent(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan audit event: %w", err)
+			}
+			fetched++
+			after = event.Position
+			if err = fn(event); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("error iterating rows: %w", err)
+		}
+		if fetched < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// CountUnchainedAuditEvents returns the number of committed events not chained yet
+func (r *QuoteRepository) CountUnchainedAuditEvents() (int64, error) {
+	var count int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_events WHERE position IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unchained audit events: %w", err)
+	}
+	return count, nil
+}
+
+// ChainAuditEvents appends up to limit committed events that are not chained yet to the
+// hash chain, in ID order, and returns how many it chained. Events still being written are
+// not visible yet and are chained by a later call, after the events that committed before
+// them. Only one transaction chains at a time; the other callers chain nothing.
+func (r *QuoteRepository) ChainAuditEvents(ctx context.Context, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(`+auditChainLock+`)`).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock the audit chain: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	var position int64
+	var prevHash string
+	err = tx.QueryRowContext(ctx, `SELECT position, hash FROM audit_events WHERE position IS NOT NULL
+		ORDER BY position DESC LIMIT 1`).Scan(&position, &prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to get the end of the audit chain: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT `+auditColumns+` FROM audit_events WHERE position IS NULL ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get unchained audit events: %w", err)
+	}
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, event := range events {
+		position++
+		event.Position, event.PrevHash = position, prevHash
+		event.Hash = event.ComputeHash()
+		_, err = tx.ExecContext(ctx, `UPDATE audit_events SET position = $2, prev_hash = $3, hash = $4 WHERE id = $1`,
+			event.ID, event.Position, event.PrevHash, event.Hash)
+		if err != nil {
+			return 0, fmt.Errorf("failed to chain audit event %d: %w", event.ID, err)
+		}
+		prevHash = event.Hash
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(events), nil
+}
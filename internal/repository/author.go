@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func scanAuthor(row rowScanner) (*domain.Author, error) {
+	author := &domain.Author{}
+	if err := row.Scan(&author.Name, &author.Bio, &author.QuoteCount); err != nil {
+		return nil, err
+	}
+	return author, nil
+}
+
+func (r *QuoteRepository) queryAuthors(query string, args ...interface{}) ([]*domain.Author, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []*domain.Author
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan author: %w", err)
+		}
+		authors = append(authors, author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return authors, nil
+}
+
+// GetAuthors returns the named authors who have live quotes or a bio; other names are skipped
+func (r *QuoteRepository) GetAuthors(names []string) ([]*domain.Author, error) {
+	query := `SELECT n.name, COALESCE(a.bio, ''), COUNT(q.id)
+		FROM unnest($1::text[]) AS n(name)
+		LEFT JOIN authors a ON a.name = n.name
+		LEFT JOIN quotes q ON q.author = n.name AND q.deleted_at IS NULL
+		GROUP BY n.name, a.name, a.bio
+		HAVING COUNT(q.id) > 0 OR a.name IS NOT NULL
+		ORDER BY n.name`
+
+	return r.queryAuthors(query, pq.Array(names))
+}
+
+// ListAuthors returns up to limit authors of live quotes whose names sort after after, by name
+func (r *QuoteRepository) ListAuthors(after string, limit int) ([]*domain.Author, error) {
+	query := `SELECT q.author, COALESCE(a.bio, ''), COUNT(*)
+		FROM quotes q LEFT JOIN authors a ON a.name = q.author
+		WHERE q.deleted_at IS NULL AND q.author > $1
+		GROUP BY q.author, a.bio
+		ORDER BY q.author LIMIT $2`
+
+	return r.queryAuthors(query, after, limit)
+}
+
+// SetAuthorBio stores the bio of an author, replacing any previous one, and logs the change
+// under entry. Triggers keep a revision of the author and announce it in the outbox.
+func (r *QuoteRepository) SetAuthorBio(ctx context.Context, entry domain.AuditEntry, name, bio string) error {
+	tx, err := r.beginWrite(ctx, entry, "")
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before, after []byte
+	err = tx.QueryRowContext(ctx, `SELECT to_jsonb(a) FROM authors a WHERE name = $1 FOR UPDATE`, name).Scan(&before)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to lock author: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO authors AS a (name, bio) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET bio = EXCLUDED.bio, updated_at = CURRENT_TIMESTAMP
+		RETURNING to_jsonb(a)`, name, bio).Scan(&after)
+	if err != nil {
+		return fmt.Errorf("failed to set author bio: %w", err)
+	}
+
+	if err = tx.record(ctx, before, after); err != nil {
+		return err
+	}
+	return tx.commit(ctx)
+}
+
+func (r *QuoteRepository) queryTags(query string, args ...interface{}) ([]*domain.Tag, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*domain.Tag
+	for rows.Next() {
+		tag := &domain.Tag{}
+		if err := rows.Scan(&tag.Name, &tag.QuoteCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetTags returns the named tags that live quotes carry; other names are skipped
+func (r *QuoteRepository) GetTags(names []string) ([]*domain.Tag, error) {
+	query := `SELECT tag, COUNT(*) FROM quotes, unnest(tags) AS tag
+		WHERE deleted_at IS NULL AND tags && $1::text[] AND tag = ANY($1)
+		GROUP BY tag ORDER BY tag`
+
+	return r.queryTags(query, pq.Array(names))
+}
+
+// ListTags returns up to limit tags of live quotes that sort after after, by name
+func (r *QuoteRepository) ListTags(after string, limit int) ([]*domain.Tag, error) {
+	query := `SELECT tag, COUNT(*) FROM quotes, unnest(tags) AS tag
+		WHERE deleted_at IS NULL AND tag > $1
+		GROUP BY tag ORDER BY tag LIMIT $2`
+
+	return r.queryTags(query, after, limit)
+}
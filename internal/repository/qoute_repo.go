@@ -1,130 +1,212 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"github.com/lib/pq"
 	"github.com/shoksin/quotes-service/internal/domain"
-
-	_ "github.com/lib/pq"
 )
 
 type QuoteRepository struct {
 	db *sql.DB
 }
 
+// quoteColumns is the column list understood by scanQuote
+const quoteColumns = `id, author, quote, created_at, updated_at, version, tags, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanQuote(row rowScanner) (*domain.Quote, error) {
+	quote := &domain.Quote{}
+	err := row.Scan(&quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt, &quote.UpdatedAt, &quote.Version, pq.Array(&quote.Tags), &quote.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
 func NewQuoteRepository(db *sql.DB) *QuoteRepository {
 	return &QuoteRepository{db: db}
 }
 
 // Create creates a new quote
-func (r *QuoteRepository) Create(quote *domain.Quote) (*domain.Quote, error) {
-	query := `INSERT INTO quotes (author, quote) VALUES ($1, $2) RETURNING id, created_at`
+func (r *QuoteRepository) Create(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
+	query := `INSERT INTO quotes (author, quote, tags) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at, version`
 
-	row := r.db.QueryRow(query, quote.Author, quote.Quote)
+	tx, err := r.beginWrite(ctx, entry, "")
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	err := row.Scan(&quote.ID, &quote.CreatedAt)
+	row := tx.QueryRowContext(ctx, query, quote.Author, quote.Quote, pq.Array(quote.Tags))
+
+	err = row.Scan(&quote.ID, &quote.CreatedAt, &quote.UpdatedAt, &quote.Version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create quote: %w", err)
 	}
+	tx.touch(quote.ID)
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
 
 	return quote, nil
 }
 
-// GetAll returns all quotes
-func (r *QuoteRepository) GetAll() ([]*domain.Quote, error) {
-	query := `SELECT id, author, quote, created_at FROM quotes ORDER BY created_at DESC`
+// GetRandom returns a random quote
+func (r *QuoteRepository) GetRandom() (*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE deleted_at IS NULL ORDER BY RANDOM() LIMIT 1`
 
-	rows, err := r.db.Query(query)
+	quote, err := scanQuote(r.db.QueryRow(query))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quotes: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNoQuotesFound
+		}
+		return nil, fmt.Errorf("failed to get random quote: %w", err)
+	}
+
+	return quote, nil
+}
+
+// ListIDs returns the IDs of the live quotes, smallest first
+func (r *QuoteRepository) ListIDs() ([]int, error) {
+	rows, err := r.db.Query(`SELECT id FROM quotes WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote IDs: %w", err)
 	}
 	defer rows.Close()
 
-	var quotes []*domain.Quote
+	ids := []int{}
 	for rows.Next() {
-		quote := &domain.Quote{}
-		err = rows.Scan(&quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan quote ID: %w", err)
 		}
-		quotes = append(quotes, quote)
+		ids = append(ids, id)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	return quotes, nil
+	return ids, nil
 }
 
-// GetByAuthor returns quotes by author
-func (r *QuoteRepository) GetByAuthor(author string) ([]*domain.Quote, error) {
-	query := `SELECT id, author, quote, created_at FROM quotes WHERE author = $1 ORDER BY created_at DESC`
+// GetByID returns a quote by ID
+func (r *QuoteRepository) GetByID(id int) (*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = $1 AND deleted_at IS NULL`
 
-	rows, err := r.db.Query(query, author)
+	quote, err := scanQuote(r.db.QueryRow(query, id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quotes by author: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
 	}
-	defer rows.Close()
 
-	var quotes []*domain.Quote
-	for rows.Next() {
-		quote := &domain.Quote{}
-		err = rows.Scan(&quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan quote: %w", err)
-		}
-		quotes = append(quotes, quote)
+	return quote, nil
+}
+
+// Update replaces the author, text and tags of a quote. A non-zero quote.Version makes
+// the update conditional on the stored version; a mismatch returns *domain.VersionConflictError.
+func (r *QuoteRepository) Update(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
+	tx, err := r.beginWrite(ctx, entry, "")
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+	if _, err = tx.lock(ctx, `id = $1`, quote.ID); err != nil {
+		return nil, err
+	}
+	updated, err := updateQuote(ctx, tx.Tx, quote)
+	if err != nil {
+		return nil, err
 	}
 
-	return quotes, nil
-}
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
 
-// GetRandom returns a random quote
-func (r *QuoteRepository) GetRandom() (*domain.Quote, error) {
-	query := `SELECT id, author, quote, created_at FROM quotes ORDER BY RANDOM() LIMIT 1`
+	return updated, nil
+}
 
-	quote := &domain.Quote{}
-	row := r.db.QueryRow(query)
+func updateQuote(ctx context.Context, tx *sql.Tx, quote *domain.Quote) (*domain.Quote, error) {
+	query := `UPDATE quotes SET author = $2, quote = $3, tags = $4
+		WHERE id = $1 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		RETURNING ` + quoteColumns
 
-	err := row.Scan(&quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt)
+	updated, err := scanQuote(tx.QueryRowContext(ctx, query, quote.ID, quote.Author, quote.Quote, pq.Array(quote.Tags), quote.Version))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, domain.ErrNoQuotesFound
+			return nil, conflictOrNotFound(ctx, tx, quote.ID, false)
 		}
-		return nil, fmt.Errorf("failed to get random quote: %w", err)
+		return nil, fmt.Errorf("failed to update quote: %w", err)
 	}
 
-	return quote, nil
+	return updated, nil
 }
 
-// GetByID returns a quote by ID
-func (r *QuoteRepository) GetByID(id int) (*domain.Quote, error) {
-	query := `SELECT id, author, quote, created_at FROM quotes WHERE id = $1`
-
-	quote := &domain.Quote{}
-	row := r.db.QueryRow(query, id)
-
-	err := row.Scan(&quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt)
+// conflictOrNotFound explains why a conditional write matched no rows. Quotes in the trash
+// count only when withDeleted is set.
+func conflictOrNotFound(ctx context.Context, tx *sql.Tx, id int, withDeleted bool) error {
+	current, err := scanQuote(tx.QueryRowContext(ctx, `SELECT `+quoteColumns+` FROM quotes
+		WHERE id = $1 AND ($2 OR deleted_at IS NULL)`, id, withDeleted))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, domain.ErrQuoteNotFound
+			return domain.ErrQuoteNotFound
 		}
-		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
+		return fmt.Errorf("failed to get quote by ID: %w", err)
 	}
+	return &domain.VersionConflictError{Current: current}
+}
 
-	return quote, nil
+// Version returns the current version of the quotes collection
+func (r *QuoteRepository) Version() (*domain.CollectionVersion, error) {
+	query := `SELECT version, updated_at FROM quotes_version`
+
+	version := &domain.CollectionVersion{}
+	err := r.db.QueryRow(query).Scan(&version.Version, &version.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Delete moves a quote to the trash. A non-zero version makes the delete conditional
+// on the stored version; a mismatch returns *domain.VersionConflictError.
+func (r *QuoteRepository) Delete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
+	query := `UPDATE quotes SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)`
+
+	return r.deleteOne(ctx, entry, domain.ChangeDelete, query, id, version)
 }
 
-// Delete deletes a quote by ID
-func (r *QuoteRepository) Delete(id int) error {
-	query := `DELETE FROM quotes WHERE id = $1`
+// HardDelete removes a quote for good, whether it is live or in the trash. A non-zero
+// version makes the delete conditional in the same way as Delete.
+func (r *QuoteRepository) HardDelete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
+	query := `DELETE FROM quotes WHERE id = $1 AND ($2 = 0 OR version = $2)`
+
+	return r.deleteOne(ctx, entry, domain.ChangePurge, query, id, version)
+}
 
-	result, err := r.db.Exec(query, id)
+func (r *QuoteRepository) deleteOne(ctx context.Context, entry domain.AuditEntry, change domain.ChangeType, query string, id, version int) error {
+	tx, err := r.beginWrite(ctx, entry, change)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.lock(ctx, `id = $1`, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete quote: %w", err)
 	}
@@ -135,8 +217,32 @@ func (r *QuoteRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return domain.ErrQuoteNotFound
+		if version == 0 {
+			return domain.ErrQuoteNotFound
+		}
+		// A hard delete also reaches quotes in the trash
+		return conflictOrNotFound(ctx, tx.Tx, id, change == domain.ChangePurge)
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return err
 	}
 
 	return nil
 }
+
+// GetNth returns the quote at position n modulo the number of quotes, ordered by ID
+func (r *QuoteRepository) GetNth(n int64) (*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE deleted_at IS NULL ORDER BY id
+		OFFSET $1 % GREATEST((SELECT COUNT(*) FROM quotes WHERE deleted_at IS NULL), 1) LIMIT 1`
+
+	quote, err := scanQuote(r.db.QueryRow(query, n))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNoQuotesFound
+		}
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return quote, nil
+}
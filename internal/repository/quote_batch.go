@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// insertChunkSize keeps multi-row inserts well below the 65535 bind parameter limit
+const insertChunkSize = 1000
+
+// CreateBatch inserts all quotes in a single transaction using multi-row inserts
+func (r *QuoteRepository) CreateBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	tx, err := r.beginWrite(ctx, entry, "")
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(quotes); start += insertChunkSize {
+		end := min(start+insertChunkSize, len(quotes))
+		if err = insertChunk(ctx, tx.Tx, quotes[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	for _, quote := range quotes {
+		tx.touch(quote.ID)
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// insertChunk inserts quotes from parallel arrays. Postgres does not promise to return
+// inserted rows in input order, so the IDs are drawn up front next to each row's
+// ordinal and the returned rows are matched back by ID.
+func insertChunk(ctx context.Context, tx *sql.Tx, quotes []*domain.Quote) error {
+	authors := make([]string, len(quotes))
+	texts := make([]string, len(quotes))
+	tags := make([]string, len(quotes))
+	for i, quote := range quotes {
+		authors[i], texts[i], tags[i] = quote.Author, quote.Quote, "{}"
+		// Arrays of arrays must be rectangular, so the tags travel as array literals
+		value, err := pq.StringArray(quote.Tags).Value()
+		if err != nil {
+			return fmt.Errorf("failed to encode tags: %w", err)
+		}
+		if value != nil {
+			tags[i] = value.(string)
+		}
+	}
+
+	// input calls nextval, so Postgres evaluates it once even though it is read twice
+	query := `WITH input AS (
+			SELECT nextval(pg_get_serial_sequence('quotes', 'id')) AS id, author, quote, tags::TEXT[] AS tags, ord
+			FROM unnest($1::TEXT[], $2::TEXT[], $3::TEXT[]) WITH ORDINALITY AS i(author, quote, tags, ord)
+		), inserted AS (
+			INSERT INTO quotes (id, author, quote, tags)
+			SELECT id, author, quote, tags FROM input
+			RETURNING id, created_at, updated_at, version
+		)
+		SELECT input.ord, inserted.id, inserted.created_at, inserted.updated_at, inserted.version
+		FROM inserted JOIN input USING (id)`
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(authors), pq.Array(texts), pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to create quotes: %w", err)
+	}
+	defer rows.Close()
+
+	scanned := 0
+	for rows.Next() {
+		var ord int
+		var created domain.Quote
+		if err = rows.Scan(&ord, &created.ID, &created.CreatedAt, &created.UpdatedAt, &created.Version); err != nil {
+			return fmt.Errorf("failed to scan created quote: %w", err)
+		}
+		if ord < 1 || ord > len(quotes) {
+			return fmt.Errorf("failed to create quotes: unexpected ordinal %d", ord)
+		}
+		quote := quotes[ord-1]
+		quote.ID, quote.CreatedAt, quote.UpdatedAt, quote.Version = created.ID, created.CreatedAt, created.UpdatedAt, created.Version
+		scanned++
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+	if scanned != len(quotes) {
+		return fmt.Errorf("failed to create quotes: expected %d rows, got %d", len(quotes), scanned)
+	}
+
+	return nil
+}
+
+// DeleteBatch moves all quotes matching the filter to the trash in one transaction and returns their IDs.
+// When dryRun is set the matching IDs are returned without deleting anything. When more than
+// maxItems quotes match, nothing is deleted and domain.ErrBatchTooLarge is returned; 0 means no limit.
+func (r *QuoteRepository) DeleteBatch(ctx context.Context, entry domain.AuditEntry, filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error) {
+	where, arg := deleteFilterClause(filter)
+
+	tx, err := r.beginWrite(ctx, entry, domain.ChangeDelete)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, err := tx.lock(ctx, `deleted_at IS NULL AND `+where, arg)
+	if err != nil {
+		return nil, err
+	}
+	if maxItems > 0 && len(ids) > maxItems {
+		return nil, fmt.Errorf("%w: %d quotes match, at most %d may be deleted at once", domain.ErrBatchTooLarge, len(ids), maxItems)
+	}
+	// A dry run rolls back, releasing the locks without logging anything
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE quotes SET deleted_at = CURRENT_TIMESTAMP WHERE id = ANY($1)`, idArray(ids)); err != nil {
+		return nil, fmt.Errorf("failed to delete quotes: %w", err)
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func deleteFilterClause(filter domain.DeleteFilter) (string, interface{}) {
+	if len(filter.IDs) > 0 {
+		return `id = ANY($1)`, idArray(filter.IDs)
+	}
+	return `author = $1`, filter.Author
+}
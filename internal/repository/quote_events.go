@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// outboxSequenceLock is the advisory lock held by the one transaction numbering events
+const outboxSequenceLock = `hashtext('outbox_sequence')`
+
+// SequenceEvents gives the committed events that have no position yet the next positions in
+// the change log and returns how many it numbered. The positions of one call only become
+// visible once it commits, and only one transaction numbers events at a time, so a position
+// never becomes visible after a higher one. The other callers number nothing. Numbered
+// events are announced on the quote_events channel.
+func (r *QuoteRepository) SequenceEvents(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(`+outboxSequenceLock+`)`).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock the change log: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE outbox o SET position = p.position
+		FROM (SELECT id, nextval('outbox_position_seq') AS position
+			FROM (SELECT id FROM outbox WHERE position IS NULL ORDER BY id) pending) p
+		WHERE o.id = p.id`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to number events: %w", err)
+	}
+	numbered, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if numbered == 0 {
+		return 0, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, `SELECT pg_notify('quote_events', '')`); err != nil {
+		return 0, fmt.Errorf("failed to announce events: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return numbered, nil
+}
+
+// ListEvents returns up to limit numbered quote events with a position above afterID, oldest
+// first. The ID of a listed event is its position in the change log.
+func (r *QuoteRepository) ListEvents(afterID int64, limit int) ([]*domain.WebhookEvent, error) {
+	query := `SELECT position, event_type, created_at, payload FROM outbox WHERE position > $1 AND event_type LIKE 'quote.%' ORDER BY position LIMIT $2`
+
+	rows, err := r.db.Query(query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.WebhookEvent{}
+	for rows.Next() {
+		event := &domain.WebhookEvent{}
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.Type, &event.OccurredAt, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		event.Data = json.RawMessage(payload)
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// LatestEventID returns the position of the newest numbered quote event, or 0 when there is none
+func (r *QuoteRepository) LatestEventID() (int64, error) {
+	var id int64
+	if err := r.db.QueryRow(`SELECT COALESCE(MAX(position), 0) FROM outbox WHERE event_type LIKE 'quote.%'`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get latest event: %w", err)
+	}
+	return id, nil
+}
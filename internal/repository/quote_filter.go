@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// quoteSortColumns maps the sort keys of a listing to what they order by and the type a
+// cursor key is cast to. They are the only SQL taken from a filter; every value is passed
+// as an argument.
+var quoteSortColumns = map[string]struct{ column, cast string }{
+	"created_at": {"created_at", "timestamptz"},
+	"author":     {"author", "text"},
+	"length":     {"char_length(quote)", "integer"},
+}
+
+// quoteQuery collects the conditions of a listing and numbers their arguments
+type quoteQuery struct {
+	conds []string
+	args  []interface{}
+}
+
+// where adds cond, with each ? standing for the next of args
+func (q *quoteQuery) where(cond string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		cond = strings.Replace(cond, "?", "$"+strconv.Itoa(len(q.args)), 1)
+	}
+	q.conds = append(q.conds, cond)
+}
+
+// buildQuoteList returns the query listing the quotes selected by filter, with its arguments
+func buildQuoteList(filter domain.QuoteFilter) (string, []interface{}, error) {
+	q := &quoteQuery{conds: []string{"deleted_at IS NULL"}}
+	if filter.Author != "" {
+		q.where("author = ?", filter.Author)
+	}
+	if filter.Tag != "" {
+		q.where("tags @> ARRAY[?::text]", filter.Tag)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		q.where("created_at > ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		q.where("created_at < ?", filter.CreatedBefore)
+	}
+	if filter.MinLength > 0 {
+		q.where("char_length(quote) >= ?", filter.MinLength)
+	}
+	if filter.MaxLength > 0 {
+		q.where("char_length(quote) <= ?", filter.MaxLength)
+	}
+
+	order := "id"
+	if filter.Sort != "" {
+		key, ok := quoteSortColumns[filter.Sort.Key()]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidQuoteFilter, filter.Sort.Key())
+		}
+		dir, cmp := "", ">"
+		if filter.Sort.Descending() {
+			dir, cmp = " DESC", "<"
+		}
+		// Ties are broken by ID, so the quote after the cursor is the same on every call,
+		// even once the quote of the cursor is changed or deleted
+		if !filter.After.IsZero() {
+			q.where("("+key.column+", id) "+cmp+" (?::"+key.cast+", ?)", filter.After.Key, filter.After.ID)
+		}
+		order = key.column + dir + ", id" + dir
+	} else if !filter.After.IsZero() {
+		q.where("id > ?", filter.After.ID)
+	}
+
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE ` + strings.Join(q.conds, " AND ") + ` ORDER BY ` + order
+	if filter.Limit > 0 {
+		q.args = append(q.args, filter.Limit)
+		query += ` LIMIT $` + strconv.Itoa(len(q.args))
+	}
+	return query, q.args, nil
+}
+
+// List returns the live quotes selected by filter
+func (r *QuoteRepository) List(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	query, args, err := buildQuoteList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []*domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// revisionColumns is the column list understood by scanRevision
+const revisionColumns = `quote_id, revision, change_type, actor, created_at, snapshot`
+
+func scanRevision(row rowScanner) (*domain.Revision, error) {
+	revision := &domain.Revision{}
+	var snapshot []byte
+	err := row.Scan(&revision.QuoteID, &revision.Revision, &revision.Change, &revision.Actor, &revision.CreatedAt, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode revision snapshot: %w", err)
+	}
+	return revision, nil
+}
+
+// writeTx is a transaction changing quotes. The quotes it touches are logged to audit_events
+// under its entry when it commits.
+type writeTx struct {
+	*sql.Tx
+	entry   domain.AuditEntry
+	touched []int
+	// before holds the locked rows by ID, as the before side of their audit events
+	before map[string]json.RawMessage
+}
+
+// beginWrite starts a transaction for a change made under entry. The quotes_revision trigger
+// attributes the change to entry.Actor and records it as change, or derives the change type
+// from the statement when change is empty.
+func (r *QuoteRepository) beginWrite(ctx context.Context, entry domain.AuditEntry, change domain.ChangeType) (*writeTx, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `SELECT set_config('quotes.actor', $1, true), set_config('quotes.change', $2, true)`,
+		entry.Actor, string(change))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to set change context: %w", err)
+	}
+
+	return &writeTx{Tx: tx, entry: entry, before: make(map[string]json.RawMessage)}, nil
+}
+
+// lock locks the quotes matching where, which may refer to args as $1 onwards, and keeps
+// their rows for the audit log. It returns their IDs, smallest first.
+func (tx *writeTx) lock(ctx context.Context, where string, args ...interface{}) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, to_jsonb(q) FROM quotes q WHERE `+where+` ORDER BY id FOR UPDATE`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock quotes: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		var row []byte
+		if err = rows.Scan(&id, &row); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		tx.before[strconv.Itoa(id)] = row
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	tx.touch(ids...)
+	return ids, nil
+}
+
+// idArray passes quote IDs as a Postgres array
+func idArray(ids []int) interface{} {
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+	return pq.Array(values)
+}
+
+// touch marks quotes as changed by the transaction. Quotes that were not locked first,
+// such as new ones, are logged without a before side.
+func (tx *writeTx) touch(ids ...int) {
+	tx.touched = append(tx.touched, ids...)
+}
+
+// record logs an audit event that concerns no quote, with the rows before and after the change
+func (tx *writeTx) record(ctx context.Context, before, after json.RawMessage) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO audit_events (action, actor, request_id, client_ip, before, after)
+		VALUES ($1, $2, $3, $4, $5::JSONB, $6::JSONB)`,
+		string(tx.entry.Action), tx.entry.Actor, tx.entry.RequestID, tx.entry.ClientIP, nullJSON(before), nullJSON(after))
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// nullJSON passes an empty snapshot as NULL
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return []byte(value)
+}
+
+// commit writes one audit event for each touched quote, with the quote as the transaction
+// leaves it as the after side, and commits
+func (tx *writeTx) commit(ctx context.Context) error {
+	if len(tx.touched) > 0 {
+		before, err := json.Marshal(tx.before)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit snapshots: %w", err)
+		}
+
+		ids := make([]int, 0, len(tx.touched))
+		seen := make(map[int]bool, len(tx.touched))
+		for _, id := range tx.touched {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO audit_events (action, actor, quote_id, request_id, client_ip, before, after)
+			SELECT $1, $2, t.id, $3, $4, $5::JSONB -> t.id::TEXT, to_jsonb(q)
+			FROM unnest($6::INTEGER[]) WITH ORDINALITY AS t(id, ord)
+			LEFT JOIN quotes q ON q.id = t.id
+			ORDER BY t.ord`,
+			string(tx.entry.Action), tx.entry.Actor, tx.entry.RequestID, tx.entry.ClientIP, before, idArray(ids))
+		if err != nil {
+			return fmt.Errorf("failed to record audit events: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns every revision of a quote, newest first. Revisions outlive the
+// quote, so they are listed for deleted quotes too.
+func (r *QuoteRepository) ListRevisions(quoteID int) ([]*domain.Revision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM quote_revisions WHERE quote_id = $1 ORDER BY revision DESC`
+
+	rows, err := r.db.Query(query, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*domain.Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, domain.ErrQuoteNotFound
+	}
+
+	return revisions, nil
+}
+
+// ListRevisionsByQuotes returns every revision of the given quotes ordered by quote ID,
+// newest revision first
+func (r *QuoteRepository) ListRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM quote_revisions WHERE quote_id = ANY($1) ORDER BY quote_id, revision DESC`
+
+	rows, err := r.db.Query(query, idArray(quoteIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*domain.Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns one revision of a quote
+func (r *QuoteRepository) GetRevision(quoteID, revision int) (*domain.Revision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM quote_revisions WHERE quote_id = $1 AND revision = $2`
+
+	rev, err := scanRevision(r.db.QueryRow(query, quoteID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// Restore writes the author, text and tags of a revision back to its quote. A quote that
+// still exists, live or in the trash, is updated in place, conditionally on version when it
+// is non-zero; a purged quote is recreated under its old ID with a version above any it had before.
+// When another restore recreates it first, *domain.VersionConflictError is returned.
+func (r *QuoteRepository) Restore(ctx context.Context, entry domain.AuditEntry, quoteID, revision, version int) (*domain.Quote, error) {
+	tx, err := r.beginWrite(ctx, entry, domain.ChangeRestore)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rev, err := scanRevision(tx.QueryRowContext(ctx,
+		`SELECT `+revisionColumns+` FROM quote_revisions WHERE quote_id = $1 AND revision = $2`, quoteID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	snapshot := rev.Snapshot
+
+	if _, err = tx.lock(ctx, `id = $1`, quoteID); err != nil {
+		return nil, err
+	}
+	tx.touch(quoteID)
+
+	// Restoring a quote that is in the trash also takes it out
+	restored, err := scanQuote(tx.QueryRowContext(ctx, `UPDATE quotes SET author = $2, quote = $3, tags = $4, deleted_at = NULL
+		WHERE id = $1 AND ($5 = 0 OR version = $5)
+		RETURNING `+quoteColumns,
+		quoteID, snapshot.Author, snapshot.Quote, pq.Array(snapshot.Tags), version))
+	if err == sql.ErrNoRows {
+		err = conflictOrNotFound(ctx, tx.Tx, quoteID, true)
+	} else if err != nil {
+		err = fmt.Errorf("failed to restore quote: %w", err)
+	}
+	if err == domain.ErrQuoteNotFound && version == 0 {
+		restored, err = scanQuote(tx.QueryRowContext(ctx, `INSERT INTO quotes (id, author, quote, tags, created_at, version)
+			VALUES ($1, $2, $3, $4, $5,
+				(SELECT COALESCE(MAX((snapshot->>'version')::INTEGER), 0) + 1 FROM quote_revisions WHERE quote_id = $1))
+			RETURNING `+quoteColumns,
+			quoteID, snapshot.Author, snapshot.Quote, pq.Array(snapshot.Tags), snapshot.CreatedAt))
+		if isUniqueViolation(err) {
+			// A concurrent restore recreated the quote first
+			return nil, r.recreatedConflict(quoteID)
+		} else if err != nil {
+			err = fmt.Errorf("failed to recreate quote: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// recreatedConflict reports a quote that another transaction recreated, with its state if
+// it can still be read
+func (r *QuoteRepository) recreatedConflict(id int) error {
+	current, err := scanQuote(r.db.QueryRow(`SELECT `+quoteColumns+` FROM quotes WHERE id = $1 AND deleted_at IS NULL`, id))
+	if err != nil {
+		return &domain.VersionConflictError{}
+	}
+	return &domain.VersionConflictError{Current: current}
+}
+
+// isUniqueViolation reports whether err is a Postgres unique_violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
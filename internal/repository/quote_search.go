@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// likeEscaper makes user input match literally inside a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListPages returns one page of live quotes in ID order for every request, all in a single
+// query. Each request's Limit is used as given.
+func (r *QuoteRepository) ListPages(reqs []domain.PageRequest) ([][]*domain.Quote, error) {
+	authors := make([]string, len(reqs))
+	tags := make([]string, len(reqs))
+	afterIDs := make([]int64, len(reqs))
+	limits := make([]int64, len(reqs))
+	for i, req := range reqs {
+		authors[i], tags[i], afterIDs[i], limits[i] = req.Author, req.Tag, int64(req.AfterID), int64(req.Limit)
+	}
+
+	query := `SELECT p.ord, q.id, q.author, q.quote, q.created_at, q.updated_at, q.version, q.tags, q.deleted_at
+		FROM unnest($1::text[], $2::text[], $3::int[], $4::int[]) WITH ORDINALITY AS p(author, tag, after_id, lim, ord)
+		CROSS JOIN LATERAL (
+			SELECT ` + quoteColumns + ` FROM quotes
+			WHERE deleted_at IS NULL AND id > p.after_id
+				AND (p.author = '' OR author = p.author) AND (p.tag = '' OR tags @> ARRAY[p.tag])
+			ORDER BY id LIMIT p.lim
+		) q
+		ORDER BY p.ord, q.id`
+
+	rows, err := r.db.Query(query, pq.Array(authors), pq.Array(tags), pq.Array(afterIDs), pq.Array(limits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make([][]*domain.Quote, len(reqs))
+	for rows.Next() {
+		var ord int
+		quote := &domain.Quote{}
+		err := rows.Scan(&ord, &quote.ID, &quote.Author, &quote.Quote, &quote.CreatedAt, &quote.UpdatedAt, &quote.Version, pq.Array(&quote.Tags), &quote.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		pages[ord-1] = append(pages[ord-1], quote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return pages, nil
+}
+
+// Search returns up to limit live quotes, newest first, whose text, author or a tag
+// contains text, ignoring case
+func (r *QuoteRepository) Search(text string, limit int) ([]*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes
+		WHERE deleted_at IS NULL AND (quote ILIKE $1 OR author ILIKE $1
+			OR EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE tag ILIKE $1))
+		ORDER BY created_at DESC, id DESC LIMIT $2`
+
+	rows, err := r.db.Query(query, "%"+likeEscaper.Replace(text)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []*domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// GetByIDs returns the live quotes with the given IDs in ID order; missing IDs are skipped
+func (r *QuoteRepository) GetByIDs(ids []int) ([]*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id`
+
+	rows, err := r.db.Query(query, idArray(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes by ID: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []*domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// streamFetchSize is the number of rows read per page of a stream
+const streamFetchSize = 500
+
+// Stream calls fn for every quote, oldest first, reading pages by ID so the collection is
+// never held in memory. No query or transaction stays open while fn runs, so a slow reader
+// holds no connection. Iteration stops at the first error from fn.
+func (r *QuoteRepository) Stream(fn func(*domain.Quote) error) error {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE deleted_at IS NULL AND id > $1 ORDER BY id LIMIT $2`
+
+	after := 0
+	for {
+		rows, err := r.db.Query(query, after, streamFetchSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch quotes: %w", err)
+		}
+
+		page := make([]*domain.Quote, 0, streamFetchSize)
+		for rows.Next() {
+			quote, err := scanQuote(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan quote: %w", err)
+			}
+			page = append(page, quote)
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("error iterating rows: %w", err)
+		}
+
+		for _, quote := range page {
+			after = quote.ID
+			if err = fn(quote); err != nil {
+				return err
+			}
+		}
+		if len(page) < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// ImportBatch inserts the quotes that do not already exist with the same author and text;
+// quotes in the trash do not count. It returns only the inserted quotes; the rest were duplicates.
+func (r *QuoteRepository) ImportBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	if len(quotes) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.beginWrite(ctx, entry, "")
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialise concurrent imports so the NOT EXISTS check cannot race
+	if _, err = tx.ExecContext(ctx, `LOCK TABLE quotes IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("failed to lock quotes: %w", err)
+	}
+
+	var inserted []*domain.Quote
+	for start := 0; start < len(quotes); start += insertChunkSize {
+		end := min(start+insertChunkSize, len(quotes))
+
+		var query strings.Builder
+		query.WriteString(`INSERT INTO quotes (author, quote, tags)
+			SELECT v.author, v.quote, v.tags FROM (VALUES `)
+		args := make([]interface{}, 0, (end-start)*3)
+		for i, quote := range quotes[start:end] {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "($%d::varchar, $%d::text, $%d::text[])", i*3+1, i*3+2, i*3+3)
+			args = append(args, quote.Author, quote.Quote, pq.Array(quote.Tags))
+		}
+		query.WriteString(`) AS v(author, quote, tags)
+			WHERE NOT EXISTS (SELECT 1 FROM quotes q WHERE q.author = v.author AND q.quote = v.quote AND q.deleted_at IS NULL)
+			RETURNING ` + quoteColumns)
+
+		rows, err := tx.QueryContext(ctx, query.String(), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import quotes: %w", err)
+		}
+		for rows.Next() {
+			quote, err := scanQuote(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan quote: %w", err)
+			}
+			inserted = append(inserted, quote)
+			tx.touch(quote.ID)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating rows: %w", err)
+		}
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// ListDeleted returns the quotes in the trash, most recently deleted first
+func (r *QuoteRepository) ListDeleted() ([]*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted quotes: %w", err)
+	}
+	defer rows.Close()
+
+	quotes := []*domain.Quote{}
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// GetByIDWithDeleted returns a quote by ID whether it is live or in the trash
+func (r *QuoteRepository) GetByIDWithDeleted(id int) (*domain.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = $1`
+
+	quote, err := scanQuote(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
+	}
+
+	return quote, nil
+}
+
+// Undelete takes a quote out of the trash
+func (r *QuoteRepository) Undelete(ctx context.Context, entry domain.AuditEntry, id int) (*domain.Quote, error) {
+	query := `UPDATE quotes SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING ` + quoteColumns
+
+	tx, err := r.beginWrite(ctx, entry, domain.ChangeRestore)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.lock(ctx, `id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	quote, err := scanQuote(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotInTrash
+		}
+		return nil, fmt.Errorf("failed to restore quote: %w", err)
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// PurgeDeleted removes the quotes that were moved to the trash before the given time
+// and returns how many were removed
+func (r *QuoteRepository) PurgeDeleted(ctx context.Context, entry domain.AuditEntry, before time.Time) (int64, error) {
+	tx, err := r.beginWrite(ctx, entry, domain.ChangePurge)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	ids, err := tx.lock(ctx, `deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM quotes WHERE id = ANY($1)`, idArray(ids)); err != nil {
+		return 0, fmt.Errorf("failed to purge deleted quotes: %w", err)
+	}
+
+	if err = tx.commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// statsViews are the views of migrations/014 read for the statistics, each with a
+// materialized twin named with an _mv suffix
+var statsViews = []string{"quote_stats_summary", "quote_stats_daily", "quote_stats_authors", "quote_stats_words"}
+
+// statsRefreshLock is the advisory lock key held while the materialized views are refreshed
+const statsRefreshLock = 0x71756f7465 // "quote"
+
+// Stats computes the collection statistics, all from one snapshot. Materialized policies
+// read the views as of their last refresh; stopWords are left out of the common words.
+func (r *QuoteRepository) Stats(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error) {
+	view := func(name string) string {
+		if policy.Materialized {
+			return name + "_mv"
+		}
+		return name
+	}
+
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stats := &domain.CollectionStats{}
+	err = tx.QueryRow(`SELECT as_of, quotes, authors, avg_length, min_length, max_length, p50_length, p90_length, p99_length
+		FROM `+view("quote_stats_summary")).Scan(&stats.AsOf, &stats.TotalQuotes, &stats.TotalAuthors,
+		&stats.Length.Average, &stats.Length.Min, &stats.Length.Max, &stats.Length.P50, &stats.Length.P90, &stats.Length.P99)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote stats: %w", err)
+	}
+	stats.AsOf = stats.AsOf.UTC()
+
+	series := []struct {
+		unit   string
+		length int
+		dest   *[]*domain.StatsBucket
+	}{
+		{"day", policy.Days, &stats.QuotesPerDay},
+		{"week", policy.Weeks, &stats.QuotesPerWeek},
+		{"month", policy.Months, &stats.QuotesPerMonth},
+	}
+	for _, s := range series {
+		if *s.dest, err = statsSeries(tx, view("quote_stats_daily"), s.unit, s.length, stats); err != nil {
+			return nil, err
+		}
+	}
+
+	if stats.TopAuthors, err = topAuthors(tx, view("quote_stats_authors"), policy.TopAuthors); err != nil {
+		return nil, err
+	}
+	if err = commonWords(tx, view("quote_stats_words"), stats.TopAuthors, policy.TopWords, stopWords); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// statsSeries counts the quotes of the last length periods of unit up to stats.AsOf,
+// including the empty ones
+func statsSeries(tx *sql.Tx, daily, unit string, length int, stats *domain.CollectionStats) ([]*domain.StatsBucket, error) {
+	query := `WITH bounds AS (SELECT date_trunc($1, $2::timestamptz AT TIME ZONE 'UTC') AS last, ('1 ' || $1)::interval AS step)
+		SELECT b.start AT TIME ZONE 'UTC', COALESCE(sum(d.quotes), 0)
+		FROM bounds, generate_series(bounds.last - ($3::int - 1) * bounds.step, bounds.last, bounds.step) AS b(start)
+		LEFT JOIN ` + daily + ` d ON date_trunc($1, d.day::timestamp) = b.start
+		GROUP BY b.start ORDER BY b.start`
+
+	rows, err := tx.Query(query, unit, stats.AsOf, length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count quotes per %s: %w", unit, err)
+	}
+	defer rows.Close()
+
+	buckets := []*domain.StatsBucket{}
+	for rows.Next() {
+		bucket := &domain.StatsBucket{}
+		if err := rows.Scan(&bucket.Start, &bucket.Quotes); err != nil {
+			return nil, fmt.Errorf("failed to scan stats bucket: %w", err)
+		}
+		bucket.Start = bucket.Start.UTC()
+		buckets = append(buckets, bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// topAuthors returns the limit authors with the most quotes, ties by name
+func topAuthors(tx *sql.Tx, authors string, limit int) ([]*domain.AuthorStats, error) {
+	rows, err := tx.Query(`SELECT author, quotes FROM `+authors+` ORDER BY quotes DESC, author LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top authors: %w", err)
+	}
+	defer rows.Close()
+
+	top := []*domain.AuthorStats{}
+	for rows.Next() {
+		author := &domain.AuthorStats{CommonWords: []*domain.WordCount{}}
+		if err := rows.Scan(&author.Author, &author.Quotes); err != nil {
+			return nil, fmt.Errorf("failed to scan author stats: %w", err)
+		}
+		top = append(top, author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return top, nil
+}
+
+// commonWords fills in the limit most used words of each author, ties by word
+func commonWords(tx *sql.Tx, words string, authors []*domain.AuthorStats, limit int, stopWords []string) error {
+	if len(authors) == 0 || limit <= 0 {
+		return nil
+	}
+	byName := make(map[string]*domain.AuthorStats, len(authors))
+	names := make([]string, len(authors))
+	for i, author := range authors {
+		byName[author.Author] = author
+		names[i] = author.Author
+	}
+
+	query := `SELECT author, word, uses FROM (
+			SELECT author, word, uses, row_number() OVER (PARTITION BY author ORDER BY uses DESC, word) AS rank
+			FROM ` + words + `
+			WHERE author = ANY($1) AND NOT (word = ANY($2))
+		) ranked
+		WHERE rank <= $3
+		ORDER BY author, rank`
+
+	rows, err := tx.Query(query, pq.Array(names), pq.Array(stopWords), limit)
+	if err != nil {
+		return fmt.Errorf("failed to get common words: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author string
+		word := &domain.WordCount{}
+		if err := rows.Scan(&author, &word.Word, &word.Uses); err != nil {
+			return fmt.Errorf("failed to scan word count: %w", err)
+		}
+		byName[author].CommonWords = append(byName[author].CommonWords, word)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshStats refreshes the materialized statistics. It returns false without refreshing
+// when another replica is already doing so.
+func (r *QuoteRepository) RefreshStats(ctx context.Context) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, statsRefreshLock).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to lock stats refresh: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	for _, view := range statsViews {
+		if _, err := tx.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY `+view+`_mv`); err != nil {
+			return false, fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit stats refresh: %w", err)
+	}
+	return true, nil
+}
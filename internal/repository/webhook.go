@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// webhookColumns is the column list understood by scanWebhook
+const webhookColumns = `id, url, secret, events, active, created_at, updated_at`
+
+// deliveryColumns is the column list understood by scanDelivery, over webhook_deliveries d joined with outbox o
+const deliveryColumns = `d.id, d.subscription_id, d.outbox_id, o.event_type, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func scanWebhook(row rowScanner) (*domain.WebhookSubscription, error) {
+	sub := &domain.WebhookSubscription{}
+	var events []string
+	err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&events), &sub.Active, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	sub.Events = make([]domain.EventType, len(events))
+	for i, event := range events {
+		sub.Events[i] = domain.EventType(event)
+	}
+	return sub, nil
+}
+
+func scanDelivery(row rowScanner, extra ...interface{}) (*domain.WebhookDelivery, error) {
+	d := &domain.WebhookDelivery{}
+	dest := []interface{}{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func eventArray(events []domain.EventType) interface{} {
+	values := make([]string, len(events))
+	for i, event := range events {
+		values[i] = string(event)
+	}
+	return pq.Array(values)
+}
+
+// ListWebhooks returns every webhook subscription
+func (r *QuoteRepository) ListWebhooks() ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.Query(`SELECT ` + webhookColumns + ` FROM webhook_subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return subs, nil
+}
+
+// GetWebhook returns a webhook subscription by ID
+func (r *QuoteRepository) GetWebhook(id int) (*domain.WebhookSubscription, error) {
+	sub, err := scanWebhook(r.db.QueryRow(`SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return sub, nil
+}
+
+// CreateWebhook stores a new webhook subscription
+func (r *QuoteRepository) CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	query := `INSERT INTO webhook_subscriptions (url, secret, events, active) VALUES ($1, $2, $3, $4) RETURNING ` + webhookColumns
+
+	created, err := scanWebhook(r.db.QueryRowContext(ctx, query, sub.URL, sub.Secret, eventArray(sub.Events), sub.Active))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateWebhook replaces the URL, events and active flag of a subscription, and its secret
+// when sub.Secret is not empty
+func (r *QuoteRepository) UpdateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	query := `UPDATE webhook_subscriptions
+		SET url = $2, secret = COALESCE(NULLIF($3, ''), secret), events = $4, active = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + webhookColumns
+
+	updated, err := scanWebhook(r.db.QueryRowContext(ctx, query, sub.ID, sub.URL, sub.Secret, eventArray(sub.Events), sub.Active))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteWebhook removes a subscription together with its deliveries
+func (r *QuoteRepository) DeleteWebhook(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns up to limit deliveries of a subscription, newest first,
+// optionally only those with the given status
+func (r *QuoteRepository) ListDeliveries(subscriptionID int, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries d JOIN outbox o ON o.id = d.outbox_id
+		WHERE d.subscription_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, subscriptionID, string(status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues a delivery of a subscription again with a fresh set of attempts,
+// whether it was delivered, is dead or still pending
+func (r *QuoteRepository) Redeliver(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries d
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, last_status_code = 0, last_error = '', delivered_at = NULL,
+			lease_owner = NULL, leased_until = NULL
+		FROM outbox o
+		WHERE o.id = d.outbox_id AND d.id = $1 AND d.subscription_id = $2
+		RETURNING ` + deliveryColumns
+
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, query, deliveryID, subscriptionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to redeliver: %w", err)
+	}
+	return d, nil
+}
+
+// FanOutEvents creates a delivery for each of up to limit undispatched outbox events and each
+// active subscription to its type, and marks the events dispatched. Events locked by another
+// dispatcher are skipped. It returns the number of events dispatched.
+func (r *QuoteRepository) FanOutEvents(ctx context.Context, limit int) (int64, error) {
+	query := `WITH batch AS (
+			SELECT id, event_type FROM outbox WHERE dispatched_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED
+		), fanned AS (
+			INSERT INTO webhook_deliveries (subscription_id, outbox_id)
+			SELECT s.id, b.id FROM batch b
+			JOIN webhook_subscriptions s ON s.active AND (cardinality(s.events) = 0 OR b.event_type = ANY(s.events))
+			ON CONFLICT DO NOTHING
+		)
+		UPDATE outbox SET dispatched_at = CURRENT_TIMESTAMP WHERE id IN (SELECT id FROM batch)`
+
+	result, err := r.db.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fan out events: %w", err)
+	}
+
+	dispatched, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return dispatched, nil
+}
+
+// ClaimDeliveries leases up to limit due pending deliveries to the caller under a new lease
+// owner, pushing their next attempt to the end of the lease and skipping rows another dispatcher
+// is claiming. Should the caller die or stall before recording an attempt, the delivery becomes
+// due again when the lease runs out, and the late attempt is no longer recorded. Deliveries of
+// inactive subscriptions stay pending, to be sent once their subscription is active again.
+func (r *QuoteRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	var b [16]byte
+	rand.Read(b[:])
+	owner := hex.EncodeToString(b[:])
+
+	query := `WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id AND s.active
+			WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2),
+			leased_until = CURRENT_TIMESTAMP + make_interval(secs => $2), lease_owner = $3
+		FROM due, outbox o, webhook_subscriptions s
+		WHERE d.id = due.id AND o.id = d.outbox_id AND s.id = d.subscription_id AND s.active
+		RETURNING ` + deliveryColumns + `, s.url, s.secret, o.payload, o.created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds(), owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		event := &domain.WebhookEvent{}
+		var url, secret string
+		var payload []byte
+		d, err := scanDelivery(rows, &url, &secret, &payload, &event.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		event.ID, event.Type, event.Data = d.EventID, d.EventType, json.RawMessage(payload)
+		d.URL, d.Secret, d.Event, d.LeaseOwner = url, secret, event, owner
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordDeliveryAttempt stores the outcome of an attempt to deliver and ends its lease. An
+// attempt whose lease ran out, or was taken over by a redeliver, is not recorded and
+// domain.ErrLeaseLost is returned.
+func (r *QuoteRepository) RecordDeliveryAttempt(ctx context.Context, attempt *domain.DeliveryAttempt) error {
+	query := `UPDATE webhook_deliveries
+		SET attempts = attempts + 1, status = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN CURRENT_TIMESTAMP END,
+			lease_owner = NULL, leased_until = NULL
+		WHERE id = $1 AND lease_owner = $6 AND leased_until > CURRENT_TIMESTAMP`
+
+	result, err := r.db.ExecContext(ctx, query, attempt.DeliveryID, string(attempt.Status), attempt.StatusCode,
+		attempt.Error, attempt.NextAttemptAt, attempt.LeaseOwner)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if recorded == 0 {
+		return domain.ErrLeaseLost
+	}
+	return nil
+}
+
+// PruneOutbox removes the events logged before the given time whose deliveries are all
+// finished, together with those deliveries, and returns how many events it removed. Events
+// still waiting to be dispatched, numbered or delivered are kept.
+func (r *QuoteRepository) PruneOutbox(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM webhook_deliveries d USING outbox o
+		WHERE o.id = d.outbox_id AND o.created_at < $1 AND d.status <> 'pending'`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune deliveries: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM outbox o
+		WHERE o.created_at < $1 AND o.dispatched_at IS NOT NULL AND o.position IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.outbox_id = o.id)`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return pruned, nil
+}
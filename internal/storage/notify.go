@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shoksin/quotes-service/configs"
+)
+
+// listenerPingInterval is how often an idle listener checks that its connection is alive
+const listenerPingInterval = 90 * time.Second
+
+// ListenNotifications LISTENs on channel over a dedicated connection until ctx is done.
+// The returned channel receives a value after notifications arrive, and after the connection
+// is re-established, when notifications may have been missed. Bursts are coalesced, so
+// receivers must read whatever changed rather than count wake-ups.
+func ListenNotifications(ctx context.Context, databaseConfig configs.DatabaseConfig, channel string) (<-chan struct{}, error) {
+	listener := pq.NewListener(dataSourceName(databaseConfig), time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Notification listener on %s: %v", channel, err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer listener.Close()
+
+		ticker := time.NewTicker(listenerPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+				// A nil notification reports a reconnect, which wakes the receiver just the same
+				select {
+				case wake <- struct{}{}:
+				default:
+				}
+			case <-ticker.C:
+				go listener.Ping()
+			}
+		}
+	}()
+
+	return wake, nil
+}
@@ -8,9 +8,12 @@ import (
 	"log"
 )
 
+func dataSourceName(databaseConfig configs.DatabaseConfig) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", databaseConfig.Host, databaseConfig.Port, databaseConfig.User, databaseConfig.Password, databaseConfig.Name)
+}
+
 func NewPostgresConnection(databaseConfig configs.DatabaseConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", databaseConfig.Host, databaseConfig.Port, databaseConfig.User, databaseConfig.Password, databaseConfig.Name)
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", dataSourceName(databaseConfig))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
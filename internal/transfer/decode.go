@@ -0,0 +1,266 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DecodeOptions tunes how records are read
+type DecodeOptions struct {
+	// DefaultAuthor is used for fortune entries without an attribution line
+	DefaultAuthor string
+}
+
+// record accepts the shape produced by the JSON exports; id, timestamps and version are ignored on import
+type record struct {
+	ID        int        `json:"id"`
+	Author    string     `json:"author"`
+	Quote     string     `json:"quote"`
+	Tags      []string   `json:"tags"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// Records returns an iterator over the records in r. Errors wrapping domain.ErrMalformedRecord
+// concern a single record and iteration may continue; any other error is fatal.
+func Records(format Format, r io.Reader, opts DecodeOptions) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	switch format {
+	case FormatCSV:
+		return csvRecords(r)
+	case FormatJSONL:
+		return jsonlRecords(r)
+	case FormatJSON:
+		return jsonRecords(r)
+	case FormatFortune:
+		return fortuneRecords(r, opts)
+	}
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		yield(nil, fmt.Errorf("unsupported format %q", format))
+	}
+}
+
+func malformed(n int, cause error) error {
+	return fmt.Errorf("record %d: %w: %v", n, domain.ErrMalformedRecord, cause)
+}
+
+func csvRecords(r io.Reader) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+
+		header, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			yield(nil, fmt.Errorf("failed to read CSV header: %w", err))
+			return
+		}
+
+		authorCol, quoteCol := -1, -1
+		for i, name := range header {
+			switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+			case "author":
+				authorCol = i
+			case "quote":
+				quoteCol = i
+			}
+		}
+		if authorCol < 0 || quoteCol < 0 {
+			yield(nil, errors.New("CSV header must contain author and quote columns"))
+			return
+		}
+
+		for n := 1; ; n++ {
+			row, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			var parseErr *csv.ParseError
+			switch {
+			case errors.As(err, &parseErr):
+				if !yield(nil, malformed(n, err)) {
+					return
+				}
+				continue
+			case err != nil:
+				yield(nil, fmt.Errorf("failed to read CSV: %w", err))
+				return
+			case len(row) <= max(authorCol, quoteCol):
+				if !yield(nil, malformed(n, fmt.Errorf("expected at least %d fields, got %d", max(authorCol, quoteCol)+1, len(row)))) {
+					return
+				}
+				continue
+			}
+
+			if !yield(&domain.CreateQuoteRequest{Author: row[authorCol], Quote: row[quoteCol]}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func jsonlRecords(r io.Reader) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+		n := 0
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			n++
+
+			var rec record
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&rec); err != nil {
+				if !yield(nil, malformed(n, err)) {
+					return
+				}
+				continue
+			}
+			// More reports false before a stray '}' or ']', so look for the end of the line instead
+			if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+				if !yield(nil, malformed(n, errors.New("unexpected data after JSON value"))) {
+					return
+				}
+				continue
+			}
+
+			if !yield(&domain.CreateQuoteRequest{Author: rec.Author, Quote: rec.Quote, Tags: rec.Tags}, nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read JSONL: %w", err))
+		}
+	}
+}
+
+func jsonRecords(r io.Reader) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if delim, ok := tok.(json.Delim); err != nil || !ok || delim != '[' {
+			yield(nil, errors.New("JSON import must be an array of quotes"))
+			return
+		}
+
+		for n := 1; dec.More(); n++ {
+			var rec record
+			if err = dec.Decode(&rec); err != nil {
+				// Syntax errors leave the stream in an unknown state
+				var syntaxErr *json.SyntaxError
+				if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+					yield(nil, fmt.Errorf("malformed JSON at offset %d: %w", dec.InputOffset(), err))
+					return
+				}
+				if !yield(nil, malformed(n, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(&domain.CreateQuoteRequest{Author: rec.Author, Quote: rec.Quote, Tags: rec.Tags}, nil) {
+				return
+			}
+		}
+
+		if _, err = dec.Token(); err != nil {
+			yield(nil, fmt.Errorf("malformed JSON at offset %d: %w", dec.InputOffset(), err))
+		}
+	}
+}
+
+// fortuneRecords reads %-separated entries. The last line of an entry is taken as
+// the attribution when it starts with "--" or an em dash.
+func fortuneRecords(r io.Reader, opts DecodeOptions) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+		var lines []string
+		n := 0
+
+		flush := func() bool {
+			entry := lines
+			lines = nil
+			if strings.TrimSpace(strings.Join(entry, "")) == "" {
+				return true
+			}
+			n++
+
+			author, text := splitAttribution(entry)
+			if author == "" {
+				author = opts.DefaultAuthor
+			}
+			if author == "" {
+				return yield(nil, malformed(n, errors.New("missing attribution line")))
+			}
+			return yield(&domain.CreateQuoteRequest{Author: author, Quote: text}, nil)
+		}
+
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), " \t\r")
+			if line == "%" {
+				if !flush() {
+					return
+				}
+				continue
+			}
+			// Undo the escaping applied by the fortune encoder
+			if strings.TrimSpace(line) == "%" {
+				line = strings.TrimPrefix(line, " ")
+			}
+			lines = append(lines, line)
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read fortune file: %w", err))
+			return
+		}
+		flush()
+	}
+}
+
+func splitAttribution(lines []string) (author, text string) {
+	last := len(lines) - 1
+	for last >= 0 && strings.TrimSpace(lines[last]) == "" {
+		last--
+	}
+	if last < 0 {
+		return "", ""
+	}
+
+	candidate := strings.TrimSpace(lines[last])
+	for _, prefix := range []string{"--", "—", "―"} {
+		if strings.HasPrefix(candidate, prefix) {
+			author = strings.TrimSpace(strings.TrimPrefix(candidate, prefix))
+			lines = lines[:last]
+			break
+		}
+	}
+
+	return author, strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,154 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Encoder writes quotes one at a time so exports never buffer the whole collection
+type Encoder interface {
+	Encode(quote *domain.Quote) error
+	// Close writes any trailing data and flushes the underlying writer
+	Close() error
+}
+
+// NewEncoder returns an encoder for the given format
+func NewEncoder(format Format, w io.Writer) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w), nil
+	case FormatJSONL:
+		return &jsonlEncoder{enc: json.NewEncoder(w)}, nil
+	case FormatJSON:
+		return &jsonEncoder{w: w}, nil
+	case FormatFortune:
+		return &fortuneEncoder{w: w}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+var csvHeader = []string{"id", "author", "quote", "created_at"}
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(quote *domain.Quote) error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+	return e.w.Write([]string{
+		strconv.Itoa(quote.ID),
+		quote.Author,
+		quote.Quote,
+		quote.CreatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(quote *domain.Quote) error {
+	return e.enc.Encode(quote)
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+// jsonEncoder streams a JSON array element by element
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(quote *domain.Quote) error {
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	if _, err = e.w.Write(data); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+func (e *jsonEncoder) Close() error {
+	suffix := "\n]\n"
+	if e.count == 0 {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(e.w, suffix)
+	return err
+}
+
+// fortuneEncoder writes the classic Unix fortune format: entries separated by "%" lines,
+// with the attribution on its own line after the text.
+type fortuneEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *fortuneEncoder) Encode(quote *domain.Quote) error {
+	var b strings.Builder
+	if e.count > 0 {
+		b.WriteString("%\n")
+	}
+	// A line consisting of a single "%" would split the entry on import
+	for _, line := range strings.Split(quote.Quote, "\n") {
+		if strings.TrimSpace(line) == "%" {
+			line = " " + line
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteString("\t\t-- ")
+	b.WriteString(quote.Author)
+	b.WriteByte('\n')
+
+	e.count++
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *fortuneEncoder) Close() error {
+	if e.count == 0 {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "%\n")
+	return err
+}
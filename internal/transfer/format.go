@@ -0,0 +1,73 @@
+package transfer
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Format identifies a quote collection file format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatJSON    Format = "json"
+	FormatFortune Format = "fortune"
+)
+
+// Formats lists every supported format
+var Formats = []Format{FormatCSV, FormatJSONL, FormatJSON, FormatFortune}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(name)))
+	switch f {
+	case FormatCSV, FormatJSONL, FormatJSON, FormatFortune:
+		return f, nil
+	case "ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected one of csv, jsonl, json, fortune", name)
+}
+
+// FormatFromContentType maps a request Content-Type to a format
+func FormatFromContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, true
+	case "application/x-ndjson", "application/jsonl":
+		return FormatJSONL, true
+	case "application/json":
+		return FormatJSON, true
+	case "text/plain":
+		return FormatFortune, true
+	}
+	return "", false
+}
+
+// ContentType returns the media type used when serving the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Extension returns the conventional file extension for the format
+func (f Format) Extension() string {
+	if f == FormatFortune {
+		return "txt"
+	}
+	return string(f)
+}
@@ -0,0 +1,195 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func sampleQuotes() []*domain.Quote {
+	created := time.Date(2023, 12, 7, 10, 30, 0, 0, time.UTC)
+	return []*domain.Quote{
+		{ID: 1, Author: "Confucius", Quote: "Life is simple, but we insist on making it complicated.", CreatedAt: created},
+		{ID: 2, Author: "Lao Tzu, \"Tao Te Ching\"", Quote: "A journey of a thousand miles\nbegins with a single step.", CreatedAt: created},
+		{ID: 3, Author: "Anonymous", Quote: "Lines with a lone\n%\nmust survive the fortune format.", CreatedAt: created},
+	}
+}
+
+func collect(t *testing.T, format Format, input string, opts DecodeOptions) ([]*domain.CreateQuoteRequest, []error) {
+	t.Helper()
+	var reqs []*domain.CreateQuoteRequest
+	var errs []error
+	for req, err := range Records(format, strings.NewReader(input), opts) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, errs
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			enc, err := NewEncoder(format, &buf)
+			if err != nil {
+				t.Fatalf("NewEncoder() error = %v", err)
+			}
+			for _, quote := range sampleQuotes() {
+				if err = enc.Encode(quote); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+			}
+			if err = enc.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			reqs, errs := collect(t, format, buf.String(), DecodeOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected decode errors: %v\n%s", errs, buf.String())
+			}
+
+			want := sampleQuotes()
+			if len(reqs) != len(want) {
+				t.Fatalf("expected %d records, got %d\n%s", len(want), len(reqs), buf.String())
+			}
+			for i, req := range reqs {
+				if req.Author != want[i].Author || req.Quote != want[i].Quote {
+					t.Errorf("record %d: got %q / %q, want %q / %q", i, req.Author, req.Quote, want[i].Author, want[i].Quote)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyExport(t *testing.T) {
+	expected := map[Format]string{
+		FormatCSV:     "id,author,quote,created_at\n",
+		FormatJSONL:   "",
+		FormatJSON:    "[]\n",
+		FormatFortune: "",
+	}
+	for format, want := range expected {
+		var buf bytes.Buffer
+		enc, _ := NewEncoder(format, &buf)
+		if err := enc.Close(); err != nil {
+			t.Fatalf("%s: Close() error = %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", format, want, buf.String())
+		}
+	}
+}
+
+func TestFortuneRecords(t *testing.T) {
+	input := `You will be told a great secret.
+%
+Do not meddle in the affairs of wizards,
+for they are subtle and quick to anger.
+		-- J. R. R. Tolkien
+%
+Сначала они тебя не замечают.
+	— Махатма Ганди
+
+%
+%
+`
+	reqs, errs := collect(t, FormatFortune, input, DecodeOptions{})
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(reqs))
+	}
+	if reqs[0].Author != "J. R. R. Tolkien" || !strings.HasSuffix(reqs[0].Quote, "quick to anger.") {
+		t.Errorf("unexpected first record: %+v", reqs[0])
+	}
+	if reqs[1].Author != "Махатма Ганди" || reqs[1].Quote != "Сначала они тебя не замечают." {
+		t.Errorf("unexpected second record: %+v", reqs[1])
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Fatalf("expected one malformed record for the unattributed entry, got %v", errs)
+	}
+
+	reqs, errs = collect(t, FormatFortune, input, DecodeOptions{DefaultAuthor: "Unknown"})
+	if len(errs) != 0 || len(reqs) != 3 || reqs[0].Author != "Unknown" {
+		t.Errorf("expected default author to be applied, got %v %v", reqs, errs)
+	}
+}
+
+func TestCSVRecords(t *testing.T) {
+	input := "quote,author,tags\n" +
+		"\"Quote, with comma\",Author,x\n" +
+		"only one field\n" +
+		"Second,Author 2,y\n"
+
+	reqs, errs := collect(t, FormatCSV, input, DecodeOptions{})
+	if len(reqs) != 2 || reqs[0].Quote != "Quote, with comma" || reqs[1].Author != "Author 2" {
+		t.Errorf("unexpected records: %+v", reqs)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Errorf("expected one malformed record, got %v", errs)
+	}
+
+	_, errs = collect(t, FormatCSV, "id,text\n1,foo\n", DecodeOptions{})
+	if len(errs) != 1 || errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Errorf("expected a fatal header error, got %v", errs)
+	}
+}
+
+func TestJSONRecords(t *testing.T) {
+	reqs, errs := collect(t, FormatJSON, `[{"author":"A","quote":"Q"},{"autor":"B","quote":"Q"},{"author":"C","quote":"Q"}]`, DecodeOptions{})
+	if len(reqs) != 2 {
+		t.Errorf("expected 2 records, got %d", len(reqs))
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Errorf("expected unknown field to be a malformed record, got %v", errs)
+	}
+
+	_, errs = collect(t, FormatJSON, `{"author":"A"}`, DecodeOptions{})
+	if len(errs) != 1 || errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Errorf("expected a fatal error for non-array input, got %v", errs)
+	}
+
+	_, errs = collect(t, FormatJSON, `[{"author":"A","quote":"Q"}, {"author": }]`, DecodeOptions{})
+	if len(errs) != 1 || errors.Is(errs[0], domain.ErrMalformedRecord) {
+		t.Errorf("expected a fatal syntax error, got %v", errs)
+	}
+}
+
+func TestJSONLRecords(t *testing.T) {
+	input := "{\"author\":\"A\",\"quote\":\"Q\"}\n\nnot json\n{\"id\":5,\"author\":\"B\",\"quote\":\"Q\",\"created_at\":\"2023-12-07T10:30:00Z\"}\n"
+	reqs, errs := collect(t, FormatJSONL, input, DecodeOptions{})
+	if len(reqs) != 2 || reqs[1].Author != "B" {
+		t.Errorf("unexpected records: %+v", reqs)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "record 2") {
+		t.Errorf("expected malformed record 2, got %v", errs)
+	}
+}
+
+func TestJSONLRecords_TrailingData(t *testing.T) {
+	input := "{\"author\":\"A\",\"quote\":\"Q\"}]\n{\"author\":\"B\",\"quote\":\"Q\"}}\n{\"author\":\"C\",\"quote\":\"Q\"} {}\n"
+	reqs, errs := collect(t, FormatJSONL, input, DecodeOptions{})
+	if len(reqs) != 0 {
+		t.Errorf("expected no records, got %+v", reqs)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected three malformed records, got %v", errs)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(" CSV "); err != nil || f != FormatCSV {
+		t.Errorf("ParseFormat(CSV) = %v, %v", f, err)
+	}
+	if f, err := ParseFormat("ndjson"); err != nil || f != FormatJSONL {
+		t.Errorf("ParseFormat(ndjson) = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
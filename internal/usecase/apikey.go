@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// apiKeyBytes is the entropy of a generated API key
+const apiKeyBytes = 32
+
+// KeyStore persists the API keys created through the admin API, by the digest of the key
+type KeyStore interface {
+	ListAPIKeys() ([]*domain.APIKey, error)
+	GetAPIKeyByDigest(digest string) (*domain.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *domain.APIKey, digest string) (*domain.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id int) error
+}
+
+func newAPIKey() string {
+	var b [apiKeyBytes]byte
+	rand.Read(b[:])
+	return "qk_" + base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// GetAPIKeys returns the stored API keys without the keys themselves. The keys of
+// API_KEYS are not listed.
+func (uc *QuoteUseCase) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return uc.quoteRepository.ListAPIKeys()
+}
+
+// CreateAPIKey generates and stores an API key. The response carries the key, which is
+// not returned again.
+func (uc *QuoteUseCase) CreateAPIKey(ctx context.Context, req *domain.APIKeyRequest) (*domain.APIKey, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{Name: strings.TrimSpace(req.Name), Role: req.Role}
+	if key.Role == "" {
+		key.Role = domain.RoleUser
+	}
+	secret := newAPIKey()
+	created, err := uc.quoteRepository.CreateAPIKey(ctx, key, domain.APIKeyDigest(secret))
+	if err != nil {
+		return nil, err
+	}
+	created.Key = secret
+	return created, nil
+}
+
+// RevokeAPIKey deletes a stored API key; requests made with it are rejected from then on
+func (uc *QuoteUseCase) RevokeAPIKey(ctx context.Context, id int) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return uc.quoteRepository.DeleteAPIKey(ctx, id)
+}
+
+// Authenticate returns the caller a stored API key belongs to, or domain.ErrInvalidAPIKey.
+// It runs before any caller is known, so it needs no principal.
+func (uc *QuoteUseCase) Authenticate(key string) (*domain.Principal, error) {
+	stored, err := uc.quoteRepository.GetAPIKeyByDigest(domain.APIKeyDigest(key))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	return &domain.Principal{Name: stored.Name, Role: stored.Role}, nil
+}
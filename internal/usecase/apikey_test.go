@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_CreateAPIKey(t *testing.T) {
+	var digest string
+	mockRepo := &MockQuoteRepository{
+		CreateAPIKeyFunc: func(key *domain.APIKey, d string) (*domain.APIKey, error) {
+			digest = d
+			created := *key
+			created.ID = 1
+			return &created, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	key, err := useCase.CreateAPIKey(adminCtx, &domain.APIKeyRequest{Name: " ci "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(key.Key, "qk_") || digest != domain.APIKeyDigest(key.Key) {
+		t.Errorf("expected a generated key stored by its digest, got %q and %q", key.Key, digest)
+	}
+	if key.Name != "ci" || key.Role != domain.RoleUser {
+		t.Errorf("expected a user key named ci, got %+v", key)
+	}
+
+	again, err := useCase.CreateAPIKey(adminCtx, &domain.APIKeyRequest{Name: "ops", Role: domain.RoleAdmin})
+	if err != nil || again.Role != domain.RoleAdmin || again.Key == key.Key {
+		t.Errorf("expected a new admin key, got %+v (%v)", again, err)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		req           *domain.APIKeyRequest
+		expectedError error
+	}{
+		{name: "anonymous", ctx: context.Background(), req: &domain.APIKeyRequest{Name: "ci"}, expectedError: domain.ErrForbidden},
+		{name: "empty name", ctx: adminCtx, req: &domain.APIKeyRequest{Name: " "}, expectedError: domain.ErrInvalidAPIKeyRequest},
+		{name: "colon in name", ctx: adminCtx, req: &domain.APIKeyRequest{Name: "a:b"}, expectedError: domain.ErrInvalidAPIKeyRequest},
+		{name: "unknown role", ctx: adminCtx, req: &domain.APIKeyRequest{Name: "ci", Role: "root"}, expectedError: domain.ErrInvalidAPIKeyRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := useCase.CreateAPIKey(tt.ctx, tt.req); !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_RevokeAPIKey(t *testing.T) {
+	var deleted int
+	mockRepo := &MockQuoteRepository{
+		DeleteAPIKeyFunc: func(id int) error {
+			if id != 1 {
+				return domain.ErrAPIKeyNotFound
+			}
+			deleted = id
+			return nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	if err := useCase.RevokeAPIKey(context.Background(), 1); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if err := useCase.RevokeAPIKey(adminCtx, 0); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if err := useCase.RevokeAPIKey(adminCtx, 2); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if err := useCase.RevokeAPIKey(adminCtx, 1); err != nil || deleted != 1 {
+		t.Errorf("expected key 1 deleted, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_Authenticate(t *testing.T) {
+	dbErr := errors.New("database error")
+	mockRepo := &MockQuoteRepository{
+		GetAPIKeyByDigestFunc: func(digest string) (*domain.APIKey, error) {
+			switch digest {
+			case domain.APIKeyDigest("qk_ops"):
+				return &domain.APIKey{ID: 1, Name: "ops", Role: domain.RoleAdmin}, nil
+			case domain.APIKeyDigest("qk_broken"):
+				return nil, dbErr
+			}
+			return nil, domain.ErrAPIKeyNotFound
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	principal, err := useCase.Authenticate("qk_ops")
+	if err != nil || principal.Name != "ops" || !principal.IsAdmin() {
+		t.Errorf("expected ops as admin, got %+v (%v)", principal, err)
+	}
+	if _, err := useCase.Authenticate("qk_revoked"); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey, got %v", err)
+	}
+	if _, err := useCase.Authenticate("qk_broken"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the store error, got %v", err)
+	}
+}
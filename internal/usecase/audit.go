@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+const (
+	// DefaultAuditPageSize is used when an audit query sets no limit
+	DefaultAuditPageSize = 50
+	// MaxAuditPageSize caps the limit of an audit query
+	MaxAuditPageSize = 500
+	// auditChainBatchSize is the number of events chained per transaction
+	auditChainBatchSize = 500
+)
+
+// AuditStore reads the audit log and extends its hash chain
+type AuditStore interface {
+	ListAuditEvents(filter domain.AuditFilter) ([]*domain.AuditEvent, error)
+	StreamAuditEvents(filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error
+	StreamAuditChain(fn func(*domain.AuditEvent) error) error
+	CountUnchainedAuditEvents() (int64, error)
+	ChainAuditEvents(ctx context.Context, limit int) (int, error)
+}
+
+// auditEntry names the actor and request behind a change from ctx, to be logged as action
+func auditEntry(ctx context.Context, action domain.AuditAction) domain.AuditEntry {
+	request := domain.RequestInfoFromContext(ctx)
+	return domain.AuditEntry{
+		Action:    action,
+		Actor:     domain.ActorFromContext(ctx),
+		RequestID: request.ID,
+		ClientIP:  request.ClientIP,
+	}
+}
+
+// GetAuditEvents returns a page of audit events matching the filter, newest first.
+// Only admins may read the audit log.
+func (uc *QuoteUseCase) GetAuditEvents(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > MaxAuditPageSize {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidAuditFilter, MaxAuditPageSize)
+	}
+	if err := validateAuditFilter(filter); err != nil {
+		return nil, err
+	}
+
+	// One extra event tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	events, err := uc.quoteRepository.ListAuditEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.AuditPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextCursor = page.Events[limit-1].ID
+	}
+	return page, nil
+}
+
+// ExportAuditEvents streams every audit event matching the filter to fn, oldest first.
+// The filter's limit is ignored. Only admins may read the audit log.
+func (uc *QuoteUseCase) ExportAuditEvents(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := validateAuditFilter(filter); err != nil {
+		return err
+	}
+
+	return uc.quoteRepository.StreamAuditEvents(filter, fn)
+}
+
+// VerifyAuditLog recomputes the hash chain of the audit log and reports the first event that
+// was changed, or that follows a removed event. Events not chained yet are counted as pending.
+func (uc *QuoteUseCase) VerifyAuditLog(ctx context.Context) (*domain.AuditVerification, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &domain.AuditVerification{Valid: true}
+	err := uc.quoteRepository.StreamAuditChain(func(event *domain.AuditEvent) error {
+		result.Checked++
+		if result.Valid && (event.PrevHash != result.LastHash || event.ComputeHash() != event.Hash) {
+			result.Valid = false
+			result.BrokenAt = event.ID
+		}
+		result.LastHash = event.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Pending, err = uc.quoteRepository.CountUnchainedAuditEvents(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ChainAuditLog adds the committed audit events that are not chained yet to the hash chain
+// and returns how many it added
+func (uc *QuoteUseCase) ChainAuditLog(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		chained, err := uc.quoteRepository.ChainAuditEvents(ctx, auditChainBatchSize)
+		total += chained
+		if err != nil || chained < auditChainBatchSize {
+			return total, err
+		}
+	}
+}
+
+// RunAuditChainer calls ChainAuditLog every interval until ctx is done. Replicas may all run
+// it; one chains at a time.
+func (uc *QuoteUseCase) RunAuditChainer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.ChainAuditLog(ctx); err != nil {
+				log.Printf("Failed to chain audit events: %v", err)
+			}
+		}
+	}
+}
+
+func validateAuditFilter(filter domain.AuditFilter) error {
+	switch {
+	case filter.QuoteID < 0:
+		return fmt.Errorf("%w: quote_id must be positive", domain.ErrInvalidAuditFilter)
+	case filter.BeforeID < 0:
+		return fmt.Errorf("%w: cursor must be positive", domain.ErrInvalidAuditFilter)
+	case !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until):
+		return fmt.Errorf("%w: since must be before until", domain.ErrInvalidAuditFilter)
+	}
+	return nil
+}
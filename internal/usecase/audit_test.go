@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+var adminCtx = domain.WithPrincipal(context.Background(), &domain.Principal{Name: "root", Role: domain.RoleAdmin})
+
+// auditChain returns n correctly chained events, oldest first
+func auditChain(n int) []*domain.AuditEvent {
+	events := make([]*domain.AuditEvent, n)
+	prev := ""
+	for i := range events {
+		event := &domain.AuditEvent{
+			ID:         int64(i + 1),
+			Action:     domain.ActionQuoteCreate,
+			Actor:      "alice",
+			QuoteID:    i + 1,
+			After:      []byte(`{"id": 1}`),
+			OccurredAt: time.Date(2024, 3, 1, 9, i, 0, 0, time.UTC),
+			PrevHash:   prev,
+		}
+		event.Hash = event.ComputeHash()
+		prev = event.Hash
+		events[i] = event
+	}
+	return events
+}
+
+func TestQuoteUseCase_GetAuditEvents(t *testing.T) {
+	var gotFilter domain.AuditFilter
+	mockRepo := &MockQuoteRepository{
+		ListAuditEventsFunc: func(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+			gotFilter = filter
+			events := auditChain(5)
+			var page []*domain.AuditEvent
+			for i := len(events) - 1; i >= 0 && len(page) < filter.Limit; i-- {
+				if filter.BeforeID == 0 || events[i].ID < filter.BeforeID {
+					page = append(page, events[i])
+				}
+			}
+			return page, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	page, err := useCase.GetAuditEvents(adminCtx, domain.AuditFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Events) != 2 || page.Events[0].ID != 5 || page.NextCursor != 4 {
+		t.Errorf("expected events 5 and 4 with cursor 4, got %d events and cursor %d", len(page.Events), page.NextCursor)
+	}
+
+	page, err = useCase.GetAuditEvents(adminCtx, domain.AuditFilter{Limit: 2, BeforeID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Events) != 1 || page.NextCursor != 0 {
+		t.Errorf("expected the last page with one event, got %d events and cursor %d", len(page.Events), page.NextCursor)
+	}
+
+	if _, err = useCase.GetAuditEvents(adminCtx, domain.AuditFilter{}); err != nil || gotFilter.Limit != DefaultAuditPageSize+1 {
+		t.Errorf("expected the default page size, got limit %d (%v)", gotFilter.Limit, err)
+	}
+}
+
+func TestQuoteUseCase_GetAuditEvents_Rejects(t *testing.T) {
+	now := time.Now()
+	userCtx := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "bob", Role: domain.RoleUser})
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		filter        domain.AuditFilter
+		expectedError error
+	}{
+		{name: "anonymous", ctx: context.Background(), expectedError: domain.ErrForbidden},
+		{name: "not an admin", ctx: userCtx, expectedError: domain.ErrForbidden},
+		{name: "limit too large", ctx: adminCtx, filter: domain.AuditFilter{Limit: MaxAuditPageSize + 1}, expectedError: domain.ErrInvalidAuditFilter},
+		{name: "negative limit", ctx: adminCtx, filter: domain.AuditFilter{Limit: -1}, expectedError: domain.ErrInvalidAuditFilter},
+		{name: "negative quote ID", ctx: adminCtx, filter: domain.AuditFilter{QuoteID: -1}, expectedError: domain.ErrInvalidAuditFilter},
+		{name: "empty time range", ctx: adminCtx, filter: domain.AuditFilter{Since: now, Until: now}, expectedError: domain.ErrInvalidAuditFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockQuoteRepository{
+				ListAuditEventsFunc: func(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+					t.Error("expected the repository not to be queried")
+					return nil, nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			if _, err := useCase.GetAuditEvents(tt.ctx, tt.filter); !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_VerifyAuditLog(t *testing.T) {
+	tests := []struct {
+		name             string
+		tamper           func(events []*domain.AuditEvent) []*domain.AuditEvent
+		expectedValid    bool
+		expectedBrokenAt int64
+		expectedChecked  int64
+	}{
+		{
+			name:            "intact chain",
+			tamper:          func(events []*domain.AuditEvent) []*domain.AuditEvent { return events },
+			expectedValid:   true,
+			expectedChecked: 4,
+		},
+		{
+			name: "edited event",
+			tamper: func(events []*domain.AuditEvent) []*domain.AuditEvent {
+				events[1].Actor = "mallory"
+				return events
+			},
+			expectedBrokenAt: 2,
+			expectedChecked:  4,
+		},
+		{
+			name: "removed event",
+			tamper: func(events []*domain.AuditEvent) []*domain.AuditEvent {
+				return append(events[:2], events[3:]...)
+			},
+			expectedBrokenAt: 4,
+			expectedChecked:  3,
+		},
+		{
+			name: "rehashed edit",
+			tamper: func(events []*domain.AuditEvent) []*domain.AuditEvent {
+				events[2].After = []byte(`{"id": 2}`)
+				events[2].Hash = events[2].ComputeHash()
+				return events
+			},
+			expectedBrokenAt: 4,
+			expectedChecked:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := tt.tamper(auditChain(4))
+			mockRepo := &MockQuoteRepository{
+				StreamAuditChainFunc: func(fn func(*domain.AuditEvent) error) error {
+					for _, event := range events {
+						if err := fn(event); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+				CountUnchainedFunc: func() (int64, error) { return 2, nil },
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			result, err := useCase.VerifyAuditLog(adminCtx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Valid != tt.expectedValid || result.BrokenAt != tt.expectedBrokenAt || result.Checked != tt.expectedChecked {
+				t.Errorf("expected valid=%v broken_at=%d checked=%d, got %+v",
+					tt.expectedValid, tt.expectedBrokenAt, tt.expectedChecked, result)
+			}
+			if result.LastHash != events[len(events)-1].Hash {
+				t.Errorf("expected the last hash to be reported")
+			}
+			if result.Pending != 2 {
+				t.Errorf("expected 2 pending events, got %d", result.Pending)
+			}
+		})
+	}
+
+	if _, err := NewQuoteUseCase(&MockQuoteRepository{}).VerifyAuditLog(context.Background()); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for anonymous callers, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_ChainAuditLog(t *testing.T) {
+	unchained := auditChainBatchSize + 7
+	var calls int
+	mockRepo := &MockQuoteRepository{
+		ChainAuditEventsFunc: func(limit int) (int, error) {
+			calls++
+			chained := min(limit, unchained)
+			unchained -= chained
+			return chained, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	chained, err := useCase.ChainAuditLog(context.Background())
+	if err != nil || chained != auditChainBatchSize+7 {
+		t.Fatalf("expected %d chained events, got %d, %v", auditChainBatchSize+7, chained, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected chaining to stop after a short batch, got %d calls", calls)
+	}
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// AuthorStore reads the authors and tags of live quotes and keeps author bios
+type AuthorStore interface {
+	GetAuthors(names []string) ([]*domain.Author, error)
+	ListAuthors(after string, limit int) ([]*domain.Author, error)
+	SetAuthorBio(ctx context.Context, entry domain.AuditEntry, name, bio string) error
+	GetTags(names []string) ([]*domain.Tag, error)
+	ListTags(after string, limit int) ([]*domain.Tag, error)
+}
+
+// GetAuthors returns the named authors that have live quotes or a bio, ordered by name
+func (uc *QuoteUseCase) GetAuthors(names []string) ([]*domain.Author, error) {
+	if len(names) == 0 {
+		return []*domain.Author{}, nil
+	}
+	return uc.quoteRepository.GetAuthors(names)
+}
+
+// ListAuthors returns the authors of live quotes ordered by name, starting after the given name
+func (uc *QuoteUseCase) ListAuthors(after string, limit int) (*domain.AuthorPage, error) {
+	if limit <= 0 {
+		limit = domain.DefaultPageSize
+	}
+	limit = min(limit, domain.MaxPageSize)
+
+	// The name comes last, so keys stay apart whatever it contains
+	authors, err := cached(uc, "authors", strconv.Itoa(limit)+":"+after, func() ([]*domain.Author, error) {
+		return uc.quoteRepository.ListAuthors(after, limit+1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.AuthorPage{Authors: authors}
+	if len(authors) > limit {
+		page.Authors, page.HasNext = authors[:limit], true
+	}
+	return page, nil
+}
+
+// SetAuthorBio replaces the bio of an author, who must have quotes or a bio already;
+// an empty bio clears it. Only admins may do this.
+func (uc *QuoteUseCase) SetAuthorBio(ctx context.Context, name, bio string) (*domain.Author, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrInvalidAuthor
+	}
+	if err := domain.ValidateBio(bio); err != nil {
+		return nil, err
+	}
+
+	authors, err := uc.quoteRepository.GetAuthors([]string{name})
+	if err != nil {
+		return nil, err
+	}
+	if len(authors) == 0 {
+		return nil, domain.ErrAuthorNotFound
+	}
+
+	author := authors[0]
+	author.Bio = strings.TrimSpace(bio)
+	if err = uc.quoteRepository.SetAuthorBio(ctx, auditEntry(ctx, domain.ActionAuthorUpdate), name, author.Bio); err != nil {
+		return nil, err
+	}
+	uc.invalidateCache(ctx)
+	return author, nil
+}
+
+// GetTags returns the named tags that live quotes carry, ordered by name
+func (uc *QuoteUseCase) GetTags(names []string) ([]*domain.Tag, error) {
+	if len(names) == 0 {
+		return []*domain.Tag{}, nil
+	}
+	return uc.quoteRepository.GetTags(names)
+}
+
+// ListTags returns the tags of live quotes ordered by name, starting after the given name
+func (uc *QuoteUseCase) ListTags(after string, limit int) (*domain.TagPage, error) {
+	if limit <= 0 {
+		limit = domain.DefaultPageSize
+	}
+	limit = min(limit, domain.MaxPageSize)
+
+	tags, err := uc.quoteRepository.ListTags(after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.TagPage{Tags: tags}
+	if len(tags) > limit {
+		page.Tags, page.HasNext = tags[:limit], true
+	}
+	return page, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_ListAuthors(t *testing.T) {
+	var gotAfter string
+	var gotLimit int
+	mockRepo := &MockQuoteRepository{
+		ListAuthorsFunc: func(after string, limit int) ([]*domain.Author, error) {
+			gotAfter, gotLimit = after, limit
+			return []*domain.Author{{Name: "Confucius"}, {Name: "Seneca"}, {Name: "Zeno"}}[:min(limit, 3)], nil
+		},
+		ListTagsFunc: func(after string, limit int) ([]*domain.Tag, error) {
+			return []*domain.Tag{{Name: "life", QuoteCount: 3}}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	page, err := useCase.ListAuthors("Aristotle", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAfter != "Aristotle" || gotLimit != 3 {
+		t.Errorf("expected ListAuthors(Aristotle, 3), got ListAuthors(%s, %d)", gotAfter, gotLimit)
+	}
+	if len(page.Authors) != 2 || !page.HasNext {
+		t.Errorf("expected two authors and a next page, got %+v", page)
+	}
+
+	if _, err = useCase.ListAuthors("", 0); err != nil || gotLimit != domain.DefaultPageSize+1 {
+		t.Errorf("expected the default page size, got limit %d (%v)", gotLimit, err)
+	}
+
+	tags, err := useCase.ListTags("", 10)
+	if err != nil || len(tags.Tags) != 1 || tags.HasNext {
+		t.Errorf("expected the only tag, got %+v (%v)", tags, err)
+	}
+}
+
+func TestQuoteUseCase_SetAuthorBio(t *testing.T) {
+	var stored string
+	var audited domain.AuditEntry
+	mockRepo := &MockQuoteRepository{
+		GetAuthorsFunc: func(names []string) ([]*domain.Author, error) {
+			if names[0] != "Seneca" {
+				return []*domain.Author{}, nil
+			}
+			return []*domain.Author{{Name: "Seneca", Bio: "old", QuoteCount: 4}}, nil
+		},
+		SetAuthorBioFunc: func(entry domain.AuditEntry, name, bio string) error {
+			stored, audited = bio, entry
+			return nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+	admin := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "root", Role: domain.RoleAdmin})
+
+	author, err := useCase.SetAuthorBio(admin, " Seneca ", " Roman Stoic philosopher. ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != "Roman Stoic philosopher." || author.Bio != stored || author.QuoteCount != 4 {
+		t.Errorf("expected the trimmed bio to be stored and returned, got %q, %+v", stored, author)
+	}
+	if audited.Action != domain.ActionAuthorUpdate || audited.Actor != "root" {
+		t.Errorf("expected the change to be audited as author.update by root, got %+v", audited)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		author        string
+		bio           string
+		expectedError error
+	}{
+		{name: "anonymous", ctx: context.Background(), author: "Seneca", bio: "bio", expectedError: domain.ErrForbidden},
+		{name: "not an admin", ctx: domain.WithPrincipal(context.Background(), &domain.Principal{Name: "reader"}), author: "Seneca", bio: "bio", expectedError: domain.ErrForbidden},
+		{name: "unknown author", author: "Nobody", bio: "bio", expectedError: domain.ErrAuthorNotFound},
+		{name: "blank name", author: " ", bio: "bio", expectedError: domain.ErrInvalidAuthor},
+		{name: "too long", author: "Seneca", bio: strings.Repeat("a", domain.MaxBioLength+1), expectedError: domain.ErrInvalidBio},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = admin
+			}
+			if _, err := useCase.SetAuthorBio(ctx, tt.author, tt.bio); !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
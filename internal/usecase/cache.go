@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores encoded read results shared by every caller. Implementations live in
+// internal/cache; a failing cache is logged and bypassed.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Invalidate drops every entry
+	Invalidate(ctx context.Context) error
+}
+
+// cacheTimeout bounds a cache operation, so a slow cache costs little more than a miss
+const cacheTimeout = 200 * time.Millisecond
+
+var (
+	// cacheHits and cacheMisses count cache lookups by read, such as random or authors
+	cacheHits   = expvar.NewMap("cache_hits")
+	cacheMisses = expvar.NewMap("cache_misses")
+	// cacheErrors counts the cache operations that failed and were bypassed
+	cacheErrors = expvar.NewMap("cache_errors")
+)
+
+// readCache puts a Cache in front of the hot reads. Concurrent misses of one key share a
+// single load, and loads overtaken by an invalidation are not stored.
+type readCache struct {
+	store Cache
+	ttl   time.Duration
+	// generation counts the invalidations of this replica
+	generation atomic.Uint64
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a load shared by the concurrent misses of a key
+type flight struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// WithCache serves hot reads from store for up to ttl, or until quotes or authors change
+func WithCache(store Cache, ttl time.Duration) Option {
+	return func(uc *QuoteUseCase) {
+		uc.cache = &readCache{store: store, ttl: ttl, flights: make(map[string]*flight)}
+	}
+}
+
+// cached returns the value of the read name for key from the cache, or loads and stores it.
+// Without a cache it only loads.
+func cached[T any](uc *QuoteUseCase, name, key string, load func() (T, error)) (T, error) {
+	c := uc.cache
+	if c == nil {
+		return load()
+	}
+	key = name + ":" + key
+
+	var value T
+	if data, ok := c.get(key); ok && json.Unmarshal(data, &value) == nil {
+		cacheHits.Add(name, 1)
+		return value, nil
+	}
+	cacheMisses.Add(name, 1)
+
+	data, err := c.do(key, func() ([]byte, error) {
+		loaded, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return value, err
+	}
+	// Every caller decodes its own copy, so none can change what the others see
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func (c *readCache) get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheTimeout)
+	defer cancel()
+
+	data, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		cacheErrors.Add("get", 1)
+		log.Printf("Failed to read %s from the cache: %v", key, err)
+		return nil, false
+	}
+	return data, ok
+}
+
+// do runs load once for all the concurrent callers asking for key and stores its result
+func (c *readCache) do(key string, load func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		return f.value, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	c.flights[key] = f
+	c.mu.Unlock()
+
+	generation := c.generation.Load()
+	f.value, f.err = load()
+
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	close(f.done)
+
+	// A load that raced with a write may have read the old data
+	if f.err == nil && c.generation.Load() == generation {
+		ctx, cancel := context.WithTimeout(context.Background(), cacheTimeout)
+		defer cancel()
+		if err := c.store.Set(ctx, key, f.value, c.ttl); err != nil {
+			cacheErrors.Add("set", 1)
+			log.Printf("Failed to store %s in the cache: %v", key, err)
+		}
+	}
+	return f.value, f.err
+}
+
+func (c *readCache) invalidate(ctx context.Context) {
+	c.generation.Add(1)
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+	if err := c.store.Invalidate(ctx); err != nil {
+		cacheErrors.Add("invalidate", 1)
+		log.Printf("Failed to invalidate the cache: %v", err)
+	}
+}
+
+// invalidateCache drops the cached reads after a write. Other replicas learn about the
+// write from RunCacheInvalidator.
+func (uc *QuoteUseCase) invalidateCache(ctx context.Context) {
+	if uc.cache != nil {
+		uc.cache.invalidate(context.WithoutCancel(ctx))
+	}
+}
+
+// RunCacheInvalidator drops the cached reads whenever wake fires, until ctx is done. wake
+// is meant to fire after writes to quotes or authors on any replica, and after a lost
+// connection when such notifications may have been missed.
+func (uc *QuoteUseCase) RunCacheInvalidator(ctx context.Context, wake <-chan struct{}) {
+	if uc.cache == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+			uc.cache.invalidate(ctx)
+		}
+	}
+}
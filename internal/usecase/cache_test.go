@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/cache"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// counter reads a counter of an expvar map, 0 when it was never added to
+func counter(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestQuoteUseCase_Cache_HitsAndMisses(t *testing.T) {
+	var loads atomic.Int32
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		GetNthFunc: func(n int64) (*domain.Quote, error) {
+			loads.Add(1)
+			return &domain.Quote{ID: 7, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity"}, nil
+		},
+	}, WithCache(cache.NewMemory(10), time.Minute))
+
+	hits, misses := counter(cacheHits, "daily"), counter(cacheMisses, "daily")
+	day := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, err := uc.GetDailyQuote(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Changing the copy of one caller leaves the cached quote alone
+	first.Author = "changed"
+	second, err := uc.GetDailyQuote(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loads.Load() != 1 {
+		t.Errorf("expected one load, got %d", loads.Load())
+	}
+	if second.ID != 7 || second.Author != "Seneca" {
+		t.Errorf("unexpected cached quote %+v", second)
+	}
+	if got := counter(cacheHits, "daily"); got != hits+1 {
+		t.Errorf("expected 1 hit, got %d", got-hits)
+	}
+	if got := counter(cacheMisses, "daily"); got != misses+1 {
+		t.Errorf("expected 1 miss, got %d", got-misses)
+	}
+
+	// Another day is another key
+	uc.GetDailyQuote(day.AddDate(0, 0, 1))
+	if loads.Load() != 2 {
+		t.Errorf("expected the next day to be loaded, got %d loads", loads.Load())
+	}
+}
+
+func TestQuoteUseCase_Cache_Singleflight(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			loads.Add(1)
+			<-release
+			return []*domain.Quote{{ID: 1, Author: filter.Author, Quote: "Know thyself"}}, nil
+		},
+	}, WithCache(cache.NewMemory(10), time.Minute))
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan []*domain.Quote, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quotes, err := uc.GetQuotesByAuthor("Socrates")
+			if err != nil {
+				t.Error(err)
+			}
+			results <- quotes
+		}()
+	}
+	// Let the callers pile up behind the first load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if loads.Load() != 1 {
+		t.Errorf("expected concurrent misses to share one load, got %d", loads.Load())
+	}
+	for quotes := range results {
+		if len(quotes) != 1 || quotes[0].Author != "Socrates" {
+			t.Errorf("unexpected result %+v", quotes)
+		}
+	}
+}
+
+func TestQuoteUseCase_Cache_InvalidatedByWrites(t *testing.T) {
+	ctx := context.Background()
+	var loads atomic.Int32
+	repo := &MockQuoteRepository{
+		ListIDsFunc: func() ([]int, error) {
+			loads.Add(1)
+			return []int{1}, nil
+		},
+		GetByIDFunc: func(id int) (*domain.Quote, error) {
+			return &domain.Quote{ID: id, Author: "Seneca", Quote: "We suffer more in imagination than in reality"}, nil
+		},
+		CreateFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+			quote.ID = 2
+			return quote, nil
+		},
+		UpdateFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+			return quote, nil
+		},
+		DeleteFunc: func(id, version int) error {
+			return nil
+		},
+		UndeleteFunc: func(id int) (*domain.Quote, error) {
+			return &domain.Quote{ID: id}, nil
+		},
+		DeleteBatchFunc: func(filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error) {
+			return []int{1}, nil
+		},
+		GetAuthorsFunc: func(names []string) ([]*domain.Author, error) {
+			return []*domain.Author{{Name: names[0], QuoteCount: 1}}, nil
+		},
+		SetAuthorBioFunc: func(entry domain.AuditEntry, name, bio string) error {
+			return nil
+		},
+	}
+	uc := NewQuoteUseCase(repo, WithCache(cache.NewMemory(10), time.Minute))
+	req := &domain.CreateQuoteRequest{Author: "Seneca", Quote: "Begin at once to live"}
+
+	admin := domain.WithPrincipal(ctx, &domain.Principal{Name: "root", Role: domain.RoleAdmin})
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"create", func() error { _, err := uc.CreateQuote(ctx, req); return err }},
+		{"update", func() error {
+			_, err := uc.UpdateQuote(ctx, 1, &domain.UpdateQuoteRequest{CreateQuoteRequest: *req})
+			return err
+		}},
+		{"delete", func() error { return uc.DeleteQuote(ctx, 1, 0) }},
+		{"restore", func() error { _, err := uc.RestoreQuote(admin, 1); return err }},
+		{"batch delete", func() error {
+			_, err := uc.DeleteQuotes(ctx, &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1}}})
+			return err
+		}},
+		{"author bio", func() error { _, err := uc.SetAuthorBio(admin, "Seneca", "Stoic"); return err }},
+	}
+	for _, tt := range writes {
+		t.Run(tt.name, func(t *testing.T) {
+			uc.GetRandomQuote()
+			before := loads.Load()
+			uc.GetRandomQuote()
+			if loads.Load() != before {
+				t.Fatal("expected the quote IDs to be cached")
+			}
+			if err := tt.write(); err != nil {
+				t.Fatal(err)
+			}
+			uc.GetRandomQuote()
+			if loads.Load() != before+1 {
+				t.Errorf("expected the write to invalidate the cache, got %d loads", loads.Load()-before)
+			}
+		})
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		uc.GetRandomQuote()
+		before := loads.Load()
+		uc.DeleteQuotes(ctx, &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1}}, DryRun: true})
+		uc.GetRandomQuote()
+		if loads.Load() != before {
+			t.Errorf("expected a dry run to keep the cache, got %d loads", loads.Load()-before)
+		}
+	})
+
+	t.Run("failed write", func(t *testing.T) {
+		repo.DeleteFunc = func(id, version int) error { return domain.ErrQuoteNotFound }
+		uc.GetRandomQuote()
+		before := loads.Load()
+		uc.DeleteQuote(ctx, 1, 0)
+		uc.GetRandomQuote()
+		if loads.Load() != before {
+			t.Errorf("expected a failed write to keep the cache, got %d loads", loads.Load()-before)
+		}
+	})
+}
+
+func TestQuoteUseCase_Cache_LoadOvertakenByWrite(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan struct{}), make(chan struct{})
+	var loads atomic.Int32
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		ListIDsFunc: func() ([]int, error) {
+			if loads.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			return []int{1}, nil
+		},
+		GetByIDFunc: func(id int) (*domain.Quote, error) {
+			return &domain.Quote{ID: id}, nil
+		},
+		DeleteFunc: func(id, version int) error { return nil },
+	}, WithCache(cache.NewMemory(10), time.Minute))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uc.GetRandomQuote()
+	}()
+	<-started
+	uc.DeleteQuote(ctx, 1, 0)
+	close(release)
+	<-done
+
+	uc.GetRandomQuote()
+	if loads.Load() != 2 {
+		t.Errorf("expected the load that raced with the delete not to be cached, got %d loads", loads.Load())
+	}
+}
+
+func TestQuoteUseCase_RunCacheInvalidator(t *testing.T) {
+	var loads atomic.Int32
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		ListAuthorsFunc: func(after string, limit int) ([]*domain.Author, error) {
+			loads.Add(1)
+			return []*domain.Author{{Name: "Seneca", QuoteCount: 3}}, nil
+		},
+	}, WithCache(cache.NewMemory(10), time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wake := make(chan struct{})
+	go uc.RunCacheInvalidator(ctx, wake)
+
+	uc.ListAuthors("", 10)
+	uc.ListAuthors("", 10)
+	if loads.Load() != 1 {
+		t.Fatalf("expected the authors to be cached, got %d loads", loads.Load())
+	}
+
+	// A notification about a write on another replica; the send returns once the
+	// invalidator has taken it, and the second one once the first has been handled
+	wake <- struct{}{}
+	wake <- struct{}{}
+	uc.ListAuthors("", 10)
+	if loads.Load() != 2 {
+		t.Errorf("expected the notification to invalidate the cache, got %d loads", loads.Load())
+	}
+}
+
+// failingCache is a cache whose every operation fails
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("cache unavailable")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("cache unavailable")
+}
+
+func (failingCache) Invalidate(ctx context.Context) error {
+	return errors.New("cache unavailable")
+}
+
+func TestQuoteUseCase_Cache_Unavailable(t *testing.T) {
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			return []*domain.Quote{{ID: 1, Author: filter.Author}}, nil
+		},
+		CreateFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+			return quote, nil
+		},
+	}, WithCache(failingCache{}, time.Minute))
+
+	errs := counter(cacheErrors, "get")
+	quotes, err := uc.GetQuotesByAuthor("Seneca")
+	if err != nil || len(quotes) != 1 {
+		t.Fatalf("expected the read to bypass the cache, got %v %v", quotes, err)
+	}
+	if _, err := uc.CreateQuote(context.Background(), &domain.CreateQuoteRequest{Author: "Seneca", Quote: "Begin at once to live"}); err != nil {
+		t.Errorf("expected the write to succeed without the cache, got %v", err)
+	}
+	if got := counter(cacheErrors, "get"); got != errs+1 {
+		t.Errorf("expected the failed read to be counted, got %d", got-errs)
+	}
+}
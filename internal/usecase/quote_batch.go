@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// BatchStore writes and deletes quotes in bulk, each batch in one transaction
+type BatchStore interface {
+	CreateBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error)
+	DeleteBatch(ctx context.Context, entry domain.AuditEntry, filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error)
+}
+
+// CreateQuotes validates and inserts a batch of quotes in one transaction.
+// In atomic mode any invalid item rejects the whole batch with domain.ErrBatchRejected;
+// otherwise valid items are inserted and invalid ones are reported per item.
+func (uc *QuoteUseCase) CreateQuotes(ctx context.Context, req *domain.BatchCreateRequest) (*domain.BatchCreateResult, error) {
+	if len(req.Quotes) == 0 {
+		return nil, domain.ErrEmptyBatch
+	}
+	if uc.batchLimits.MaxCreateItems > 0 && len(req.Quotes) > uc.batchLimits.MaxCreateItems {
+		return nil, domain.ErrBatchTooLarge
+	}
+
+	result := &domain.BatchCreateResult{
+		Atomic: req.Atomic,
+		Items:  make([]*domain.BatchItemResult, len(req.Quotes)),
+	}
+
+	now := time.Now()
+	valid := make([]*domain.Quote, 0, len(req.Quotes))
+	validIdx := make([]int, 0, len(req.Quotes))
+
+	for i, item := range req.Quotes {
+		result.Items[i] = &domain.BatchItemResult{Index: i}
+		if item == nil {
+			item = &domain.CreateQuoteRequest{}
+		}
+
+		if err := uc.validationRules.Validate(item); err != nil {
+			result.Failed++
+			result.Items[i].Error = err.Error()
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				result.Items[i].Error = domain.MsgValidationFailed
+				result.Items[i].Details = verr.Errors
+			}
+			continue
+		}
+
+		item.Normalize()
+		valid = append(valid, &domain.Quote{Author: item.Author, Quote: item.Quote, Tags: item.Tags, CreatedAt: now, UpdatedAt: now})
+		validIdx = append(validIdx, i)
+	}
+
+	if result.Failed > 0 && req.Atomic {
+		return result, domain.ErrBatchRejected
+	}
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	created, err := uc.quoteRepository.CreateBatch(ctx, auditEntry(ctx, domain.ActionBatchCreate), valid)
+	if err != nil {
+		return nil, err
+	}
+	uc.invalidateCache(ctx)
+
+	for j, quote := range created {
+		result.Items[validIdx[j]].Quote = quote
+	}
+	result.Created = len(created)
+
+	return result, nil
+}
+
+// DeleteQuotes moves every quote matching the filter to the trash, or only reports them on a dry run.
+// Author filters are held to the same MaxDeleteItems as ID lists, checked against the quotes they match.
+func (uc *QuoteUseCase) DeleteQuotes(ctx context.Context, req *domain.BatchDeleteRequest) (*domain.BatchDeleteResult, error) {
+	filter := req.Filter
+	filter.Author = strings.TrimSpace(filter.Author)
+
+	if (len(filter.IDs) == 0) == (filter.Author == "") {
+		return nil, domain.ErrInvalidDeleteFilter
+	}
+	if uc.batchLimits.MaxDeleteItems > 0 && len(filter.IDs) > uc.batchLimits.MaxDeleteItems {
+		return nil, domain.ErrBatchTooLarge
+	}
+	for _, id := range filter.IDs {
+		if id <= 0 {
+			return nil, domain.ErrInvalidID
+		}
+	}
+
+	ids, err := uc.quoteRepository.DeleteBatch(ctx, auditEntry(ctx, domain.ActionBatchDelete), filter, req.DryRun, uc.batchLimits.MaxDeleteItems)
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun && len(ids) > 0 {
+		uc.invalidateCache(ctx)
+	}
+
+	return &domain.BatchDeleteResult{
+		DryRun:  req.DryRun,
+		Deleted: len(ids),
+		IDs:     ids,
+	}, nil
+}
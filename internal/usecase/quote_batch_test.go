@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_CreateQuotes(t *testing.T) {
+	validItem := func() *domain.CreateQuoteRequest {
+		return &domain.CreateQuoteRequest{Author: " Author ", Quote: " Quote "}
+	}
+
+	tests := []struct {
+		name            string
+		request         *domain.BatchCreateRequest
+		limits          domain.BatchLimits
+		expectedError   error
+		expectRepoCall  bool
+		expectedCreated int
+		expectedFailed  int
+	}{
+		{
+			name:          "empty batch",
+			request:       &domain.BatchCreateRequest{Atomic: true},
+			limits:        domain.DefaultBatchLimits,
+			expectedError: domain.ErrEmptyBatch,
+		},
+		{
+			name: "batch too large",
+			request: &domain.BatchCreateRequest{
+				Quotes: []*domain.CreateQuoteRequest{validItem(), validItem(), validItem()},
+			},
+			limits:        domain.BatchLimits{MaxCreateItems: 2},
+			expectedError: domain.ErrBatchTooLarge,
+		},
+		{
+			name: "atomic batch all valid",
+			request: &domain.BatchCreateRequest{
+				Quotes: []*domain.CreateQuoteRequest{validItem(), validItem()},
+				Atomic: true,
+			},
+			limits:          domain.DefaultBatchLimits,
+			expectRepoCall:  true,
+			expectedCreated: 2,
+		},
+		{
+			name: "atomic batch with invalid item",
+			request: &domain.BatchCreateRequest{
+				Quotes: []*domain.CreateQuoteRequest{validItem(), {Author: "", Quote: "Quote"}},
+				Atomic: true,
+			},
+			limits:         domain.DefaultBatchLimits,
+			expectedError:  domain.ErrBatchRejected,
+			expectedFailed: 1,
+		},
+		{
+			name: "non-atomic batch with invalid item",
+			request: &domain.BatchCreateRequest{
+				Quotes: []*domain.CreateQuoteRequest{validItem(), nil, {Author: "Author", Quote: ""}},
+				Atomic: false,
+			},
+			limits:          domain.DefaultBatchLimits,
+			expectRepoCall:  true,
+			expectedCreated: 1,
+			expectedFailed:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoCalled := false
+			mockRepo := &MockQuoteRepository{
+				CreateBatchFunc: func(quotes []*domain.Quote) ([]*domain.Quote, error) {
+					repoCalled = true
+					for i, quote := range quotes {
+						if quote.Author != "Author" || quote.Quote != "Quote" {
+							t.Errorf("expected normalized quote, got %+v", quote)
+						}
+						quote.ID = i + 1
+					}
+					return quotes, nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo, WithBatchLimits(tt.limits))
+
+			result, err := useCase.CreateQuotes(context.Background(), tt.request)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if repoCalled != tt.expectRepoCall {
+				t.Errorf("expected repository call %v, got %v", tt.expectRepoCall, repoCalled)
+			}
+			if result == nil {
+				return
+			}
+			if result.Created != tt.expectedCreated {
+				t.Errorf("expected %d created, got %d", tt.expectedCreated, result.Created)
+			}
+			if result.Failed != tt.expectedFailed {
+				t.Errorf("expected %d failed, got %d", tt.expectedFailed, result.Failed)
+			}
+			if len(result.Items) != len(tt.request.Quotes) {
+				t.Errorf("expected %d item results, got %d", len(tt.request.Quotes), len(result.Items))
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_CreateQuotes_RepositoryError(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		CreateBatchFunc: func(quotes []*domain.Quote) ([]*domain.Quote, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	_, err := useCase.CreateQuotes(context.Background(), &domain.BatchCreateRequest{
+		Quotes: []*domain.CreateQuoteRequest{{Author: "Author", Quote: "Quote"}},
+		Atomic: true,
+	})
+	if err == nil || err.Error() != "database error" {
+		t.Errorf("expected database error, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_DeleteQuotes(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *domain.BatchDeleteRequest
+		limits        domain.BatchLimits
+		expectedError error
+		expectedIDs   int
+	}{
+		{
+			name:        "delete by IDs",
+			request:     &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1, 2}}},
+			limits:      domain.DefaultBatchLimits,
+			expectedIDs: 2,
+		},
+		{
+			name:        "dry run by author",
+			request:     &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{Author: " Author "}, DryRun: true},
+			limits:      domain.DefaultBatchLimits,
+			expectedIDs: 2,
+		},
+		{
+			name:          "empty filter",
+			request:       &domain.BatchDeleteRequest{},
+			limits:        domain.DefaultBatchLimits,
+			expectedError: domain.ErrInvalidDeleteFilter,
+		},
+		{
+			name:          "both IDs and author",
+			request:       &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1}, Author: "Author"}},
+			limits:        domain.DefaultBatchLimits,
+			expectedError: domain.ErrInvalidDeleteFilter,
+		},
+		{
+			name:          "invalid ID",
+			request:       &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1, 0}}},
+			limits:        domain.DefaultBatchLimits,
+			expectedError: domain.ErrInvalidID,
+		},
+		{
+			name:          "too many IDs",
+			request:       &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{IDs: []int{1, 2, 3}}},
+			limits:        domain.BatchLimits{MaxDeleteItems: 2},
+			expectedError: domain.ErrBatchTooLarge,
+		},
+		{
+			name:          "author matching too many quotes",
+			request:       &domain.BatchDeleteRequest{Filter: domain.DeleteFilter{Author: "Author"}},
+			limits:        domain.BatchLimits{MaxDeleteItems: 1},
+			expectedError: domain.ErrBatchTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockQuoteRepository{
+				DeleteBatchFunc: func(filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error) {
+					if dryRun != tt.request.DryRun {
+						t.Errorf("expected dryRun %v, got %v", tt.request.DryRun, dryRun)
+					}
+					if filter.Author != "" && filter.Author != "Author" {
+						t.Errorf("expected trimmed author, got %q", filter.Author)
+					}
+					if maxItems != tt.limits.MaxDeleteItems {
+						t.Errorf("expected the delete limit %d, got %d", tt.limits.MaxDeleteItems, maxItems)
+					}
+					if maxItems > 0 && maxItems < 2 {
+						return nil, domain.ErrBatchTooLarge
+					}
+					return []int{1, 2}, nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo, WithBatchLimits(tt.limits))
+
+			result, err := useCase.DeleteQuotes(context.Background(), tt.request)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if result.Deleted != tt.expectedIDs || len(result.IDs) != tt.expectedIDs {
+				t.Errorf("expected %d deleted, got %+v", tt.expectedIDs, result)
+			}
+			if result.DryRun != tt.request.DryRun {
+				t.Errorf("expected DryRun %v, got %v", tt.request.DryRun, result.DryRun)
+			}
+		})
+	}
+}
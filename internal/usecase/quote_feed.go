@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// DefaultRecentLimit is used when GetRecentQuotes is called with a non-positive limit
+const DefaultRecentLimit = 20
+
+// GetRecentQuotes returns the newest quotes, optionally limited to the author and/or tag of filter.
+// Its other fields are ignored.
+func (uc *QuoteUseCase) GetRecentQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	filter = normalizeQuoteFilter(domain.QuoteFilter{Author: filter.Author, Tag: filter.Tag, Limit: filter.Limit})
+	filter.Sort = "-created_at"
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultRecentLimit
+	}
+	return uc.quoteRepository.List(filter)
+}
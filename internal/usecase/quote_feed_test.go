@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_GetRecentQuotes(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        domain.QuoteFilter
+		expectedQuery domain.QuoteFilter
+	}{
+		{
+			name:          "all quotes with explicit limit",
+			filter:        domain.QuoteFilter{Limit: 5},
+			expectedQuery: domain.QuoteFilter{Sort: "-created_at", Limit: 5},
+		},
+		{
+			name:          "default limit",
+			expectedQuery: domain.QuoteFilter{Sort: "-created_at", Limit: DefaultRecentLimit},
+		},
+		{
+			name:          "normalized author and tag",
+			filter:        domain.QuoteFilter{Author: " Seneca ", Tag: " Stoicism", Limit: 10},
+			expectedQuery: domain.QuoteFilter{Author: "Seneca", Tag: "stoicism", Sort: "-created_at", Limit: 10},
+		},
+		{
+			name:          "other fields ignored",
+			filter:        domain.QuoteFilter{Sort: "author", MinLength: 3, After: domain.QuoteCursor{ID: 7}},
+			expectedQuery: domain.QuoteFilter{Sort: "-created_at", Limit: DefaultRecentLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter domain.QuoteFilter
+			mockRepo := &MockQuoteRepository{
+				ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					gotFilter = filter
+					return []*domain.Quote{}, nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			if _, err := useCase.GetRecentQuotes(tt.filter); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotFilter != tt.expectedQuery {
+				t.Errorf("expected filter %+v, got %+v", tt.expectedQuery, gotFilter)
+			}
+		})
+	}
+}
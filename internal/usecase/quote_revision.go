@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// RevisionStore reads the history of quotes and restores earlier revisions
+type RevisionStore interface {
+	ListRevisions(quoteID int) ([]*domain.Revision, error)
+	GetRevision(quoteID, revision int) (*domain.Revision, error)
+	Restore(ctx context.Context, entry domain.AuditEntry, quoteID, revision, version int) (*domain.Quote, error)
+}
+
+// GetRevisions returns the history of a quote, newest first, including after it was deleted
+func (uc *QuoteUseCase) GetRevisions(id int) ([]*domain.Revision, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+
+	return uc.quoteRepository.ListRevisions(id)
+}
+
+// GetRevision returns one revision of a quote
+func (uc *QuoteUseCase) GetRevision(id, revision int) (*domain.Revision, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+	if revision <= 0 {
+		return nil, domain.ErrInvalidRevision
+	}
+
+	return uc.quoteRepository.GetRevision(id, revision)
+}
+
+// DiffRevisions compares two revisions of a quote word by word
+func (uc *QuoteUseCase) DiffRevisions(id, from, to int) (*domain.RevisionDiff, error) {
+	fromRev, err := uc.GetRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := uc.GetRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.DiffRevisions(fromRev, toRev), nil
+}
+
+// RestoreRevision brings a quote back to the author, text and tags it had at a revision,
+// recreating it when it has been deleted. A non-zero version makes the restore of an
+// existing quote conditional in the same way as UpdateQuote.
+func (uc *QuoteUseCase) RestoreRevision(ctx context.Context, id, revision, version int) (*domain.Quote, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+	if revision <= 0 {
+		return nil, domain.ErrInvalidRevision
+	}
+	if version < 0 {
+		return nil, domain.ErrInvalidVersion
+	}
+
+	quote, err := uc.quoteRepository.Restore(ctx, auditEntry(ctx, domain.ActionQuoteRevert), id, revision, version)
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return quote, err
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/diff"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func revisionStore() map[int]*domain.Revision {
+	return map[int]*domain.Revision{
+		1: {QuoteID: 1, Revision: 1, Change: domain.ChangeCreate, Snapshot: &domain.Quote{
+			ID: 1, Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity.", Tags: []string{"luck", "work"},
+		}},
+		2: {QuoteID: 1, Revision: 2, Change: domain.ChangeUpdate, Snapshot: &domain.Quote{
+			ID: 1, Author: "Lucius Annaeus Seneca", Quote: "Luck is what happens when practice meets opportunity.", Tags: []string{"luck", "stoicism"},
+		}},
+	}
+}
+
+func TestQuoteUseCase_DiffRevisions(t *testing.T) {
+	store := revisionStore()
+	mockRepo := &MockQuoteRepository{
+		GetRevisionFunc: func(quoteID, revision int) (*domain.Revision, error) {
+			if rev, ok := store[revision]; ok && quoteID == 1 {
+				return rev, nil
+			}
+			return nil, domain.ErrRevisionNotFound
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	got, err := useCase.DiffRevisions(1, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &domain.RevisionDiff{
+		QuoteID: 1,
+		From:    1,
+		To:      2,
+		Author: []diff.Edit{
+			{Op: diff.Insert, Text: "Lucius Annaeus"},
+			{Op: diff.Equal, Text: "Seneca"},
+		},
+		Quote: []diff.Edit{
+			{Op: diff.Equal, Text: "Luck is what happens when"},
+			{Op: diff.Delete, Text: "preparation"},
+			{Op: diff.Insert, Text: "practice"},
+			{Op: diff.Equal, Text: "meets opportunity."},
+		},
+		TagsAdded:   []string{"stoicism"},
+		TagsRemoved: []string{"work"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err = useCase.DiffRevisions(1, 1, 9); !errors.Is(err, domain.ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+	if _, err = useCase.DiffRevisions(1, 0, 2); !errors.Is(err, domain.ErrInvalidRevision) {
+		t.Errorf("expected ErrInvalidRevision, got %v", err)
+	}
+	if _, err = useCase.DiffRevisions(-1, 1, 2); !errors.Is(err, domain.ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_GetRevisions(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		ListRevisionsFunc: func(quoteID int) ([]*domain.Revision, error) {
+			if quoteID != 1 {
+				return nil, domain.ErrQuoteNotFound
+			}
+			store := revisionStore()
+			return []*domain.Revision{store[2], store[1]}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	revisions, err := useCase.GetRevisions(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 2 {
+		t.Errorf("expected two revisions, newest first, got %+v", revisions)
+	}
+
+	if _, err = useCase.GetRevisions(9); !errors.Is(err, domain.ErrQuoteNotFound) {
+		t.Errorf("expected ErrQuoteNotFound, got %v", err)
+	}
+	if _, err = useCase.GetRevisions(0); !errors.Is(err, domain.ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_RestoreRevision(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            int
+		revision      int
+		version       int
+		mockErr       error
+		expectedError error
+	}{
+		{name: "successful restore", id: 1, revision: 1, version: 3},
+		{name: "invalid ID", id: 0, revision: 1, expectedError: domain.ErrInvalidID},
+		{name: "invalid revision", id: 1, revision: 0, expectedError: domain.ErrInvalidRevision},
+		{name: "invalid version", id: 1, revision: 1, version: -1, expectedError: domain.ErrInvalidVersion},
+		{name: "revision not found", id: 1, revision: 7, mockErr: domain.ErrRevisionNotFound, expectedError: domain.ErrRevisionNotFound},
+		{
+			name: "version conflict", id: 1, revision: 1, version: 2,
+			mockErr:       &domain.VersionConflictError{Current: &domain.Quote{ID: 1, Version: 3}},
+			expectedError: domain.ErrVersionConflict,
+		},
+		{
+			// A quote in the trash is restored in place, so a stale version conflicts with it
+			name: "version conflict in the trash", id: 1, revision: 1, version: 2,
+			mockErr:       &domain.VersionConflictError{Current: &domain.Quote{ID: 1, Version: 3, DeletedAt: &time.Time{}}},
+			expectedError: domain.ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockQuoteRepository{
+				RestoreFunc: func(quoteID, revision, version int) (*domain.Quote, error) {
+					if quoteID != tt.id || revision != tt.revision || version != tt.version {
+						t.Errorf("expected restore(%d, %d, %d), got restore(%d, %d, %d)",
+							tt.id, tt.revision, tt.version, quoteID, revision, version)
+					}
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return &domain.Quote{ID: quoteID, Version: 4}, nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			quote, err := useCase.RestoreRevision(context.Background(), tt.id, tt.revision, tt.version)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if quote.ID != tt.id {
+				t.Errorf("expected quote %d, got %d", tt.id, quote.ID)
+			}
+		})
+	}
+}
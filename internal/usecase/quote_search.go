@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// SearchStore finds quotes by a substring of their text, author or tags
+type SearchStore interface {
+	Search(text string, limit int) ([]*domain.Quote, error)
+}
+
+// LoaderStore answers many lookups of one kind with a single query, for batched loading
+type LoaderStore interface {
+	ListPages(reqs []domain.PageRequest) ([][]*domain.Quote, error)
+	GetByIDs(ids []int) ([]*domain.Quote, error)
+	ListRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error)
+}
+
+// ListQuotesPage returns one page of quotes in ID order. Pages are keyed by the last ID
+// rather than an offset, so quotes added or deleted between calls do not shift them.
+func (uc *QuoteUseCase) ListQuotesPage(req domain.PageRequest) (*domain.QuotePage, error) {
+	pages, err := uc.ListQuotesPages([]domain.PageRequest{req})
+	if err != nil {
+		return nil, err
+	}
+	return pages[0], nil
+}
+
+// ListQuotesPages is ListQuotesPage for several requests at once, answered with one query
+func (uc *QuoteUseCase) ListQuotesPages(reqs []domain.PageRequest) ([]*domain.QuotePage, error) {
+	queries := make([]domain.PageRequest, len(reqs))
+	for i, req := range reqs {
+		if req.AfterID < 0 {
+			return nil, domain.ErrInvalidPageToken
+		}
+		if req.Limit <= 0 {
+			req.Limit = domain.DefaultPageSize
+		}
+		req.Limit = min(req.Limit, domain.MaxPageSize)
+		req.Author = strings.TrimSpace(req.Author)
+		req.Tag = strings.ToLower(strings.TrimSpace(req.Tag))
+
+		// One extra row tells whether another page follows
+		queries[i] = req
+		queries[i].Limit++
+	}
+
+	results, err := uc.quoteRepository.ListPages(queries)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*domain.QuotePage, len(reqs))
+	for i, quotes := range results {
+		limit := queries[i].Limit - 1
+		pages[i] = &domain.QuotePage{Quotes: quotes}
+		if len(quotes) > limit {
+			pages[i].Quotes = quotes[:limit]
+			pages[i].NextAfterID = quotes[limit-1].ID
+		}
+	}
+	return pages, nil
+}
+
+// ListQuotes returns one page of the quotes selected by filter, in its sort order or by ID.
+// Like ListQuotesPage, pages are keyed by the last quote of the previous one, whose sort
+// key the cursor carries.
+func (uc *QuoteUseCase) ListQuotes(filter domain.QuoteFilter) (*domain.QuotePage, error) {
+	if err := validateQuoteFilter(filter); err != nil {
+		return nil, err
+	}
+	if !filter.After.IsZero() && !filter.After.ValidFor(filter.Sort) {
+		return nil, domain.ErrInvalidPageToken
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultPageSize
+	}
+	limit := min(filter.Limit, domain.MaxPageSize)
+	filter = normalizeQuoteFilter(filter)
+
+	// One extra row tells whether another page follows
+	filter.Limit = limit + 1
+	quotes, err := uc.quoteRepository.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.QuotePage{Quotes: quotes}
+	if len(quotes) > limit {
+		page.Quotes = quotes[:limit]
+		page.Next = domain.NewQuoteCursor(quotes[limit-1], filter.Sort)
+	}
+	return page, nil
+}
+
+// FilterQuotes returns all the quotes selected by filter, in its sort order or by ID
+func (uc *QuoteUseCase) FilterQuotes(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	if err := validateQuoteFilter(filter); err != nil {
+		return nil, err
+	}
+	filter.After, filter.Limit = domain.QuoteCursor{}, 0
+	return uc.quoteRepository.List(normalizeQuoteFilter(filter))
+}
+
+func normalizeQuoteFilter(filter domain.QuoteFilter) domain.QuoteFilter {
+	filter.Author = strings.TrimSpace(filter.Author)
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	return filter
+}
+
+func validateQuoteFilter(filter domain.QuoteFilter) error {
+	switch {
+	case !filter.Sort.Valid():
+		return fmt.Errorf("%w: sort must be one of %s, optionally prefixed with -", domain.ErrInvalidQuoteFilter, strings.Join(domain.QuoteSortKeys, ", "))
+	case filter.MinLength < 0 || filter.MaxLength < 0:
+		return fmt.Errorf("%w: lengths must be positive", domain.ErrInvalidQuoteFilter)
+	case filter.MaxLength > 0 && filter.MinLength > filter.MaxLength:
+		return fmt.Errorf("%w: min_length must not exceed max_length", domain.ErrInvalidQuoteFilter)
+	case !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore):
+		return fmt.Errorf("%w: created_after must be before created_before", domain.ErrInvalidQuoteFilter)
+	}
+	return nil
+}
+
+// SearchQuotes returns the newest quotes whose text, author or one of the tags contains
+// query, ignoring case
+func (uc *QuoteUseCase) SearchQuotes(query string, limit int) ([]*domain.Quote, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrInvalidQuery
+	}
+	if limit <= 0 {
+		limit = domain.DefaultSearchLimit
+	}
+
+	return uc.quoteRepository.Search(query, min(limit, domain.MaxSearchLimit))
+}
+
+// GetQuotesByIDs returns the live quotes with the given IDs in ID order, skipping the missing ones
+func (uc *QuoteUseCase) GetQuotesByIDs(ids []int) ([]*domain.Quote, error) {
+	if len(ids) == 0 {
+		return []*domain.Quote{}, nil
+	}
+	return uc.quoteRepository.GetByIDs(ids)
+}
+
+// GetRevisionsByQuotes returns the revisions of several quotes, grouped by quote ID
+// and newest first within each quote
+func (uc *QuoteUseCase) GetRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error) {
+	if len(quoteIDs) == 0 {
+		return []*domain.Revision{}, nil
+	}
+	return uc.quoteRepository.ListRevisionsByQuotes(quoteIDs)
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// pagesUpTo answers page requests from quotes 1-n and records them in got
+func pagesUpTo(n int, got *[]domain.PageRequest) func(reqs []domain.PageRequest) ([][]*domain.Quote, error) {
+	return func(reqs []domain.PageRequest) ([][]*domain.Quote, error) {
+		*got = reqs
+		pages := make([][]*domain.Quote, len(reqs))
+		for i, req := range reqs {
+			for id := req.AfterID + 1; id <= n && len(pages[i]) < req.Limit; id++ {
+				pages[i] = append(pages[i], &domain.Quote{ID: id})
+			}
+		}
+		return pages, nil
+	}
+}
+
+func TestQuoteUseCase_ListQuotesPage(t *testing.T) {
+	var got []domain.PageRequest
+	useCase := NewQuoteUseCase(&MockQuoteRepository{ListPagesFunc: pagesUpTo(5, &got)})
+
+	page, err := useCase.ListQuotesPage(domain.PageRequest{Author: " Seneca ", Tag: " Stoic", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != (domain.PageRequest{Author: "Seneca", Tag: "stoic", Limit: 3}) {
+		t.Errorf("expected one normalized request for 3 rows, got %+v", got)
+	}
+	if len(page.Quotes) != 2 || page.NextAfterID != 2 {
+		t.Errorf("expected quotes 1-2 and a next page after 2, got %d quotes, next %d", len(page.Quotes), page.NextAfterID)
+	}
+
+	page, err = useCase.ListQuotesPage(domain.PageRequest{AfterID: 3, Limit: 2})
+	if err != nil || len(page.Quotes) != 2 || page.NextAfterID != 0 {
+		t.Errorf("expected the last page with quotes 4-5, got %+v (%v)", page, err)
+	}
+
+	if _, err = useCase.ListQuotesPage(domain.PageRequest{Limit: 10000}); err != nil || got[0].Limit != domain.MaxPageSize+1 {
+		t.Errorf("expected the page size to be capped, got %+v (%v)", got, err)
+	}
+	if _, err = useCase.ListQuotesPage(domain.PageRequest{}); err != nil || got[0].Limit != domain.DefaultPageSize+1 {
+		t.Errorf("expected the default page size, got %+v (%v)", got, err)
+	}
+	if _, err = useCase.ListQuotesPage(domain.PageRequest{AfterID: -1}); !errors.Is(err, domain.ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_ListQuotesPages(t *testing.T) {
+	var got []domain.PageRequest
+	useCase := NewQuoteUseCase(&MockQuoteRepository{ListPagesFunc: pagesUpTo(5, &got)})
+
+	pages, err := useCase.ListQuotesPages([]domain.PageRequest{{Limit: 1}, {AfterID: 4, Limit: 3}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected both pages in one call, got %+v", got)
+	}
+	if len(pages[0].Quotes) != 1 || pages[0].NextAfterID != 1 {
+		t.Errorf("expected quote 1 and more to follow, got %+v", pages[0])
+	}
+	if len(pages[1].Quotes) != 1 || pages[1].Quotes[0].ID != 5 || pages[1].NextAfterID != 0 {
+		t.Errorf("expected only quote 5, got %+v", pages[1])
+	}
+}
+
+func TestQuoteUseCase_ListQuotes(t *testing.T) {
+	var got domain.QuoteFilter
+	useCase := NewQuoteUseCase(&MockQuoteRepository{
+		ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			got = filter
+			// Quotes 5 down to 1, as sorted by -created_at
+			var quotes []*domain.Quote
+			for id := 5; id > 0 && len(quotes) < filter.Limit; id-- {
+				quotes = append(quotes, &domain.Quote{ID: id, CreatedAt: time.Date(2026, 1, id, 12, 0, 0, 500, time.UTC)})
+			}
+			return quotes, nil
+		},
+	})
+
+	page, err := useCase.ListQuotes(domain.QuoteFilter{Author: " Seneca ", Tag: " Stoic", Sort: "-created_at", MinLength: 10, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := domain.QuoteFilter{Author: "Seneca", Tag: "stoic", Sort: "-created_at", MinLength: 10, Limit: 3}
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	next := domain.QuoteCursor{Key: "2026-01-04T12:00:00.0000005Z", ID: 4}
+	if len(page.Quotes) != 2 || page.Next != next {
+		t.Errorf("expected quotes 5-4 and a next page after %+v, got %d quotes, next %+v", next, len(page.Quotes), page.Next)
+	}
+
+	after := domain.QuoteFilter{Sort: "-created_at", After: next}
+	if _, err = useCase.ListQuotes(after); err != nil || got.After != next {
+		t.Errorf("expected the listing to continue after %+v, got %+v (%v)", next, got, err)
+	}
+
+	if _, err = useCase.ListQuotes(domain.QuoteFilter{Limit: 10000}); err != nil || got.Limit != domain.MaxPageSize+1 {
+		t.Errorf("expected the page size to be capped, got %+v (%v)", got, err)
+	}
+	if page, err = useCase.ListQuotes(domain.QuoteFilter{}); err != nil || got.Limit != domain.DefaultPageSize+1 || !page.Next.IsZero() {
+		t.Errorf("expected one page of the default size, got %+v (%v)", got, err)
+	}
+	invalidCursors := []domain.QuoteFilter{
+		{After: domain.QuoteCursor{ID: -1}},
+		{After: domain.QuoteCursor{Key: "Seneca", ID: 4}},
+		{Sort: "author", After: domain.QuoteCursor{ID: 4}},
+		{Sort: "-length", After: domain.QuoteCursor{Key: "long", ID: 4}},
+		{Sort: "created_at", After: domain.QuoteCursor{Key: "2026-01-04", ID: 4}},
+	}
+	for _, filter := range invalidCursors {
+		if _, err = useCase.ListQuotes(filter); !errors.Is(err, domain.ErrInvalidPageToken) {
+			t.Errorf("expected %+v to be rejected with ErrInvalidPageToken, got %v", filter, err)
+		}
+	}
+
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	invalid := []domain.QuoteFilter{
+		{Sort: "id"},
+		{Sort: "--length"},
+		{MinLength: -1},
+		{MinLength: 20, MaxLength: 10},
+		{CreatedAfter: day, CreatedBefore: day},
+	}
+	for _, filter := range invalid {
+		if _, err := useCase.ListQuotes(filter); !errors.Is(err, domain.ErrInvalidQuoteFilter) {
+			t.Errorf("expected %+v to be rejected, got %v", filter, err)
+		}
+	}
+}
+
+func TestQuoteUseCase_FilterQuotes(t *testing.T) {
+	var got domain.QuoteFilter
+	useCase := NewQuoteUseCase(&MockQuoteRepository{
+		ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+			got = filter
+			return []*domain.Quote{{ID: 1}, {ID: 2}}, nil
+		},
+	})
+
+	quotes, err := useCase.FilterQuotes(domain.QuoteFilter{Tag: " Wit ", Sort: "length", After: domain.QuoteCursor{ID: 9}, Limit: 5})
+	if err != nil || len(quotes) != 2 {
+		t.Fatalf("expected 2 quotes, got %v (%v)", quotes, err)
+	}
+	if expected := (domain.QuoteFilter{Tag: "wit", Sort: "length"}); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if _, err = useCase.FilterQuotes(domain.QuoteFilter{Sort: "id"}); !errors.Is(err, domain.ErrInvalidQuoteFilter) {
+		t.Errorf("expected ErrInvalidQuoteFilter, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_SearchQuotes(t *testing.T) {
+	var gotText string
+	var gotLimit int
+	mockRepo := &MockQuoteRepository{
+		SearchFunc: func(text string, limit int) ([]*domain.Quote, error) {
+			gotText, gotLimit = text, limit
+			return []*domain.Quote{{ID: 1, Quote: "Know thyself"}}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	tests := []struct {
+		name          string
+		query         string
+		limit         int
+		expectedLimit int
+		expectedError error
+	}{
+		{name: "default limit", query: " thyself ", expectedLimit: domain.DefaultSearchLimit},
+		{name: "capped limit", query: "thyself", limit: 1000, expectedLimit: domain.MaxSearchLimit},
+		{name: "blank query", query: "  ", expectedError: domain.ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotLimit = "", 0
+			quotes, err := useCase.SearchQuotes(tt.query, tt.limit)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if gotText != "thyself" || gotLimit != tt.expectedLimit || len(quotes) != 1 {
+				t.Errorf("expected Search(thyself, %d), got Search(%q, %d)", tt.expectedLimit, gotText, gotLimit)
+			}
+		})
+	}
+}
@@ -1,51 +1,110 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"github.com/shoksin/quotes-service/internal/domain"
+	"hash/fnv"
 	"math/rand"
 	"strings"
 	"time"
 )
 
 type QuoteRepository interface {
-	Create(quote *domain.Quote) (*domain.Quote, error)
-	GetAll() ([]*domain.Quote, error)
-	GetByAuthor(author string) ([]*domain.Quote, error)
+	BatchStore
+	TransferStore
+	RevisionStore
+	TrashStore
+	AuditStore
+	WebhookStore
+	EventStore
+	SearchStore
+	LoaderStore
+	AuthorStore
+	StatsStore
+	KeyStore
+
+	Create(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error)
+	List(filter domain.QuoteFilter) ([]*domain.Quote, error)
 	GetRandom() (*domain.Quote, error)
-	Delete(id int) error
+	ListIDs() ([]int, error)
+	Delete(ctx context.Context, entry domain.AuditEntry, id, version int) error
 	GetByID(id int) (*domain.Quote, error)
+	GetNth(n int64) (*domain.Quote, error)
+	Update(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error)
+	Version() (*domain.CollectionVersion, error)
 }
 
 type QuoteUseCase struct {
 	quoteRepository QuoteRepository
+	validationRules domain.ValidationRules
+	batchLimits     domain.BatchLimits
+	webhookSender   WebhookSender
+	webhookPolicy   domain.WebhookPolicy
+	events          *eventHub
+	cache           *readCache
+	statsPolicy     domain.StatsPolicy
 }
 
-func NewQuoteUseCase(quoteRepository QuoteRepository) *QuoteUseCase {
-	return &QuoteUseCase{
+// Option configures optional QuoteUseCase settings
+type Option func(*QuoteUseCase)
+
+// WithValidationRules overrides domain.DefaultValidationRules
+func WithValidationRules(rules domain.ValidationRules) Option {
+	return func(uc *QuoteUseCase) {
+		uc.validationRules = rules
+	}
+}
+
+// WithBatchLimits overrides domain.DefaultBatchLimits
+func WithBatchLimits(limits domain.BatchLimits) Option {
+	return func(uc *QuoteUseCase) {
+		uc.batchLimits = limits
+	}
+}
+
+func NewQuoteUseCase(quoteRepository QuoteRepository, opts ...Option) *QuoteUseCase {
+	uc := &QuoteUseCase{
 		quoteRepository: quoteRepository,
+		validationRules: domain.DefaultValidationRules,
+		batchLimits:     domain.DefaultBatchLimits,
+		webhookPolicy:   domain.DefaultWebhookPolicy,
+		statsPolicy:     domain.DefaultStatsPolicy,
+		events:          newEventHub(),
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
 }
 
-func (uc *QuoteUseCase) CreateQuote(req *domain.CreateQuoteRequest) (*domain.Quote, error) {
-	if err := req.Validate(); err != nil {
+// CreateQuote validates and stores a new quote. Writes are attributed to the actor in ctx.
+func (uc *QuoteUseCase) CreateQuote(ctx context.Context, req *domain.CreateQuoteRequest) (*domain.Quote, error) {
+	if err := uc.validationRules.Validate(req); err != nil {
 		return nil, err
 	}
 
 	// Trim whitespace and normalize the input
-	req.Author = strings.TrimSpace(req.Author)
-	req.Quote = strings.TrimSpace(req.Quote)
+	req.Normalize()
 
+	now := time.Now()
 	quote := &domain.Quote{
 		Author:    req.Author,
 		Quote:     req.Quote,
-		CreatedAt: time.Now(),
+		Tags:      req.Tags,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
-	return uc.quoteRepository.Create(quote)
+	created, err := uc.quoteRepository.Create(ctx, auditEntry(ctx, domain.ActionQuoteCreate), quote)
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return created, err
 }
 
 func (uc *QuoteUseCase) GetAllQuotes() ([]*domain.Quote, error) {
-	return uc.quoteRepository.GetAll()
+	return uc.quoteRepository.List(domain.QuoteFilter{Sort: "-created_at"})
 }
 
 func (uc *QuoteUseCase) GetQuotesByAuthor(author string) ([]*domain.Quote, error) {
@@ -54,28 +113,104 @@ func (uc *QuoteUseCase) GetQuotesByAuthor(author string) ([]*domain.Quote, error
 	}
 
 	author = strings.TrimSpace(author)
-	return uc.quoteRepository.GetByAuthor(author)
+	if author == "" {
+		// An empty author filters nothing, while no quote has a blank author
+		return []*domain.Quote{}, nil
+	}
+	return cached(uc, "author_quotes", author, func() ([]*domain.Quote, error) {
+		return uc.quoteRepository.List(domain.QuoteFilter{Author: author, Sort: "-created_at"})
+	})
 }
 
 func (uc *QuoteUseCase) GetRandomQuote() (*domain.Quote, error) {
-	quotes, err := uc.quoteRepository.GetAll()
+	// The pick stays random on every call; only the IDs it is made from are cached, and the
+	// quote itself is read by its key
+	ids, err := cached(uc, "random", "ids", uc.quoteRepository.ListIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(quotes) == 0 {
+	if len(ids) == 0 {
 		return nil, domain.ErrNoQuotesFound
 	}
 
-	randomIndex := rand.Intn(len(quotes))
-	return quotes[randomIndex], nil
+	quote, err := uc.quoteRepository.GetByID(ids[rand.Intn(len(ids))])
+	if errors.Is(err, domain.ErrQuoteNotFound) {
+		// Deleted since the IDs were cached, before this replica heard of it
+		return uc.quoteRepository.GetRandom()
+	}
+	return quote, err
+}
+
+// GetDailyQuote returns the quote of the day. Every caller gets the same quote for the
+// same UTC date, and the choice only changes when the day does or quotes are added or removed.
+func (uc *QuoteUseCase) GetDailyQuote(day time.Time) (*domain.Quote, error) {
+	date := day.UTC().Format(time.DateOnly)
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	// Clear the sign bit so the offset is never negative
+	n := int64(h.Sum64() >> 1)
+	return cached(uc, "daily", date, func() (*domain.Quote, error) {
+		return uc.quoteRepository.GetNth(n)
+	})
+}
+
+// GetQuote returns a quote by ID
+func (uc *QuoteUseCase) GetQuote(id int) (*domain.Quote, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+
+	return uc.quoteRepository.GetByID(id)
+}
+
+// UpdateQuote replaces the author, text and tags of an existing quote.
+// When req.Version is set the update only succeeds if the quote is still at that version,
+// otherwise it returns a *domain.VersionConflictError holding the current quote.
+func (uc *QuoteUseCase) UpdateQuote(ctx context.Context, id int, req *domain.UpdateQuoteRequest) (*domain.Quote, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+	if req.Version < 0 {
+		return nil, domain.ErrInvalidVersion
+	}
+	if err := uc.validationRules.Validate(&req.CreateQuoteRequest); err != nil {
+		return nil, err
+	}
+
+	req.Normalize()
+
+	updated, err := uc.quoteRepository.Update(ctx, auditEntry(ctx, domain.ActionQuoteUpdate), &domain.Quote{
+		ID:      id,
+		Author:  req.Author,
+		Quote:   req.Quote,
+		Tags:    req.Tags,
+		Version: req.Version,
+	})
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return updated, err
+}
+
+// GetQuotesVersion returns the version of the quotes collection, which changes on every write
+func (uc *QuoteUseCase) GetQuotesVersion() (*domain.CollectionVersion, error) {
+	return uc.quoteRepository.Version()
 }
 
-// DeleteQuote deletes a quote by ID
-func (uc *QuoteUseCase) DeleteQuote(id int) error {
+// DeleteQuote moves a quote to the trash. A non-zero version makes the delete conditional
+// in the same way as UpdateQuote.
+func (uc *QuoteUseCase) DeleteQuote(ctx context.Context, id, version int) error {
 	if id <= 0 {
 		return domain.ErrInvalidID
 	}
+	if version < 0 {
+		return domain.ErrInvalidVersion
+	}
 
-	return uc.quoteRepository.Delete(id)
+	err := uc.quoteRepository.Delete(ctx, auditEntry(ctx, domain.ActionQuoteDelete), id, version)
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return err
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,44 +12,97 @@ import (
 // MockQuoteRepository is a mock implementation of QuoteRepository interface
 type MockQuoteRepository struct {
 	CreateFunc      func(quote *domain.Quote) (*domain.Quote, error)
-	GetAllFunc      func() ([]*domain.Quote, error)
-	GetByAuthorFunc func(author string) ([]*domain.Quote, error)
+	ListFunc        func(filter domain.QuoteFilter) ([]*domain.Quote, error)
 	GetRandomFunc   func() (*domain.Quote, error)
-	DeleteFunc      func(id int) error
+	DeleteFunc      func(id, version int) error
 	GetByIDFunc     func(id int) (*domain.Quote, error)
+	ListIDsFunc     func() ([]int, error)
+	CreateBatchFunc func(quotes []*domain.Quote) ([]*domain.Quote, error)
+	DeleteBatchFunc func(filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error)
+	StreamFunc      func(fn func(*domain.Quote) error) error
+	ImportBatchFunc func(quotes []*domain.Quote) ([]*domain.Quote, error)
+	GetNthFunc      func(n int64) (*domain.Quote, error)
+	UpdateFunc      func(quote *domain.Quote) (*domain.Quote, error)
+	VersionFunc     func() (*domain.CollectionVersion, error)
+
+	ListRevisionsFunc func(quoteID int) ([]*domain.Revision, error)
+	GetRevisionFunc   func(quoteID, revision int) (*domain.Revision, error)
+	RestoreFunc       func(quoteID, revision, version int) (*domain.Quote, error)
+
+	HardDeleteFunc         func(id, version int) error
+	ListDeletedFunc        func() ([]*domain.Quote, error)
+	GetByIDWithDeletedFunc func(id int) (*domain.Quote, error)
+	UndeleteFunc           func(id int) (*domain.Quote, error)
+	PurgeDeletedFunc       func(entry domain.AuditEntry, before time.Time) (int64, error)
+
+	ListAuditEventsFunc   func(filter domain.AuditFilter) ([]*domain.AuditEvent, error)
+	StreamAuditEventsFunc func(filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error
+	StreamAuditChainFunc  func(fn func(*domain.AuditEvent) error) error
+	CountUnchainedFunc    func() (int64, error)
+	ChainAuditEventsFunc  func(limit int) (int, error)
+
+	ListWebhooksFunc          func() ([]*domain.WebhookSubscription, error)
+	GetWebhookFunc            func(id int) (*domain.WebhookSubscription, error)
+	CreateWebhookFunc         func(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	UpdateWebhookFunc         func(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	DeleteWebhookFunc         func(id int) error
+	ListDeliveriesFunc        func(subscriptionID int, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+	RedeliverFunc             func(subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error)
+	FanOutEventsFunc          func(limit int) (int64, error)
+	ClaimDeliveriesFunc       func(limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	RecordDeliveryAttemptFunc func(attempt *domain.DeliveryAttempt) error
+	PruneOutboxFunc           func(before time.Time) (int64, error)
+	SequenceEventsFunc        func() (int64, error)
+	ListEventsFunc            func(afterID int64, limit int) ([]*domain.WebhookEvent, error)
+	LatestEventIDFunc         func() (int64, error)
+	ListPagesFunc             func(reqs []domain.PageRequest) ([][]*domain.Quote, error)
+	SearchFunc                func(text string, limit int) ([]*domain.Quote, error)
+	GetByIDsFunc              func(ids []int) ([]*domain.Quote, error)
+	ListRevisionsByQuotesFunc func(quoteIDs []int) ([]*domain.Revision, error)
+	GetAuthorsFunc            func(names []string) ([]*domain.Author, error)
+	ListAuthorsFunc           func(after string, limit int) ([]*domain.Author, error)
+	SetAuthorBioFunc          func(entry domain.AuditEntry, name, bio string) error
+	GetTagsFunc               func(names []string) ([]*domain.Tag, error)
+	ListTagsFunc              func(after string, limit int) ([]*domain.Tag, error)
+	ListAPIKeysFunc           func() ([]*domain.APIKey, error)
+	GetAPIKeyByDigestFunc     func(digest string) (*domain.APIKey, error)
+	CreateAPIKeyFunc          func(key *domain.APIKey, digest string) (*domain.APIKey, error)
+	DeleteAPIKeyFunc          func(id int) error
+	StatsFunc                 func(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error)
+	RefreshStatsFunc          func() (bool, error)
 }
 
-func (m *MockQuoteRepository) Create(quote *domain.Quote) (*domain.Quote, error) {
+func (m *MockQuoteRepository) Create(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(quote)
 	}
 	return nil, nil
 }
 
-func (m *MockQuoteRepository) GetAll() ([]*domain.Quote, error) {
-	if m.GetAllFunc != nil {
-		return m.GetAllFunc()
+func (m *MockQuoteRepository) List(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(filter)
 	}
 	return nil, nil
 }
 
-func (m *MockQuoteRepository) GetByAuthor(author string) ([]*domain.Quote, error) {
-	if m.GetByAuthorFunc != nil {
-		return m.GetByAuthorFunc(author)
+func (m *MockQuoteRepository) GetRandom() (*domain.Quote, error) {
+	if m.GetRandomFunc != nil {
+		return m.GetRandomFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockQuoteRepository) GetRandom() (*domain.Quote, error) {
-	if m.GetRandomFunc != nil {
-		return m.GetRandomFunc()
+func (m *MockQuoteRepository) ListIDs() ([]int, error) {
+	if m.ListIDsFunc != nil {
+		return m.ListIDsFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockQuoteRepository) Delete(id int) error {
+func (m *MockQuoteRepository) Delete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
 	if m.DeleteFunc != nil {
-		return m.DeleteFunc(id)
+		return m.DeleteFunc(id, version)
 	}
 	return nil
 }
@@ -60,6 +114,349 @@ func (m *MockQuoteRepository) GetByID(id int) (*domain.Quote, error) {
 	return nil, nil
 }
 
+func (m *MockQuoteRepository) CreateBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	if m.CreateBatchFunc != nil {
+		return m.CreateBatchFunc(quotes)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteRepository) DeleteBatch(ctx context.Context, entry domain.AuditEntry, filter domain.DeleteFilter, dryRun bool, maxItems int) ([]int, error) {
+	if m.DeleteBatchFunc != nil {
+		return m.DeleteBatchFunc(filter, dryRun, maxItems)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteRepository) Stream(fn func(*domain.Quote) error) error {
+	if m.StreamFunc != nil {
+		return m.StreamFunc(fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) ImportBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	if m.ImportBatchFunc != nil {
+		return m.ImportBatchFunc(quotes)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteRepository) GetNth(n int64) (*domain.Quote, error) {
+	if m.GetNthFunc != nil {
+		return m.GetNthFunc(n)
+	}
+	return nil, domain.ErrNoQuotesFound
+}
+
+func (m *MockQuoteRepository) Update(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(quote)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteRepository) Version() (*domain.CollectionVersion, error) {
+	if m.VersionFunc != nil {
+		return m.VersionFunc()
+	}
+	return &domain.CollectionVersion{}, nil
+}
+
+func (m *MockQuoteRepository) ListRevisions(quoteID int) ([]*domain.Revision, error) {
+	if m.ListRevisionsFunc != nil {
+		return m.ListRevisionsFunc(quoteID)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteRepository) GetRevision(quoteID, revision int) (*domain.Revision, error) {
+	if m.GetRevisionFunc != nil {
+		return m.GetRevisionFunc(quoteID, revision)
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+
+func (m *MockQuoteRepository) Restore(ctx context.Context, entry domain.AuditEntry, quoteID, revision, version int) (*domain.Quote, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(quoteID, revision, version)
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+
+func (m *MockQuoteRepository) HardDelete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
+	if m.HardDeleteFunc != nil {
+		return m.HardDeleteFunc(id, version)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) ListDeleted() ([]*domain.Quote, error) {
+	if m.ListDeletedFunc != nil {
+		return m.ListDeletedFunc()
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteRepository) GetByIDWithDeleted(id int) (*domain.Quote, error) {
+	if m.GetByIDWithDeletedFunc != nil {
+		return m.GetByIDWithDeletedFunc(id)
+	}
+	return nil, domain.ErrQuoteNotFound
+}
+
+func (m *MockQuoteRepository) Undelete(ctx context.Context, entry domain.AuditEntry, id int) (*domain.Quote, error) {
+	if m.UndeleteFunc != nil {
+		return m.UndeleteFunc(id)
+	}
+	return nil, domain.ErrNotInTrash
+}
+
+func (m *MockQuoteRepository) PurgeDeleted(ctx context.Context, entry domain.AuditEntry, before time.Time) (int64, error) {
+	if m.PurgeDeletedFunc != nil {
+		return m.PurgeDeletedFunc(entry, before)
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ListAuditEvents(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	if m.ListAuditEventsFunc != nil {
+		return m.ListAuditEventsFunc(filter)
+	}
+	return []*domain.AuditEvent{}, nil
+}
+
+func (m *MockQuoteRepository) StreamAuditEvents(filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	if m.StreamAuditEventsFunc != nil {
+		return m.StreamAuditEventsFunc(filter, fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) StreamAuditChain(fn func(*domain.AuditEvent) error) error {
+	if m.StreamAuditChainFunc != nil {
+		return m.StreamAuditChainFunc(fn)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) CountUnchainedAuditEvents() (int64, error) {
+	if m.CountUnchainedFunc != nil {
+		return m.CountUnchainedFunc()
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ChainAuditEvents(ctx context.Context, limit int) (int, error) {
+	if m.ChainAuditEventsFunc != nil {
+		return m.ChainAuditEventsFunc(limit)
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ListWebhooks() ([]*domain.WebhookSubscription, error) {
+	if m.ListWebhooksFunc != nil {
+		return m.ListWebhooksFunc()
+	}
+	return []*domain.WebhookSubscription{}, nil
+}
+
+func (m *MockQuoteRepository) GetWebhook(id int) (*domain.WebhookSubscription, error) {
+	if m.GetWebhookFunc != nil {
+		return m.GetWebhookFunc(id)
+	}
+	return nil, domain.ErrWebhookNotFound
+}
+
+func (m *MockQuoteRepository) CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if m.CreateWebhookFunc != nil {
+		return m.CreateWebhookFunc(sub)
+	}
+	return sub, nil
+}
+
+func (m *MockQuoteRepository) UpdateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if m.UpdateWebhookFunc != nil {
+		return m.UpdateWebhookFunc(sub)
+	}
+	return sub, nil
+}
+
+func (m *MockQuoteRepository) DeleteWebhook(ctx context.Context, id int) error {
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(id)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) ListDeliveries(subscriptionID int, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	if m.ListDeliveriesFunc != nil {
+		return m.ListDeliveriesFunc(subscriptionID, status, limit)
+	}
+	return []*domain.WebhookDelivery{}, nil
+}
+
+func (m *MockQuoteRepository) Redeliver(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+	if m.RedeliverFunc != nil {
+		return m.RedeliverFunc(subscriptionID, deliveryID)
+	}
+	return nil, domain.ErrDeliveryNotFound
+}
+
+func (m *MockQuoteRepository) FanOutEvents(ctx context.Context, limit int) (int64, error) {
+	if m.FanOutEventsFunc != nil {
+		return m.FanOutEventsFunc(limit)
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	if m.ClaimDeliveriesFunc != nil {
+		return m.ClaimDeliveriesFunc(limit, lease)
+	}
+	return nil, nil
+}
+
+func (m *MockQuoteRepository) RecordDeliveryAttempt(ctx context.Context, attempt *domain.DeliveryAttempt) error {
+	if m.RecordDeliveryAttemptFunc != nil {
+		return m.RecordDeliveryAttemptFunc(attempt)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) SequenceEvents(ctx context.Context) (int64, error) {
+	if m.SequenceEventsFunc != nil {
+		return m.SequenceEventsFunc()
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) PruneOutbox(ctx context.Context, before time.Time) (int64, error) {
+	if m.PruneOutboxFunc != nil {
+		return m.PruneOutboxFunc(before)
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ListEvents(afterID int64, limit int) ([]*domain.WebhookEvent, error) {
+	if m.ListEventsFunc != nil {
+		return m.ListEventsFunc(afterID, limit)
+	}
+	return []*domain.WebhookEvent{}, nil
+}
+
+func (m *MockQuoteRepository) LatestEventID() (int64, error) {
+	if m.LatestEventIDFunc != nil {
+		return m.LatestEventIDFunc()
+	}
+	return 0, nil
+}
+
+func (m *MockQuoteRepository) ListPages(reqs []domain.PageRequest) ([][]*domain.Quote, error) {
+	if m.ListPagesFunc != nil {
+		return m.ListPagesFunc(reqs)
+	}
+	return make([][]*domain.Quote, len(reqs)), nil
+}
+
+func (m *MockQuoteRepository) Search(text string, limit int) ([]*domain.Quote, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(text, limit)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteRepository) GetByIDs(ids []int) ([]*domain.Quote, error) {
+	if m.GetByIDsFunc != nil {
+		return m.GetByIDsFunc(ids)
+	}
+	return []*domain.Quote{}, nil
+}
+
+func (m *MockQuoteRepository) ListRevisionsByQuotes(quoteIDs []int) ([]*domain.Revision, error) {
+	if m.ListRevisionsByQuotesFunc != nil {
+		return m.ListRevisionsByQuotesFunc(quoteIDs)
+	}
+	return []*domain.Revision{}, nil
+}
+
+func (m *MockQuoteRepository) GetAuthors(names []string) ([]*domain.Author, error) {
+	if m.GetAuthorsFunc != nil {
+		return m.GetAuthorsFunc(names)
+	}
+	return []*domain.Author{}, nil
+}
+
+func (m *MockQuoteRepository) ListAuthors(after string, limit int) ([]*domain.Author, error) {
+	if m.ListAuthorsFunc != nil {
+		return m.ListAuthorsFunc(after, limit)
+	}
+	return []*domain.Author{}, nil
+}
+
+func (m *MockQuoteRepository) SetAuthorBio(ctx context.Context, entry domain.AuditEntry, name, bio string) error {
+	if m.SetAuthorBioFunc != nil {
+		return m.SetAuthorBioFunc(entry, name, bio)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) GetTags(names []string) ([]*domain.Tag, error) {
+	if m.GetTagsFunc != nil {
+		return m.GetTagsFunc(names)
+	}
+	return []*domain.Tag{}, nil
+}
+
+func (m *MockQuoteRepository) ListAPIKeys() ([]*domain.APIKey, error) {
+	if m.ListAPIKeysFunc != nil {
+		return m.ListAPIKeysFunc()
+	}
+	return []*domain.APIKey{}, nil
+}
+
+func (m *MockQuoteRepository) GetAPIKeyByDigest(digest string) (*domain.APIKey, error) {
+	if m.GetAPIKeyByDigestFunc != nil {
+		return m.GetAPIKeyByDigestFunc(digest)
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *MockQuoteRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey, digest string) (*domain.APIKey, error) {
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(key, digest)
+	}
+	return key, nil
+}
+
+func (m *MockQuoteRepository) DeleteAPIKey(ctx context.Context, id int) error {
+	if m.DeleteAPIKeyFunc != nil {
+		return m.DeleteAPIKeyFunc(id)
+	}
+	return nil
+}
+
+func (m *MockQuoteRepository) Stats(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error) {
+	if m.StatsFunc != nil {
+		return m.StatsFunc(policy, stopWords)
+	}
+	return &domain.CollectionStats{}, nil
+}
+
+func (m *MockQuoteRepository) RefreshStats(ctx context.Context) (bool, error) {
+	if m.RefreshStatsFunc != nil {
+		return m.RefreshStatsFunc()
+	}
+	return true, nil
+}
+
+func (m *MockQuoteRepository) ListTags(after string, limit int) ([]*domain.Tag, error) {
+	if m.ListTagsFunc != nil {
+		return m.ListTagsFunc(after, limit)
+	}
+	return []*domain.Tag{}, nil
+}
+
 func TestQuoteUseCase_CreateQuote(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -110,6 +507,14 @@ func TestQuoteUseCase_CreateQuote(t *testing.T) {
 			},
 			expectedError: domain.ErrInvalidQuote,
 		},
+		{
+			name: "validation error - whitespace only author",
+			request: &domain.CreateQuoteRequest{
+				Author: "   ",
+				Quote:  "Test Quote",
+			},
+			expectedError: domain.ErrInvalidAuthor,
+		},
 		{
 			name: "repository error",
 			request: &domain.CreateQuoteRequest{
@@ -130,10 +535,10 @@ func TestQuoteUseCase_CreateQuote(t *testing.T) {
 			}
 			useCase := NewQuoteUseCase(mockRepo)
 
-			result, err := useCase.CreateQuote(tt.request)
+			result, err := useCase.CreateQuote(context.Background(), tt.request)
 
 			if tt.expectedError != nil {
-				if err == nil || err.Error() != tt.expectedError.Error() {
+				if err == nil || (!errors.Is(err, tt.expectedError) && err.Error() != tt.expectedError.Error()) {
 					t.Errorf("expected error %v, got %v", tt.expectedError, err)
 				}
 				return
@@ -186,7 +591,12 @@ func TestQuoteUseCase_GetAllQuotes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockQuoteRepository{
-				GetAllFunc: tt.mockFunc,
+				ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					if filter != (domain.QuoteFilter{Sort: "-created_at"}) {
+						t.Errorf("expected the newest quotes first, got %+v", filter)
+					}
+					return tt.mockFunc()
+				},
 			}
 			useCase := NewQuoteUseCase(mockRepo)
 
@@ -242,12 +652,9 @@ func TestQuoteUseCase_GetQuotesByAuthor(t *testing.T) {
 			name:   "whitespace only author",
 			author: "   ",
 			mockFunc: func(author string) ([]*domain.Quote, error) {
-				// Current implementation trims after checking for empty,
-				// so whitespace-only strings pass through
-				if author != "" {
-					t.Errorf("expected empty string after trim, got '%s'", author)
-				}
-				return []*domain.Quote{}, nil
+				// A blank author would lift the filter and list every quote
+				t.Error("expected a blank author not to reach the repository")
+				return nil, nil
 			},
 			expectedLen: 0,
 		},
@@ -264,7 +671,12 @@ func TestQuoteUseCase_GetQuotesByAuthor(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockQuoteRepository{
-				GetByAuthorFunc: tt.mockFunc,
+				ListFunc: func(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+					if filter.Sort != "-created_at" {
+						t.Errorf("expected the newest quotes first, got %q", filter.Sort)
+					}
+					return tt.mockFunc(filter.Author)
+				},
 			}
 			useCase := NewQuoteUseCase(mockRepo)
 
@@ -289,20 +701,29 @@ func TestQuoteUseCase_GetQuotesByAuthor(t *testing.T) {
 }
 
 func TestQuoteUseCase_GetRandomQuote(t *testing.T) {
+	quotes := map[int]*domain.Quote{
+		1: {ID: 1, Author: "Author1", Quote: "Quote1"},
+		2: {ID: 2, Author: "Author2", Quote: "Quote2"},
+		3: {ID: 3, Author: "Author3", Quote: "Quote3"},
+	}
+	getByID := func(id int) (*domain.Quote, error) {
+		if quote, ok := quotes[id]; ok {
+			return quote, nil
+		}
+		return nil, domain.ErrQuoteNotFound
+	}
+
 	tests := []struct {
 		name          string
-		mockFunc      func() ([]*domain.Quote, error)
+		mockFunc      func() ([]int, error)
+		getRandom     func() (*domain.Quote, error)
 		expectedError error
 		checkResult   func(t *testing.T, result *domain.Quote)
 	}{
 		{
 			name: "successful get random quote",
-			mockFunc: func() ([]*domain.Quote, error) {
-				return []*domain.Quote{
-					{ID: 1, Author: "Author1", Quote: "Quote1"},
-					{ID: 2, Author: "Author2", Quote: "Quote2"},
-					{ID: 3, Author: "Author3", Quote: "Quote3"},
-				}, nil
+			mockFunc: func() ([]int, error) {
+				return []int{1, 2, 3}, nil
 			},
 			checkResult: func(t *testing.T, result *domain.Quote) {
 				if result == nil {
@@ -313,16 +734,30 @@ func TestQuoteUseCase_GetRandomQuote(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "picked quote deleted since",
+			mockFunc: func() ([]int, error) {
+				return []int{9}, nil
+			},
+			getRandom: func() (*domain.Quote, error) {
+				return quotes[2], nil
+			},
+			checkResult: func(t *testing.T, result *domain.Quote) {
+				if result == nil || result.ID != 2 {
+					t.Errorf("expected a quote picked by the database, got %+v", result)
+				}
+			},
+		},
 		{
 			name: "no quotes found",
-			mockFunc: func() ([]*domain.Quote, error) {
-				return []*domain.Quote{}, nil
+			mockFunc: func() ([]int, error) {
+				return []int{}, nil
 			},
 			expectedError: domain.ErrNoQuotesFound,
 		},
 		{
 			name: "repository error",
-			mockFunc: func() ([]*domain.Quote, error) {
+			mockFunc: func() ([]int, error) {
 				return nil, errors.New("database error")
 			},
 			expectedError: errors.New("database error"),
@@ -332,7 +767,9 @@ func TestQuoteUseCase_GetRandomQuote(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockQuoteRepository{
-				GetAllFunc: tt.mockFunc,
+				ListIDsFunc:   tt.mockFunc,
+				GetByIDFunc:   getByID,
+				GetRandomFunc: tt.getRandom,
 			}
 			useCase := NewQuoteUseCase(mockRepo)
 
@@ -360,13 +797,14 @@ func TestQuoteUseCase_DeleteQuote(t *testing.T) {
 	tests := []struct {
 		name          string
 		id            int
-		mockFunc      func(id int) error
+		version       int
+		mockFunc      func(id, version int) error
 		expectedError error
 	}{
 		{
 			name: "successful deletion",
 			id:   1,
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				if id != 1 {
 					t.Errorf("expected id 1, got %d", id)
 				}
@@ -386,7 +824,7 @@ func TestQuoteUseCase_DeleteQuote(t *testing.T) {
 		{
 			name: "quote not found",
 			id:   999,
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return domain.ErrQuoteNotFound
 			},
 			expectedError: domain.ErrQuoteNotFound,
@@ -394,11 +832,37 @@ func TestQuoteUseCase_DeleteQuote(t *testing.T) {
 		{
 			name: "repository error",
 			id:   1,
-			mockFunc: func(id int) error {
+			mockFunc: func(id, version int) error {
 				return errors.New("database error")
 			},
 			expectedError: errors.New("database error"),
 		},
+		{
+			name:    "conditional deletion",
+			id:      1,
+			version: 2,
+			mockFunc: func(id, version int) error {
+				if version != 2 {
+					t.Errorf("expected version 2, got %d", version)
+				}
+				return nil
+			},
+		},
+		{
+			name:          "invalid version",
+			id:            1,
+			version:       -1,
+			expectedError: domain.ErrInvalidVersion,
+		},
+		{
+			name:    "version conflict",
+			id:      1,
+			version: 2,
+			mockFunc: func(id, version int) error {
+				return &domain.VersionConflictError{Current: &domain.Quote{ID: 1, Version: 3}}
+			},
+			expectedError: &domain.VersionConflictError{Current: &domain.Quote{ID: 1, Version: 3}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -408,7 +872,7 @@ func TestQuoteUseCase_DeleteQuote(t *testing.T) {
 			}
 			useCase := NewQuoteUseCase(mockRepo)
 
-			err := useCase.DeleteQuote(tt.id)
+			err := useCase.DeleteQuote(context.Background(), tt.id, tt.version)
 
 			if tt.expectedError != nil {
 				if err == nil || err.Error() != tt.expectedError.Error() {
@@ -424,6 +888,180 @@ func TestQuoteUseCase_DeleteQuote(t *testing.T) {
 	}
 }
 
+func TestQuoteUseCase_GetQuote(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            int
+		mockFunc      func(id int) (*domain.Quote, error)
+		expectedError error
+	}{
+		{
+			name: "existing quote",
+			id:   1,
+			mockFunc: func(id int) (*domain.Quote, error) {
+				return &domain.Quote{ID: id, Author: "Author", Quote: "Quote"}, nil
+			},
+		},
+		{
+			name:          "invalid ID",
+			id:            0,
+			expectedError: domain.ErrInvalidID,
+		},
+		{
+			name: "quote not found",
+			id:   999,
+			mockFunc: func(id int) (*domain.Quote, error) {
+				return nil, domain.ErrQuoteNotFound
+			},
+			expectedError: domain.ErrQuoteNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockQuoteRepository{
+				GetByIDFunc: tt.mockFunc,
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			quote, err := useCase.GetQuote(tt.id)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if quote.ID != tt.id {
+				t.Errorf("expected quote %d, got %d", tt.id, quote.ID)
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_GetDailyQuote(t *testing.T) {
+	var offsets []int64
+	mockRepo := &MockQuoteRepository{
+		GetNthFunc: func(n int64) (*domain.Quote, error) {
+			offsets = append(offsets, n)
+			return &domain.Quote{ID: 1}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	morning := time.Date(2024, 3, 1, 0, 30, 0, 0, time.UTC)
+	// The same instant in UTC+3 is still 1 March in UTC
+	evening := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC).In(time.FixedZone("MSK", 3*60*60))
+	nextDay := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, day := range []time.Time{morning, evening, nextDay} {
+		if _, err := useCase.GetDailyQuote(day); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if offsets[0] != offsets[1] {
+		t.Errorf("expected the same quote for the whole UTC day, got offsets %v", offsets)
+	}
+	if offsets[0] == offsets[2] {
+		t.Errorf("expected a different quote on the next day, got offsets %v", offsets)
+	}
+	for _, n := range offsets {
+		if n < 0 {
+			t.Errorf("expected non-negative offsets, got %v", offsets)
+		}
+	}
+
+	_, err := NewQuoteUseCase(&MockQuoteRepository{}).GetDailyQuote(morning)
+	if !errors.Is(err, domain.ErrNoQuotesFound) {
+		t.Errorf("expected ErrNoQuotesFound, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_UpdateQuote(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            int
+		request       *domain.UpdateQuoteRequest
+		mockFunc      func(quote *domain.Quote) (*domain.Quote, error)
+		expectedError error
+	}{
+		{
+			name:    "successful update",
+			id:      1,
+			request: &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: " Seneca ", Quote: "New text ", Tags: []string{"Stoicism"}}},
+			mockFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+				if quote.ID != 1 || quote.Author != "Seneca" || quote.Quote != "New text" || quote.Tags[0] != "stoicism" {
+					t.Errorf("expected normalized quote, got %+v", quote)
+				}
+				return quote, nil
+			},
+		},
+		{
+			name:          "invalid ID",
+			id:            0,
+			request:       &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Text"}},
+			expectedError: domain.ErrInvalidID,
+		},
+		{
+			name:          "invalid request",
+			id:            1,
+			request:       &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: "", Quote: "Text"}},
+			expectedError: domain.ErrInvalidAuthor,
+		},
+		{
+			name:    "quote not found",
+			id:      999,
+			request: &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Text"}},
+			mockFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+				return nil, domain.ErrQuoteNotFound
+			},
+			expectedError: domain.ErrQuoteNotFound,
+		},
+		{
+			name:          "negative version",
+			id:            1,
+			request:       &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Text"}, Version: -1},
+			expectedError: domain.ErrInvalidVersion,
+		},
+		{
+			name:    "version conflict",
+			id:      1,
+			request: &domain.UpdateQuoteRequest{CreateQuoteRequest: domain.CreateQuoteRequest{Author: "Seneca", Quote: "Text"}, Version: 3},
+			mockFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+				if quote.Version != 3 {
+					t.Errorf("expected the expected version to reach the repository, got %d", quote.Version)
+				}
+				return nil, &domain.VersionConflictError{Current: &domain.Quote{ID: 1, Version: 4}}
+			},
+			expectedError: domain.ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockQuoteRepository{UpdateFunc: tt.mockFunc}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			_, err := useCase.UpdateQuote(context.Background(), tt.id, tt.request)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 // TestQuoteUseCase_CreateQuote_CreatedAtSet tests that CreatedAt is set when creating a quote
 func TestQuoteUseCase_CreateQuote_CreatedAtSet(t *testing.T) {
 	beforeTest := time.Now()
@@ -449,7 +1087,7 @@ func TestQuoteUseCase_CreateQuote_CreatedAtSet(t *testing.T) {
 		Quote:  "Test Quote",
 	}
 
-	result, err := useCase.CreateQuote(request)
+	result, err := useCase.CreateQuote(context.Background(), request)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -458,3 +1096,35 @@ func TestQuoteUseCase_CreateQuote_CreatedAtSet(t *testing.T) {
 		t.Error("result CreatedAt should be set")
 	}
 }
+
+func TestQuoteUseCase_CreateQuote_ValidationRules(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		CreateFunc: func(quote *domain.Quote) (*domain.Quote, error) {
+			t.Error("repository should not be called for invalid input")
+			return nil, nil
+		},
+	}
+
+	useCase := NewQuoteUseCase(mockRepo, WithValidationRules(domain.ValidationRules{
+		MaxAuthorLength: 5,
+		MaxQuoteLength:  10,
+	}))
+
+	_, err := useCase.CreateQuote(context.Background(), &domain.CreateQuoteRequest{
+		Author: "Too long author",
+		Quote:  "Too long quote text",
+	})
+
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *domain.ValidationError, got %v", err)
+	}
+	if len(verr.Errors) != 2 {
+		t.Fatalf("expected 2 field errors, got %d: %v", len(verr.Errors), verr)
+	}
+	for _, fe := range verr.Errors {
+		if fe.Code != domain.CodeTooLong {
+			t.Errorf("expected code %q for %s, got %q", domain.CodeTooLong, fe.Field, fe.Code)
+		}
+	}
+}
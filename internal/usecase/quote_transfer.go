@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// importChunkSize is the number of quotes written per import transaction
+const importChunkSize = 500
+
+// TransferStore reads the whole collection for export and writes imported quotes
+type TransferStore interface {
+	Stream(fn func(*domain.Quote) error) error
+	ImportBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error)
+}
+
+// ExportQuotes streams every quote to fn without loading the collection into memory
+func (uc *QuoteUseCase) ExportQuotes(fn func(*domain.Quote) error) error {
+	return uc.quoteRepository.Stream(fn)
+}
+
+// ImportQuotes validates and stores records, skipping quotes that already exist
+// (same author and text) in the database or earlier in the same import.
+// Malformed or invalid records are reported and skipped; any other decoding error
+// aborts the import with domain.ErrInvalidImport. Quotes are committed a chunk at a time,
+// so an aborted import keeps the chunks already written and the report returned with
+// the error counts them.
+func (uc *QuoteUseCase) ImportQuotes(ctx context.Context, records iter.Seq2[*domain.CreateQuoteRequest, error]) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{Errors: []*domain.ImportError{}}
+	seen := make(map[string]struct{})
+	pending := make([]*domain.Quote, 0, importChunkSize)
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		inserted, err := uc.quoteRepository.ImportBatch(ctx, auditEntry(ctx, domain.ActionImport), pending)
+		if err != nil {
+			return err
+		}
+		if len(inserted) > 0 {
+			uc.invalidateCache(ctx)
+		}
+		report.Imported += len(inserted)
+		report.Duplicates += len(pending) - len(inserted)
+		pending = pending[:0]
+		return nil
+	}
+
+	n := 0
+	for req, err := range records {
+		n++
+		if err != nil {
+			if !errors.Is(err, domain.ErrMalformedRecord) {
+				return report, fmt.Errorf("%w: %w", domain.ErrInvalidImport, err)
+			}
+			report.Total++
+			report.Failed++
+			report.Errors = append(report.Errors, &domain.ImportError{Record: n, Error: err.Error()})
+			continue
+		}
+
+		report.Total++
+		if err = uc.validationRules.Validate(req); err != nil {
+			report.Failed++
+			importErr := &domain.ImportError{Record: n, Error: err.Error()}
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				importErr.Error = domain.MsgValidationFailed
+				importErr.Details = verr.Errors
+			}
+			report.Errors = append(report.Errors, importErr)
+			continue
+		}
+
+		req.Normalize()
+		quote := &domain.Quote{Author: req.Author, Quote: req.Quote, Tags: req.Tags}
+		if _, ok := seen[quote.DuplicateKey()]; ok {
+			report.Duplicates++
+			continue
+		}
+		seen[quote.DuplicateKey()] = struct{}{}
+
+		pending = append(pending, quote)
+		if len(pending) == importChunkSize {
+			if err = flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
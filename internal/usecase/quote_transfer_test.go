@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+type record struct {
+	req *domain.CreateQuoteRequest
+	err error
+}
+
+func records(items ...record) iter.Seq2[*domain.CreateQuoteRequest, error] {
+	return func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		for _, item := range items {
+			if !yield(item.req, item.err) {
+				return
+			}
+		}
+	}
+}
+
+func TestQuoteUseCase_ImportQuotes(t *testing.T) {
+	var imported [][]*domain.Quote
+	mockRepo := &MockQuoteRepository{
+		ImportBatchFunc: func(quotes []*domain.Quote) ([]*domain.Quote, error) {
+			imported = append(imported, quotes)
+			// Pretend the first quote already exists in the database
+			return quotes[1:], nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	report, err := useCase.ImportQuotes(context.Background(), records(
+		record{req: &domain.CreateQuoteRequest{Author: "A", Quote: "existing"}},
+		record{req: &domain.CreateQuoteRequest{Author: " B ", Quote: "new"}},
+		record{req: &domain.CreateQuoteRequest{Author: "B", Quote: "new "}},
+		record{req: &domain.CreateQuoteRequest{Author: "", Quote: "invalid"}},
+		record{err: fmt.Errorf("record 5: %w: bad csv", domain.ErrMalformedRecord)},
+		record{req: &domain.CreateQuoteRequest{Author: "C", Quote: "another"}},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(imported) != 1 || len(imported[0]) != 3 {
+		t.Fatalf("expected one chunk of 3 quotes, got %v", imported)
+	}
+	if imported[0][1].Author != "B" || imported[0][1].Quote != "new" {
+		t.Errorf("expected normalized quote, got %+v", imported[0][1])
+	}
+
+	if report.Total != 6 || report.Imported != 2 || report.Duplicates != 2 || report.Failed != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Record != 4 || report.Errors[1].Record != 5 {
+		t.Errorf("unexpected report errors: %+v", report.Errors)
+	}
+	if len(report.Errors[0].Details) == 0 {
+		t.Error("validation failures should include field details")
+	}
+}
+
+func TestQuoteUseCase_ImportQuotes_Chunks(t *testing.T) {
+	calls := 0
+	mockRepo := &MockQuoteRepository{
+		ImportBatchFunc: func(quotes []*domain.Quote) ([]*domain.Quote, error) {
+			calls++
+			return quotes, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	seq := func(yield func(*domain.CreateQuoteRequest, error) bool) {
+		for i := 0; i < importChunkSize+1; i++ {
+			if !yield(&domain.CreateQuoteRequest{Author: "Author", Quote: fmt.Sprintf("Quote %d", i)}, nil) {
+				return
+			}
+		}
+	}
+
+	report, err := useCase.ImportQuotes(context.Background(), seq)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 repository calls, got %d", calls)
+	}
+	if report.Imported != importChunkSize+1 {
+		t.Errorf("expected %d imported, got %d", importChunkSize+1, report.Imported)
+	}
+}
+
+func TestQuoteUseCase_ImportQuotes_FatalError(t *testing.T) {
+	useCase := NewQuoteUseCase(&MockQuoteRepository{
+		ImportBatchFunc: func(quotes []*domain.Quote) ([]*domain.Quote, error) {
+			t.Error("nothing should be written")
+			return nil, nil
+		},
+	})
+
+	report, err := useCase.ImportQuotes(context.Background(), records(
+		record{req: &domain.CreateQuoteRequest{Author: "A", Quote: "Q"}},
+		record{err: errors.New("unexpected EOF")},
+	))
+	if !errors.Is(err, domain.ErrInvalidImport) {
+		t.Fatalf("expected ErrInvalidImport, got %v", err)
+	}
+	if report == nil || report.Total != 1 {
+		t.Errorf("expected partial report, got %+v", report)
+	}
+}
+
+func TestQuoteUseCase_ExportQuotes(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		StreamFunc: func(fn func(*domain.Quote) error) error {
+			for i := 1; i <= 3; i++ {
+				if err := fn(&domain.Quote{ID: i}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	stop := errors.New("stop")
+	count := 0
+	err := useCase.ExportQuotes(func(quote *domain.Quote) error {
+		count++
+		if quote.ID == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || count != 2 {
+		t.Errorf("expected export to stop after 2 quotes, got count=%d err=%v", count, err)
+	}
+}
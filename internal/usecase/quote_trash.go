@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// PurgerActor is recorded as the actor of quotes removed by the trash purger
+const PurgerActor = "purger"
+
+// TrashStore lists, restores and removes soft-deleted quotes
+type TrashStore interface {
+	ListDeleted() ([]*domain.Quote, error)
+	GetByIDWithDeleted(id int) (*domain.Quote, error)
+	Undelete(ctx context.Context, entry domain.AuditEntry, id int) (*domain.Quote, error)
+	HardDelete(ctx context.Context, entry domain.AuditEntry, id, version int) error
+	PurgeDeleted(ctx context.Context, entry domain.AuditEntry, before time.Time) (int64, error)
+}
+
+// requireAdmin returns domain.ErrForbidden unless ctx carries an admin principal
+func requireAdmin(ctx context.Context) error {
+	if !domain.PrincipalFromContext(ctx).IsAdmin() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// GetTrash returns the deleted quotes that have not been purged yet. Only admins may do this.
+func (uc *QuoteUseCase) GetTrash(ctx context.Context) ([]*domain.Quote, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return uc.quoteRepository.ListDeleted()
+}
+
+// GetQuoteWithDeleted returns a quote whether it is live or in the trash, as a hard delete
+// or the restore of a revision may target either. Only admins may do this.
+func (uc *QuoteUseCase) GetQuoteWithDeleted(ctx context.Context, id int) (*domain.Quote, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+	return uc.quoteRepository.GetByIDWithDeleted(id)
+}
+
+// RestoreQuote takes a deleted quote out of the trash. Only admins may do this.
+func (uc *QuoteUseCase) RestoreQuote(ctx context.Context, id int) (*domain.Quote, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+
+	quote, err := uc.quoteRepository.Undelete(ctx, auditEntry(ctx, domain.ActionQuoteRestore), id)
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return quote, err
+}
+
+// HardDeleteQuote removes a quote for good, bypassing the trash. Only admins may do this.
+func (uc *QuoteUseCase) HardDeleteQuote(ctx context.Context, id, version int) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return domain.ErrInvalidID
+	}
+	if version < 0 {
+		return domain.ErrInvalidVersion
+	}
+
+	err := uc.quoteRepository.HardDelete(ctx, auditEntry(ctx, domain.ActionQuoteHardDelete), id, version)
+	if err == nil {
+		uc.invalidateCache(ctx)
+	}
+	return err
+}
+
+// PurgeTrash removes the quotes that have been in the trash for longer than retention
+func (uc *QuoteUseCase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	entry := auditEntry(ctx, domain.ActionTrashPurge)
+	entry.Actor = PurgerActor
+	return uc.quoteRepository.PurgeDeleted(ctx, entry, time.Now().Add(-retention))
+}
+
+// RunTrashPurger calls PurgeTrash every interval until ctx is done
+func (uc *QuoteUseCase) RunTrashPurger(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := uc.PurgeTrash(ctx, retention)
+			if err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d quotes from the trash", purged)
+			}
+		}
+	}
+}
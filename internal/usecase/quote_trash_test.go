@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_RestoreQuote(t *testing.T) {
+	trashed := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo := &MockQuoteRepository{
+		UndeleteFunc: func(id int) (*domain.Quote, error) {
+			if id != 1 {
+				return nil, domain.ErrNotInTrash
+			}
+			return &domain.Quote{ID: 1, Version: 3}, nil
+		},
+		ListDeletedFunc: func() ([]*domain.Quote, error) {
+			return []*domain.Quote{{ID: 1, DeletedAt: &trashed}}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+	admin := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "root", Role: domain.RoleAdmin})
+	user := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "bob", Role: domain.RoleUser})
+
+	trash, err := useCase.GetTrash(admin)
+	if err != nil || len(trash) != 1 || !trash[0].DeletedAt.Equal(trashed) {
+		t.Fatalf("expected the trashed quote, got %v, %v", trash, err)
+	}
+
+	quote, err := useCase.RestoreQuote(admin, 1)
+	if err != nil || quote.ID != 1 {
+		t.Fatalf("expected the restored quote, got %v, %v", quote, err)
+	}
+
+	if _, err = useCase.RestoreQuote(admin, 2); !errors.Is(err, domain.ErrNotInTrash) {
+		t.Errorf("expected ErrNotInTrash, got %v", err)
+	}
+	if _, err = useCase.RestoreQuote(admin, 0); !errors.Is(err, domain.ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+
+	// The trash is for admins only
+	for _, ctx := range []context.Context{context.Background(), user} {
+		if _, err = useCase.GetTrash(ctx); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected GetTrash to be forbidden, got %v", err)
+		}
+		if _, err = useCase.RestoreQuote(ctx, 1); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected RestoreQuote to be forbidden, got %v", err)
+		}
+	}
+}
+
+func TestQuoteUseCase_HardDeleteQuote(t *testing.T) {
+	admin := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "root", Role: domain.RoleAdmin})
+	user := domain.WithPrincipal(context.Background(), &domain.Principal{Name: "bob", Role: domain.RoleUser})
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		id            int
+		version       int
+		expectedError error
+	}{
+		{name: "admin", ctx: admin, id: 1},
+		{name: "admin conditional", ctx: admin, id: 1, version: 4},
+		{name: "anonymous", ctx: context.Background(), id: 1, expectedError: domain.ErrForbidden},
+		{name: "not an admin", ctx: user, id: 1, expectedError: domain.ErrForbidden},
+		{name: "invalid ID", ctx: admin, id: 0, expectedError: domain.ErrInvalidID},
+		{name: "invalid version", ctx: admin, id: 1, version: -1, expectedError: domain.ErrInvalidVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mockRepo := &MockQuoteRepository{
+				HardDeleteFunc: func(id, version int) error {
+					called = true
+					if id != tt.id || version != tt.version {
+						t.Errorf("expected HardDelete(%d, %d), got HardDelete(%d, %d)", tt.id, tt.version, id, version)
+					}
+					return nil
+				},
+			}
+			useCase := NewQuoteUseCase(mockRepo)
+
+			err := useCase.HardDeleteQuote(tt.ctx, tt.id, tt.version)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				if called {
+					t.Error("repository must not be called when the request is rejected")
+				}
+				return
+			}
+			if err != nil || !called {
+				t.Errorf("expected the quote to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_PurgeTrash(t *testing.T) {
+	var gotBefore time.Time
+	var gotActor string
+	var gotAction domain.AuditAction
+	mockRepo := &MockQuoteRepository{
+		PurgeDeletedFunc: func(entry domain.AuditEntry, before time.Time) (int64, error) {
+			gotBefore, gotActor, gotAction = before, entry.Actor, entry.Action
+			return 3, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	start := time.Now()
+	purged, err := useCase.PurgeTrash(context.Background(), 24*time.Hour)
+	if err != nil || purged != 3 {
+		t.Fatalf("expected 3 purged quotes, got %d, %v", purged, err)
+	}
+	if cutoff := start.Add(-24 * time.Hour); gotBefore.Before(cutoff) || gotBefore.After(time.Now().Add(-24*time.Hour)) {
+		t.Errorf("expected a cutoff 24h ago, got %v", gotBefore)
+	}
+	if gotActor != PurgerActor {
+		t.Errorf("expected purges to be attributed to %q, got %q", PurgerActor, gotActor)
+	}
+	if gotAction != domain.ActionTrashPurge {
+		t.Errorf("expected purges to be audited as %q, got %q", domain.ActionTrashPurge, gotAction)
+	}
+}
+
+func TestQuoteUseCase_RunTrashPurger(t *testing.T) {
+	purges := make(chan struct{}, 10)
+	mockRepo := &MockQuoteRepository{
+		PurgeDeletedFunc: func(entry domain.AuditEntry, before time.Time) (int64, error) {
+			purges <- struct{}{}
+			return 0, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		useCase.RunTrashPurger(ctx, time.Millisecond, time.Hour)
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-purges:
+		case <-time.After(time.Second):
+			t.Fatal("expected the purger to run periodically")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the purger to stop when the context is cancelled")
+	}
+}
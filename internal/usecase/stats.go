@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// StatsStore computes the collection statistics and refreshes their materialized views
+type StatsStore interface {
+	Stats(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error)
+	RefreshStats(ctx context.Context) (bool, error)
+}
+
+// WithStatsPolicy overrides domain.DefaultStatsPolicy
+func WithStatsPolicy(policy domain.StatsPolicy) Option {
+	return func(uc *QuoteUseCase) {
+		uc.statsPolicy = policy
+	}
+}
+
+// GetStats returns the collection statistics. They are cached like the other hot reads,
+// so AsOf tells how fresh they are.
+func (uc *QuoteUseCase) GetStats() (*domain.CollectionStats, error) {
+	return cached(uc, "stats", "", func() (*domain.CollectionStats, error) {
+		return uc.quoteRepository.Stats(uc.statsPolicy, domain.StopWords())
+	})
+}
+
+// RefreshStats brings the materialized statistics up to date. It does nothing unless
+// the policy is materialized, or while another replica is refreshing them.
+func (uc *QuoteUseCase) RefreshStats(ctx context.Context) error {
+	if !uc.statsPolicy.Materialized {
+		return nil
+	}
+	refreshed, err := uc.quoteRepository.RefreshStats(ctx)
+	if err == nil && refreshed {
+		uc.invalidateCache(ctx)
+	}
+	return err
+}
+
+// RunStatsRefresher calls RefreshStats at once, so the views do not start as old as the
+// last run of the service, then every interval until ctx is done. A zero interval only
+// refreshes at start.
+func (uc *QuoteUseCase) RunStatsRefresher(ctx context.Context, interval time.Duration) {
+	if err := uc.RefreshStats(ctx); err != nil {
+		log.Printf("Failed to refresh stats: %v", err)
+	}
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.RefreshStats(ctx); err != nil {
+				log.Printf("Failed to refresh stats: %v", err)
+			}
+		}
+	}
+}
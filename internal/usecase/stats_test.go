@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/cache"
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestQuoteUseCase_GetStats(t *testing.T) {
+	asOf := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var gotPolicy domain.StatsPolicy
+	var gotStopWords []string
+	var loads atomic.Int32
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		StatsFunc: func(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error) {
+			loads.Add(1)
+			gotPolicy, gotStopWords = policy, stopWords
+			return &domain.CollectionStats{AsOf: asOf, TotalQuotes: 3, TotalAuthors: 2}, nil
+		},
+	}, WithStatsPolicy(domain.StatsPolicy{Days: 7, TopAuthors: 3, TopWords: 5}), WithCache(cache.NewMemory(10), time.Minute))
+
+	stats, err := uc.GetStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalQuotes != 3 || !stats.AsOf.Equal(asOf) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if gotPolicy != (domain.StatsPolicy{Days: 7, TopAuthors: 3, TopWords: 5}) {
+		t.Errorf("expected the configured policy, got %+v", gotPolicy)
+	}
+	if !slices.Contains(gotStopWords, "the") || !slices.Contains(gotStopWords, "что") {
+		t.Errorf("expected English and Russian stop words, got %d words", len(gotStopWords))
+	}
+
+	// A cached copy keeps the time it was computed at
+	stats, _ = uc.GetStats()
+	if loads.Load() != 1 || !stats.AsOf.Equal(asOf) {
+		t.Errorf("expected the stats to be cached as of %s, got %d loads and %s", asOf, loads.Load(), stats.AsOf)
+	}
+}
+
+func TestQuoteUseCase_RefreshStats(t *testing.T) {
+	ctx := context.Background()
+	var refreshes, loads atomic.Int32
+	refreshed := true
+	var refreshErr error
+	repo := &MockQuoteRepository{
+		StatsFunc: func(policy domain.StatsPolicy, stopWords []string) (*domain.CollectionStats, error) {
+			loads.Add(1)
+			return &domain.CollectionStats{}, nil
+		},
+		RefreshStatsFunc: func() (bool, error) {
+			refreshes.Add(1)
+			return refreshed, refreshErr
+		},
+	}
+
+	livePolicy := domain.DefaultStatsPolicy
+	livePolicy.Materialized = false
+	live := NewQuoteUseCase(repo, WithStatsPolicy(livePolicy))
+	if err := live.RefreshStats(ctx); err != nil || refreshes.Load() != 0 {
+		t.Errorf("expected live stats not to be refreshed, got %d refreshes (%v)", refreshes.Load(), err)
+	}
+
+	uc := NewQuoteUseCase(repo, WithCache(cache.NewMemory(10), time.Minute))
+
+	uc.GetStats()
+	if err := uc.RefreshStats(ctx); err != nil || refreshes.Load() != 1 {
+		t.Fatalf("expected one refresh, got %d (%v)", refreshes.Load(), err)
+	}
+	uc.GetStats()
+	if loads.Load() != 2 {
+		t.Errorf("expected the refresh to invalidate the cached stats, got %d loads", loads.Load())
+	}
+
+	// Another replica is refreshing, so the cached stats stay
+	refreshed = false
+	uc.RefreshStats(ctx)
+	uc.GetStats()
+	if loads.Load() != 2 {
+		t.Errorf("expected a skipped refresh to keep the cache, got %d loads", loads.Load())
+	}
+
+	refreshErr = errors.New("database error")
+	if err := uc.RefreshStats(ctx); err == nil {
+		t.Error("expected the refresh error")
+	}
+}
+
+func TestQuoteUseCase_RunStatsRefresher(t *testing.T) {
+	var refreshes atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	uc := NewQuoteUseCase(&MockQuoteRepository{
+		RefreshStatsFunc: func() (bool, error) {
+			refreshes.Add(1)
+			cancel()
+			return true, nil
+		},
+	})
+
+	// The first refresh does not wait for the interval
+	done := make(chan struct{})
+	go func() {
+		uc.RunStatsRefresher(ctx, time.Hour)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the refresher to stop once ctx is done")
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("expected one refresh at start, got %d", refreshes.Load())
+	}
+
+	// Without an interval the views are only refreshed at start
+	uc.RunStatsRefresher(context.Background(), 0)
+	if refreshes.Load() != 2 {
+		t.Errorf("expected a refresh at start without an interval, got %d", refreshes.Load())
+	}
+}
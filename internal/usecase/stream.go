@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+const (
+	// streamPageSize is the number of logged events read per query
+	streamPageSize = 500
+	// streamBufferSize is the number of live events held for a stream that is not keeping up.
+	// A stream falling further behind is ended; its client resumes from the change log.
+	streamBufferSize = 256
+)
+
+// EventStore numbers the outbox events in commit order and reads them back as a change log
+type EventStore interface {
+	SequenceEvents(ctx context.Context) (int64, error)
+	ListEvents(afterID int64, limit int) ([]*domain.WebhookEvent, error)
+	LatestEventID() (int64, error)
+}
+
+// eventHub fans the events read by RunEventStream out to the open streams of this replica
+type eventHub struct {
+	mu   sync.Mutex
+	subs map[chan *domain.WebhookEvent]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan *domain.WebhookEvent]struct{})}
+}
+
+func (h *eventHub) subscribe() chan *domain.WebhookEvent {
+	ch := make(chan *domain.WebhookEvent, streamBufferSize)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan *domain.WebhookEvent) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
+
+// publish hands events to every subscriber without blocking. A subscriber whose buffer is
+// full is dropped and its channel closed.
+func (h *eventHub) publish(events []*domain.WebhookEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		for _, e := range events {
+			select {
+			case ch <- e:
+				continue
+			default:
+			}
+			delete(h.subs, ch)
+			close(ch)
+			break
+		}
+	}
+}
+
+// StreamEvents streams the quote events matching filter. Events logged after lastEventID are
+// replayed first; a zero lastEventID starts the stream with the next change. The channel is
+// closed when ctx is done, when the change log cannot be read or when the receiver falls too
+// far behind, after which the client resumes from the last event it received.
+func (uc *QuoteUseCase) StreamEvents(ctx context.Context, lastEventID int64, filter domain.StreamFilter) (<-chan *domain.WebhookEvent, error) {
+	if lastEventID < 0 {
+		return nil, domain.ErrInvalidEventID
+	}
+	filter.Author = strings.TrimSpace(filter.Author)
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+
+	// Subscribing before reading the log means no event can fall between the replay and the
+	// live events; those already replayed are skipped by ID
+	live := uc.events.subscribe()
+	cursor := lastEventID
+	if cursor == 0 {
+		latest, err := uc.quoteRepository.LatestEventID()
+		if err != nil {
+			uc.events.unsubscribe(live)
+			return nil, err
+		}
+		cursor = latest
+	}
+
+	out := make(chan *domain.WebhookEvent)
+	go func() {
+		defer close(out)
+		defer uc.events.unsubscribe(live)
+
+		send := func(e *domain.WebhookEvent) bool {
+			cursor = e.ID
+			if !filter.Match(e) {
+				return true
+			}
+			select {
+			case out <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			events, err := uc.quoteRepository.ListEvents(cursor, streamPageSize)
+			if err != nil {
+				log.Printf("Failed to replay quote events after %d: %v", cursor, err)
+				return
+			}
+			for _, e := range events {
+				if !send(e) {
+					return
+				}
+			}
+			if len(events) < streamPageSize {
+				break
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-live:
+				if !ok {
+					return
+				}
+				if e.ID <= cursor {
+					continue
+				}
+				if !send(e) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// RunEventStream reads new events from the change log and publishes them to the open streams
+// whenever wake fires, and at least every interval in case a wake-up was lost, until ctx is
+// done. A nil wake or zero interval disables that trigger.
+func (uc *QuoteUseCase) RunEventStream(ctx context.Context, wake <-chan struct{}, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	cursor, err := uc.quoteRepository.LatestEventID()
+	if err != nil {
+		log.Printf("Failed to start the event stream: %v", err)
+		cursor = -1
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-tick:
+		}
+
+		if cursor < 0 {
+			if cursor, err = uc.quoteRepository.LatestEventID(); err != nil {
+				log.Printf("Failed to start the event stream: %v", err)
+				cursor = -1
+			}
+			continue
+		}
+		// Only one replica numbers new events at a time; it announces them, waking the rest again
+		if _, err = uc.quoteRepository.SequenceEvents(ctx); err != nil {
+			log.Printf("Failed to number quote events: %v", err)
+		}
+		if cursor, err = uc.publishEvents(cursor); err != nil {
+			log.Printf("Failed to read quote events: %v", err)
+		}
+	}
+}
+
+// publishEvents publishes the logged events after cursor and returns the ID of the last one
+func (uc *QuoteUseCase) publishEvents(cursor int64) (int64, error) {
+	for {
+		events, err := uc.quoteRepository.ListEvents(cursor, streamPageSize)
+		if err != nil {
+			return cursor, err
+		}
+		if len(events) > 0 {
+			uc.events.publish(events)
+			cursor = events[len(events)-1].ID
+		}
+		if len(events) < streamPageSize {
+			return cursor, nil
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// eventLog is an in-memory change log behind MockQuoteRepository.ListEvents
+type eventLog struct {
+	mu     sync.Mutex
+	events []*domain.WebhookEvent
+}
+
+func (l *eventLog) append(author string, tags ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := int64(len(l.events) + 1)
+	data, _ := json.Marshal(domain.Quote{ID: int(id), Author: author, Quote: "text", Tags: tags})
+	l.events = append(l.events, &domain.WebhookEvent{ID: id, Type: domain.EventQuoteCreated, Data: data})
+}
+
+func (l *eventLog) list(afterID int64, limit int) ([]*domain.WebhookEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := []*domain.WebhookEvent{}
+	for _, e := range l.events {
+		if e.ID > afterID && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (l *eventLog) latest() (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(len(l.events)), nil
+}
+
+func receive(t *testing.T, events <-chan *domain.WebhookEvent) *domain.WebhookEvent {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("stream closed unexpectedly")
+		}
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return nil
+}
+
+func TestQuoteUseCase_StreamEvents(t *testing.T) {
+	changes := &eventLog{}
+	changes.append("Seneca")
+	changes.append("Confucius")
+	changes.append("Seneca", "stoic")
+
+	started := make(chan struct{})
+	var once sync.Once
+	mockRepo := &MockQuoteRepository{
+		ListEventsFunc: changes.list,
+		LatestEventIDFunc: func() (int64, error) {
+			defer once.Do(func() { close(started) })
+			return changes.latest()
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wake := make(chan struct{}, 1)
+	go useCase.RunEventStream(ctx, wake, 0)
+	<-started
+
+	resumed, err := useCase.StreamEvents(ctx, 1, domain.StreamFilter{Author: " Seneca "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fresh, err := useCase.StreamEvents(ctx, 0, domain.StreamFilter{Tag: "Stoic"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Events after Last-Event-ID are replayed from the log
+	if e := receive(t, resumed); e.ID != 3 {
+		t.Errorf("expected event 3 to be replayed, got %d", e.ID)
+	}
+
+	changes.append("Confucius", "stoic")
+	changes.append("Seneca")
+	wake <- struct{}{}
+
+	if e := receive(t, resumed); e.ID != 5 {
+		t.Errorf("expected live event 5 for the author, got %d", e.ID)
+	}
+	// A stream without Last-Event-ID only sees new events
+	if e := receive(t, fresh); e.ID != 4 {
+		t.Errorf("expected live event 4 for the tag, got %d", e.ID)
+	}
+
+	cancel()
+	for range resumed {
+	}
+	for range fresh {
+	}
+}
+
+func TestQuoteUseCase_StreamEvents_Errors(t *testing.T) {
+	failure := errors.New("connection refused")
+	useCase := NewQuoteUseCase(&MockQuoteRepository{
+		LatestEventIDFunc: func() (int64, error) {
+			return 0, failure
+		},
+	})
+
+	if _, err := useCase.StreamEvents(context.Background(), -1, domain.StreamFilter{}); !errors.Is(err, domain.ErrInvalidEventID) {
+		t.Errorf("expected ErrInvalidEventID, got %v", err)
+	}
+	if _, err := useCase.StreamEvents(context.Background(), 0, domain.StreamFilter{}); !errors.Is(err, failure) {
+		t.Errorf("expected the repository error, got %v", err)
+	}
+	if len(useCase.events.subs) != 0 {
+		t.Errorf("expected a failed stream to unsubscribe, %d left", len(useCase.events.subs))
+	}
+}
+
+func TestEventHub_DropsSlowSubscribers(t *testing.T) {
+	hub := newEventHub()
+	slow := hub.subscribe()
+
+	events := make([]*domain.WebhookEvent, streamBufferSize+1)
+	for i := range events {
+		events[i] = &domain.WebhookEvent{ID: int64(i + 1)}
+	}
+	hub.publish(events)
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != streamBufferSize {
+		t.Errorf("expected the %d buffered events before the channel closed, got %d", streamBufferSize, received)
+	}
+	if len(hub.subs) != 0 {
+		t.Errorf("expected the slow subscriber to be dropped")
+	}
+	// Unsubscribing after being dropped is harmless
+	hub.unsubscribe(slow)
+}
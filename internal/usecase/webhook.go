@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+const (
+	// outboxBatchSize is the number of outbox events fanned out to deliveries per round
+	outboxBatchSize = 500
+	// maxDeliveryError bounds the error text stored with a failed attempt
+	maxDeliveryError = 500
+	// DeliveryListLimit is the number of deliveries listed per subscription
+	DeliveryListLimit = 100
+)
+
+// WebhookStore persists webhook subscriptions and their deliveries, which are fanned out
+// from the outbox
+type WebhookStore interface {
+	ListWebhooks() ([]*domain.WebhookSubscription, error)
+	GetWebhook(id int) (*domain.WebhookSubscription, error)
+	CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	ListDeliveries(subscriptionID int, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error)
+	FanOutEvents(ctx context.Context, limit int) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, attempt *domain.DeliveryAttempt) error
+	PruneOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookSender delivers a webhook and returns the response status code.
+// Statuses outside 2xx are reported as errors.
+type WebhookSender interface {
+	Send(ctx context.Context, d *domain.WebhookDelivery) (int, error)
+}
+
+// WithWebhooks enables webhook dispatch through sender, retried according to policy
+func WithWebhooks(sender WebhookSender, policy domain.WebhookPolicy) Option {
+	return func(uc *QuoteUseCase) {
+		uc.webhookSender = sender
+		uc.webhookPolicy = policy
+	}
+}
+
+func newWebhookSecret() string {
+	var b [32]byte
+	rand.Read(b[:])
+	return "whsec_" + hex.EncodeToString(b[:])
+}
+
+// GetWebhooks returns every webhook subscription without its secret
+func (uc *QuoteUseCase) GetWebhooks(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	subs, err := uc.quoteRepository.ListWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+// GetWebhook returns a webhook subscription without its secret
+func (uc *QuoteUseCase) GetWebhook(ctx context.Context, id int) (*domain.WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	sub, err := uc.quoteRepository.GetWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// CreateWebhook registers a subscription. The response carries the signing secret,
+// generated unless the request sets one; it is not returned again.
+func (uc *QuoteUseCase) CreateWebhook(ctx context.Context, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	sub := webhookFromRequest(req)
+	if sub.Secret == "" {
+		sub.Secret = newWebhookSecret()
+	}
+	return uc.quoteRepository.CreateWebhook(ctx, sub)
+}
+
+// UpdateWebhook replaces a subscription. Its secret is only rotated, and returned,
+// when the request sets a new one.
+func (uc *QuoteUseCase) UpdateWebhook(ctx context.Context, id int, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	sub := webhookFromRequest(req)
+	sub.ID = id
+	updated, err := uc.quoteRepository.UpdateWebhook(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	if req.Secret == "" {
+		updated.Secret = ""
+	}
+	return updated, nil
+}
+
+func webhookFromRequest(req *domain.WebhookSubscriptionRequest) *domain.WebhookSubscription {
+	sub := &domain.WebhookSubscription{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: true,
+	}
+	if sub.Events == nil {
+		sub.Events = []domain.EventType{}
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	return sub
+}
+
+// DeleteWebhook removes a subscription and its deliveries
+func (uc *QuoteUseCase) DeleteWebhook(ctx context.Context, id int) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return uc.quoteRepository.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns the latest deliveries of a subscription, optionally only those with status
+func (uc *QuoteUseCase) GetDeliveries(ctx context.Context, subscriptionID int, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	if _, err := uc.GetWebhook(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	switch status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", domain.ErrInvalidWebhook, status)
+	}
+
+	return uc.quoteRepository.ListDeliveries(subscriptionID, status, DeliveryListLimit)
+}
+
+// RedeliverWebhook queues a delivery again with a fresh set of attempts
+func (uc *QuoteUseCase) RedeliverWebhook(ctx context.Context, subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if subscriptionID <= 0 || deliveryID <= 0 {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	return uc.quoteRepository.Redeliver(ctx, subscriptionID, deliveryID)
+}
+
+// DispatchWebhooks fans new outbox events out to the subscriptions and sends one batch of
+// due deliveries concurrently. Failed deliveries are retried with exponential backoff until
+// the policy's attempts run out, then marked dead. It returns the number delivered.
+func (uc *QuoteUseCase) DispatchWebhooks(ctx context.Context) (int, error) {
+	if uc.webhookSender == nil {
+		return 0, nil
+	}
+
+	if _, err := uc.quoteRepository.FanOutEvents(ctx, outboxBatchSize); err != nil {
+		return 0, err
+	}
+
+	deliveries, err := uc.quoteRepository.ClaimDeliveries(ctx, uc.webhookPolicy.BatchSize, uc.webhookPolicy.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered int
+	)
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			code, err := uc.webhookSender.Send(ctx, d)
+			attempt := uc.deliveryAttempt(d, code, err, time.Now())
+			if err := uc.quoteRepository.RecordDeliveryAttempt(ctx, attempt); err != nil {
+				// The lease ran out or runs out, and the delivery is attempted again
+				log.Printf("Failed to record webhook delivery %d: %v", d.ID, err)
+				return
+			}
+			if attempt.Status == domain.DeliveryDelivered {
+				mu.Lock()
+				delivered++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return delivered, nil
+}
+
+// deliveryAttempt decides what follows an attempt to deliver d that ended with code and err
+func (uc *QuoteUseCase) deliveryAttempt(d *domain.WebhookDelivery, code int, err error, now time.Time) *domain.DeliveryAttempt {
+	attempt := &domain.DeliveryAttempt{
+		DeliveryID:    d.ID,
+		LeaseOwner:    d.LeaseOwner,
+		Status:        domain.DeliveryDelivered,
+		StatusCode:    code,
+		NextAttemptAt: now,
+	}
+	if err == nil {
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	if len(attempt.Error) > maxDeliveryError {
+		attempt.Error = attempt.Error[:maxDeliveryError]
+	}
+
+	attempts := d.Attempts + 1
+	if attempts >= uc.webhookPolicy.MaxAttempts {
+		attempt.Status = domain.DeliveryDead
+		return attempt
+	}
+	attempt.Status = domain.DeliveryPending
+	attempt.NextAttemptAt = now.Add(uc.webhookPolicy.Backoff(attempts))
+	return attempt
+}
+
+// RunWebhookDispatcher calls DispatchWebhooks every interval until ctx is done
+func (uc *QuoteUseCase) RunWebhookDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.DispatchWebhooks(ctx); err != nil {
+				log.Printf("Failed to dispatch webhooks: %v", err)
+			}
+		}
+	}
+}
+
+// PruneOutbox removes the logged events older than retention whose deliveries are finished.
+// Streams can no longer replay them.
+func (uc *QuoteUseCase) PruneOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	return uc.quoteRepository.PruneOutbox(ctx, time.Now().Add(-retention))
+}
+
+// RunOutboxPruner calls PruneOutbox every interval until ctx is done
+func (uc *QuoteUseCase) RunOutboxPruner(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := uc.PruneOutbox(ctx, retention)
+			if err != nil {
+				log.Printf("Failed to prune the outbox: %v", err)
+			} else if pruned > 0 {
+				log.Printf("Pruned %d events from the outbox", pruned)
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+type senderFunc func(ctx context.Context, d *domain.WebhookDelivery) (int, error)
+
+func (f senderFunc) Send(ctx context.Context, d *domain.WebhookDelivery) (int, error) {
+	return f(ctx, d)
+}
+
+func TestQuoteUseCase_CreateWebhook(t *testing.T) {
+	var stored *domain.WebhookSubscription
+	mockRepo := &MockQuoteRepository{
+		CreateWebhookFunc: func(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+			stored = sub
+			created := *sub
+			created.ID = 1
+			return &created, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	sub, err := useCase.CreateWebhook(adminCtx, &domain.WebhookSubscriptionRequest{URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(sub.Secret, "whsec_") || stored.Secret != sub.Secret {
+		t.Errorf("expected a generated secret to be stored and returned, got %q", sub.Secret)
+	}
+	if !sub.Active || sub.Events == nil || len(sub.Events) != 0 {
+		t.Errorf("expected an active subscription to every event, got %+v", sub)
+	}
+
+	inactive := false
+	sub, err = useCase.CreateWebhook(adminCtx, &domain.WebhookSubscriptionRequest{
+		URL: "http://hooks.internal/quotes", Secret: "mine", Events: []domain.EventType{domain.EventQuoteDeleted}, Active: &inactive,
+	})
+	if err != nil || sub.Secret != "mine" || sub.Active {
+		t.Errorf("expected the given secret and an inactive subscription, got %+v (%v)", sub, err)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		req           *domain.WebhookSubscriptionRequest
+		expectedError error
+	}{
+		{name: "anonymous", ctx: context.Background(), req: &domain.WebhookSubscriptionRequest{URL: "https://example.com"}, expectedError: domain.ErrForbidden},
+		{name: "relative URL", ctx: adminCtx, req: &domain.WebhookSubscriptionRequest{URL: "/hook"}, expectedError: domain.ErrInvalidWebhook},
+		{name: "other scheme", ctx: adminCtx, req: &domain.WebhookSubscriptionRequest{URL: "ftp://example.com"}, expectedError: domain.ErrInvalidWebhook},
+		{
+			name: "unknown event", ctx: adminCtx,
+			req:           &domain.WebhookSubscriptionRequest{URL: "https://example.com", Events: []domain.EventType{"quote.liked"}},
+			expectedError: domain.ErrInvalidWebhook,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := useCase.CreateWebhook(tt.ctx, tt.req); !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestQuoteUseCase_GetWebhooks_HidesSecrets(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		ListWebhooksFunc: func() ([]*domain.WebhookSubscription, error) {
+			return []*domain.WebhookSubscription{{ID: 1, URL: "https://example.com", Secret: "whsec_1"}}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	subs, err := useCase.GetWebhooks(adminCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs) != 1 || subs[0].Secret != "" {
+		t.Errorf("expected the secret to be hidden, got %+v", subs)
+	}
+}
+
+func TestQuoteUseCase_UpdateWebhook(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		UpdateWebhookFunc: func(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+			updated := *sub
+			if updated.Secret == "" {
+				updated.Secret = "whsec_kept"
+			}
+			return &updated, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	sub, err := useCase.UpdateWebhook(adminCtx, 3, &domain.WebhookSubscriptionRequest{URL: "https://example.com/new"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.ID != 3 || sub.Secret != "" {
+		t.Errorf("expected subscription 3 without its kept secret, got %+v", sub)
+	}
+
+	sub, err = useCase.UpdateWebhook(adminCtx, 3, &domain.WebhookSubscriptionRequest{URL: "https://example.com/new", Secret: "rotated"})
+	if err != nil || sub.Secret != "rotated" {
+		t.Errorf("expected the rotated secret to be returned, got %+v (%v)", sub, err)
+	}
+}
+
+func TestQuoteUseCase_GetDeliveries(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		GetWebhookFunc: func(id int) (*domain.WebhookSubscription, error) {
+			if id != 1 {
+				return nil, domain.ErrWebhookNotFound
+			}
+			return &domain.WebhookSubscription{ID: 1}, nil
+		},
+		ListDeliveriesFunc: func(subscriptionID int, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+			return []*domain.WebhookDelivery{{ID: 5, SubscriptionID: subscriptionID, Status: status}}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	deliveries, err := useCase.GetDeliveries(adminCtx, 1, domain.DeliveryDead)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Status != domain.DeliveryDead {
+		t.Errorf("expected the dead deliveries, got %+v (%v)", deliveries, err)
+	}
+	if _, err = useCase.GetDeliveries(adminCtx, 2, ""); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if _, err = useCase.GetDeliveries(adminCtx, 1, "lost"); !errors.Is(err, domain.ErrInvalidWebhook) {
+		t.Errorf("expected ErrInvalidWebhook, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_DispatchWebhooks(t *testing.T) {
+	policy := domain.WebhookPolicy{MaxAttempts: 3, BackoffBase: time.Minute, BackoffMax: time.Hour, BatchSize: 10, Lease: time.Minute}
+
+	var mu sync.Mutex
+	attempts := map[int64]*domain.DeliveryAttempt{}
+	fannedOut := false
+	mockRepo := &MockQuoteRepository{
+		FanOutEventsFunc: func(limit int) (int64, error) {
+			fannedOut = true
+			return 2, nil
+		},
+		ClaimDeliveriesFunc: func(limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+			if !fannedOut {
+				t.Error("expected events to be fanned out before deliveries are claimed")
+			}
+			if limit != policy.BatchSize || lease != policy.Lease {
+				t.Errorf("expected claim(%d, %v), got claim(%d, %v)", policy.BatchSize, policy.Lease, limit, lease)
+			}
+			return []*domain.WebhookDelivery{
+				{ID: 1, URL: "https://ok.example.com", LeaseOwner: "claim-1"},
+				{ID: 2, URL: "https://down.example.com", Attempts: 0},
+				{ID: 3, URL: "https://down.example.com", Attempts: 2},
+			}, nil
+		},
+		RecordDeliveryAttemptFunc: func(attempt *domain.DeliveryAttempt) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts[attempt.DeliveryID] = attempt
+			return nil
+		},
+	}
+	sender := senderFunc(func(ctx context.Context, d *domain.WebhookDelivery) (int, error) {
+		if strings.Contains(d.URL, "down") {
+			return 503, errors.New("unexpected status 503")
+		}
+		return 200, nil
+	})
+	useCase := NewQuoteUseCase(mockRepo, WithWebhooks(sender, policy))
+
+	start := time.Now()
+	delivered, err := useCase.DispatchWebhooks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("expected 1 delivered, got %d", delivered)
+	}
+
+	if a := attempts[1]; a == nil || a.Status != domain.DeliveryDelivered || a.StatusCode != 200 || a.LeaseOwner != "claim-1" {
+		t.Errorf("expected delivery 1 to be delivered, got %+v", a)
+	}
+	retry := attempts[2]
+	if retry == nil || retry.Status != domain.DeliveryPending || retry.StatusCode != 503 || retry.Error == "" {
+		t.Fatalf("expected delivery 2 to be retried, got %+v", retry)
+	}
+	if wait := retry.NextAttemptAt.Sub(start); wait < time.Minute || wait > time.Minute+time.Second {
+		t.Errorf("expected a retry after the base backoff, got %v", wait)
+	}
+	if a := attempts[3]; a == nil || a.Status != domain.DeliveryDead {
+		t.Errorf("expected delivery 3 to be dead after its last attempt, got %+v", a)
+	}
+}
+
+func TestWebhookPolicy_Backoff(t *testing.T) {
+	policy := domain.WebhookPolicy{BackoffBase: 30 * time.Second, BackoffMax: 5 * time.Minute}
+
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, expected := range want {
+		if got := policy.Backoff(i + 1); got != expected {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+	if got := policy.Backoff(1000); got != 5*time.Minute {
+		t.Errorf("expected the backoff to stay capped, got %v", got)
+	}
+}
+
+func TestQuoteUseCase_RedeliverWebhook(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		RedeliverFunc: func(subscriptionID int, deliveryID int64) (*domain.WebhookDelivery, error) {
+			if subscriptionID != 1 || deliveryID != 9 {
+				return nil, domain.ErrDeliveryNotFound
+			}
+			return &domain.WebhookDelivery{ID: 9, SubscriptionID: 1, Status: domain.DeliveryPending}, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	if d, err := useCase.RedeliverWebhook(adminCtx, 1, 9); err != nil || d.Status != domain.DeliveryPending {
+		t.Errorf("expected a pending delivery, got %+v (%v)", d, err)
+	}
+	if _, err := useCase.RedeliverWebhook(adminCtx, 2, 9); !errors.Is(err, domain.ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+	if _, err := useCase.RedeliverWebhook(context.Background(), 1, 9); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestQuoteUseCase_PruneOutbox(t *testing.T) {
+	var gotBefore time.Time
+	mockRepo := &MockQuoteRepository{
+		PruneOutboxFunc: func(before time.Time) (int64, error) {
+			gotBefore = before
+			return 5, nil
+		},
+	}
+	useCase := NewQuoteUseCase(mockRepo)
+
+	start := time.Now()
+	pruned, err := useCase.PruneOutbox(context.Background(), 24*time.Hour)
+	if err != nil || pruned != 5 {
+		t.Fatalf("expected 5 pruned events, got %d, %v", pruned, err)
+	}
+	if cutoff := start.Add(-24 * time.Hour); gotBefore.Before(cutoff) || gotBefore.After(time.Now().Add(-24*time.Hour)) {
+		t.Errorf("expected a cutoff 24h ago, got %v", gotBefore)
+	}
+}
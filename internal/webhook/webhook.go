@@ -0,0 +1,204 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// Every request carries the headers
+//
+//	X-Webhook-ID:        delivery ID, stable across retries
+//	X-Webhook-Event:     event type, such as quote.created
+//	X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed by the secret>
+//
+// Receivers should recompute the signature with Verify and reject stale timestamps.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+const (
+	IDHeader        = "X-Webhook-ID"
+	EventHeader     = "X-Webhook-Event"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the X-Webhook-Signature value for body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts))
+	m.Write([]byte{'.'})
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify checks a X-Webhook-Signature value against body. Signatures older or newer
+// than tolerance relative to now are rejected, unless tolerance is zero.
+func Verify(secret, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+		}
+	}
+	if !hmac.Equal([]byte(sig), []byte(mac(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ErrForbiddenAddress is returned for a subscriber URL that resolves to an address the
+// sender may not reach
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// Sender POSTs deliveries to subscriber URLs
+type Sender struct {
+	client               *http.Client
+	allowPrivateNetworks bool
+}
+
+// Option configures optional Sender settings
+type Option func(*Sender)
+
+// WithPrivateNetworks lets deliveries reach loopback, link-local and private addresses,
+// which are refused by default. Meant for development and tests.
+func WithPrivateNetworks(allowed bool) Option {
+	return func(s *Sender) {
+		s.allowPrivateNetworks = allowed
+	}
+}
+
+// NewSender returns a Sender that gives up on a request after timeout
+func NewSender(timeout time.Duration, opts ...Option) *Sender {
+	s := &Sender{}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !s.allowPrivateNetworks {
+		// Checked on the resolved address at dial time, so a name that resolves
+		// elsewhere on a later lookup cannot slip past
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		}
+	}
+
+	s.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// No proxy, so the dialed address is the subscriber's own
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		// A redirect would resend the signed body to a URL the subscription did not name
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return s
+}
+
+// deniedPrefixes are the ranges not covered by the netip helpers that still reach hosts
+// inside the network, or that embed an IPv4 address which might
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // this network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, and the broadcast address
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4
+}
+
+// checkAddress refuses loopback, link-local (such as the 169.254.169.254 metadata
+// service), private (RFC 1918 and fc00::/7), unspecified and multicast addresses,
+// and those in deniedPrefixes
+func checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+		}
+	}
+	return nil
+}
+
+// Send delivers the event of d and returns the response status code. Any status
+// outside 2xx is an error.
+func (s *Sender) Send(ctx context.Context, d *domain.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "quotes-service-webhooks")
+	req.Header.Set(IDHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(EventHeader, string(d.EventType))
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":1,"type":"quote.created"}`)
+	sentAt := time.Unix(1700000000, 0)
+	signature := Sign("secret", sentAt, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   bool
+	}{
+		{name: "valid", secret: "secret", signature: signature, body: body, now: sentAt.Add(time.Minute)},
+		{name: "wrong secret", secret: "other", signature: signature, body: body, now: sentAt, wantErr: true},
+		{name: "altered body", secret: "secret", signature: signature, body: []byte(`{"id":2}`), now: sentAt, wantErr: true},
+		{name: "stale", secret: "secret", signature: signature, body: body, now: sentAt.Add(10 * time.Minute), wantErr: true},
+		{name: "malformed", secret: "secret", signature: "v1=abc", body: body, now: sentAt, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.body, 5*time.Minute, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	delivery := &domain.WebhookDelivery{
+		ID:        42,
+		EventType: domain.EventQuoteUpdated,
+		URL:       server.URL,
+		Secret:    "whsec_test",
+		Event: &domain.WebhookEvent{
+			ID:         7,
+			Type:       domain.EventQuoteUpdated,
+			OccurredAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			Data:       json.RawMessage(`{"id": 3, "author": "Seneca"}`),
+		},
+	}
+
+	code, err := NewSender(time.Second, WithPrivateNetworks(true)).Send(context.Background(), delivery)
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("expected 204 without error, got %d (%v)", code, err)
+	}
+	if gotHeaders.Get(IDHeader) != "42" || gotHeaders.Get(EventHeader) != "quote.updated" {
+		t.Errorf("unexpected delivery headers: %v", gotHeaders)
+	}
+	if err = Verify("whsec_test", gotHeaders.Get(SignatureHeader), gotBody, time.Minute, time.Now()); err != nil {
+		t.Errorf("expected a verifiable signature, got %v", err)
+	}
+
+	var event domain.WebhookEvent
+	if err = json.Unmarshal(gotBody, &event); err != nil || event.ID != 7 || event.Type != domain.EventQuoteUpdated {
+		t.Errorf("unexpected body %s (%v)", gotBody, err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if code, err = NewSender(time.Second, WithPrivateNetworks(true)).Send(context.Background(), delivery); err == nil || code != http.StatusServiceUnavailable {
+		t.Errorf("expected an error for 503, got %d (%v)", code, err)
+	}
+
+	if _, err = NewSender(time.Second).Send(context.Background(), delivery); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("expected loopback deliveries to be refused, got %v", err)
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"169.254.169.254:80", false},
+		{"10.0.0.5:8080", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.10:80", false},
+		{"[fd00::1]:80", false},
+		{"[::ffff:10.0.0.1]:80", false},
+		{"0.0.0.0:80", false},
+		{"0.1.2.3:80", false},
+		{"100.64.0.1:80", false},
+		{"192.0.0.8:80", false},
+		{"198.18.0.1:80", false},
+		{"240.0.0.1:80", false},
+		{"255.255.255.255:80", false},
+		{"[64:ff9b::a00:1]:80", false},
+		{"[64:ff9b:1::a00:1]:80", false},
+		{"[2002:a00:1::1]:80", false},
+		{"[::ffff:100.64.0.1]:80", false},
+		{"100.128.0.1:443", true},
+		{"198.20.0.1:443", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkAddress(tt.address)
+			if (err == nil) != tt.allowed {
+				t.Errorf("expected allowed=%v, got %v", tt.allowed, err)
+			}
+			if err != nil && !errors.Is(err, ErrForbiddenAddress) {
+				t.Errorf("expected ErrForbiddenAddress, got %v", err)
+			}
+		})
+	}
+}
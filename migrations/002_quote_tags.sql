@@ -0,0 +1,5 @@
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_quotes_tags ON quotes USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_quotes_author_created_at ON quotes (author, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_quotes_created_at ON quotes (created_at DESC);
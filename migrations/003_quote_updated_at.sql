@@ -0,0 +1,18 @@
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+UPDATE quotes SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE quotes ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE quotes ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION quotes_set_updated_at() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_updated_at ON quotes;
+CREATE TRIGGER quotes_updated_at
+    BEFORE UPDATE ON quotes
+    FOR EACH ROW
+EXECUTE FUNCTION quotes_set_updated_at();
@@ -0,0 +1,27 @@
+-- quotes_version is bumped by every statement that changes quotes, so list responses
+-- get a validator that also changes when rows are deleted
+CREATE TABLE IF NOT EXISTS quotes_version
+(
+    id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version    BIGINT                   NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO quotes_version (id) VALUES (TRUE) ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION quotes_bump_version() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE quotes_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_version_bump ON quotes;
+CREATE TRIGGER quotes_version_bump
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON quotes
+    FOR EACH STATEMENT
+EXECUTE FUNCTION quotes_bump_version();
+
+COMMENT ON TABLE quotes_version IS
+    'Collection version, bumped by every statement that changes quotes; validator of list responses';
@@ -0,0 +1,19 @@
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Every update of a row bumps its version, whichever code path performs it
+CREATE OR REPLACE FUNCTION quotes_bump_row_version() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_row_version ON quotes;
+CREATE TRIGGER quotes_row_version
+    BEFORE UPDATE ON quotes
+    FOR EACH ROW
+EXECUTE FUNCTION quotes_bump_row_version();
+
+COMMENT ON COLUMN quotes.version IS
+    'Row version of one quote, bumped by every update of the row; checked by conditional writes';
@@ -0,0 +1,54 @@
+-- quote_revisions keeps a snapshot of a quote after every change. The rows are written by a
+-- trigger, so they commit or roll back together with the change itself whatever code path makes it.
+-- The application names the actor and, for restores, the change type with set_config.
+CREATE TABLE IF NOT EXISTS quote_revisions
+(
+    quote_id    INTEGER                  NOT NULL,
+    revision    INTEGER                  NOT NULL,
+    change_type VARCHAR(16)              NOT NULL,
+    actor       VARCHAR(255)             NOT NULL,
+    snapshot    JSONB                    NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (quote_id, revision)
+);
+
+-- Quotes that existed before revisions were recorded start from their current state
+INSERT INTO quote_revisions (quote_id, revision, change_type, actor, snapshot, created_at)
+SELECT id, 1, 'create', 'migration', to_jsonb(q), updated_at
+FROM quotes q
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION quotes_record_revision() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed quotes;
+    change  TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    change := COALESCE(NULLIF(current_setting('quotes.change', true), ''),
+                       CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END);
+
+    -- Writes to the same quote are serialised by its row lock, so MAX + 1 cannot race
+    INSERT INTO quote_revisions (quote_id, revision, change_type, actor, snapshot)
+    SELECT changed.id,
+           COALESCE(MAX(revision), 0) + 1,
+           change,
+           COALESCE(NULLIF(current_setting('quotes.actor', true), ''), current_user),
+           to_jsonb(changed)
+    FROM quote_revisions
+    WHERE quote_id = changed.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_revision ON quotes;
+CREATE TRIGGER quotes_revision
+    AFTER INSERT OR UPDATE OR DELETE ON quotes
+    FOR EACH ROW
+EXECUTE FUNCTION quotes_record_revision();
@@ -0,0 +1,4 @@
+-- Deleted quotes stay in the table with deleted_at set until they are purged
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_deleted_at ON quotes (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,56 @@
+-- audit_events is an append-only log of every change to quotes. The application writes the
+-- rows in the transaction of the change (writeTx.commit), and one background chainer
+-- (ChainAuditEvents) links them into a hash chain after commit, so quote writes do not queue
+-- behind a lock held until commit. Every chained row stores the hash of the row before it,
+-- so editing or removing a row is detectable by recomputing the chain. position is the
+-- place of an event in the chain, NULL until the chainer reaches it.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    action      VARCHAR(64)              NOT NULL,
+    actor       VARCHAR(255)             NOT NULL,
+    quote_id    INTEGER,
+    request_id  VARCHAR(128)             NOT NULL DEFAULT '',
+    client_ip   VARCHAR(64)              NOT NULL DEFAULT '',
+    before      JSONB,
+    after       JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    position    BIGINT,
+    prev_hash   VARCHAR(64)              NOT NULL DEFAULT '',
+    hash        VARCHAR(64)              NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_quote_id ON audit_events (quote_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_request_id ON audit_events (request_id) WHERE request_id <> '';
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_events_position ON audit_events (position);
+CREATE INDEX IF NOT EXISTS idx_audit_events_unchained ON audit_events (id) WHERE position IS NULL;
+
+-- The chainer may fill in the chain of an event once; nothing else may change
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.position IS NULL AND NEW.position IS NOT NULL
+        AND (NEW.id, NEW.action, NEW.actor, NEW.quote_id, NEW.request_id, NEW.client_ip,
+             NEW.before, NEW.after, NEW.occurred_at)
+        IS NOT DISTINCT FROM
+            (OLD.id, OLD.action, OLD.actor, OLD.quote_id, OLD.request_id, OLD.client_ip,
+             OLD.before, OLD.after, OLD.occurred_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only();
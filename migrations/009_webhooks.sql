@@ -0,0 +1,94 @@
+-- outbox holds the events announced to webhook subscribers. Like revisions the rows are
+-- written by a trigger in the transaction of the change, so an event exists if and only if
+-- its change committed. The dispatcher fans each event out to webhook_deliveries.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id            BIGSERIAL PRIMARY KEY,
+    event_type    VARCHAR(32)              NOT NULL,
+    quote_id      INTEGER                  NOT NULL,
+    payload       JSONB                    NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (id) WHERE dispatched_at IS NULL;
+-- Events older than OUTBOX_RETENTION whose deliveries are finished are pruned
+CREATE INDEX IF NOT EXISTS idx_outbox_created_at ON outbox (created_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id         SERIAL PRIMARY KEY,
+    url        TEXT                     NOT NULL,
+    secret     VARCHAR(255)             NOT NULL,
+    events     TEXT[]                   NOT NULL DEFAULT '{}',
+    active     BOOLEAN                  NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               BIGSERIAL PRIMARY KEY,
+    subscription_id  INTEGER                  NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    outbox_id        BIGINT                   NOT NULL REFERENCES outbox (id),
+    status           VARCHAR(16)              NOT NULL DEFAULT 'pending',
+    attempts         INTEGER                  NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INTEGER                  NOT NULL DEFAULT 0,
+    last_error       TEXT                     NOT NULL DEFAULT '',
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at     TIMESTAMP WITH TIME ZONE,
+    -- A claimed delivery is leased to one dispatcher. Its attempt is only recorded while that
+    -- dispatcher still holds the lease, so a dispatcher that stalled past its lease cannot
+    -- overwrite the outcome of the attempt that took over.
+    lease_owner      VARCHAR(64),
+    leased_until     TIMESTAMP WITH TIME ZONE,
+    UNIQUE (subscription_id, outbox_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_outbox ON webhook_deliveries (outbox_id);
+
+-- A quote moving to the trash is announced as deleted and one coming back as created;
+-- purging a quote that is already in the trash announces nothing
+CREATE OR REPLACE FUNCTION quotes_enqueue_event() RETURNS TRIGGER AS
+$$
+DECLARE
+    event   TEXT;
+    changed quotes;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        changed := NEW;
+        IF NEW.deleted_at IS NULL THEN
+            event := 'quote.created';
+        END IF;
+    ELSIF TG_OP = 'UPDATE' THEN
+        changed := NEW;
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            event := 'quote.deleted';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            event := 'quote.created';
+        ELSIF NEW.deleted_at IS NULL THEN
+            event := 'quote.updated';
+        END IF;
+    ELSE
+        changed := OLD;
+        IF OLD.deleted_at IS NULL THEN
+            event := 'quote.deleted';
+        END IF;
+    END IF;
+
+    IF event IS NOT NULL THEN
+        INSERT INTO outbox (event_type, quote_id, payload) VALUES (event, changed.id, to_jsonb(changed));
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_outbox ON quotes;
+CREATE TRIGGER quotes_outbox
+    AFTER INSERT OR UPDATE OR DELETE ON quotes
+    FOR EACH ROW
+EXECUTE FUNCTION quotes_enqueue_event();
@@ -0,0 +1,27 @@
+-- outbox doubles as the change log replayed by GET /quotes/stream, whose readers resume after
+-- the last event they saw. The log is ordered by position rather than ID: IDs are taken at
+-- insert and may commit out of order, while positions are handed out after commit by one
+-- sequencer at a time (SequenceEvents), so a position never becomes visible after a higher
+-- one and writers take no lock for it.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS position BIGINT;
+CREATE SEQUENCE IF NOT EXISTS outbox_position_seq;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_position ON outbox (position);
+CREATE INDEX IF NOT EXISTS idx_outbox_unnumbered ON outbox (id) WHERE position IS NULL;
+
+-- Wakes the stream of every replica once the transaction commits. The payload is empty, so
+-- Postgres folds the notifications of one transaction into one; listeners read the events
+-- themselves from outbox.
+CREATE OR REPLACE FUNCTION outbox_notify() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('quote_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_notify ON outbox;
+CREATE TRIGGER outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH STATEMENT
+EXECUTE FUNCTION outbox_notify();
@@ -0,0 +1,59 @@
+-- authors holds what is known about an author beyond their quotes. Authors are still
+-- identified by the name stored with each quote; a row only exists once a bio is set.
+CREATE TABLE IF NOT EXISTS authors
+(
+    name       VARCHAR(255) PRIMARY KEY,
+    bio        TEXT                     NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bio changes go through the same write path as quote changes: the application logs the audit
+-- event, and triggers keep a revision of the author and announce the change in the outbox.
+CREATE TABLE IF NOT EXISTS author_revisions
+(
+    name       VARCHAR(255)             NOT NULL,
+    revision   INTEGER                  NOT NULL,
+    actor      VARCHAR(255)             NOT NULL,
+    snapshot   JSONB                    NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name, revision)
+);
+
+CREATE OR REPLACE FUNCTION authors_record_revision() RETURNS TRIGGER AS
+$$
+BEGIN
+    -- Writes to the same author are serialised by its row lock, so MAX + 1 cannot race
+    INSERT INTO author_revisions (name, revision, actor, snapshot)
+    SELECT NEW.name,
+           COALESCE(MAX(revision), 0) + 1,
+           COALESCE(NULLIF(current_setting('quotes.actor', true), ''), current_user),
+           to_jsonb(NEW)
+    FROM author_revisions
+    WHERE name = NEW.name;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS authors_revision ON authors;
+CREATE TRIGGER authors_revision
+    AFTER INSERT OR UPDATE ON authors
+    FOR EACH ROW
+EXECUTE FUNCTION authors_record_revision();
+
+-- Author events carry no quote
+ALTER TABLE outbox ALTER COLUMN quote_id DROP NOT NULL;
+
+CREATE OR REPLACE FUNCTION authors_enqueue_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO outbox (event_type, payload) VALUES ('author.updated', to_jsonb(NEW));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS authors_outbox ON authors;
+CREATE TRIGGER authors_outbox
+    AFTER INSERT OR UPDATE ON authors
+    FOR EACH ROW
+EXECUTE FUNCTION authors_enqueue_event();
@@ -0,0 +1,11 @@
+-- api_keys holds the API keys created through the admin API, next to the fixed ones of
+-- API_KEYS. Only the SHA-256 digest of a key is stored; the key itself is shown once, when it
+-- is created. Revoking a key deletes its row.
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(64)              NOT NULL,
+    role       VARCHAR(16)              NOT NULL CHECK (role IN ('user', 'admin')),
+    digest     CHAR(64)                 NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
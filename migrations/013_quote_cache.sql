@@ -0,0 +1,22 @@
+-- Tells every replica to drop its cached reads once a write to quotes or authors commits.
+-- Like quote_events the payload is empty, so the notifications of one transaction fold
+-- into one. Authors are included because their bios show up in the cached author lists.
+CREATE OR REPLACE FUNCTION quote_cache_notify() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('quote_cache', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS quotes_cache_notify ON quotes;
+CREATE TRIGGER quotes_cache_notify
+    AFTER INSERT OR UPDATE OR DELETE ON quotes
+    FOR EACH STATEMENT
+EXECUTE FUNCTION quote_cache_notify();
+
+DROP TRIGGER IF EXISTS authors_cache_notify ON authors;
+CREATE TRIGGER authors_cache_notify
+    AFTER INSERT OR UPDATE OR DELETE ON authors
+    FOR EACH STATEMENT
+EXECUTE FUNCTION quote_cache_notify();
@@ -0,0 +1,5 @@
+-- Keyset indexes for the sort orders of GET /quotes. Each ends in id, the tie-breaker the
+-- cursor continues from, and only covers live quotes, the only ones listed.
+CREATE INDEX IF NOT EXISTS idx_quotes_live_created_at ON quotes (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_quotes_live_author ON quotes (author, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_quotes_live_length ON quotes (char_length(quote), id) WHERE deleted_at IS NULL;
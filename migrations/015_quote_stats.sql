@@ -0,0 +1,54 @@
+-- Collection statistics for GET /stats. Each view aggregates the live quotes whenever it
+-- is read; its _mv twin materializes it, so that with STATS_MATERIALIZED the statistics
+-- are served as of the last refresh instead. The unique indexes let the twins be
+-- refreshed concurrently, without blocking their readers.
+CREATE OR REPLACE VIEW quote_stats_summary AS
+SELECT now() AS as_of,
+       count(*) AS quotes,
+       count(DISTINCT author) AS authors,
+       COALESCE(avg(char_length(quote)), 0)::DOUBLE PRECISION AS avg_length,
+       COALESCE(min(char_length(quote)), 0) AS min_length,
+       COALESCE(max(char_length(quote)), 0) AS max_length,
+       COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY char_length(quote)), 0) AS p50_length,
+       COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY char_length(quote)), 0) AS p90_length,
+       COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY char_length(quote)), 0) AS p99_length
+FROM quotes
+WHERE deleted_at IS NULL;
+
+-- Weeks and months are rolled up from the days when read
+CREATE OR REPLACE VIEW quote_stats_daily AS
+SELECT (created_at AT TIME ZONE 'UTC')::DATE AS day, count(*) AS quotes
+FROM quotes
+WHERE deleted_at IS NULL
+GROUP BY 1;
+
+CREATE OR REPLACE VIEW quote_stats_authors AS
+SELECT author, count(*) AS quotes
+FROM quotes
+WHERE deleted_at IS NULL
+GROUP BY author;
+
+-- Words are runs of letters. The text is read with the root ICU collation, whose [[:alpha:]]
+-- and lower() follow Unicode whatever the locale of the database, so this migration fails on
+-- a server built without ICU; the word keeps the default collation so the indexes and
+-- comparisons around it are the usual ones. Stop words are left in and filtered when read,
+-- so the lists can change without a refresh.
+CREATE OR REPLACE VIEW quote_stats_words AS
+SELECT q.author, w.word COLLATE "default" AS word, count(*) AS uses
+FROM quotes q,
+     regexp_split_to_table(lower(q.quote COLLATE "und-x-icu"), '[^[:alpha:]]+') AS w(word)
+WHERE q.deleted_at IS NULL
+  AND char_length(w.word) > 1
+GROUP BY q.author, w.word;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS quote_stats_summary_mv AS SELECT * FROM quote_stats_summary;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_stats_summary_mv ON quote_stats_summary_mv (as_of);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS quote_stats_daily_mv AS SELECT * FROM quote_stats_daily;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_stats_daily_mv ON quote_stats_daily_mv (day);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS quote_stats_authors_mv AS SELECT * FROM quote_stats_authors;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_stats_authors_mv ON quote_stats_authors_mv (author);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS quote_stats_words_mv AS SELECT * FROM quote_stats_words;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_stats_words_mv ON quote_stats_words_mv (author, word);
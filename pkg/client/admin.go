@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// auditQuery encodes the filter as the query of the audit endpoints
+func auditQuery(filter AuditFilter) url.Values {
+	query := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	set("actor", filter.Actor)
+	set("action", string(filter.Action))
+	set("request_id", filter.RequestID)
+	if filter.QuoteID != 0 {
+		query.Set("quote_id", strconv.Itoa(filter.QuoteID))
+	}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.BeforeID != 0 {
+		query.Set("cursor", strconv.FormatInt(filter.BeforeID, 10))
+	}
+	if filter.Limit != 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	return query
+}
+
+// GetAuditEvents GET /admin/audit returns one page of audit events, newest first. Pass
+// the page's NextCursor as filter.BeforeID for the next one.
+func (c *Client) GetAuditEvents(ctx context.Context, filter AuditFilter) (*AuditPage, error) {
+	var page AuditPage
+	if err := c.call(ctx, get("/admin/audit", auditQuery(filter)), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AuditEvents yields the audit events matching filter, newest first, reading a page of
+// filter.Limit events at a time
+func (c *Client) AuditEvents(ctx context.Context, filter AuditFilter) iter.Seq2[*AuditEvent, error] {
+	return func(yield func(*AuditEvent, error) bool) {
+		for {
+			page, err := c.GetAuditEvents(ctx, filter)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, event := range page.Events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+			if page.NextCursor == 0 {
+				return
+			}
+			filter.BeforeID = page.NextCursor
+		}
+	}
+}
+
+// ExportAuditEvents GET /admin/audit/export yields every audit event matching filter,
+// oldest first, in a single stream. The filter's limit is ignored.
+func (c *Client) ExportAuditEvents(ctx context.Context, filter AuditFilter) iter.Seq2[*AuditEvent, error] {
+	return func(yield func(*AuditEvent, error) bool) {
+		filter.Limit = 0
+		resp, err := c.do(ctx, get("/admin/audit/export", auditQuery(filter)))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var event AuditEvent
+			if err := dec.Decode(&event); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, fmt.Errorf("quotes: audit export interrupted: %w", err))
+				}
+				return
+			}
+			if !yield(&event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// VerifyAuditLog GET /admin/audit/verify
+func (c *Client) VerifyAuditLog(ctx context.Context) (*AuditVerification, error) {
+	var result AuditVerification
+	if err := c.call(ctx, get("/admin/audit/verify", nil), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func webhookPath(id int, suffix string) string {
+	return "/admin/webhooks/" + strconv.Itoa(id) + suffix
+}
+
+// GetWebhooks GET /admin/webhooks
+func (c *Client) GetWebhooks(ctx context.Context) ([]*WebhookSubscription, error) {
+	var subs []*WebhookSubscription
+	if err := c.call(ctx, get("/admin/webhooks", nil), nil, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetWebhook GET /admin/webhooks/{id}
+func (c *Client) GetWebhook(ctx context.Context, id int) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.call(ctx, get(webhookPath(id, ""), nil), nil, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// CreateWebhook POST /admin/webhooks. The returned subscription carries its secret, which
+// is not shown again.
+func (c *Client) CreateWebhook(ctx context.Context, req *WebhookSubscriptionRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.call(ctx, &request{method: http.MethodPost, path: "/admin/webhooks"}, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateWebhook PUT /admin/webhooks/{id}
+func (c *Client) UpdateWebhook(ctx context.Context, id int, req *WebhookSubscriptionRequest) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := c.call(ctx, &request{method: http.MethodPut, path: webhookPath(id, "")}, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// DeleteWebhook DELETE /admin/webhooks/{id}
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	return c.call(ctx, &request{method: http.MethodDelete, path: webhookPath(id, "")}, nil, nil)
+}
+
+// GetDeliveries GET /admin/webhooks/{id}/deliveries lists the deliveries of a subscription,
+// only those in status unless it is empty
+func (c *Client) GetDeliveries(ctx context.Context, subscriptionID int, status DeliveryStatus) ([]*WebhookDelivery, error) {
+	var query url.Values
+	if status != "" {
+		query = url.Values{"status": {string(status)}}
+	}
+	var deliveries []*WebhookDelivery
+	if err := c.call(ctx, get(webhookPath(subscriptionID, "/deliveries"), query), nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhook POST /admin/webhooks/{id}/deliveries/{delivery}:redeliver queues a
+// delivery to be sent again
+func (c *Client) RedeliverWebhook(ctx context.Context, subscriptionID int, deliveryID int64) (*WebhookDelivery, error) {
+	path := webhookPath(subscriptionID, "/deliveries/"+strconv.FormatInt(deliveryID, 10)+":redeliver")
+	var delivery WebhookDelivery
+	if err := c.call(ctx, &request{method: http.MethodPost, path: path}, nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetAPIKeys GET /admin/keys lists the keys created through the API, without the keys themselves
+func (c *Client) GetAPIKeys(ctx context.Context) ([]*Key, error) {
+	var keys []*Key
+	if err := c.call(ctx, get("/admin/keys", nil), nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CreateAPIKey POST /admin/keys. The returned record carries the key, which is not shown again.
+func (c *Client) CreateAPIKey(ctx context.Context, req *KeyRequest) (*Key, error) {
+	var key Key
+	if err := c.call(ctx, &request{method: http.MethodPost, path: "/admin/keys"}, req, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey DELETE /admin/keys/{id}
+func (c *Client) RevokeAPIKey(ctx context.Context, id int) error {
+	return c.call(ctx, &request{method: http.MethodDelete, path: "/admin/keys/" + strconv.Itoa(id)}, nil, nil)
+}
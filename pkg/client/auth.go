@@ -0,0 +1,31 @@
+package client
+
+import "net/http"
+
+// Auth adds credentials to every request the client sends
+type Auth interface {
+	Apply(req *http.Request) error
+}
+
+// AuthFunc adapts a function to Auth, for schemes such as short-lived tokens fetched per request
+type AuthFunc func(req *http.Request) error
+
+func (f AuthFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken sends an API key as "Authorization: Bearer <key>"
+func BearerToken(key string) Auth {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+key)
+		return nil
+	})
+}
+
+// APIKey sends an API key as "X-API-Key: <key>"
+func APIKey(key string) Auth {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set("X-API-Key", key)
+		return nil
+	})
+}
@@ -0,0 +1,226 @@
+// Package client is a Go client for the quotes service HTTP API.
+//
+//	c, err := client.New("https://quotes.example.com", client.WithAuth(client.BearerToken(key)))
+//	quote, err := c.GetQuote(ctx, 42)
+//	if errors.Is(err, client.ErrQuoteNotFound) { ... }
+//
+// Its methods mirror the use cases of the service and take the same request and result
+// types. Failed responses are returned as *Error, which matches the errors the service
+// reported with errors.Is. Requests are retried after 429 and 5xx responses as
+// RetryPolicy describes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiVersion is the version of the API the client speaks. It is named in every path, so
+// the client keeps working when the service changes the version of unversioned paths.
+const apiVersion = "/v1"
+
+// Client calls the quotes service. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	auth       Auth
+	retry      RetryPolicy
+	userAgent  string
+	// sleep waits between attempts; tests replace it
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures optional Client settings
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithAuth adds credentials to every request
+func WithAuth(auth Auth) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New returns a client for the service at baseURL, such as https://quotes.example.com.
+// A path in baseURL is kept as a prefix of every endpoint.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("quotes: invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("quotes: base URL must be http or https, got %q", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		userAgent:  "quotes-service-client",
+		sleep:      sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request describes a call; it is built into a new *http.Request for every attempt
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// body is sent as it is on every attempt
+	body []byte
+	// stream is a body that can only be read once, so the request is never retried
+	stream io.Reader
+}
+
+// do sends req, retrying as the policy allows, and returns the response of the first
+// attempt that got a status below 400. Other responses are returned as *Error. The caller
+// closes the body of the response.
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+	if req.stream != nil {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		httpReq, err := c.newRequest(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		var apiErr *Error
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			err = fmt.Errorf("quotes: %s %s: %w", req.method, req.path, err)
+		case resp.StatusCode < 400:
+			return resp, nil
+		default:
+			apiErr = decodeError(resp)
+			err = apiErr
+		}
+
+		status := 0
+		if apiErr != nil {
+			status = apiErr.StatusCode
+		}
+		if attempt >= attempts || !retryable(req.method, status) {
+			return nil, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if apiErr != nil && apiErr.RetryAfter > 0 {
+			if apiErr.RetryAfter > c.retry.MaxDelay {
+				return nil, err
+			}
+			delay = apiErr.RetryAfter
+		}
+		if serr := c.sleep(ctx, delay); serr != nil {
+			return nil, serr
+		}
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, req *request) (*http.Request, error) {
+	u := *c.baseURL
+	u.Path += apiVersion + req.path
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	switch {
+	case req.stream != nil:
+		body = req.stream
+	case req.body != nil:
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("quotes: %w", err)
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if c.userAgent != "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.auth != nil {
+		if err := c.auth.Apply(httpReq); err != nil {
+			return nil, fmt.Errorf("quotes: failed to authorize request: %w", err)
+		}
+	}
+	return httpReq, nil
+}
+
+// call sends req with in, if not nil, as its JSON body and decodes the response into out,
+// if not nil
+func (c *Client) call(ctx context.Context, req *request, in, out interface{}) error {
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("quotes: failed to encode request: %w", err)
+		}
+		req.body = body
+		if req.header == nil {
+			req.header = http.Header{}
+		}
+		req.header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("quotes: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func get(path string, query url.Values) *request {
+	return &request{method: http.MethodGet, path: path, query: query}
+}
@@ -0,0 +1,694 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/auth"
+	handler "github.com/shoksin/quotes-service/internal/delivery/http"
+	"github.com/shoksin/quotes-service/internal/delivery/http/middleware"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/usecase"
+)
+
+const (
+	userKey  = "user-key"
+	adminKey = "admin-key"
+)
+
+// keyAuthenticator knows a user and an admin key
+type keyAuthenticator struct{}
+
+func (keyAuthenticator) Authenticate(key string) (*domain.Principal, error) {
+	switch key {
+	case userKey:
+		return &domain.Principal{Name: "reader", Role: domain.RoleUser}, nil
+	case adminKey:
+		return &domain.Principal{Name: "ops", Role: domain.RoleAdmin}, nil
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
+
+// newServer serves the real handlers and use cases over repo, accepting the keys of
+// keyAuthenticator and the keys stored in repo. wrap, if not nil, sits in front of them to
+// fake failures.
+func newServer(t *testing.T, repo *memoryRepository, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	uc := usecase.NewQuoteUseCase(repo)
+	handler.NewQuoteHandler(uc).RegisterRoutes(mux)
+
+	var h http.Handler = middleware.AuthMiddleware(auth.Chain{keyAuthenticator{}, uc}, mux)
+	if wrap != nil {
+		h = wrap(h)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient returns a client of srv that records the delays it would sleep for
+// instead of waiting
+func newTestClient(t *testing.T, srv *httptest.Server, key string, opts ...Option) (*Client, *[]time.Duration) {
+	t.Helper()
+	opts = append([]Option{WithAuth(BearerToken(key))}, opts...)
+	c, err := New(srv.URL+"/", opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var delays []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return c, &delays
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "quotes.example.com", "ftp://quotes.example.com", "http://[::1"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("New(%q) expected error, got nil", baseURL)
+		}
+	}
+
+	c, err := New("https://example.com/api/")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if c.baseURL.Path != "/api" {
+		t.Errorf("base path = %q, want /api", c.baseURL.Path)
+	}
+}
+
+func TestClient_QuoteLifecycle(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	c, _ := newTestClient(t, srv, userKey)
+
+	quotes, err := c.GetAllQuotes(ctx)
+	if err != nil {
+		t.Fatalf("GetAllQuotes() error = %v", err)
+	}
+	if quotes == nil || len(quotes) != 0 {
+		t.Fatalf("GetAllQuotes() = %v, want an empty list", quotes)
+	}
+	if _, err := c.GetRandomQuote(ctx); !errors.Is(err, ErrNoQuotesFound) {
+		t.Errorf("GetRandomQuote() error = %v, want ErrNoQuotesFound", err)
+	}
+
+	created, err := c.CreateQuote(ctx, &CreateQuoteRequest{Author: " Seneca ", Quote: "Luck is what happens when preparation meets opportunity."})
+	if err != nil {
+		t.Fatalf("CreateQuote() error = %v", err)
+	}
+	if created.ID == 0 || created.Author != "Seneca" || created.Version != 1 {
+		t.Fatalf("CreateQuote() = %+v", created)
+	}
+
+	got, err := c.GetQuote(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetQuote() error = %v", err)
+	}
+	if got.Quote != created.Quote {
+		t.Errorf("GetQuote() quote = %q, want %q", got.Quote, created.Quote)
+	}
+
+	byAuthor, err := c.GetQuotesByAuthor(ctx, "Seneca")
+	if err != nil || len(byAuthor) != 1 {
+		t.Errorf("GetQuotesByAuthor() = %v, %v, want one quote", byAuthor, err)
+	}
+	if daily, err := c.GetDailyQuote(ctx); err != nil || daily.ID != created.ID {
+		t.Errorf("GetDailyQuote() = %v, %v, want quote %d", daily, err, created.ID)
+	}
+
+	updated, err := c.UpdateQuote(ctx, created.ID, &UpdateQuoteRequest{
+		CreateQuoteRequest: CreateQuoteRequest{Author: "Seneca", Quote: "We suffer more in imagination than in reality."},
+		Version:            created.Version,
+	})
+	if err != nil {
+		t.Fatalf("UpdateQuote() error = %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("UpdateQuote() version = %d, want 2", updated.Version)
+	}
+
+	if err := c.DeleteQuote(ctx, created.ID, updated.Version); err != nil {
+		t.Fatalf("DeleteQuote() error = %v", err)
+	}
+	if _, err := c.GetQuote(ctx, created.ID); !errors.Is(err, ErrQuoteNotFound) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetQuote() after delete error = %v, want ErrQuoteNotFound", err)
+	}
+
+	if _, err := c.GetTrash(ctx); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetTrash() as a user error = %v, want ErrForbidden", err)
+	}
+	admin, _ := newTestClient(t, srv, adminKey)
+	trash, err := admin.GetTrash(ctx)
+	if err != nil || len(trash) != 1 || trash[0].ID != created.ID {
+		t.Fatalf("GetTrash() = %v, %v, want the deleted quote", trash, err)
+	}
+	if _, err := admin.RestoreQuote(ctx, created.ID); err != nil {
+		t.Fatalf("RestoreQuote() error = %v", err)
+	}
+	if _, err := admin.RestoreQuote(ctx, created.ID); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("RestoreQuote() twice error = %v, want ErrNotInTrash", err)
+	}
+}
+
+func TestClient_ListAndSearch(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	c, _ := newTestClient(t, srv, userKey)
+	for i, author := range []string{"Rumi", "Hafez", "Rumi", "Rumi", "Saadi"} {
+		req := &CreateQuoteRequest{Author: author, Quote: strings.Repeat("love ", i+1), Tags: []string{"poetry"}}
+		if _, err := c.CreateQuote(ctx, req); err != nil {
+			t.Fatalf("CreateQuote() error = %v", err)
+		}
+	}
+
+	page, err := c.ListQuotes(ctx, ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListQuotes() error = %v", err)
+	}
+	if len(page.Quotes) != 2 || page.NextCursor != strconv.Itoa(page.Quotes[1].ID) {
+		t.Fatalf("ListQuotes() = %+v, want 2 quotes and a cursor", page)
+	}
+
+	var ids []int
+	for quote, err := range c.Quotes(ctx, ListOptions{Author: "Rumi", Limit: 1}) {
+		if err != nil {
+			t.Fatalf("Quotes() error = %v", err)
+		}
+		ids = append(ids, quote.ID)
+	}
+	if want := []int{1, 3, 4}; !slices.Equal(ids, want) {
+		t.Errorf("Quotes() ids = %v, want %v", ids, want)
+	}
+
+	page, err = c.ListQuotes(ctx, ListOptions{Tag: "sufism"})
+	if err != nil || len(page.Quotes) != 0 || page.NextCursor != "" {
+		t.Errorf("ListQuotes(unknown tag) = %+v, %v, want an empty last page", page, err)
+	}
+
+	// Longest first: the texts repeat "love " once per position in the list
+	page, err = c.ListQuotes(ctx, ListOptions{Sort: "-length", MinLength: 10, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListQuotes(sorted) error = %v", err)
+	}
+	if len(page.Quotes) != 2 || page.Quotes[0].ID != 5 || page.Quotes[1].ID != 4 || page.NextCursor == "" {
+		t.Fatalf("ListQuotes(sorted) = %+v, want quotes 5 and 4 and a cursor", page)
+	}
+	cursor := page.NextCursor
+	page, err = c.ListQuotes(ctx, ListOptions{Sort: "-length", MinLength: 10, Limit: 2, Cursor: cursor})
+	if err != nil || len(page.Quotes) != 1 || page.Quotes[0].ID != 3 || page.NextCursor != "" {
+		t.Errorf("ListQuotes(sorted, next page) = %+v, %v, want quote 3 alone", page, err)
+	}
+	if _, err := c.ListQuotes(ctx, ListOptions{Sort: "length", Cursor: cursor}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("ListQuotes(cursor of another sort) error = %v, want ErrInvalidPageToken", err)
+	}
+	if _, err := c.ListQuotes(ctx, ListOptions{Sort: "popularity"}); !errors.Is(err, ErrInvalidQuoteFilter) {
+		t.Errorf("ListQuotes(unknown sort) error = %v, want ErrInvalidQuoteFilter", err)
+	}
+
+	found, err := c.SearchQuotes(ctx, "hafez", 10)
+	if err != nil || len(found) != 1 || found[0].Author != "Hafez" {
+		t.Errorf("SearchQuotes() = %v, %v, want the quote by Hafez", found, err)
+	}
+	if _, err := c.SearchQuotes(ctx, " ", 0); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("SearchQuotes(blank) error = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	srv := newServer(t, repo, nil)
+	c, _ := newTestClient(t, srv, userKey)
+
+	quote, err := c.CreateQuote(ctx, &CreateQuoteRequest{Author: "Marcus Aurelius", Quote: "The impediment to action advances action."})
+	if err != nil {
+		t.Fatalf("CreateQuote() error = %v", err)
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := c.CreateQuote(ctx, &CreateQuoteRequest{Author: "", Quote: "Anonymous"})
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			t.Fatalf("CreateQuote() error = %v, want a 400 *Error", err)
+		}
+		if !errors.Is(err, ErrInvalidAuthor) || errors.Is(err, ErrInvalidQuote) {
+			t.Errorf("CreateQuote() error = %v, want ErrInvalidAuthor only", err)
+		}
+		if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "author" {
+			t.Errorf("Details = %v, want the author field", apiErr.Details)
+		}
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		_, err := c.UpdateQuote(ctx, quote.ID, &UpdateQuoteRequest{
+			CreateQuoteRequest: CreateQuoteRequest{Author: "Marcus Aurelius", Quote: "Waste no more time arguing."},
+			Version:            7,
+		})
+		var conflict *VersionConflictError
+		if !errors.Is(err, ErrVersionConflict) || !errors.As(err, &conflict) {
+			t.Fatalf("UpdateQuote() error = %v, want a *VersionConflictError", err)
+		}
+		if conflict.Current.ID != quote.ID || conflict.Current.Version != 1 {
+			t.Errorf("Current = %+v, want the stored quote", conflict.Current)
+		}
+
+		err = c.DeleteQuote(ctx, quote.ID, 3)
+		if !errors.As(err, &conflict) || conflict.Current.Version != 1 {
+			t.Errorf("DeleteQuote() error = %v, want a *VersionConflictError", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := c.GetQuote(ctx, 999); !errors.Is(err, ErrQuoteNotFound) {
+			t.Errorf("GetQuote() error = %v, want ErrQuoteNotFound", err)
+		}
+		if err := c.DeleteQuote(ctx, 999, 0); !errors.Is(err, ErrQuoteNotFound) {
+			t.Errorf("DeleteQuote() error = %v, want ErrQuoteNotFound", err)
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		err := c.HardDeleteQuote(ctx, quote.ID, 0)
+		var apiErr *Error
+		if !errors.Is(err, ErrForbidden) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+			t.Errorf("HardDeleteQuote() error = %v, want a 403 matching ErrForbidden", err)
+		}
+		if _, err := c.GetAuditEvents(ctx, AuditFilter{}); !errors.Is(err, ErrForbidden) {
+			t.Errorf("GetAuditEvents() error = %v, want ErrForbidden", err)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		bad, _ := newTestClient(t, srv, "stolen-key")
+		var apiErr *Error
+		if _, err := bad.GetAllQuotes(ctx); !errors.Is(err, ErrInvalidAPIKey) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("GetAllQuotes() error = %v, want a 401 matching ErrInvalidAPIKey", err)
+		}
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		admin, _ := newTestClient(t, srv, adminKey)
+		if err := admin.HardDeleteQuote(ctx, quote.ID, quote.Version); err != nil {
+			t.Fatalf("HardDeleteQuote() error = %v", err)
+		}
+		if trash, _ := admin.GetTrash(ctx); len(trash) != 0 {
+			t.Errorf("GetTrash() = %v, want the quote gone for good", trash)
+		}
+	})
+}
+
+func TestClient_CreateQuotes(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	c, _ := newTestClient(t, srv, userKey)
+
+	items := []*CreateQuoteRequest{
+		{Author: "Epictetus", Quote: "No man is free who is not master of himself."},
+		{Author: "Epictetus", Quote: ""},
+	}
+
+	result, err := c.CreateQuotes(ctx, &BatchCreateRequest{Quotes: items, Atomic: true})
+	if !errors.Is(err, ErrBatchRejected) {
+		t.Fatalf("CreateQuotes(atomic) error = %v, want ErrBatchRejected", err)
+	}
+	if result == nil || result.Failed != 1 || result.Created != 0 || len(result.Items[1].Details) == 0 {
+		t.Fatalf("CreateQuotes(atomic) result = %+v, want the rejected item reported", result)
+	}
+
+	result, err = c.CreateQuotes(ctx, &BatchCreateRequest{Quotes: items})
+	if err != nil {
+		t.Fatalf("CreateQuotes() error = %v", err)
+	}
+	if result.Created != 1 || result.Failed != 1 || result.Items[0].Quote == nil {
+		t.Errorf("CreateQuotes() result = %+v, want one created and one failed", result)
+	}
+
+	if _, err := c.CreateQuotes(ctx, &BatchCreateRequest{}); !errors.Is(err, ErrEmptyBatch) {
+		t.Errorf("CreateQuotes(empty) error = %v, want ErrEmptyBatch", err)
+	}
+}
+
+func TestClient_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	source := newServer(t, newMemoryRepository(), nil)
+	src, _ := newTestClient(t, source, userKey)
+	for _, text := range []string{"First", "Second", "Third"} {
+		if _, err := src.CreateQuote(ctx, &CreateQuoteRequest{Author: "Lao Tzu", Quote: text}); err != nil {
+			t.Fatalf("CreateQuote() error = %v", err)
+		}
+	}
+
+	var exported []*Quote
+	for quote, err := range src.ExportQuotes(ctx) {
+		if err != nil {
+			t.Fatalf("ExportQuotes() error = %v", err)
+		}
+		exported = append(exported, quote)
+	}
+	if len(exported) != 3 || exported[2].Quote != "Third" {
+		t.Fatalf("ExportQuotes() = %v, want 3 quotes in order", exported)
+	}
+
+	// Stopping early must not hang or leak the stream
+	for range src.ExportQuotes(ctx) {
+		break
+	}
+
+	target := newServer(t, newMemoryRepository(), nil)
+	dst, _ := newTestClient(t, target, userKey)
+	records := func(yield func(*CreateQuoteRequest, error) bool) {
+		for _, quote := range append(exported, exported[0]) {
+			if !yield(&CreateQuoteRequest{Author: quote.Author, Quote: quote.Quote}, nil) {
+				return
+			}
+		}
+	}
+	report, err := dst.ImportQuotes(ctx, records)
+	if err != nil {
+		t.Fatalf("ImportQuotes() error = %v", err)
+	}
+	if report.Total != 4 || report.Imported != 3 || report.Duplicates != 1 {
+		t.Errorf("ImportQuotes() report = %+v, want 3 imported and 1 duplicate", report)
+	}
+
+	var csv bytes.Buffer
+	if err := dst.ExportQuotesTo(ctx, FormatCSV, &csv); err != nil {
+		t.Fatalf("ExportQuotesTo() error = %v", err)
+	}
+	if !strings.Contains(csv.String(), "Lao Tzu") {
+		t.Errorf("ExportQuotesTo() = %q, want the quotes as CSV", csv.String())
+	}
+
+	report, err = dst.ImportQuotesFrom(ctx, strings.NewReader("Know thyself.\n%\n"), ImportOptions{Format: FormatFortune, DefaultAuthor: "Socrates"})
+	if err != nil {
+		t.Fatalf("ImportQuotesFrom() error = %v", err)
+	}
+	if report.Imported != 1 {
+		t.Errorf("ImportQuotesFrom() report = %+v, want 1 imported", report)
+	}
+	if quotes, _ := dst.GetQuotesByAuthor(ctx, "Socrates"); len(quotes) != 1 {
+		t.Errorf("GetQuotesByAuthor() = %v, want the imported quote", quotes)
+	}
+}
+
+func TestClient_ImportQuotes_RecordError(t *testing.T) {
+	srv := newServer(t, newMemoryRepository(), nil)
+	c, _ := newTestClient(t, srv, userKey)
+
+	failing := errors.New("source unavailable")
+	records := func(yield func(*CreateQuoteRequest, error) bool) {
+		if yield(&CreateQuoteRequest{Author: "Plato", Quote: "Courage is knowing what not to fear."}, nil) {
+			yield(nil, failing)
+		}
+	}
+	if _, err := c.ImportQuotes(context.Background(), records); !errors.Is(err, failing) {
+		t.Errorf("ImportQuotes() error = %v, want the error of the records", err)
+	}
+}
+
+func TestClient_AuditEvents(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	admin, _ := newTestClient(t, srv, adminKey)
+	for i := range 5 {
+		if _, err := admin.CreateQuote(ctx, &CreateQuoteRequest{Author: "Heraclitus", Quote: strings.Repeat("river ", i+1)}); err != nil {
+			t.Fatalf("CreateQuote() error = %v", err)
+		}
+	}
+
+	var ids []int64
+	for event, err := range admin.AuditEvents(ctx, AuditFilter{Actor: "ops", Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AuditEvents() error = %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if want := []int64{5, 4, 3, 2, 1}; !slices.Equal(ids, want) {
+		t.Errorf("AuditEvents() ids = %v, want %v across three pages", ids, want)
+	}
+
+	ids = ids[:0]
+	for event, err := range admin.ExportAuditEvents(ctx, AuditFilter{QuoteID: 2, Limit: 1}) {
+		if err != nil {
+			t.Fatalf("ExportAuditEvents() error = %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if want := []int64{2}; !slices.Equal(ids, want) {
+		t.Errorf("ExportAuditEvents() ids = %v, want %v", ids, want)
+	}
+
+	_, err := admin.GetAuditEvents(ctx, AuditFilter{Limit: 1000})
+	if !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Errorf("GetAuditEvents() error = %v, want ErrInvalidAuditFilter", err)
+	}
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	admin, _ := newTestClient(t, srv, adminKey)
+
+	created, err := admin.CreateWebhook(ctx, &WebhookSubscriptionRequest{URL: "https://hooks.example.com/quotes"})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	if created.Secret == "" {
+		t.Error("CreateWebhook() returned no secret")
+	}
+
+	got, err := admin.GetWebhook(ctx, created.ID)
+	if err != nil || got.URL != created.URL || got.Secret != "" {
+		t.Errorf("GetWebhook() = %+v, %v, want the subscription without its secret", got, err)
+	}
+	if subs, err := admin.GetWebhooks(ctx); err != nil || len(subs) != 1 {
+		t.Errorf("GetWebhooks() = %v, %v, want one subscription", subs, err)
+	}
+	if _, err := admin.GetWebhook(ctx, 42); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("GetWebhook() error = %v, want ErrWebhookNotFound", err)
+	}
+	if _, err := admin.CreateWebhook(ctx, &WebhookSubscriptionRequest{URL: "ftp://example.com"}); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("CreateWebhook() error = %v, want ErrInvalidWebhook", err)
+	}
+}
+
+func TestClient_APIKeys(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t, newMemoryRepository(), nil)
+	admin, _ := newTestClient(t, srv, adminKey)
+
+	created, err := admin.CreateAPIKey(ctx, &KeyRequest{Name: "ci"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if created.Key == "" || created.Role != RoleUser {
+		t.Errorf("CreateAPIKey() = %+v, want a user key with the key", created)
+	}
+	if keys, err := admin.GetAPIKeys(ctx); err != nil || len(keys) != 1 || keys[0].Key != "" {
+		t.Errorf("GetAPIKeys() = %v, %v, want one key without the key", keys, err)
+	}
+
+	// The new key authenticates as a user
+	ci, _ := newTestClient(t, srv, created.Key)
+	if _, err := ci.GetAPIKeys(ctx); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetAPIKeys() with the new key error = %v, want ErrForbidden", err)
+	}
+
+	if err := admin.RevokeAPIKey(ctx, created.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := ci.GetAPIKeys(ctx); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("GetAPIKeys() with the revoked key error = %v, want ErrInvalidAPIKey", err)
+	}
+	if err := admin.RevokeAPIKey(ctx, created.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey() error = %v, want ErrAPIKeyNotFound", err)
+	}
+	if _, err := admin.CreateAPIKey(ctx, &KeyRequest{Name: "ci", Role: "root"}); !errors.Is(err, ErrInvalidAPIKeyRequest) {
+		t.Errorf("CreateAPIKey() error = %v, want ErrInvalidAPIKeyRequest", err)
+	}
+}
+
+// failFirst answers the first n requests with status and header instead of passing them on
+func failFirst(n int32, status int, header http.Header) (func(http.Handler) http.Handler, *atomic.Int32) {
+	var calls atomic.Int32
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= n {
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write([]byte(`{"error":"` + strings.ToLower(http.StatusText(status)) + `"}`))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, &calls
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+	t.Run("5xx then success", func(t *testing.T) {
+		wrap, calls := failFirst(2, http.StatusServiceUnavailable, nil)
+		c, delays := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		if _, err := c.GetAllQuotes(ctx); err != nil {
+			t.Fatalf("GetAllQuotes() error = %v", err)
+		}
+		if calls.Load() != 3 || len(*delays) != 2 {
+			t.Fatalf("calls = %d, delays = %v, want 3 calls after 2 waits", calls.Load(), *delays)
+		}
+		// Jitter keeps every delay within the upper half of the doubled backoff
+		for i, d := range *delays {
+			ceiling := policy.BaseDelay << i
+			if d < ceiling/2 || d > ceiling {
+				t.Errorf("delay %d = %v, want between %v and %v", i, d, ceiling/2, ceiling)
+			}
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		wrap, calls := failFirst(5, http.StatusBadGateway, nil)
+		c, _ := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		var apiErr *Error
+		_, err := c.GetAllQuotes(ctx)
+		if !errors.Is(err, ErrServer) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+			t.Errorf("GetAllQuotes() error = %v, want a 502 matching ErrServer", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("calls = %d, want 3", calls.Load())
+		}
+	})
+
+	t.Run("429 honors Retry-After", func(t *testing.T) {
+		wrap, _ := failFirst(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}})
+		c, delays := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		if _, err := c.CreateQuote(ctx, &CreateQuoteRequest{Author: "Zeno", Quote: "Well-being is attained little by little."}); err != nil {
+			t.Fatalf("CreateQuote() error = %v", err)
+		}
+		if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+			t.Errorf("delays = %v, want [2s]", *delays)
+		}
+	})
+
+	t.Run("Retry-After beyond MaxDelay", func(t *testing.T) {
+		wrap, calls := failFirst(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+		c, delays := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		var apiErr *Error
+		_, err := c.GetAllQuotes(ctx)
+		if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Minute {
+			t.Errorf("GetAllQuotes() error = %v, want ErrRateLimited with RetryAfter 1m", err)
+		}
+		if calls.Load() != 1 || len(*delays) != 0 {
+			t.Errorf("calls = %d, delays = %v, want a single attempt", calls.Load(), *delays)
+		}
+	})
+
+	t.Run("POST not retried after 5xx", func(t *testing.T) {
+		wrap, calls := failFirst(1, http.StatusInternalServerError, nil)
+		c, _ := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		if _, err := c.CreateQuote(ctx, &CreateQuoteRequest{Author: "Zeno", Quote: "Steady."}); !errors.Is(err, ErrServer) {
+			t.Errorf("CreateQuote() error = %v, want ErrServer", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+
+	t.Run("4xx not retried", func(t *testing.T) {
+		wrap, calls := failFirst(0, 0, nil)
+		c, _ := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+
+		if _, err := c.GetQuote(ctx, 1); !errors.Is(err, ErrQuoteNotFound) {
+			t.Errorf("GetQuote() error = %v, want ErrQuoteNotFound", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		wrap, calls := failFirst(5, http.StatusServiceUnavailable, nil)
+		c, _ := newTestClient(t, newServer(t, newMemoryRepository(), wrap), userKey, WithRetryPolicy(policy))
+		c.sleep = sleep
+
+		ctx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(20*time.Millisecond, cancel)
+		if _, err := c.GetAllQuotes(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetAllQuotes() error = %v, want context.Canceled", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(http.Header{"Retry-After": {tt.value}}, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := newServer(t, newMemoryRepository(), nil)
+	calls := 0
+	for name, auth := range map[string]Auth{
+		"api key": APIKey(adminKey),
+		"func": AuthFunc(func(req *http.Request) error {
+			calls++
+			req.Header.Set("Authorization", "Bearer "+adminKey)
+			return nil
+		}),
+	} {
+		c, err := New(srv.URL, WithAuth(auth))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := c.GetWebhooks(context.Background()); err != nil {
+			t.Errorf("%s: GetWebhooks() error = %v", name, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("AuthFunc called %d times, want 1", calls)
+	}
+
+	failing := errors.New("token expired")
+	c, _ := New(srv.URL, WithAuth(AuthFunc(func(*http.Request) error { return failing })))
+	if _, err := c.GetAllQuotes(context.Background()); !errors.Is(err, failing) {
+		t.Errorf("GetAllQuotes() error = %v, want the error of the auth", err)
+	}
+}
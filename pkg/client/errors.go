@@ -0,0 +1,192 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+)
+
+// Errors reported by the service, the same values its use cases return. Match them with
+// errors.Is against the error of any method.
+var (
+	ErrQuoteNotFound    = domain.ErrQuoteNotFound
+	ErrNoQuotesFound    = domain.ErrNoQuotesFound
+	ErrInvalidID        = domain.ErrInvalidID
+	ErrInvalidAuthor    = domain.ErrInvalidAuthor
+	ErrInvalidQuote     = domain.ErrInvalidQuote
+	ErrInvalidTag       = domain.ErrInvalidTag
+	ErrVersionConflict  = domain.ErrVersionConflict
+	ErrInvalidVersion   = domain.ErrInvalidVersion
+	ErrRevisionNotFound = domain.ErrRevisionNotFound
+	ErrInvalidRevision  = domain.ErrInvalidRevision
+	ErrNotInTrash       = domain.ErrNotInTrash
+	ErrInvalidAPIKey    = domain.ErrInvalidAPIKey
+	ErrForbidden        = domain.ErrForbidden
+
+	ErrAPIKeyNotFound       = domain.ErrAPIKeyNotFound
+	ErrInvalidAPIKeyRequest = domain.ErrInvalidAPIKeyRequest
+
+	ErrInvalidAuditFilter = domain.ErrInvalidAuditFilter
+	ErrWebhookNotFound    = domain.ErrWebhookNotFound
+	ErrDeliveryNotFound   = domain.ErrDeliveryNotFound
+	ErrInvalidWebhook     = domain.ErrInvalidWebhook
+
+	ErrEmptyBatch          = domain.ErrEmptyBatch
+	ErrBatchTooLarge       = domain.ErrBatchTooLarge
+	ErrBatchRejected       = domain.ErrBatchRejected
+	ErrInvalidDeleteFilter = domain.ErrInvalidDeleteFilter
+	ErrInvalidImport       = domain.ErrInvalidImport
+
+	ErrInvalidQuery       = domain.ErrInvalidQuery
+	ErrInvalidPageToken   = domain.ErrInvalidPageToken
+	ErrInvalidQuoteFilter = domain.ErrInvalidQuoteFilter
+)
+
+// Errors for classes of responses the service has no more specific error for
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+)
+
+// knownErrors are matched against the error message of a response. The service writes
+// either the text of the error or, for some, a message of its own.
+var knownErrors = func() map[string]error {
+	known := make(map[string]error)
+	for _, err := range []error{
+		ErrQuoteNotFound, ErrNoQuotesFound, ErrInvalidID, ErrInvalidAuthor, ErrInvalidQuote, ErrInvalidTag,
+		ErrVersionConflict, ErrInvalidVersion, ErrRevisionNotFound, ErrInvalidRevision, ErrNotInTrash,
+		ErrInvalidAPIKey, ErrForbidden, ErrInvalidAuditFilter, ErrWebhookNotFound, ErrDeliveryNotFound,
+		ErrInvalidWebhook, ErrEmptyBatch, ErrBatchTooLarge, ErrBatchRejected, ErrInvalidDeleteFilter,
+		ErrInvalidImport, ErrInvalidQuery, ErrInvalidPageToken, ErrInvalidQuoteFilter, ErrAPIKeyNotFound,
+		ErrInvalidAPIKeyRequest,
+	} {
+		known[err.Error()] = err
+	}
+	known[domain.MsgQuotesNotFound] = ErrQuoteNotFound
+	known[domain.MsgInvalidQuoteID] = ErrInvalidID
+	known[domain.MsgNotInTrash] = ErrNotInTrash
+	known[domain.MsgVersionConflict] = ErrVersionConflict
+	known[domain.MsgPreconditionFailed] = ErrVersionConflict
+	known[domain.MsgAdminRequired] = ErrForbidden
+	known[domain.MsgAPIKeyRequired] = ErrForbidden
+	known[domain.MsgInvalidSearchQuery] = ErrInvalidQuery
+	return known
+}()
+
+// Error is a response outside 2xx. It wraps the errors above that apply, so both
+// errors.Is(err, client.ErrQuoteNotFound) and errors.As(err, &apiErr) work on it.
+type Error struct {
+	StatusCode int
+	// Message is the "error" field of the response, or the status text
+	Message string
+	// Details lists the invalid fields of a rejected quote
+	Details []*FieldError
+	// Current is the stored quote a conflicting write was rejected against
+	Current *Quote
+	// RetryAfter is how long the service asked the client to wait, zero when it did not
+	RetryAfter time.Duration
+
+	errs []error
+	body []byte
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("quotes: %d %s", e.StatusCode, e.Message)
+	if len(e.Details) > 0 {
+		details := make([]string, len(e.Details))
+		for i, fe := range e.Details {
+			details[i] = fe.Error()
+		}
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// Unwrap returns the errors the response was matched to
+func (e *Error) Unwrap() []error {
+	return e.errs
+}
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 1 << 20
+
+// decodeError reads an error response and closes its body
+func decodeError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RetryAfter: retryAfter(resp.Header, time.Now()),
+		body:       body,
+	}
+	var payload struct {
+		Error   string        `json:"error"`
+		Details []*FieldError `json:"details"`
+		Current *Quote        `json:"current"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message, apiErr.Details, apiErr.Current = payload.Error, payload.Details, payload.Current
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	if err := matchError(apiErr.Message); err != nil {
+		apiErr.errs = append(apiErr.errs, err)
+	}
+	for _, fe := range apiErr.Details {
+		fe.Err = fieldError(fe.Field)
+		apiErr.errs = append(apiErr.errs, fe)
+	}
+	if apiErr.StatusCode == http.StatusConflict && apiErr.Current != nil {
+		apiErr.errs = append(apiErr.errs, &VersionConflictError{Current: apiErr.Current})
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound:
+		apiErr.errs = append(apiErr.errs, ErrNotFound)
+	case apiErr.StatusCode == http.StatusUnauthorized && len(apiErr.errs) == 0:
+		apiErr.errs = append(apiErr.errs, ErrInvalidAPIKey)
+	case apiErr.StatusCode == http.StatusForbidden && len(apiErr.errs) == 0:
+		apiErr.errs = append(apiErr.errs, ErrForbidden)
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		apiErr.errs = append(apiErr.errs, ErrRateLimited)
+	case apiErr.StatusCode >= 500:
+		apiErr.errs = append(apiErr.errs, ErrServer)
+	}
+	return apiErr
+}
+
+// matchError finds the error a message was written for. Errors wrapped with more context,
+// such as "invalid audit filter: limit must be between 1 and 500", match by their prefix.
+func matchError(message string) error {
+	if err, ok := knownErrors[message]; ok {
+		return err
+	}
+	if prefix, _, ok := strings.Cut(message, ": "); ok {
+		return knownErrors[prefix]
+	}
+	return nil
+}
+
+// fieldError returns the error a field of a quote is reported with, as the validation
+// of the service does. Fields reported by OpenAPI validation carry a "body." prefix.
+func fieldError(field string) error {
+	field = strings.TrimPrefix(field, "body.")
+	switch {
+	case field == "author":
+		return ErrInvalidAuthor
+	case field == "quote":
+		return ErrInvalidQuote
+	case strings.HasPrefix(field, "tags"):
+		return ErrInvalidTag
+	}
+	return nil
+}
@@ -0,0 +1,446 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/usecase"
+)
+
+// memoryRepository keeps quotes in memory for the tests of the client against the real
+// use cases and handlers. Methods the tests do not reach are left to the embedded nil
+// interface and panic.
+type memoryRepository struct {
+	usecase.QuoteRepository
+
+	mu       sync.Mutex
+	quotes   []*domain.Quote
+	nextID   int
+	version  int64
+	events   []*domain.AuditEvent
+	webhooks []*domain.WebhookSubscription
+	// keys holds the stored API keys by digest
+	keys map[string]*domain.APIKey
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{nextID: 1, keys: make(map[string]*domain.APIKey)}
+}
+
+func clone(quote *domain.Quote) *domain.Quote {
+	c := *quote
+	c.Tags = slices.Clone(quote.Tags)
+	return &c
+}
+
+// audit records a write the way the repository does
+func (r *memoryRepository) audit(entry domain.AuditEntry, quoteID int) {
+	r.events = append(r.events, &domain.AuditEvent{
+		ID:         int64(len(r.events) + 1),
+		Action:     entry.Action,
+		Actor:      entry.Actor,
+		QuoteID:    quoteID,
+		OccurredAt: time.Now().UTC(),
+	})
+	r.version++
+}
+
+func (r *memoryRepository) insert(entry domain.AuditEntry, quote *domain.Quote) *domain.Quote {
+	quote = clone(quote)
+	quote.ID = r.nextID
+	quote.Version = 1
+	r.nextID++
+	r.quotes = append(r.quotes, quote)
+	r.audit(entry, quote.ID)
+	return clone(quote)
+}
+
+// find returns the stored quote with id, in the trash or not
+func (r *memoryRepository) find(id int) *domain.Quote {
+	for _, quote := range r.quotes {
+		if quote.ID == id {
+			return quote
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) live() []*domain.Quote {
+	quotes := []*domain.Quote{}
+	for _, quote := range r.quotes {
+		if quote.DeletedAt == nil {
+			quotes = append(quotes, clone(quote))
+		}
+	}
+	return quotes
+}
+
+// current returns the live quote with id, or the error the database reports for it
+func (r *memoryRepository) current(id, version int) (*domain.Quote, error) {
+	quote := r.find(id)
+	if quote == nil || quote.DeletedAt != nil {
+		return nil, domain.ErrQuoteNotFound
+	}
+	if version != 0 && version != quote.Version {
+		return nil, &domain.VersionConflictError{Current: clone(quote)}
+	}
+	return quote, nil
+}
+
+func (r *memoryRepository) Create(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insert(entry, quote), nil
+}
+
+func (r *memoryRepository) CreateBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := make([]*domain.Quote, len(quotes))
+	for i, quote := range quotes {
+		created[i] = r.insert(entry, quote)
+	}
+	return created, nil
+}
+
+func (r *memoryRepository) ImportBatch(ctx context.Context, entry domain.AuditEntry, quotes []*domain.Quote) ([]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var inserted []*domain.Quote
+	for _, quote := range quotes {
+		if !slices.ContainsFunc(r.quotes, func(q *domain.Quote) bool {
+			return q.Author == quote.Author && q.Quote == quote.Quote
+		}) {
+			inserted = append(inserted, r.insert(entry, quote))
+		}
+	}
+	return inserted, nil
+}
+
+func (r *memoryRepository) List(filter domain.QuoteFilter) ([]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quotes := slices.DeleteFunc(r.live(), func(q *domain.Quote) bool {
+		length := utf8.RuneCountInString(q.Quote)
+		return filter.Author != "" && q.Author != filter.Author ||
+			filter.Tag != "" && !slices.Contains(q.Tags, filter.Tag) ||
+			!filter.CreatedAfter.IsZero() && !q.CreatedAt.After(filter.CreatedAfter) ||
+			!filter.CreatedBefore.IsZero() && !q.CreatedAt.Before(filter.CreatedBefore) ||
+			filter.MinLength > 0 && length < filter.MinLength ||
+			filter.MaxLength > 0 && length > filter.MaxLength
+	})
+
+	slices.SortStableFunc(quotes, func(a, b *domain.Quote) int {
+		return compareCursors(filter.Sort, domain.NewQuoteCursor(a, filter.Sort), domain.NewQuoteCursor(b, filter.Sort))
+	})
+	if !filter.After.IsZero() {
+		quotes = slices.DeleteFunc(quotes, func(q *domain.Quote) bool {
+			return compareCursors(filter.Sort, domain.NewQuoteCursor(q, filter.Sort), filter.After) <= 0
+		})
+	}
+	if filter.Limit > 0 && len(quotes) > filter.Limit {
+		quotes = quotes[:filter.Limit]
+	}
+	return quotes, nil
+}
+
+// compareCursors orders two positions in a listing sorted by sort, ties broken by ID
+func compareCursors(sort domain.QuoteSort, a, b domain.QuoteCursor) int {
+	var c int
+	switch sort.Key() {
+	case "created_at":
+		at, _ := time.Parse(time.RFC3339Nano, a.Key)
+		bt, _ := time.Parse(time.RFC3339Nano, b.Key)
+		c = at.Compare(bt)
+	case "author":
+		c = strings.Compare(a.Key, b.Key)
+	case "length":
+		an, _ := strconv.Atoi(a.Key)
+		bn, _ := strconv.Atoi(b.Key)
+		c = an - bn
+	}
+	if c == 0 {
+		c = a.ID - b.ID
+	}
+	if sort.Descending() {
+		return -c
+	}
+	return c
+}
+
+func (r *memoryRepository) GetByID(id int) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quote, err := r.current(id, 0)
+	if err != nil {
+		return nil, err
+	}
+	return clone(quote), nil
+}
+
+func (r *memoryRepository) ListIDs() ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := []int{}
+	for _, quote := range r.live() {
+		ids = append(ids, quote.ID)
+	}
+	return ids, nil
+}
+
+func (r *memoryRepository) GetNth(n int64) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quotes := r.live()
+	if len(quotes) == 0 {
+		return nil, domain.ErrNoQuotesFound
+	}
+	return quotes[n%int64(len(quotes))], nil
+}
+
+func (r *memoryRepository) Stream(fn func(*domain.Quote) error) error {
+	quotes, _ := r.List(domain.QuoteFilter{})
+	for _, quote := range quotes {
+		if err := fn(quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) Version() (*domain.CollectionVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &domain.CollectionVersion{Version: r.version}, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, entry domain.AuditEntry, quote *domain.Quote) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, err := r.current(quote.ID, quote.Version)
+	if err != nil {
+		return nil, err
+	}
+	stored.Author, stored.Quote, stored.Tags = quote.Author, quote.Quote, slices.Clone(quote.Tags)
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.audit(entry, stored.ID)
+	return clone(stored), nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, err := r.current(id, version)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	stored.DeletedAt = &now
+	r.audit(entry, id)
+	return nil
+}
+
+func (r *memoryRepository) HardDelete(ctx context.Context, entry domain.AuditEntry, id, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.current(id, version); err != nil {
+		return err
+	}
+	r.quotes = slices.DeleteFunc(r.quotes, func(q *domain.Quote) bool { return q.ID == id })
+	r.audit(entry, id)
+	return nil
+}
+
+func (r *memoryRepository) ListDeleted() ([]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quotes := []*domain.Quote{}
+	for _, quote := range r.quotes {
+		if quote.DeletedAt != nil {
+			quotes = append(quotes, clone(quote))
+		}
+	}
+	return quotes, nil
+}
+
+func (r *memoryRepository) GetByIDWithDeleted(id int) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quote := r.find(id)
+	if quote == nil {
+		return nil, domain.ErrQuoteNotFound
+	}
+	return clone(quote), nil
+}
+
+func (r *memoryRepository) Undelete(ctx context.Context, entry domain.AuditEntry, id int) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.find(id)
+	if stored == nil {
+		return nil, domain.ErrQuoteNotFound
+	}
+	if stored.DeletedAt == nil {
+		return nil, domain.ErrNotInTrash
+	}
+	stored.DeletedAt = nil
+	r.audit(entry, id)
+	return clone(stored), nil
+}
+
+// matches applies the parts of an audit filter the tests use
+func matches(filter domain.AuditFilter, event *domain.AuditEvent) bool {
+	return (filter.Actor == "" || event.Actor == filter.Actor) &&
+		(filter.Action == "" || event.Action == filter.Action) &&
+		(filter.QuoteID == 0 || event.QuoteID == filter.QuoteID)
+}
+
+func (r *memoryRepository) ListAuditEvents(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := []*domain.AuditEvent{}
+	for _, event := range slices.Backward(r.events) {
+		if len(events) == filter.Limit {
+			break
+		}
+		if (filter.BeforeID == 0 || event.ID < filter.BeforeID) && matches(filter, event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryRepository) StreamAuditEvents(filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	r.mu.Lock()
+	events := slices.Clone(r.events)
+	r.mu.Unlock()
+	for _, event := range events {
+		if !matches(filter, event) {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListWebhooks() ([]*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := make([]*domain.WebhookSubscription, len(r.webhooks))
+	for i, sub := range r.webhooks {
+		c := *sub
+		subs[i] = &c
+	}
+	return subs, nil
+}
+
+func (r *memoryRepository) GetWebhook(id int) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.webhooks {
+		if sub.ID == id {
+			c := *sub
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrWebhookNotFound
+}
+
+func (r *memoryRepository) CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := *sub
+	created.ID = len(r.webhooks) + 1
+	r.webhooks = append(r.webhooks, &created)
+	result := created
+	return &result, nil
+}
+
+func (r *memoryRepository) ListAPIKeys() ([]*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := []*domain.APIKey{}
+	for _, key := range r.keys {
+		c := *key
+		keys = append(keys, &c)
+	}
+	slices.SortFunc(keys, func(a, b *domain.APIKey) int { return a.ID - b.ID })
+	return keys, nil
+}
+
+func (r *memoryRepository) GetAPIKeyByDigest(digest string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key, ok := r.keys[digest]; ok {
+		c := *key
+		return &c, nil
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey, digest string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := *key
+	created.ID = len(r.keys) + 1
+	created.CreatedAt = time.Now().UTC()
+	r.keys[digest] = &created
+	result := created
+	return &result, nil
+}
+
+func (r *memoryRepository) DeleteAPIKey(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for digest, key := range r.keys {
+		if key.ID == id {
+			delete(r.keys, digest)
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryRepository) ListPages(reqs []domain.PageRequest) ([][]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pages := make([][]*domain.Quote, len(reqs))
+	for i, req := range reqs {
+		pages[i] = []*domain.Quote{}
+		for _, quote := range r.live() {
+			if len(pages[i]) == req.Limit {
+				break
+			}
+			if quote.ID > req.AfterID && (req.Author == "" || quote.Author == req.Author) &&
+				(req.Tag == "" || slices.Contains(quote.Tags, req.Tag)) {
+				pages[i] = append(pages[i], quote)
+			}
+		}
+	}
+	return pages, nil
+}
+
+func (r *memoryRepository) Search(text string, limit int) ([]*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	text = strings.ToLower(text)
+	quotes := []*domain.Quote{}
+	for _, quote := range slices.Backward(r.live()) {
+		if len(quotes) == limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(quote.Quote+" "+quote.Author+" "+strings.Join(quote.Tags, " ")), text) {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes, nil
+}
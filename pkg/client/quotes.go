@@ -0,0 +1,482 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quoteList decodes a list of quotes, which the service writes as {} when it is empty
+type quoteList []*Quote
+
+func (l *quoteList) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "{}" {
+		*l = quoteList{}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]*Quote)(l))
+}
+
+func quotePath(id int, suffix string) string {
+	return "/quotes/" + strconv.Itoa(id) + suffix
+}
+
+// CreateQuote POST /quotes
+func (c *Client) CreateQuote(ctx context.Context, req *CreateQuoteRequest) (*Quote, error) {
+	var quote Quote
+	if err := c.call(ctx, &request{method: http.MethodPost, path: "/quotes"}, req, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// GetAllQuotes GET /quotes
+func (c *Client) GetAllQuotes(ctx context.Context) ([]*Quote, error) {
+	return c.getQuotes(ctx, nil)
+}
+
+// GetQuotesByAuthor GET /quotes?author=
+func (c *Client) GetQuotesByAuthor(ctx context.Context, author string) ([]*Quote, error) {
+	return c.getQuotes(ctx, url.Values{"author": {author}})
+}
+
+func (c *Client) getQuotes(ctx context.Context, query url.Values) ([]*Quote, error) {
+	var quotes quoteList
+	if err := c.call(ctx, get("/quotes", query), nil, &quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// ListOptions select a page of GET /quotes
+type ListOptions struct {
+	Author string
+	Tag    string
+	// CreatedAfter and CreatedBefore bound the creation time, both exclusive
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// MinLength and MaxLength bound the length of the text in characters, both inclusive
+	MinLength int
+	MaxLength int
+	// Sort is created_at, author or length, descending with a leading -; empty for ID order
+	Sort string
+	// Limit is the page size; 0 lets the service choose
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page. It is opaque
+	// and only continues a listing with the same Sort.
+	Cursor string
+}
+
+// QuotePage is one page of quotes
+type QuotePage struct {
+	Quotes []*Quote `json:"quotes"`
+	// NextCursor continues the listing after this page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListQuotes GET /quotes?limit=&cursor= returns one page of quotes
+func (c *Client) ListQuotes(ctx context.Context, opts ListOptions) (*QuotePage, error) {
+	query := url.Values{"limit": {strconv.Itoa(opts.Limit)}}
+	if opts.Author != "" {
+		query.Set("author", opts.Author)
+	}
+	if opts.Tag != "" {
+		query.Set("tag", opts.Tag)
+	}
+	if !opts.CreatedAfter.IsZero() {
+		query.Set("created_after", opts.CreatedAfter.Format(time.RFC3339Nano))
+	}
+	if !opts.CreatedBefore.IsZero() {
+		query.Set("created_before", opts.CreatedBefore.Format(time.RFC3339Nano))
+	}
+	if opts.MinLength != 0 {
+		query.Set("min_length", strconv.Itoa(opts.MinLength))
+	}
+	if opts.MaxLength != 0 {
+		query.Set("max_length", strconv.Itoa(opts.MaxLength))
+	}
+	if opts.Sort != "" {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+
+	resp, err := c.do(ctx, get("/quotes", query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var quotes quoteList
+	if err := json.NewDecoder(resp.Body).Decode(&quotes); err != nil {
+		return nil, fmt.Errorf("quotes: failed to decode response: %w", err)
+	}
+	return &QuotePage{Quotes: quotes, NextCursor: nextCursor(resp.Header)}, nil
+}
+
+// Quotes yields the quotes selected by opts, reading a page of opts.Limit quotes at a time
+func (c *Client) Quotes(ctx context.Context, opts ListOptions) iter.Seq2[*Quote, error] {
+	return func(yield func(*Quote, error) bool) {
+		for {
+			page, err := c.ListQuotes(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, quote := range page.Quotes {
+				if !yield(quote, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+}
+
+// nextCursor reads the cursor of the rel="next" link the service sends with a page
+// that is not the last one
+func nextCursor(header http.Header) string {
+	for _, link := range header.Values("Link") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+		if cursor := u.Query().Get("cursor"); cursor != "" {
+			return cursor
+		}
+	}
+	return ""
+}
+
+// SearchQuotes GET /quotes/search returns the newest quotes whose text, author or tags
+// contain query, ignoring case. A limit of 0 lets the service choose.
+func (c *Client) SearchQuotes(ctx context.Context, query string, limit int) ([]*Quote, error) {
+	values := url.Values{"q": {query}}
+	if limit != 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	var quotes quoteList
+	if err := c.call(ctx, get("/quotes/search", values), nil, &quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// GetRandomQuote GET /quotes/random
+func (c *Client) GetRandomQuote(ctx context.Context) (*Quote, error) {
+	return c.getQuote(ctx, "/quotes/random", ErrNoQuotesFound)
+}
+
+// GetDailyQuote GET /quotes/daily returns the quote of the current UTC day
+func (c *Client) GetDailyQuote(ctx context.Context) (*Quote, error) {
+	return c.getQuote(ctx, "/quotes/daily", ErrNoQuotesFound)
+}
+
+// GetQuote GET /quotes/{id}
+func (c *Client) GetQuote(ctx context.Context, id int) (*Quote, error) {
+	return c.getQuote(ctx, quotePath(id, ""), ErrQuoteNotFound)
+}
+
+// getQuote reads one quote. The service answers a missing quote and an empty collection
+// alike, so notFound tells which of the two a 404 means here.
+func (c *Client) getQuote(ctx context.Context, path string, notFound error) (*Quote, error) {
+	var quote Quote
+	if err := c.call(ctx, get(path, nil), nil, &quote); err != nil {
+		var apiErr *Error
+		if notFound != ErrQuoteNotFound && errors.As(err, &apiErr) && errors.Is(err, ErrQuoteNotFound) {
+			for i, e := range apiErr.errs {
+				if e == ErrQuoteNotFound {
+					apiErr.errs[i] = notFound
+				}
+			}
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// UpdateQuote PUT /quotes/{id}. A non-zero req.Version makes the update conditional; it
+// then fails with a *VersionConflictError holding the current quote.
+func (c *Client) UpdateQuote(ctx context.Context, id int, req *UpdateQuoteRequest) (*Quote, error) {
+	var quote Quote
+	if err := c.call(ctx, &request{method: http.MethodPut, path: quotePath(id, "")}, req, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// DeleteQuote DELETE /quotes/{id} moves a quote to the trash. A non-zero version makes the
+// delete conditional in the same way as UpdateQuote.
+func (c *Client) DeleteQuote(ctx context.Context, id, version int) error {
+	return c.deleteQuote(ctx, id, version, false)
+}
+
+// HardDeleteQuote DELETE /quotes/{id}?hard=true removes a quote for good. Only admins may do this.
+func (c *Client) HardDeleteQuote(ctx context.Context, id, version int) error {
+	return c.deleteQuote(ctx, id, version, true)
+}
+
+func (c *Client) deleteQuote(ctx context.Context, id, version int, hard bool) error {
+	req := &request{method: http.MethodDelete, path: quotePath(id, "")}
+	if hard {
+		req.query = url.Values{"hard": {"true"}}
+	}
+	if err := c.ifMatch(ctx, req, id, version); err != nil {
+		return err
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ifMatch makes req conditional on the quote still being at version. The API takes the
+// condition as an ETag, so the quote is read first: a quote at another version fails
+// with a *VersionConflictError, and the ETag of one at version makes the server reject
+// the request if it changes in between.
+func (c *Client) ifMatch(ctx context.Context, req *request, id, version int) error {
+	if version == 0 {
+		return nil
+	}
+	if version < 0 {
+		return ErrInvalidVersion
+	}
+
+	resp, err := c.do(ctx, get(quotePath(id, ""), nil))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var current Quote
+	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+		return fmt.Errorf("quotes: failed to decode response: %w", err)
+	}
+	if current.Version != version {
+		return &VersionConflictError{Current: &current}
+	}
+
+	req.header = http.Header{"If-Match": {resp.Header.Get("ETag")}}
+	return nil
+}
+
+// CreateQuotes POST /quotes:batch. When req.Atomic is false some quotes may be rejected
+// while the rest are created; the result tells which. When it is true an invalid quote
+// rejects the batch, which returns the result along with an error matching ErrBatchRejected.
+func (c *Client) CreateQuotes(ctx context.Context, req *BatchCreateRequest) (*BatchCreateResult, error) {
+	items := req.Quotes
+	if items == nil {
+		items = []*CreateQuoteRequest{}
+	}
+	r := &request{
+		method: http.MethodPost,
+		path:   "/quotes:batch",
+		query:  url.Values{"atomic": {strconv.FormatBool(req.Atomic)}},
+	}
+
+	var result BatchCreateResult
+	err := c.call(ctx, r, items, &result)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		apiErr.Message = ErrBatchRejected.Error()
+		apiErr.errs = append(apiErr.errs, ErrBatchRejected)
+		if json.Unmarshal(apiErr.body, &result) == nil {
+			return &result, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteQuotes DELETE /quotes moves the quotes selected by req.Filter to the trash, or only
+// lists them when req.DryRun is set
+func (c *Client) DeleteQuotes(ctx context.Context, req *BatchDeleteRequest) (*BatchDeleteResult, error) {
+	r := &request{
+		method: http.MethodDelete,
+		path:   "/quotes",
+		query:  url.Values{"dry_run": {strconv.FormatBool(req.DryRun)}},
+	}
+
+	var result BatchDeleteResult
+	if err := c.call(ctx, r, req.Filter, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportQuotes GET /quotes/export yields every quote, streaming them as the service reads
+// them. Stopping the loop early closes the connection.
+func (c *Client) ExportQuotes(ctx context.Context) iter.Seq2[*Quote, error] {
+	return func(yield func(*Quote, error) bool) {
+		resp, err := c.do(ctx, get("/quotes/export", url.Values{"format": {string(FormatJSONL)}}))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var quote Quote
+			if err := dec.Decode(&quote); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, fmt.Errorf("quotes: export interrupted: %w", err))
+				}
+				return
+			}
+			if !yield(&quote, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ExportQuotesTo GET /quotes/export copies every quote to w in the given format
+func (c *Client) ExportQuotesTo(ctx context.Context, format Format, w io.Writer) error {
+	resp, err := c.do(ctx, get("/quotes/export", url.Values{"format": {string(format)}}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("quotes: export interrupted: %w", err)
+	}
+	return nil
+}
+
+// ImportQuotes POST /quotes/import streams records to the service as JSON lines. Quotes
+// that already exist are skipped; invalid ones are listed in the report. An error from
+// records aborts the upload.
+func (c *Client) ImportQuotes(ctx context.Context, records iter.Seq2[*CreateQuoteRequest, error]) (*ImportReport, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		enc := json.NewEncoder(pw)
+		for record, err := range records {
+			if err == nil {
+				err = enc.Encode(record)
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+	defer pr.Close()
+
+	return c.importQuotes(ctx, pr, ImportOptions{Format: FormatJSONL})
+}
+
+// ImportOptions describe a file for ImportQuotesFrom
+type ImportOptions struct {
+	Format Format
+	// DefaultAuthor is used for records without an author, such as most fortune files
+	DefaultAuthor string
+}
+
+// ImportQuotesFrom POST /quotes/import uploads a file in the given format
+func (c *Client) ImportQuotesFrom(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	return c.importQuotes(ctx, r, opts)
+}
+
+func (c *Client) importQuotes(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	req := &request{
+		method: http.MethodPost,
+		path:   "/quotes/import",
+		query:  url.Values{},
+		header: http.Header{"Content-Type": {"application/octet-stream"}},
+		stream: r,
+	}
+	// Without a format the service rejects the upload as it cannot tell the format either
+	if opts.Format != "" {
+		req.query.Set("format", string(opts.Format))
+		req.header.Set("Content-Type", opts.Format.ContentType())
+	}
+	if opts.DefaultAuthor != "" {
+		req.query.Set("default_author", opts.DefaultAuthor)
+	}
+
+	var report ImportReport
+	if err := c.call(ctx, req, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetRevisions GET /quotes/{id}/revisions
+func (c *Client) GetRevisions(ctx context.Context, id int) ([]*Revision, error) {
+	var revisions []*Revision
+	if err := c.call(ctx, get(quotePath(id, "/revisions"), nil), nil, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetRevision GET /quotes/{id}/revisions/{rev}
+func (c *Client) GetRevision(ctx context.Context, id, revision int) (*Revision, error) {
+	var rev Revision
+	if err := c.call(ctx, get(quotePath(id, "/revisions/"+strconv.Itoa(revision)), nil), nil, &rev); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// DiffRevisions GET /quotes/{id}/revisions/diff?from=&to=
+func (c *Client) DiffRevisions(ctx context.Context, id, from, to int) (*RevisionDiff, error) {
+	query := url.Values{"from": {strconv.Itoa(from)}, "to": {strconv.Itoa(to)}}
+	var d RevisionDiff
+	if err := c.call(ctx, get(quotePath(id, "/revisions/diff"), query), nil, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RestoreRevision POST /quotes/{id}/revisions/{rev}:restore brings a quote back to an
+// earlier revision. A non-zero version makes the restore conditional in the same way as
+// UpdateQuote.
+func (c *Client) RestoreRevision(ctx context.Context, id, revision, version int) (*Quote, error) {
+	req := &request{method: http.MethodPost, path: quotePath(id, "/revisions/"+strconv.Itoa(revision)+":restore")}
+	if err := c.ifMatch(ctx, req, id, version); err != nil {
+		return nil, err
+	}
+
+	var quote Quote
+	if err := c.call(ctx, req, nil, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// GetTrash GET /trash
+func (c *Client) GetTrash(ctx context.Context) ([]*Quote, error) {
+	var quotes []*Quote
+	if err := c.call(ctx, get("/trash", nil), nil, &quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// RestoreQuote POST /quotes/{id}:restore takes a quote out of the trash
+func (c *Client) RestoreQuote(ctx context.Context, id int) (*Quote, error) {
+	var quote Quote
+	if err := c.call(ctx, &request{method: http.MethodPost, path: quotePath(id, ":restore")}, nil, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried after 429 and 5xx responses and after
+// network errors.
+//
+// A 429 means the request was not processed, so any request is retried. Other failures
+// are only retried for GET, HEAD, PUT and DELETE, which are safe to repeat; a POST may
+// have taken effect before the response was lost. Requests with a streamed body, such as
+// imports, are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles before each further one.
+	// Every delay is then picked at random from its upper half, so clients that failed
+	// together do not retry together.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay. A Retry-After asking for longer is not waited for and the
+	// response is returned instead.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy is given
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// backoff returns the delay before retry number retry, counted from 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.MaxDelay)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryable reports whether a request with method may be sent again after a response
+// with status, or after a network error when status is 0
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if status != 0 && status < 500 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
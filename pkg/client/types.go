@@ -0,0 +1,88 @@
+package client
+
+import (
+	"github.com/shoksin/quotes-service/internal/diff"
+	"github.com/shoksin/quotes-service/internal/domain"
+	"github.com/shoksin/quotes-service/internal/transfer"
+)
+
+// The types below are the ones the service itself uses, so requests and responses cannot
+// drift from the API. They are aliases, which lets code outside this module name them.
+type (
+	Quote              = domain.Quote
+	CreateQuoteRequest = domain.CreateQuoteRequest
+	UpdateQuoteRequest = domain.UpdateQuoteRequest
+	FieldError         = domain.FieldError
+
+	BatchCreateRequest = domain.BatchCreateRequest
+	BatchCreateResult  = domain.BatchCreateResult
+	BatchItemResult    = domain.BatchItemResult
+	BatchDeleteRequest = domain.BatchDeleteRequest
+	BatchDeleteResult  = domain.BatchDeleteResult
+	DeleteFilter       = domain.DeleteFilter
+	ImportReport       = domain.ImportReport
+	ImportError        = domain.ImportError
+
+	Revision     = domain.Revision
+	RevisionDiff = domain.RevisionDiff
+	ChangeType   = domain.ChangeType
+	Edit         = diff.Edit
+
+	AuditAction       = domain.AuditAction
+	AuditEvent        = domain.AuditEvent
+	AuditFilter       = domain.AuditFilter
+	AuditPage         = domain.AuditPage
+	AuditVerification = domain.AuditVerification
+
+	EventType                  = domain.EventType
+	WebhookSubscription        = domain.WebhookSubscription
+	WebhookSubscriptionRequest = domain.WebhookSubscriptionRequest
+	WebhookDelivery            = domain.WebhookDelivery
+	DeliveryStatus             = domain.DeliveryStatus
+
+	// Key is a stored API key; the name APIKey is taken by the Auth scheme
+	Key        = domain.APIKey
+	KeyRequest = domain.APIKeyRequest
+	Role       = domain.Role
+
+	VersionConflictError = domain.VersionConflictError
+
+	// Format is a file format of ExportQuotesTo and ImportQuotesFrom
+	Format = transfer.Format
+)
+
+// File formats of the export and import endpoints
+const (
+	FormatCSV     = transfer.FormatCSV
+	FormatJSONL   = transfer.FormatJSONL
+	FormatJSON    = transfer.FormatJSON
+	FormatFortune = transfer.FormatFortune
+)
+
+// Formats lists every file format
+var Formats = transfer.Formats
+
+// ParseFormat validates a format name such as "csv"
+func ParseFormat(name string) (Format, error) {
+	return transfer.ParseFormat(name)
+}
+
+// Webhook event types a subscription may select
+const (
+	EventQuoteCreated = domain.EventQuoteCreated
+	EventQuoteUpdated = domain.EventQuoteUpdated
+	EventQuoteDeleted = domain.EventQuoteDeleted
+)
+
+// Roles an API key may have
+const (
+	RoleUser  = domain.RoleUser
+	RoleAdmin = domain.RoleAdmin
+)
+
+// Delivery states accepted by GetDeliveries
+const (
+	DeliveryPending   = domain.DeliveryPending
+	DeliveryDelivered = domain.DeliveryDelivered
+	DeliveryDead      = domain.DeliveryDead
+)